@@ -8,7 +8,7 @@
 
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/genesis"
-	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/runtime/executor"
 )
 
 var (
@@ -60,7 +60,11 @@
 	// DefaultGrandpaAuthority is true if the node is a grandpa authority (overwrites previous settings)
 	DefaultGrandpaAuthority = true
 	// DefaultWasmInterpreter is the name of the wasm interpreter to use by default
-	DefaultWasmInterpreter = wasmer.Name
+	DefaultWasmInterpreter = executor.Default
+	// DefaultOffchainWorker is the default execution mode of the offchain workers
+	DefaultOffchainWorker = "when-validating"
+	// DefaultRuntimePoolSize is the default number of instances of each runtime instance pool
+	DefaultRuntimePoolSize = 8
 
 	// NetworkConfig
 
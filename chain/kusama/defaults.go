@@ -6,7 +6,7 @@
 import (
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/genesis"
-	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/runtime/executor"
 )
 
 var (
@@ -54,7 +54,11 @@
 	// DefaultRoles Default node roles
 	DefaultRoles = byte(1) // full node (see Table D.2)
 	// DefaultWasmInterpreter is the name of the wasm interpreter to use by default
-	DefaultWasmInterpreter = wasmer.Name
+	DefaultWasmInterpreter = executor.Default
+	// DefaultOffchainWorker is the default execution mode of the offchain workers
+	DefaultOffchainWorker = "when-validating"
+	// DefaultRuntimePoolSize is the default number of instances of each runtime instance pool
+	DefaultRuntimePoolSize = 8
 
 	// NetworkConfig
 
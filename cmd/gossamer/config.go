@@ -14,14 +14,14 @@
 	"github.com/ChainSafe/gossamer/chain/gssmr"
 	"github.com/ChainSafe/gossamer/dot"
 	ctoml "github.com/ChainSafe/gossamer/dot/config/toml"
+	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
-	"github.com/ChainSafe/gossamer/lib/runtime/life"
-	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/runtime/executor"
 	"github.com/ChainSafe/gossamer/lib/utils"
 	"github.com/urfave/cli"
 )
@@ -407,6 +407,24 @@ func setLogConfig(flagsKVStore stringKVStore, tomlConfig *ctoml.Config,
 	return nil
 }
 
+// reloadLogConfig loads the log levels from the log flags and the `[log]`
+// section of the TOML configuration, and applies them to the node loggers.
+func reloadLogConfig(ctx *cli.Context, node *dot.Node) error {
+	tomlCfg, cfg, err := setupConfigFromChain(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = setLogConfig(ctx, tomlCfg, &cfg.Global, &cfg.Log)
+	if err != nil {
+		return fmt.Errorf("cannot set log configuration: %w", err)
+	}
+
+	node.ReloadLogConfig(cfg.Global.LogLvl, cfg.Log)
+	logger.Infof("reloaded package log configuration: %s", cfg.Log)
+	return nil
+}
+
 // setDotInitConfig sets dot.InitConfig using flag values from the cli context
 func setDotInitConfig(ctx *cli.Context, tomlCfg ctoml.InitConfig, cfg *dot.InitConfig) {
 	if tomlCfg.Genesis != "" {
@@ -583,6 +601,37 @@ func setDotCoreConfig(ctx *cli.Context, tomlCfg ctoml.CoreConfig, cfg *dot.CoreC
 		cfg.BABELead = ctx.GlobalBool(BABELeadFlag.Name)
 	}
 
+	cfg.OffchainWorker = core.OffchainWorkerMode(tomlCfg.OffchainWorker)
+	if mode := ctx.GlobalString(OffchainWorkerFlag.Name); mode != "" {
+		cfg.OffchainWorker = core.OffchainWorkerMode(mode)
+	}
+
+	if cfg.OffchainWorker == "" {
+		cfg.OffchainWorker = core.OffchainWorkerMode(gssmr.DefaultOffchainWorker)
+	} else if !cfg.OffchainWorker.IsValid() {
+		logger.Warnf("invalid offchain worker mode %q, defaulting to %s", cfg.OffchainWorker, gssmr.DefaultOffchainWorker)
+		cfg.OffchainWorker = core.OffchainWorkerMode(gssmr.DefaultOffchainWorker)
+	}
+
+	cfg.OffchainWorkerTimeout = time.Second * time.Duration(tomlCfg.OffchainWorkerTimeout)
+	if ctx.IsSet(OffchainWorkerTimeoutFlag.Name) {
+		cfg.OffchainWorkerTimeout = time.Second * time.Duration(ctx.GlobalUint(OffchainWorkerTimeoutFlag.Name))
+	}
+
+	cfg.RuntimePoolSize = tomlCfg.RuntimePoolSize
+	if ctx.IsSet(RuntimePoolSizeFlag.Name) {
+		cfg.RuntimePoolSize = ctx.GlobalInt(RuntimePoolSizeFlag.Name)
+	}
+
+	if cfg.RuntimePoolSize <= 0 {
+		cfg.RuntimePoolSize = gssmr.DefaultRuntimePoolSize
+	}
+
+	cfg.OffchainHTTPDisabled = tomlCfg.OffchainHTTPDisabled
+	if ctx.IsSet(OffchainHTTPDisabledFlag.Name) {
+		cfg.OffchainHTTPDisabled = ctx.GlobalBool(OffchainHTTPDisabledFlag.Name)
+	}
+
 	// check --roles flag and update node configuration
 	if roles := ctx.GlobalString(RolesFlag.Name); roles != "" {
 		// convert string to byte
@@ -619,12 +668,11 @@ func setDotCoreConfig(ctx *cli.Context, tomlCfg ctoml.CoreConfig, cfg *dot.CoreC
 		cfg.GrandpaAuthority = false
 	}
 
-	switch tomlCfg.WasmInterpreter {
-	case wasmer.Name:
-		cfg.WasmInterpreter = wasmer.Name
-	case life.Name:
-		cfg.WasmInterpreter = life.Name
-	case "":
+	_, ok := executor.Lookup(tomlCfg.WasmInterpreter)
+	switch {
+	case ok:
+		cfg.WasmInterpreter = tomlCfg.WasmInterpreter
+	case tomlCfg.WasmInterpreter == "":
 		cfg.WasmInterpreter = gssmr.DefaultWasmInterpreter
 	default:
 		cfg.WasmInterpreter = gssmr.DefaultWasmInterpreter
@@ -716,6 +764,14 @@ func setDotRPCConfig(ctx *cli.Context, tomlCfg ctoml.RPCConfig, cfg *dot.RPCConf
 	cfg.WSExternal = tomlCfg.WSExternal
 	cfg.WSUnsafe = tomlCfg.WSUnsafe
 	cfg.WSUnsafeExternal = tomlCfg.WSUnsafeExternal
+	cfg.MaxBatchSize = tomlCfg.MaxBatchSize
+	cfg.MethodsAllowed = tomlCfg.MethodsAllowed
+	cfg.MethodsDenied = tomlCfg.MethodsDenied
+	cfg.MaxRequestsPerSecond = tomlCfg.MaxRequestsPerSecond
+	cfg.MaxSubscriptionsPerConnection = tomlCfg.MaxSubscriptionsPerConnection
+	cfg.MaxConnections = tomlCfg.MaxConnections
+	cfg.IPCPath = tomlCfg.IPCPath
+	cfg.IPCPermissions = tomlCfg.IPCPermissions
 
 	// check --rpc flag and update node configuration
 	if enabled := ctx.GlobalBool(RPCEnabledFlag.Name); enabled || cfg.Enabled {
@@ -770,6 +826,46 @@ func setDotRPCConfig(ctx *cli.Context, tomlCfg ctoml.RPCConfig, cfg *dot.RPCConf
 		cfg.Modules = strings.Split(ctx.GlobalString(RPCModulesFlag.Name), ",")
 	}
 
+	// check --rpc-max-batch-size flag and update node configuration
+	if maxBatchSize := ctx.GlobalUint(RPCMaxBatchSizeFlag.Name); maxBatchSize != 0 {
+		cfg.MaxBatchSize = uint32(maxBatchSize)
+	}
+
+	// check --rpc-methods-allowed flag and update node configuration
+	if methods := ctx.GlobalString(RPCMethodsAllowedFlag.Name); methods != "" {
+		cfg.MethodsAllowed = strings.Split(methods, ",")
+	}
+
+	// check --rpc-methods-denied flag and update node configuration
+	if methods := ctx.GlobalString(RPCMethodsDeniedFlag.Name); methods != "" {
+		cfg.MethodsDenied = strings.Split(methods, ",")
+	}
+
+	// check --rpc-max-requests-per-second flag and update node configuration
+	if maxRequests := ctx.GlobalUint(RPCMaxRequestsPerSecondFlag.Name); maxRequests != 0 {
+		cfg.MaxRequestsPerSecond = uint32(maxRequests)
+	}
+
+	// check --rpc-max-connections flag and update node configuration
+	if maxConnections := ctx.GlobalUint(RPCMaxConnectionsFlag.Name); maxConnections != 0 {
+		cfg.MaxConnections = uint32(maxConnections)
+	}
+
+	// check --ws-max-subscriptions flag and update node configuration
+	if maxSubscriptions := ctx.GlobalUint(WSMaxSubscriptionsFlag.Name); maxSubscriptions != 0 {
+		cfg.MaxSubscriptionsPerConnection = uint32(maxSubscriptions)
+	}
+
+	// check --ipc-path flag and update node configuration
+	if ipcPath := ctx.GlobalString(IPCPathFlag.Name); ipcPath != "" {
+		cfg.IPCPath = ipcPath
+	}
+
+	// check --ipc-permissions flag and update node configuration
+	if ipcPermissions := ctx.GlobalString(IPCPermissionsFlag.Name); ipcPermissions != "" {
+		cfg.IPCPermissions = ipcPermissions
+	}
+
 	if wsport := ctx.GlobalUint(WSPortFlag.Name); wsport != 0 {
 		cfg.WSPort = uint32(wsport)
 	}
@@ -18,6 +18,7 @@
 	"github.com/ChainSafe/gossamer/chain/gssmr"
 	"github.com/ChainSafe/gossamer/dot"
 	ctoml "github.com/ChainSafe/gossamer/dot/config/toml"
+	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
@@ -386,6 +387,8 @@ func TestCoreConfigFromFlags(t *testing.T) {
 				GrandpaAuthority: true,
 				WasmInterpreter:  gssmr.DefaultWasmInterpreter,
 				GrandpaInterval:  testCfg.Core.GrandpaInterval,
+				OffchainWorker:   core.OffchainWorkerMode(gssmr.DefaultOffchainWorker),
+				RuntimePoolSize:  gssmr.DefaultRuntimePoolSize,
 			},
 		},
 		{
@@ -398,6 +401,52 @@ func TestCoreConfigFromFlags(t *testing.T) {
 				GrandpaAuthority: false,
 				WasmInterpreter:  gssmr.DefaultWasmInterpreter,
 				GrandpaInterval:  testCfg.Core.GrandpaInterval,
+				OffchainWorker:   core.OffchainWorkerMode(gssmr.DefaultOffchainWorker),
+				RuntimePoolSize:  gssmr.DefaultRuntimePoolSize,
+			},
+		},
+		{
+			"Test gossamer --offchain-http-disabled",
+			[]string{"config", "roles", "offchain-http-disabled"},
+			[]interface{}{testCfgFile, "4", true},
+			dot.CoreConfig{
+				Roles:                4,
+				BabeAuthority:        true,
+				GrandpaAuthority:     true,
+				WasmInterpreter:      gssmr.DefaultWasmInterpreter,
+				GrandpaInterval:      testCfg.Core.GrandpaInterval,
+				OffchainWorker:       core.OffchainWorkerMode(gssmr.DefaultOffchainWorker),
+				RuntimePoolSize:      gssmr.DefaultRuntimePoolSize,
+				OffchainHTTPDisabled: true,
+			},
+		},
+		{
+			"Test gossamer --runtime-pool-size",
+			[]string{"config", "roles", "runtime-pool-size"},
+			[]interface{}{testCfgFile, "4", "2"},
+			dot.CoreConfig{
+				Roles:            4,
+				BabeAuthority:    true,
+				GrandpaAuthority: true,
+				WasmInterpreter:  gssmr.DefaultWasmInterpreter,
+				GrandpaInterval:  testCfg.Core.GrandpaInterval,
+				OffchainWorker:   core.OffchainWorkerMode(gssmr.DefaultOffchainWorker),
+				RuntimePoolSize:  2,
+			},
+		},
+		{
+			"Test gossamer --offchain-worker-timeout",
+			[]string{"config", "roles", "offchain-worker-timeout"},
+			[]interface{}{testCfgFile, "4", "10"},
+			dot.CoreConfig{
+				Roles:                 4,
+				BabeAuthority:         true,
+				GrandpaAuthority:      true,
+				WasmInterpreter:       gssmr.DefaultWasmInterpreter,
+				GrandpaInterval:       testCfg.Core.GrandpaInterval,
+				OffchainWorker:        core.OffchainWorkerMode(gssmr.DefaultOffchainWorker),
+				RuntimePoolSize:       gssmr.DefaultRuntimePoolSize,
+				OffchainWorkerTimeout: 10 * time.Second,
 			},
 		},
 	}
@@ -101,10 +101,14 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 	}
 
 	cfg.Core = ctoml.CoreConfig{
-		Roles:            dcfg.Core.Roles,
-		BabeAuthority:    dcfg.Core.BabeAuthority,
-		GrandpaAuthority: dcfg.Core.GrandpaAuthority,
-		GrandpaInterval:  uint32(dcfg.Core.GrandpaInterval / time.Second),
+		Roles:                 dcfg.Core.Roles,
+		BabeAuthority:         dcfg.Core.BabeAuthority,
+		GrandpaAuthority:      dcfg.Core.GrandpaAuthority,
+		GrandpaInterval:       uint32(dcfg.Core.GrandpaInterval / time.Second),
+		OffchainWorker:        string(dcfg.Core.OffchainWorker),
+		RuntimePoolSize:       dcfg.Core.RuntimePoolSize,
+		OffchainHTTPDisabled:  dcfg.Core.OffchainHTTPDisabled,
+		OffchainWorkerTimeout: uint32(dcfg.Core.OffchainWorkerTimeout / time.Second),
 	}
 
 	cfg.Network = ctoml.NetworkConfig{
@@ -119,18 +123,26 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 	}
 
 	cfg.RPC = ctoml.RPCConfig{
-		Enabled:          dcfg.RPC.Enabled,
-		External:         dcfg.RPC.External,
-		Unsafe:           dcfg.RPC.Unsafe,
-		UnsafeExternal:   dcfg.RPC.UnsafeExternal,
-		Port:             dcfg.RPC.Port,
-		Host:             dcfg.RPC.Host,
-		Modules:          dcfg.RPC.Modules,
-		WSPort:           dcfg.RPC.WSPort,
-		WS:               dcfg.RPC.WS,
-		WSExternal:       dcfg.RPC.WSExternal,
-		WSUnsafe:         dcfg.RPC.WSUnsafe,
-		WSUnsafeExternal: dcfg.RPC.WSUnsafeExternal,
+		Enabled:                       dcfg.RPC.Enabled,
+		External:                      dcfg.RPC.External,
+		Unsafe:                        dcfg.RPC.Unsafe,
+		UnsafeExternal:                dcfg.RPC.UnsafeExternal,
+		Port:                          dcfg.RPC.Port,
+		Host:                          dcfg.RPC.Host,
+		Modules:                       dcfg.RPC.Modules,
+		WSPort:                        dcfg.RPC.WSPort,
+		WS:                            dcfg.RPC.WS,
+		WSExternal:                    dcfg.RPC.WSExternal,
+		WSUnsafe:                      dcfg.RPC.WSUnsafe,
+		WSUnsafeExternal:              dcfg.RPC.WSUnsafeExternal,
+		MaxBatchSize:                  dcfg.RPC.MaxBatchSize,
+		MethodsAllowed:                dcfg.RPC.MethodsAllowed,
+		MethodsDenied:                 dcfg.RPC.MethodsDenied,
+		MaxRequestsPerSecond:          dcfg.RPC.MaxRequestsPerSecond,
+		MaxSubscriptionsPerConnection: dcfg.RPC.MaxSubscriptionsPerConnection,
+		MaxConnections:                dcfg.RPC.MaxConnections,
+		IPCPath:                       dcfg.RPC.IPCPath,
+		IPCPermissions:                dcfg.RPC.IPCPermissions,
 	}
 
 	return cfg
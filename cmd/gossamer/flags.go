@@ -271,6 +271,46 @@
 		Name:  "rpcmods",
 		Usage: "API modules to enable via HTTP-RPC, comma separated list",
 	}
+	// RPCMaxBatchSizeFlag maximum number of requests in a batch
+	RPCMaxBatchSizeFlag = cli.UintFlag{
+		Name:  "rpc-max-batch-size",
+		Usage: "Maximum number of requests in a HTTP-RPC or websocket batch request",
+	}
+	// RPCMethodsAllowedFlag RPC methods which can be called
+	RPCMethodsAllowedFlag = cli.StringFlag{
+		Name:  "rpc-methods-allowed",
+		Usage: "Only RPC methods which can be called via HTTP-RPC and websockets, comma separated list",
+	}
+	// RPCMethodsDeniedFlag RPC methods which cannot be called
+	RPCMethodsDeniedFlag = cli.StringFlag{
+		Name:  "rpc-methods-denied",
+		Usage: "RPC methods which cannot be called via HTTP-RPC and websockets, comma separated list",
+	}
+	// RPCMaxRequestsPerSecondFlag maximum number of requests per second of each client
+	RPCMaxRequestsPerSecondFlag = cli.UintFlag{
+		Name:  "rpc-max-requests-per-second",
+		Usage: "Maximum number of requests per second of each HTTP-RPC client IP and websocket connection, 0 for no limit",
+	}
+	// RPCMaxConnectionsFlag maximum number of concurrent connections
+	RPCMaxConnectionsFlag = cli.UintFlag{
+		Name:  "rpc-max-connections",
+		Usage: "Maximum number of concurrent connections to each of the HTTP-RPC and websockets servers, 0 for no limit",
+	}
+	// IPCPathFlag IPC server socket path
+	IPCPathFlag = cli.StringFlag{
+		Name:  "ipc-path",
+		Usage: "Path of the IPC server Unix socket, relative to the base path if not absolute, IPC is disabled if empty",
+	}
+	// IPCPermissionsFlag IPC server socket file permissions
+	IPCPermissionsFlag = cli.StringFlag{
+		Name:  "ipc-permissions",
+		Usage: "Octal file permissions of the IPC server Unix socket (default: 0600)",
+	}
+	// WSMaxSubscriptionsFlag maximum number of active subscriptions of each websocket connection
+	WSMaxSubscriptionsFlag = cli.UintFlag{
+		Name:  "ws-max-subscriptions",
+		Usage: "Maximum number of active subscriptions of each websocket connection, 0 for no limit",
+	}
 	// WSPortFlag WebSocket server listening port
 	WSPortFlag = cli.IntFlag{
 		Name:  "wsport",
@@ -383,6 +423,34 @@
 	}
 )
 
+// Runtime flags
+var (
+	// RuntimePoolSizeFlag sets the number of instances of each runtime instance pool
+	RuntimePoolSizeFlag = cli.IntFlag{
+		Name:  "runtime-pool-size",
+		Usage: "Number of runtime instances used to execute the runtime calls which are not part of the block import",
+	}
+)
+
+// Offchain worker flags
+var (
+	// OffchainWorkerFlag sets for which nodes the offchain workers are executed
+	OffchainWorkerFlag = cli.StringFlag{
+		Name:  "offchain-worker",
+		Usage: `Execution of the offchain workers after each new best block ("always", "never", "when-validating")`,
+	}
+	// OffchainWorkerTimeoutFlag sets the timeout of the execution of the offchain workers
+	OffchainWorkerTimeoutFlag = cli.UintFlag{
+		Name:  "offchain-worker-timeout",
+		Usage: "Timeout in seconds after which the execution of the offchain workers is cancelled (default: 30)",
+	}
+	// OffchainHTTPDisabledFlag disables the outbound HTTP requests of the offchain workers of authority nodes
+	OffchainHTTPDisabledFlag = cli.BoolFlag{
+		Name:  "offchain-http-disabled",
+		Usage: "Disable the outbound HTTP requests of the offchain workers when running as an authority",
+	}
+)
+
 // flag sets that are shared by multiple commands
 var (
 	// GlobalFlags are flags that are valid for use with the root command and all subcommands
@@ -434,11 +502,19 @@
 		RPCHostFlag,
 		RPCPortFlag,
 		RPCModulesFlag,
+		RPCMaxBatchSizeFlag,
+		RPCMethodsAllowedFlag,
+		RPCMethodsDeniedFlag,
+		RPCMaxRequestsPerSecondFlag,
+		RPCMaxConnectionsFlag,
 		WSFlag,
 		WSExternalFlag,
 		WSUnsafeEnabledFlag,
 		WSUnsafeExternalFlag,
 		WSPortFlag,
+		WSMaxSubscriptionsFlag,
+		IPCPathFlag,
+		IPCPermissionsFlag,
 
 		// metrics flag
 		PublishMetricsFlag,
@@ -450,6 +526,14 @@
 
 		// BABE flags
 		BABELeadFlag,
+
+		// runtime flags
+		RuntimePoolSizeFlag,
+
+		// offchain worker flags
+		OffchainWorkerFlag,
+		OffchainWorkerTimeoutFlag,
+		OffchainHTTPDisabledFlag,
 	}
 )
 
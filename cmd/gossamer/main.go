@@ -7,6 +7,8 @@
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/ChainSafe/gossamer/dot"
 	"github.com/ChainSafe/gossamer/dot/state"
@@ -290,6 +292,9 @@ func gossamerAction(ctx *cli.Context) error {
 
 	logger.Info("starting node " + node.Name + "...")
 
+	// reload the log levels of the node on SIGHUP
+	go reloadLogConfigOnSignal(ctx, node)
+
 	// start node
 	err = node.Start()
 	if err != nil {
@@ -299,6 +304,21 @@ func gossamerAction(ctx *cli.Context) error {
 	return nil
 }
 
+// reloadLogConfigOnSignal reloads the log levels of the node from the log
+// flags and the TOML configuration each time a SIGHUP signal is received.
+func reloadLogConfigOnSignal(ctx *cli.Context, node *dot.Node) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	for range sighup {
+		logger.Info("signal hangup, reloading log configuration...")
+		err := reloadLogConfig(ctx, node)
+		if err != nil {
+			logger.Errorf("failed to reload log configuration: %s", err)
+		}
+	}
+}
+
 // initAction is the action for the "init" subcommand, initialises the trie and
 // state databases and loads initial state from the configured genesis file
 func initAction(ctx *cli.Context) error {
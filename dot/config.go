@@ -12,6 +12,7 @@
 	"github.com/ChainSafe/gossamer/chain/gssmr"
 	"github.com/ChainSafe/gossamer/chain/kusama"
 	"github.com/ChainSafe/gossamer/chain/polkadot"
+	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
@@ -78,6 +79,22 @@ func (l LogConfig) String() string {
 	return strings.Join(entries, ", ")
 }
 
+// targetLevels returns the log levels of the packages, keyed by
+// their logger target as used in log filters, such as `sync`.
+func (l LogConfig) targetLevels() map[string]log.Level {
+	return map[string]log.Level{
+		"core":    l.CoreLvl,
+		"digest":  l.DigestLvl,
+		"sync":    l.SyncLvl,
+		"network": l.NetworkLvl,
+		"rpc":     l.RPCLvl,
+		"state":   l.StateLvl,
+		"runtime": l.RuntimeLvl,
+		"babe":    l.BlockProducerLvl,
+		"grandpa": l.FinalityGadgetLvl,
+	}
+}
+
 // InitConfig is the configuration for the node initialization
 type InitConfig struct {
 	Genesis string
@@ -112,6 +129,13 @@ type CoreConfig struct {
 	GrandpaAuthority bool
 	WasmInterpreter  string
 	GrandpaInterval  time.Duration
+	OffchainWorker   core.OffchainWorkerMode
+	RuntimePoolSize  int
+	// OffchainWorkerTimeout is the duration after which the execution of the offchain workers
+	// is cancelled, the default timeout of the core service being used if it is zero
+	OffchainWorkerTimeout time.Duration
+	// OffchainHTTPDisabled disables the outbound HTTP requests of the offchain workers of authority nodes
+	OffchainHTTPDisabled bool
 }
 
 // RPCConfig is to marshal/unmarshal toml RPC config vars
@@ -128,6 +152,26 @@ type RPCConfig struct {
 	WSExternal       bool
 	WSUnsafe         bool
 	WSUnsafeExternal bool
+	MaxBatchSize     uint32
+	// MethodsAllowed are the only RPC methods which can be called if it is not empty.
+	MethodsAllowed []string
+	// MethodsDenied are the RPC methods which cannot be called.
+	MethodsDenied []string
+	// MaxRequestsPerSecond is the maximum number of requests per second of each
+	// HTTP-RPC client IP address and of each websocket connection, no limit if zero.
+	MaxRequestsPerSecond uint32
+	// MaxSubscriptionsPerConnection is the maximum number of active
+	// subscriptions of each websocket connection, no limit if zero.
+	MaxSubscriptionsPerConnection uint32
+	// MaxConnections is the maximum number of concurrent connections to each
+	// of the HTTP-RPC and websocket servers, no limit if zero.
+	MaxConnections uint32
+	// IPCPath is the path of the IPC Unix domain socket, relative to the
+	// base path if it is not absolute. IPC is disabled if it is empty.
+	IPCPath string
+	// IPCPermissions are the octal file permissions of the IPC socket,
+	// such as 0600, rpc.DefaultIPCPermissions is used if it is empty.
+	IPCPermissions string
 }
 
 func (r *RPCConfig) isRPCEnabled() bool {
@@ -138,6 +182,10 @@ func (r *RPCConfig) isWSEnabled() bool {
 	return r.WS || r.WSExternal || r.WSUnsafe || r.WSUnsafeExternal
 }
 
+func (r *RPCConfig) isIPCEnabled() bool {
+	return r.IPCPath != ""
+}
+
 // Strings returns the configuration in the format
 // field1=value1 field2=value2.
 func (r *RPCConfig) String() string {
@@ -153,7 +201,15 @@ func (r *RPCConfig) String() string {
 		"ws=" + fmt.Sprint(r.WS) + " " +
 		"wsexternal=" + fmt.Sprint(r.WSExternal) + " " +
 		"wsunsafe=" + fmt.Sprint(r.WSUnsafe) + " " +
-		"wsunsafeexternal=" + fmt.Sprint(r.WSUnsafeExternal)
+		"wsunsafeexternal=" + fmt.Sprint(r.WSUnsafeExternal) + " " +
+		"maxbatchsize=" + fmt.Sprint(r.MaxBatchSize) + " " +
+		"methodsallowed=" + strings.Join(r.MethodsAllowed, ",") + " " +
+		"methodsdenied=" + strings.Join(r.MethodsDenied, ",") + " " +
+		"maxrequestspersecond=" + fmt.Sprint(r.MaxRequestsPerSecond) + " " +
+		"maxsubscriptionsperconnection=" + fmt.Sprint(r.MaxSubscriptionsPerConnection) + " " +
+		"maxconnections=" + fmt.Sprint(r.MaxConnections) + " " +
+		"ipcpath=" + r.IPCPath + " " +
+		"ipcpermissions=" + r.IPCPermissions
 }
 
 // StateConfig is the config for the State service
@@ -216,6 +272,8 @@ func GssmrConfig() *Config {
 			BabeAuthority:    gssmr.DefaultBabeAuthority,
 			GrandpaAuthority: gssmr.DefaultGrandpaAuthority,
 			WasmInterpreter:  gssmr.DefaultWasmInterpreter,
+			OffchainWorker:   core.OffchainWorkerMode(gssmr.DefaultOffchainWorker),
+			RuntimePoolSize:  gssmr.DefaultRuntimePoolSize,
 			GrandpaInterval:  gssmr.DefaultGrandpaInterval,
 		},
 		Network: NetworkConfig{
@@ -278,6 +336,8 @@ func KusamaConfig() *Config {
 		Core: CoreConfig{
 			Roles:           kusama.DefaultRoles,
 			WasmInterpreter: kusama.DefaultWasmInterpreter,
+			OffchainWorker:  core.OffchainWorkerMode(kusama.DefaultOffchainWorker),
+			RuntimePoolSize: kusama.DefaultRuntimePoolSize,
 		},
 		Network: NetworkConfig{
 			Port:        kusama.DefaultNetworkPort,
@@ -336,6 +396,8 @@ func PolkadotConfig() *Config {
 		Core: CoreConfig{
 			Roles:           polkadot.DefaultRoles,
 			WasmInterpreter: polkadot.DefaultWasmInterpreter,
+			OffchainWorker:  core.OffchainWorkerMode(polkadot.DefaultOffchainWorker),
+			RuntimePoolSize: polkadot.DefaultRuntimePoolSize,
 		},
 		Network: NetworkConfig{
 			Port:        polkadot.DefaultNetworkPort,
@@ -396,6 +458,8 @@ func DevConfig() *Config {
 			BabeAuthority:    dev.DefaultBabeAuthority,
 			GrandpaAuthority: dev.DefaultGrandpaAuthority,
 			WasmInterpreter:  dev.DefaultWasmInterpreter,
+			OffchainWorker:   core.OffchainWorkerMode(dev.DefaultOffchainWorker),
+			RuntimePoolSize:  dev.DefaultRuntimePoolSize,
 			BABELead:         dev.DefaultBabeAuthority,
 		},
 		Network: NetworkConfig{
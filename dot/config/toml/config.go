@@ -75,22 +75,35 @@ type CoreConfig struct {
 	WasmInterpreter  string `toml:"wasm-interpreter,omitempty"`
 	GrandpaInterval  uint32 `toml:"grandpa-interval,omitempty"`
 	BABELead         bool   `toml:"babe-lead,omitempty"`
+	OffchainWorker   string `toml:"offchain-worker,omitempty"`
+	RuntimePoolSize  int    `toml:"runtime-pool-size,omitempty"`
+
+	OffchainHTTPDisabled  bool   `toml:"offchain-http-disabled,omitempty"`
+	OffchainWorkerTimeout uint32 `toml:"offchain-worker-timeout,omitempty"`
 }
 
 // RPCConfig is to marshal/unmarshal toml RPC config vars
 type RPCConfig struct {
-	Enabled          bool     `toml:"enabled,omitempty"`
-	Unsafe           bool     `toml:"unsafe,omitempty"`
-	UnsafeExternal   bool     `toml:"unsafe-external,omitempty"`
-	External         bool     `toml:"external,omitempty"`
-	Port             uint32   `toml:"port,omitempty"`
-	Host             string   `toml:"host,omitempty"`
-	Modules          []string `toml:"modules,omitempty"`
-	WSPort           uint32   `toml:"ws-port,omitempty"`
-	WS               bool     `toml:"ws,omitempty"`
-	WSExternal       bool     `toml:"ws-external,omitempty"`
-	WSUnsafe         bool     `toml:"ws-unsafe,omitempty"`
-	WSUnsafeExternal bool     `toml:"ws-unsafe-external,omitempty"`
+	Enabled                       bool     `toml:"enabled,omitempty"`
+	Unsafe                        bool     `toml:"unsafe,omitempty"`
+	UnsafeExternal                bool     `toml:"unsafe-external,omitempty"`
+	External                      bool     `toml:"external,omitempty"`
+	Port                          uint32   `toml:"port,omitempty"`
+	Host                          string   `toml:"host,omitempty"`
+	Modules                       []string `toml:"modules,omitempty"`
+	WSPort                        uint32   `toml:"ws-port,omitempty"`
+	WS                            bool     `toml:"ws,omitempty"`
+	WSExternal                    bool     `toml:"ws-external,omitempty"`
+	WSUnsafe                      bool     `toml:"ws-unsafe,omitempty"`
+	WSUnsafeExternal              bool     `toml:"ws-unsafe-external,omitempty"`
+	MaxBatchSize                  uint32   `toml:"max-batch-size,omitempty"`
+	MethodsAllowed                []string `toml:"methods-allowed,omitempty"`
+	MethodsDenied                 []string `toml:"methods-denied,omitempty"`
+	MaxRequestsPerSecond          uint32   `toml:"max-requests-per-second,omitempty"`
+	MaxSubscriptionsPerConnection uint32   `toml:"max-subscriptions-per-connection,omitempty"`
+	MaxConnections                uint32   `toml:"max-connections,omitempty"`
+	IPCPath                       string   `toml:"ipc-path,omitempty"`
+	IPCPermissions                string   `toml:"ipc-permissions,omitempty"`
 }
 
 // PprofConfig contains the configuration for Pprof.
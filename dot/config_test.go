@@ -56,6 +56,8 @@ func TestConfig(t *testing.T) {
 					GrandpaAuthority: true,
 					WasmInterpreter:  "wasmer",
 					GrandpaInterval:  0,
+					OffchainWorker:   "when-validating",
+					RuntimePoolSize:  8,
 				},
 				Network: NetworkConfig{
 					Port: 7001,
@@ -114,6 +116,8 @@ func TestConfig(t *testing.T) {
 					GrandpaAuthority: true,
 					WasmInterpreter:  "wasmer",
 					GrandpaInterval:  time.Second,
+					OffchainWorker:   "when-validating",
+					RuntimePoolSize:  8,
 				},
 				Network: NetworkConfig{
 					Port:              7001,
@@ -173,6 +177,8 @@ func TestConfig(t *testing.T) {
 					Roles:           byte(1),
 					WasmInterpreter: "wasmer",
 					GrandpaInterval: 0,
+					OffchainWorker:  "when-validating",
+					RuntimePoolSize: 8,
 				},
 				Network: NetworkConfig{
 					Port:              7001,
@@ -229,6 +235,8 @@ func TestConfig(t *testing.T) {
 				Core: CoreConfig{
 					Roles:           byte(1),
 					WasmInterpreter: "wasmer",
+					OffchainWorker:  "when-validating",
+					RuntimePoolSize: 8,
 				},
 				Network: NetworkConfig{
 					Port: 7001,
@@ -407,26 +415,39 @@ func TestRPCConfig_String(t *testing.T) {
 			name:      "default base case",
 			rpcConfig: RPCConfig{},
 			want: "enabled=false external=false unsafe=false unsafeexternal=false port=0 host= modules= wsport=0 ws" +
-				"=false wsexternal=false wsunsafe=false wsunsafeexternal=false",
+				"=false wsexternal=false wsunsafe=false wsunsafeexternal=false maxbatchsize=0 methodsallowed= " +
+				"methodsdenied= maxrequestspersecond=0 maxsubscriptionsperconnection=0 maxconnections=0 " +
+				"ipcpath= ipcpermissions=",
 		},
 		{
 			name: "fields changed",
 			rpcConfig: RPCConfig{
-				Enabled:          true,
-				External:         true,
-				Unsafe:           true,
-				UnsafeExternal:   true,
-				Port:             1234,
-				Host:             "5678",
-				Modules:          nil,
-				WSPort:           2345,
-				WS:               true,
-				WSExternal:       true,
-				WSUnsafe:         true,
-				WSUnsafeExternal: true,
+				Enabled:                       true,
+				External:                      true,
+				Unsafe:                        true,
+				UnsafeExternal:                true,
+				Port:                          1234,
+				Host:                          "5678",
+				Modules:                       nil,
+				WSPort:                        2345,
+				WS:                            true,
+				WSExternal:                    true,
+				WSUnsafe:                      true,
+				WSUnsafeExternal:              true,
+				MaxBatchSize:                  50,
+				MethodsAllowed:                []string{"chain_getBlock", "author_insertKey"},
+				MethodsDenied:                 []string{"author_insertKey"},
+				MaxRequestsPerSecond:          10,
+				MaxSubscriptionsPerConnection: 20,
+				MaxConnections:                30,
+				IPCPath:                       "gossamer.ipc",
+				IPCPermissions:                "0660",
 			},
 			want: "enabled=true external=true unsafe=true unsafeexternal=true port=1234 host=5678 modules= wsport" +
-				"=2345 ws=true wsexternal=true wsunsafe=true wsunsafeexternal=true",
+				"=2345 ws=true wsexternal=true wsunsafe=true wsunsafeexternal=true maxbatchsize=50 " +
+				"methodsallowed=chain_getBlock,author_insertKey methodsdenied=author_insertKey " +
+				"maxrequestspersecond=10 maxsubscriptionsperconnection=20 maxconnections=30 " +
+				"ipcpath=gossamer.ipc ipcpermissions=0660",
 		},
 	}
 	for _, tt := range tests {
@@ -44,6 +44,16 @@
 	// ErrEmptyRuntimeCode is returned when the storage :code is empty
 	ErrEmptyRuntimeCode = errors.New("new :code is empty")
 
+	// ErrStateUnavailable is returned when the state of a block cannot be loaded,
+	// for example because it has been pruned
+	ErrStateUnavailable = errors.New("state is not available")
+
+	// ErrBlockExecution is returned when the runtime fails to re-execute a block
+	ErrBlockExecution = errors.New("cannot execute block")
+
+	// ErrQueryStorageRangeTooLarge is returned when the block range of a storage query is too long
+	ErrQueryStorageRangeTooLarge = errors.New("query storage block range is too large")
+
 	errNilCodeSubstitutedState = errors.New("cannot have nil CodeSubstitutedStat")
 )
 
@@ -140,3 +140,7 @@ func NewTestService(t *testing.T, cfg *Config) *Service {
 
 	return s
 }
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -15,6 +15,7 @@
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/ChainSafe/gossamer/lib/trie"
 )
 
 //go:generate mockgen -destination=mock_core_test.go -package $GOPACKAGE . BlockState,StorageState,TransactionState,Network,EpochState,CodeSubstitutedState
@@ -29,11 +30,14 @@ type BlockState interface {
 	AddBlock(*types.Block) error
 	GetAllBlocksAtDepth(hash common.Hash) []common.Hash
 	GetBlockByHash(common.Hash) (*types.Block, error)
+	GetHeader(common.Hash) (*types.Header, error)
 	GetBlockStateRoot(bhash common.Hash) (common.Hash, error)
 	GenesisHash() common.Hash
 	GetSlotForBlock(common.Hash) (uint64, error)
 	GetFinalisedHeader(uint64, uint64) (*types.Header, error)
 	GetFinalisedHash(uint64, uint64) (common.Hash, error)
+	GetHighestFinalisedHeader() (*types.Header, error)
+	GetHashByNumber(num uint) (common.Hash, error)
 	GetImportedBlockNotifierChannel() chan *types.Block
 	FreeImportedBlockNotifierChannel(ch chan *types.Block)
 	GetFinalisedNotifierChannel() chan *types.FinalisationInfo
@@ -44,6 +48,13 @@ type BlockState interface {
 	HandleRuntimeChanges(newState *rtstorage.TrieState, in runtime.Instance, bHash common.Hash) error
 	GetRuntime(*common.Hash) (runtime.Instance, error)
 	StoreRuntime(common.Hash, runtime.Instance)
+	SetOffchainIndexChanges(hash common.Hash, changes []rtstorage.OffchainIndexChange) error
+	GetOffchainIndexChanges(hash common.Hash) ([]rtstorage.OffchainIndexChange, error)
+	SetOffchainIndexRevert(hash common.Hash, changes []rtstorage.OffchainIndexChange) error
+	GetOffchainIndexRevert(hash common.Hash) ([]rtstorage.OffchainIndexChange, error)
+	DeleteOffchainIndexRevert(hash common.Hash) error
+	DeleteOffchainIndex(hash common.Hash) error
+	SetTransactionIndex(block *types.Block, operations []rtstorage.TransactionIndexOperation, storagePeriod uint) error
 }
 
 // StorageState interface for storage state methods
@@ -51,10 +62,11 @@ type StorageState interface {
 	LoadCode(root *common.Hash) ([]byte, error)
 	LoadCodeHash(root *common.Hash) (common.Hash, error)
 	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
-	StoreTrie(*rtstorage.TrieState, *types.Header) error
+	StoreTrie(*rtstorage.TrieState, *types.Header, trie.Version) error
 	GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error)
 	GetStorage(root *common.Hash, key []byte) ([]byte, error)
 	GenerateTrieProof(stateRoot common.Hash, keys [][]byte) ([][]byte, error)
+	GenerateChildTrieProof(stateRoot common.Hash, keyToChild []byte, keys [][]byte) ([][]byte, error)
 	sync.Locker
 }
 
@@ -66,6 +78,7 @@ type TransactionState interface {
 	RemoveExtrinsicFromPool(ext types.Extrinsic)
 	PendingInPool() []*transaction.ValidTransaction
 	Exists(ext types.Extrinsic) bool
+	NotifyStatus(ext types.Extrinsic, notification transaction.StatusNotification)
 }
 
 // Network is the interface for the network service
@@ -73,6 +86,7 @@ type Network interface {
 	GossipMessage(network.NotificationsMessage)
 	IsSynced() bool
 	ReportPeer(change peerset.ReputationChange, p peer.ID)
+	Peers() []common.PeerInfo
 }
 
 // EpochState is the interface for state.EpochState
@@ -73,10 +73,11 @@ func (s *Service) HandleTransactionMessage(peerID peer.ID, msg *network.Transact
 	}
 
 	hash := head.Hash()
-	rt, err := s.blockState.GetRuntime(&hash)
+	rt, put, err := s.getRuntimeInstance(&hash)
 	if err != nil {
 		return false, err
 	}
+	defer put()
 
 	allTxsAreValid := true
 	for _, tx := range txs {
@@ -14,6 +14,7 @@
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
 	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	transaction "github.com/ChainSafe/gossamer/lib/transaction"
+	trie "github.com/ChainSafe/gossamer/lib/trie"
 	gomock "github.com/golang/mock/gomock"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 )
@@ -129,6 +130,34 @@ func (mr *MockBlockStateMockRecorder) BestBlockStateRoot() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BestBlockStateRoot", reflect.TypeOf((*MockBlockState)(nil).BestBlockStateRoot))
 }
 
+// DeleteOffchainIndex mocks base method.
+func (m *MockBlockState) DeleteOffchainIndex(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOffchainIndex", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOffchainIndex indicates an expected call of DeleteOffchainIndex.
+func (mr *MockBlockStateMockRecorder) DeleteOffchainIndex(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOffchainIndex", reflect.TypeOf((*MockBlockState)(nil).DeleteOffchainIndex), arg0)
+}
+
+// DeleteOffchainIndexRevert mocks base method.
+func (m *MockBlockState) DeleteOffchainIndexRevert(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOffchainIndexRevert", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOffchainIndexRevert indicates an expected call of DeleteOffchainIndexRevert.
+func (mr *MockBlockStateMockRecorder) DeleteOffchainIndexRevert(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOffchainIndexRevert", reflect.TypeOf((*MockBlockState)(nil).DeleteOffchainIndexRevert), arg0)
+}
+
 // FreeFinalisedNotifierChannel mocks base method.
 func (m *MockBlockState) FreeFinalisedNotifierChannel(arg0 chan *types.FinalisationInfo) {
 	m.ctrl.T.Helper()
@@ -270,6 +299,51 @@ func (mr *MockBlockStateMockRecorder) GetFinalisedNotifierChannel() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinalisedNotifierChannel", reflect.TypeOf((*MockBlockState)(nil).GetFinalisedNotifierChannel))
 }
 
+// GetHashByNumber mocks base method.
+func (m *MockBlockState) GetHashByNumber(arg0 uint) (common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHashByNumber", arg0)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHashByNumber indicates an expected call of GetHashByNumber.
+func (mr *MockBlockStateMockRecorder) GetHashByNumber(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHashByNumber", reflect.TypeOf((*MockBlockState)(nil).GetHashByNumber), arg0)
+}
+
+// GetHeader mocks base method.
+func (m *MockBlockState) GetHeader(arg0 common.Hash) (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeader", arg0)
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeader indicates an expected call of GetHeader.
+func (mr *MockBlockStateMockRecorder) GetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeader", reflect.TypeOf((*MockBlockState)(nil).GetHeader), arg0)
+}
+
+// GetHighestFinalisedHeader mocks base method.
+func (m *MockBlockState) GetHighestFinalisedHeader() (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHighestFinalisedHeader")
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHighestFinalisedHeader indicates an expected call of GetHighestFinalisedHeader.
+func (mr *MockBlockStateMockRecorder) GetHighestFinalisedHeader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestFinalisedHeader", reflect.TypeOf((*MockBlockState)(nil).GetHighestFinalisedHeader))
+}
+
 // GetImportedBlockNotifierChannel mocks base method.
 func (m *MockBlockState) GetImportedBlockNotifierChannel() chan *types.Block {
 	m.ctrl.T.Helper()
@@ -284,6 +358,36 @@ func (mr *MockBlockStateMockRecorder) GetImportedBlockNotifierChannel() *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportedBlockNotifierChannel", reflect.TypeOf((*MockBlockState)(nil).GetImportedBlockNotifierChannel))
 }
 
+// GetOffchainIndexChanges mocks base method.
+func (m *MockBlockState) GetOffchainIndexChanges(arg0 common.Hash) ([]storage.OffchainIndexChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOffchainIndexChanges", arg0)
+	ret0, _ := ret[0].([]storage.OffchainIndexChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOffchainIndexChanges indicates an expected call of GetOffchainIndexChanges.
+func (mr *MockBlockStateMockRecorder) GetOffchainIndexChanges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOffchainIndexChanges", reflect.TypeOf((*MockBlockState)(nil).GetOffchainIndexChanges), arg0)
+}
+
+// GetOffchainIndexRevert mocks base method.
+func (m *MockBlockState) GetOffchainIndexRevert(arg0 common.Hash) ([]storage.OffchainIndexChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOffchainIndexRevert", arg0)
+	ret0, _ := ret[0].([]storage.OffchainIndexChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOffchainIndexRevert indicates an expected call of GetOffchainIndexRevert.
+func (mr *MockBlockStateMockRecorder) GetOffchainIndexRevert(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOffchainIndexRevert", reflect.TypeOf((*MockBlockState)(nil).GetOffchainIndexRevert), arg0)
+}
+
 // GetRuntime mocks base method.
 func (m *MockBlockState) GetRuntime(arg0 *common.Hash) (runtime.Instance, error) {
 	m.ctrl.T.Helper()
@@ -343,6 +447,48 @@ func (mr *MockBlockStateMockRecorder) HighestCommonAncestor(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HighestCommonAncestor", reflect.TypeOf((*MockBlockState)(nil).HighestCommonAncestor), arg0, arg1)
 }
 
+// SetOffchainIndexChanges mocks base method.
+func (m *MockBlockState) SetOffchainIndexChanges(arg0 common.Hash, arg1 []storage.OffchainIndexChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOffchainIndexChanges", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOffchainIndexChanges indicates an expected call of SetOffchainIndexChanges.
+func (mr *MockBlockStateMockRecorder) SetOffchainIndexChanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOffchainIndexChanges", reflect.TypeOf((*MockBlockState)(nil).SetOffchainIndexChanges), arg0, arg1)
+}
+
+// SetOffchainIndexRevert mocks base method.
+func (m *MockBlockState) SetOffchainIndexRevert(arg0 common.Hash, arg1 []storage.OffchainIndexChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOffchainIndexRevert", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOffchainIndexRevert indicates an expected call of SetOffchainIndexRevert.
+func (mr *MockBlockStateMockRecorder) SetOffchainIndexRevert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOffchainIndexRevert", reflect.TypeOf((*MockBlockState)(nil).SetOffchainIndexRevert), arg0, arg1)
+}
+
+// SetTransactionIndex mocks base method.
+func (m *MockBlockState) SetTransactionIndex(arg0 *types.Block, arg1 []storage.TransactionIndexOperation, arg2 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTransactionIndex", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTransactionIndex indicates an expected call of SetTransactionIndex.
+func (mr *MockBlockStateMockRecorder) SetTransactionIndex(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransactionIndex", reflect.TypeOf((*MockBlockState)(nil).SetTransactionIndex), arg0, arg1, arg2)
+}
+
 // StoreRuntime mocks base method.
 func (m *MockBlockState) StoreRuntime(arg0 common.Hash, arg1 runtime.Instance) {
 	m.ctrl.T.Helper()
@@ -393,6 +539,21 @@ func (m *MockStorageState) EXPECT() *MockStorageStateMockRecorder {
 	return m.recorder
 }
 
+// GenerateChildTrieProof mocks base method.
+func (m *MockStorageState) GenerateChildTrieProof(arg0 common.Hash, arg1 []byte, arg2 [][]byte) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateChildTrieProof", arg0, arg1, arg2)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateChildTrieProof indicates an expected call of GenerateChildTrieProof.
+func (mr *MockStorageStateMockRecorder) GenerateChildTrieProof(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateChildTrieProof", reflect.TypeOf((*MockStorageState)(nil).GenerateChildTrieProof), arg0, arg1, arg2)
+}
+
 // GenerateTrieProof mocks base method.
 func (m *MockStorageState) GenerateTrieProof(arg0 common.Hash, arg1 [][]byte) ([][]byte, error) {
 	m.ctrl.T.Helper()
@@ -481,17 +642,17 @@ func (mr *MockStorageStateMockRecorder) Lock() *gomock.Call {
 }
 
 // StoreTrie mocks base method.
-func (m *MockStorageState) StoreTrie(arg0 *storage.TrieState, arg1 *types.Header) error {
+func (m *MockStorageState) StoreTrie(arg0 *storage.TrieState, arg1 *types.Header, arg2 trie.Version) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreTrie", arg0, arg1)
+	ret := m.ctrl.Call(m, "StoreTrie", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreTrie indicates an expected call of StoreTrie.
-func (mr *MockStorageStateMockRecorder) StoreTrie(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockStorageStateMockRecorder) StoreTrie(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTrie", reflect.TypeOf((*MockStorageState)(nil).StoreTrie), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTrie", reflect.TypeOf((*MockStorageState)(nil).StoreTrie), arg0, arg1, arg2)
 }
 
 // TrieState mocks base method.
@@ -572,6 +733,18 @@ func (mr *MockTransactionStateMockRecorder) Exists(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockTransactionState)(nil).Exists), arg0)
 }
 
+// NotifyStatus mocks base method.
+func (m *MockTransactionState) NotifyStatus(arg0 types.Extrinsic, arg1 transaction.StatusNotification) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyStatus", arg0, arg1)
+}
+
+// NotifyStatus indicates an expected call of NotifyStatus.
+func (mr *MockTransactionStateMockRecorder) NotifyStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyStatus", reflect.TypeOf((*MockTransactionState)(nil).NotifyStatus), arg0, arg1)
+}
+
 // PendingInPool mocks base method.
 func (m *MockTransactionState) PendingInPool() []*transaction.ValidTransaction {
 	m.ctrl.T.Helper()
@@ -674,6 +847,20 @@ func (mr *MockNetworkMockRecorder) IsSynced() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSynced", reflect.TypeOf((*MockNetwork)(nil).IsSynced))
 }
 
+// Peers mocks base method.
+func (m *MockNetwork) Peers() []common.PeerInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Peers")
+	ret0, _ := ret[0].([]common.PeerInfo)
+	return ret0
+}
+
+// Peers indicates an expected call of Peers.
+func (mr *MockNetworkMockRecorder) Peers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Peers", reflect.TypeOf((*MockNetwork)(nil).Peers))
+}
+
 // ReportPeer mocks base method.
 func (m *MockNetwork) ReportPeer(arg0 peerset.ReputationChange, arg1 peer.ID) {
 	m.ctrl.T.Helper()
@@ -10,6 +10,7 @@
 	types "github.com/ChainSafe/gossamer/dot/types"
 	common "github.com/ChainSafe/gossamer/lib/common"
 	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	trie "github.com/ChainSafe/gossamer/lib/trie"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -36,6 +37,21 @@ func (m *MockStorageState) EXPECT() *MockStorageStateMockRecorder {
 	return m.recorder
 }
 
+// GenerateChildTrieProof mocks base method.
+func (m *MockStorageState) GenerateChildTrieProof(arg0 common.Hash, arg1 []byte, arg2 [][]byte) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateChildTrieProof", arg0, arg1, arg2)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateChildTrieProof indicates an expected call of GenerateChildTrieProof.
+func (mr *MockStorageStateMockRecorder) GenerateChildTrieProof(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateChildTrieProof", reflect.TypeOf((*MockStorageState)(nil).GenerateChildTrieProof), arg0, arg1, arg2)
+}
+
 // GenerateTrieProof mocks base method.
 func (m *MockStorageState) GenerateTrieProof(arg0 common.Hash, arg1 [][]byte) ([][]byte, error) {
 	m.ctrl.T.Helper()
@@ -124,17 +140,17 @@ func (mr *MockStorageStateMockRecorder) Lock() *gomock.Call {
 }
 
 // StoreTrie mocks base method.
-func (m *MockStorageState) StoreTrie(arg0 *storage.TrieState, arg1 *types.Header) error {
+func (m *MockStorageState) StoreTrie(arg0 *storage.TrieState, arg1 *types.Header, arg2 trie.Version) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreTrie", arg0, arg1)
+	ret := m.ctrl.Call(m, "StoreTrie", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreTrie indicates an expected call of StoreTrie.
-func (mr *MockStorageStateMockRecorder) StoreTrie(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockStorageStateMockRecorder) StoreTrie(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTrie", reflect.TypeOf((*MockStorageState)(nil).StoreTrie), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTrie", reflect.TypeOf((*MockStorageState)(nil).StoreTrie), arg0, arg1, arg2)
 }
 
 // TrieState mocks base method.
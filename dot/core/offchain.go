@@ -0,0 +1,371 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// OffchainWorkerMode specifies for which nodes the offchain workers are executed
+type OffchainWorkerMode string
+
+const (
+	// OffchainWorkerAlways executes the offchain workers on every node
+	OffchainWorkerAlways = OffchainWorkerMode("always")
+	// OffchainWorkerNever never executes the offchain workers
+	OffchainWorkerNever = OffchainWorkerMode("never")
+	// OffchainWorkerWhenValidating executes the offchain workers on authority nodes only
+	OffchainWorkerWhenValidating = OffchainWorkerMode("when-validating")
+)
+
+// IsValid checks whether the offchain worker mode is valid
+func (m OffchainWorkerMode) IsValid() bool {
+	switch m {
+	case OffchainWorkerAlways, OffchainWorkerNever, OffchainWorkerWhenValidating:
+		return true
+	default:
+		return false
+	}
+}
+
+// defaultOffchainWorkerTimeout is the default duration after which the execution of the
+// offchain workers is cancelled. Cancelling interrupts their HTTP requests and sleeps, and
+// the offchain workers of the next blocks are skipped until the execution returns.
+const defaultOffchainWorkerTimeout = 30 * time.Second
+
+// Reasons for which the execution of the offchain workers fails, used as label of the failures metric.
+const (
+	offchainWorkerInstanceFailure  = "instance"
+	offchainWorkerExecutionFailure = "execution"
+	offchainWorkerTimeoutFailure   = "timeout"
+)
+
+var (
+	errOffchainWorkerRunning = errors.New("offchain workers of a previous block are still running")
+	errOffchainWorkerTimeout = errors.New("offchain workers timed out")
+)
+
+var (
+	offchainWorkerDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "gossamer_core",
+		Name:      "offchain_worker_duration_seconds",
+		Help:      "duration of the executions of the offchain workers which completed",
+	})
+	offchainWorkerFailuresCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gossamer_core",
+		Name:      "offchain_worker_failures_total",
+		Help:      "total number of executions of the offchain workers which failed",
+	}, []string{"reason"})
+)
+
+// handleOffchainWorker executes the offchain workers in the background if the block
+// is the best block, depending on the offchain worker mode.
+func (s *Service) handleOffchainWorker(block *types.Block) {
+	if s.offchainWorkerMode == "" || s.offchainWorkerMode == OffchainWorkerNever {
+		return
+	}
+
+	hash := block.Header.Hash()
+	if hash != s.blockState.BestBlockHash() {
+		return
+	}
+
+	rt, err := s.blockState.GetRuntime(&hash)
+	if err != nil {
+		logger.Warnf("failed to get runtime of block %s for the offchain workers: %s", hash, err)
+		return
+	}
+
+	if s.offchainWorkerMode == OffchainWorkerWhenValidating && !rt.Validator() {
+		return
+	}
+
+	header := block.Header
+	go func() {
+		err := s.runOffchainWorker(&header, rt)
+		if errors.Is(err, errOffchainWorkerRunning) {
+			logger.Debugf("skipping offchain workers of block %s: %s", hash, err)
+		} else if err != nil {
+			logger.Warnf("failed to execute offchain workers of block %s: %s", hash, err)
+		}
+	}()
+}
+
+// runOffchainWorker executes the offchain workers of the block in a new runtime instance,
+// and waits until they complete or time out. On timeout, the execution is cancelled and
+// releases the offchain worker slot once the runtime call returns.
+func (s *Service) runOffchainWorker(header *types.Header, rt runtime.Instance) error {
+	select {
+	case s.offchainWorkerSem <- struct{}{}:
+	default:
+		return errOffchainWorkerRunning
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	instance, err := s.newOffchainWorkerInstance(ctx, header, rt)
+	if err != nil {
+		cancel()
+		<-s.offchainWorkerSem
+		offchainWorkerFailuresCounter.WithLabelValues(offchainWorkerInstanceFailure).Inc()
+		return err
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() { <-s.offchainWorkerSem }()
+		defer cancel()
+
+		err := instance.OffchainWorker(header)
+		instance.Stop()
+		done <- err
+	}()
+
+	timer := time.NewTimer(s.offchainWorkerTimeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-done:
+	case <-timer.C:
+		cancel()
+		offchainWorkerFailuresCounter.WithLabelValues(offchainWorkerTimeoutFailure).Inc()
+		return fmt.Errorf("%w after %s", errOffchainWorkerTimeout, s.offchainWorkerTimeout)
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+
+	if err != nil {
+		offchainWorkerFailuresCounter.WithLabelValues(offchainWorkerExecutionFailure).Inc()
+		return err
+	}
+
+	offchainWorkerDuration.Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// newOffchainWorkerInstance creates the runtime instance executing the offchain workers
+// on top of the state of the block, which has access to the keystore, the transaction
+// pool and the offchain storage.
+// The HTTP requests and sleeps of the offchain workers are cancelled with the context.
+func (s *Service) newOffchainWorkerInstance(ctx context.Context, header *types.Header,
+	rt runtime.Instance) (runtime.Instance, error) {
+	code, err := s.storageState.LoadCode(&header.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load runtime code: %w", err)
+	}
+
+	state, err := s.storageState.TrieState(&header.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load state: %w", err)
+	}
+
+	cfg := runtime.InstanceConfig{
+		Storage:              state,
+		Keystore:             s.keys,
+		NodeStorage:          rt.NodeStorage(),
+		Network:              rt.NetworkService(),
+		Transaction:          s.transactionState,
+		CodeHash:             rt.GetCodeHash(),
+		OffchainHTTPDisabled: rt.OffchainHTTPDisabled(),
+		OffchainCtx:          ctx,
+	}
+
+	if rt.Validator() {
+		cfg.Role = 4
+	}
+
+	instance, err := s.newRuntimeInstance(code, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create runtime instance: %w", err)
+	}
+
+	return instance, nil
+}
+
+// handleOffchainIndex writes the offchain index changes of the best chain to the offchain storage.
+// The changes of the blocks retracted since the last update are reverted, and the changes of the
+// enacted blocks are applied.
+func (s *Service) handleOffchainIndex() error {
+	head, best := s.offchainIndexHead, s.blockState.BestBlockHash()
+	if best == head {
+		return nil
+	}
+
+	finalised, err := s.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return fmt.Errorf("cannot get highest finalised header: %w", err)
+	}
+	finalisedHash := finalised.Hash()
+
+	var retracted, enacted []common.Hash
+	ancestor, err := s.blockState.HighestCommonAncestor(head, best)
+	if err != nil {
+		// the previous head was pruned on finalisation, and the written changes of the pruned
+		// blocks were reverted by the block state, so the changes of the best chain are applied
+		// from the block finalised at the last update, which is the last block known to be written.
+		logger.Debugf("cannot find common ancestor of blocks %s and %s: %s", head, best, err)
+		enacted, err = s.canonicalChainFrom(s.offchainIndexFinalised, finalised, best)
+		if err != nil {
+			return err
+		}
+	} else {
+		retracted, err = s.blockState.SubChain(ancestor, head)
+		if err != nil {
+			return err
+		}
+
+		enacted, err = s.blockState.SubChain(ancestor, best)
+		if err != nil {
+			return err
+		}
+	}
+
+	rt, err := s.blockState.GetRuntime(nil)
+	if err != nil {
+		return err
+	}
+
+	storage := rt.NodeStorage().PersistentStorage
+	if storage == nil {
+		s.offchainIndexHead = best
+		s.offchainIndexFinalised = finalised.Number
+		return nil
+	}
+
+	// the subchains start with the common ancestor, whose changes are kept
+	for i := len(retracted) - 1; i > 0; i-- {
+		var revert []rtstorage.OffchainIndexChange
+		revert, err = s.blockState.GetOffchainIndexRevert(retracted[i])
+		if err != nil {
+			return err
+		}
+
+		err = writeOffchainIndexChanges(storage, revert)
+		if err != nil {
+			return fmt.Errorf("cannot revert offchain index changes of block %s: %w", retracted[i], err)
+		}
+
+		// the changes of the block are not written anymore, so they must
+		// not be reverted again if the block is pruned later on.
+		err = s.blockState.DeleteOffchainIndexRevert(retracted[i])
+		if err != nil {
+			return fmt.Errorf("cannot delete offchain index revert of block %s: %w", retracted[i], err)
+		}
+
+		s.offchainIndexHead = retracted[i-1]
+	}
+
+	// the enacted blocks up to the finalised block are finalised
+	finalisedIndex := 0
+	for i, hash := range enacted {
+		if hash == finalisedHash {
+			finalisedIndex = i
+		}
+	}
+
+	for i := 1; i < len(enacted); i++ {
+		hash := enacted[i]
+		err = s.applyOffchainIndex(storage, hash)
+		if err != nil {
+			return fmt.Errorf("cannot apply offchain index changes of block %s: %w", hash, err)
+		}
+
+		s.offchainIndexHead = hash
+
+		// the changes of the blocks finalised before being written cannot be reverted anymore
+		if i <= finalisedIndex {
+			err = s.blockState.DeleteOffchainIndex(hash)
+			if err != nil {
+				return fmt.Errorf("cannot delete offchain index changes of block %s: %w", hash, err)
+			}
+		}
+	}
+
+	s.offchainIndexFinalised = finalised.Number
+	return nil
+}
+
+// canonicalChainFrom returns the hashes of the canonical chain from the finalised block
+// with the given number to the best block, through the highest finalised block.
+func (s *Service) canonicalChainFrom(number uint, finalised *types.Header, best common.Hash) ([]common.Hash, error) {
+	var chain []common.Hash
+	for ; number < finalised.Number; number++ {
+		hash, err := s.blockState.GetHashByNumber(number)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get hash of finalised block number %d: %w", number, err)
+		}
+		chain = append(chain, hash)
+	}
+
+	subchain, err := s.blockState.SubChain(finalised.Hash(), best)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(chain, subchain...), nil
+}
+
+// applyOffchainIndex writes the offchain index changes of the block to the offchain storage,
+// after storing the changes reverting them.
+func (s *Service) applyOffchainIndex(storage runtime.BasicStorage, hash common.Hash) error {
+	changes, err := s.blockState.GetOffchainIndexChanges(hash)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	revert := make([]rtstorage.OffchainIndexChange, 0, len(changes))
+	reverted := make(map[string]struct{}, len(changes))
+	for _, change := range changes {
+		if _, ok := reverted[string(change.Key)]; ok {
+			continue
+		}
+		reverted[string(change.Key)] = struct{}{}
+
+		var value []byte
+		value, err = storage.Get(change.Key)
+		if errors.Is(err, chaindb.ErrKeyNotFound) {
+			revert = append(revert, rtstorage.OffchainIndexChange{Key: change.Key})
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		revert = append(revert, rtstorage.OffchainIndexChange{Key: change.Key, Value: &value})
+	}
+
+	err = s.blockState.SetOffchainIndexRevert(hash, revert)
+	if err != nil {
+		return err
+	}
+
+	return writeOffchainIndexChanges(storage, changes)
+}
+
+func writeOffchainIndexChanges(storage runtime.BasicStorage, changes []rtstorage.OffchainIndexChange) error {
+	for _, change := range changes {
+		var err error
+		if change.Value == nil {
+			err = storage.Del(change.Key)
+		} else {
+			err = storage.Put(change.Key, *change.Value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
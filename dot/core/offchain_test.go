@@ -0,0 +1,404 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffchainWorkerMode_IsValid(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, OffchainWorkerAlways.IsValid())
+	assert.True(t, OffchainWorkerNever.IsValid())
+	assert.True(t, OffchainWorkerWhenValidating.IsValid())
+	assert.False(t, OffchainWorkerMode("").IsValid())
+	assert.False(t, OffchainWorkerMode("sometimes").IsValid())
+}
+
+func Test_Service_handleOffchainWorker(t *testing.T) {
+	t.Parallel()
+
+	block := types.NewBlock(*types.NewEmptyHeader(), *types.NewBody(nil))
+	hash := block.Header.Hash()
+
+	t.Run("never", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		service := &Service{
+			blockState:         NewMockBlockState(ctrl),
+			offchainWorkerMode: OffchainWorkerNever,
+		}
+		service.handleOffchainWorker(&block)
+	})
+
+	t.Run("not best block", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		service := &Service{
+			blockState:         mockBlockState,
+			offchainWorkerMode: OffchainWorkerAlways,
+		}
+		service.handleOffchainWorker(&block)
+	})
+
+	t.Run("get runtime error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(hash)
+		mockBlockState.EXPECT().GetRuntime(&hash).Return(nil, errTestDummyError)
+		service := &Service{
+			blockState:         mockBlockState,
+			offchainWorkerMode: OffchainWorkerAlways,
+		}
+		service.handleOffchainWorker(&block)
+	})
+
+	t.Run("not validating", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("Validator").Return(false)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(hash)
+		mockBlockState.EXPECT().GetRuntime(&hash).Return(runtimeMock, nil)
+		service := &Service{
+			blockState:         mockBlockState,
+			offchainWorkerMode: OffchainWorkerWhenValidating,
+		}
+		service.handleOffchainWorker(&block)
+		runtimeMock.AssertExpectations(t)
+	})
+}
+
+func Test_Service_runOffchainWorker(t *testing.T) {
+	t.Parallel()
+
+	header := types.NewEmptyHeader()
+	header.Number = 21
+	header.StateRoot = common.Hash{2}
+
+	trieState, err := rtstorage.NewTrieState(trie.NewEmptyTrie())
+	require.NoError(t, err)
+
+	newRuntimeMock := func() *mocksruntime.Instance {
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("NodeStorage").Return(runtime.NodeStorage{})
+		runtimeMock.On("NetworkService").Return(nil)
+		runtimeMock.On("GetCodeHash").Return(common.Hash{3})
+		runtimeMock.On("OffchainHTTPDisabled").Return(false)
+		runtimeMock.On("Validator").Return(true)
+		return runtimeMock
+	}
+
+	instanceFunc := func(instance runtime.Instance, err error,
+		offchainCtx *context.Context) runtime.NewInstanceFunc {
+		return func(code []byte, cfg runtime.InstanceConfig) (runtime.Instance, error) {
+			assert.Equal(t, []byte{1}, code)
+			assert.Equal(t, trieState, cfg.Storage)
+			assert.Equal(t, common.Hash{3}, cfg.CodeHash)
+			assert.Equal(t, byte(4), cfg.Role)
+			require.NotNil(t, cfg.OffchainCtx)
+			if offchainCtx != nil {
+				*offchainCtx = cfg.OffchainCtx
+			}
+			return instance, err
+		}
+	}
+
+	newService := func(ctrl *gomock.Controller, newInstance runtime.NewInstanceFunc) *Service {
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().LoadCode(&header.StateRoot).Return([]byte{1}, nil)
+		mockStorageState.EXPECT().TrieState(&header.StateRoot).Return(trieState, nil)
+		return &Service{
+			ctx:                   context.Background(),
+			storageState:          mockStorageState,
+			newRuntimeInstance:    newInstance,
+			offchainWorkerTimeout: time.Minute,
+			offchainWorkerSem:     make(chan struct{}, 1),
+		}
+	}
+
+	t.Run("still running", func(t *testing.T) {
+		t.Parallel()
+		service := &Service{offchainWorkerSem: make(chan struct{}, 1)}
+		service.offchainWorkerSem <- struct{}{}
+
+		err := service.runOffchainWorker(header, nil)
+		assert.ErrorIs(t, err, errOffchainWorkerRunning)
+	})
+
+	t.Run("instance error", func(t *testing.T) {
+		t.Parallel()
+		service := newService(gomock.NewController(t), instanceFunc(nil, errTestDummyError, nil))
+
+		err := service.runOffchainWorker(header, newRuntimeMock())
+		assert.ErrorIs(t, err, errTestDummyError)
+		assert.Empty(t, service.offchainWorkerSem)
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+		t.Parallel()
+		instance := new(mocksruntime.Instance)
+		instance.On("OffchainWorker", header).Return(errTestDummyError)
+		instance.On("Stop").Return()
+		service := newService(gomock.NewController(t), instanceFunc(instance, nil, nil))
+
+		err := service.runOffchainWorker(header, newRuntimeMock())
+		assert.ErrorIs(t, err, errTestDummyError)
+		instance.AssertExpectations(t)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		instance := new(mocksruntime.Instance)
+		instance.On("OffchainWorker", header).Return(nil)
+		instance.On("Stop").Return()
+		service := newService(gomock.NewController(t), instanceFunc(instance, nil, nil))
+
+		err := service.runOffchainWorker(header, newRuntimeMock())
+		require.NoError(t, err)
+		instance.AssertExpectations(t)
+
+		// the semaphore is released once the instance is stopped
+		service.offchainWorkerSem <- struct{}{}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		var offchainCtx context.Context
+		release := make(chan struct{})
+		instance := new(mocksruntime.Instance)
+		instance.On("OffchainWorker", header).Run(func(mock.Arguments) {
+			<-release
+			// the execution is cancelled on timeout
+			<-offchainCtx.Done()
+		}).Return(nil)
+		instance.On("Stop").Return()
+		service := newService(gomock.NewController(t), instanceFunc(instance, nil, &offchainCtx))
+		service.offchainWorkerTimeout = 10 * time.Millisecond
+
+		err := service.runOffchainWorker(header, newRuntimeMock())
+		assert.ErrorIs(t, err, errOffchainWorkerTimeout)
+
+		// the offchain workers of the next blocks are skipped until the execution returns
+		err = service.runOffchainWorker(header, nil)
+		assert.ErrorIs(t, err, errOffchainWorkerRunning)
+
+		close(release)
+		service.offchainWorkerSem <- struct{}{}
+		instance.AssertExpectations(t)
+	})
+}
+
+func Test_Service_handleOffchainIndex(t *testing.T) {
+	t.Parallel()
+
+	ancestor := common.Hash{1}
+	retracted := common.Hash{2}
+	enacted1, enacted2 := common.Hash{3}, common.Hash{4}
+	finalised := types.NewEmptyHeader()
+
+	t.Run("up to date", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(ancestor)
+		service := &Service{
+			blockState:        mockBlockState,
+			offchainIndexHead: ancestor,
+		}
+
+		err := service.handleOffchainIndex()
+		require.NoError(t, err)
+	})
+
+	t.Run("get highest finalised header error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(enacted1)
+		mockBlockState.EXPECT().GetHighestFinalisedHeader().Return(nil, errTestDummyError)
+		service := &Service{
+			blockState:        mockBlockState,
+			offchainIndexHead: retracted,
+		}
+
+		err := service.handleOffchainIndex()
+		assert.ErrorIs(t, err, errTestDummyError)
+		assert.Equal(t, retracted, service.offchainIndexHead)
+	})
+
+	t.Run("head pruned on finalisation", func(t *testing.T) {
+		t.Parallel()
+
+		storage := runtime.NewInMemoryDB(t)
+		value := []byte("finalised")
+
+		// the blocks 1 to 3 are finalised, and the previous head was on
+		// a fork from block 1, which was pruned on the finalisation.
+		finalised := &types.Header{Number: 3}
+		finalisedHash := finalised.Hash()
+		written, unwritten := common.Hash{5}, common.Hash{6}
+
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("NodeStorage").Return(runtime.NodeStorage{PersistentStorage: storage})
+
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(enacted1)
+		mockBlockState.EXPECT().GetHighestFinalisedHeader().Return(finalised, nil)
+		mockBlockState.EXPECT().HighestCommonAncestor(retracted, enacted1).Return(common.Hash{}, errTestDummyError)
+		// the changes of the best chain are applied from the block finalised at the last update
+		mockBlockState.EXPECT().GetHashByNumber(uint(1)).Return(written, nil)
+		mockBlockState.EXPECT().GetHashByNumber(uint(2)).Return(unwritten, nil)
+		mockBlockState.EXPECT().SubChain(finalisedHash, enacted1).Return([]common.Hash{finalisedHash, enacted1}, nil)
+		mockBlockState.EXPECT().GetRuntime(nil).Return(runtimeMock, nil)
+		mockBlockState.EXPECT().GetOffchainIndexChanges(unwritten).Return([]rtstorage.OffchainIndexChange{
+			{Key: []byte("a"), Value: &value},
+		}, nil)
+		mockBlockState.EXPECT().SetOffchainIndexRevert(unwritten, []rtstorage.OffchainIndexChange{
+			{Key: []byte("a")},
+		})
+		// the changes of the finalised block are applied
+		mockBlockState.EXPECT().GetOffchainIndexChanges(finalisedHash).Return([]rtstorage.OffchainIndexChange{
+			{Key: []byte("b"), Value: &value},
+		}, nil)
+		mockBlockState.EXPECT().SetOffchainIndexRevert(finalisedHash, []rtstorage.OffchainIndexChange{
+			{Key: []byte("b")},
+		})
+		mockBlockState.EXPECT().GetOffchainIndexChanges(enacted1).Return(nil, nil)
+		mockBlockState.EXPECT().DeleteOffchainIndex(unwritten).Return(nil)
+		mockBlockState.EXPECT().DeleteOffchainIndex(finalisedHash).Return(nil)
+		service := &Service{
+			blockState:             mockBlockState,
+			offchainIndexHead:      retracted,
+			offchainIndexFinalised: 1,
+		}
+
+		err := service.handleOffchainIndex()
+		require.NoError(t, err)
+		assert.Equal(t, enacted1, service.offchainIndexHead)
+		assert.Equal(t, uint(3), service.offchainIndexFinalised)
+
+		for _, key := range []string{"a", "b"} {
+			stored, err := storage.Get([]byte(key))
+			require.NoError(t, err)
+			assert.Equal(t, value, stored)
+		}
+	})
+
+	t.Run("finalised enacted block", func(t *testing.T) {
+		t.Parallel()
+
+		storage := runtime.NewInMemoryDB(t)
+		finalisedHash := finalised.Hash()
+
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("NodeStorage").Return(runtime.NodeStorage{PersistentStorage: storage})
+
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(enacted2)
+		mockBlockState.EXPECT().GetHighestFinalisedHeader().Return(finalised, nil)
+		mockBlockState.EXPECT().HighestCommonAncestor(ancestor, enacted2).Return(ancestor, nil)
+		mockBlockState.EXPECT().SubChain(ancestor, ancestor).Return([]common.Hash{ancestor}, nil)
+		mockBlockState.EXPECT().SubChain(ancestor, enacted2).Return([]common.Hash{ancestor, finalisedHash, enacted2}, nil)
+		mockBlockState.EXPECT().GetRuntime(nil).Return(runtimeMock, nil)
+		mockBlockState.EXPECT().GetOffchainIndexChanges(finalisedHash).Return(nil, nil)
+		mockBlockState.EXPECT().GetOffchainIndexChanges(enacted2).Return(nil, nil)
+		// the changes of the finalised block cannot be reverted anymore
+		mockBlockState.EXPECT().DeleteOffchainIndex(finalisedHash).Return(nil)
+		service := &Service{
+			blockState:        mockBlockState,
+			offchainIndexHead: ancestor,
+		}
+
+		err := service.handleOffchainIndex()
+		require.NoError(t, err)
+		assert.Equal(t, enacted2, service.offchainIndexHead)
+	})
+
+	t.Run("chain reorganisation", func(t *testing.T) {
+		t.Parallel()
+
+		storage := runtime.NewInMemoryDB(t)
+		require.NoError(t, storage.Put([]byte("a"), []byte("retracted")))
+		require.NoError(t, storage.Put([]byte("b"), []byte("retracted")))
+
+		old := []byte("old")
+		value1, value2 := []byte("enacted1"), []byte("enacted2")
+
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("NodeStorage").Return(runtime.NodeStorage{PersistentStorage: storage})
+
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(enacted2)
+		mockBlockState.EXPECT().GetHighestFinalisedHeader().Return(finalised, nil)
+		mockBlockState.EXPECT().HighestCommonAncestor(retracted, enacted2).Return(ancestor, nil)
+		mockBlockState.EXPECT().SubChain(ancestor, retracted).Return([]common.Hash{ancestor, retracted}, nil)
+		mockBlockState.EXPECT().SubChain(ancestor, enacted2).Return([]common.Hash{ancestor, enacted1, enacted2}, nil)
+		mockBlockState.EXPECT().GetRuntime(nil).Return(runtimeMock, nil)
+		mockBlockState.EXPECT().GetOffchainIndexRevert(retracted).Return([]rtstorage.OffchainIndexChange{
+			{Key: []byte("a"), Value: &old},
+			{Key: []byte("b")},
+		}, nil)
+		// the changes of the retracted block are not reverted again if it is pruned
+		mockBlockState.EXPECT().DeleteOffchainIndexRevert(retracted).Return(nil)
+		mockBlockState.EXPECT().GetOffchainIndexChanges(enacted1).Return([]rtstorage.OffchainIndexChange{
+			{Key: []byte("c"), Value: &value1},
+		}, nil)
+		mockBlockState.EXPECT().SetOffchainIndexRevert(enacted1, []rtstorage.OffchainIndexChange{
+			{Key: []byte("c")},
+		})
+		mockBlockState.EXPECT().GetOffchainIndexChanges(enacted2).Return([]rtstorage.OffchainIndexChange{
+			{Key: []byte("a")},
+			{Key: []byte("c"), Value: &value2},
+			{Key: []byte("a"), Value: &value2},
+		}, nil)
+		mockBlockState.EXPECT().SetOffchainIndexRevert(enacted2, []rtstorage.OffchainIndexChange{
+			{Key: []byte("a"), Value: &old},
+			{Key: []byte("c"), Value: &value1},
+		})
+
+		service := &Service{
+			blockState:        mockBlockState,
+			offchainIndexHead: retracted,
+		}
+
+		err := service.handleOffchainIndex()
+		require.NoError(t, err)
+		assert.Equal(t, enacted2, service.offchainIndexHead)
+
+		value, err := storage.Get([]byte("a"))
+		require.NoError(t, err)
+		assert.Equal(t, value2, value)
+
+		_, err = storage.Get([]byte("b"))
+		assert.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+
+		value, err = storage.Get([]byte("c"))
+		require.NoError(t, err)
+		assert.Equal(t, value2, value)
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+)
+
+// maxRuntimePools is the number of runtime codes for which a pool of instances is kept.
+// The pool of the oldest code is closed when a pool is created for a new code.
+const maxRuntimePools = 2
+
+// poolableInstance is a runtime instance whose backend can create pools of identical instances
+type poolableInstance interface {
+	runtime.Instance
+	NewPool(size int) (runtime.InstancePool, error)
+}
+
+// runtimePools holds the pools of runtime instances, keyed by runtime code hash, used for the
+// runtime calls which are not part of the block import, so that they can run concurrently.
+type runtimePools struct {
+	sync.Mutex
+	size   int
+	pools  map[common.Hash]runtime.InstancePool
+	hashes []common.Hash // code hashes of the pools, in creation order
+}
+
+func newRuntimePools(size int) *runtimePools {
+	return &runtimePools{
+		size:  size,
+		pools: make(map[common.Hash]runtime.InstancePool),
+	}
+}
+
+// get takes an instance identical to the given runtime instance out of its pool, and returns
+// a function putting it back in the pool. The runtime instance itself is returned if it
+// cannot be pooled.
+func (p *runtimePools) get(rt runtime.Instance) (instance runtime.Instance, put func(), err error) {
+	poolable, ok := rt.(poolableInstance)
+	if p == nil || p.size < 1 || !ok || poolable.GetCodeHash().IsEmpty() {
+		return rt, func() {}, nil
+	}
+
+	pool, err := p.pool(poolable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pooled, err := pool.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pooled, func() { pool.Put(pooled) }, nil
+}
+
+// pool returns the pool of instances of the runtime code, creating it if needed
+func (p *runtimePools) pool(rt poolableInstance) (runtime.InstancePool, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	codeHash := rt.GetCodeHash()
+	if pool, ok := p.pools[codeHash]; ok {
+		return pool, nil
+	}
+
+	pool, err := rt.NewPool(p.size)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create runtime instance pool for code hash %s: %w", codeHash, err)
+	}
+
+	p.pools[codeHash] = pool
+	p.hashes = append(p.hashes, codeHash)
+	if len(p.hashes) > maxRuntimePools {
+		oldest := p.hashes[0]
+		p.pools[oldest].Close()
+		delete(p.pools, oldest)
+		p.hashes = p.hashes[1:]
+	}
+
+	return pool, nil
+}
+
+// close closes all the pools
+func (p *runtimePools) close() {
+	if p == nil {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	for _, pool := range p.pools {
+		pool.Close()
+	}
+
+	p.pools = make(map[common.Hash]runtime.InstancePool)
+	p.hashes = nil
+}
+
+// getRuntimeInstance returns an instance of the runtime of the given block, or of the best block
+// if the hash is nil, taken out of the runtime instance pools. The returned function must be called
+// to put the instance back in the pool once the runtime calls are done.
+func (s *Service) getRuntimeInstance(hash *common.Hash) (instance runtime.Instance, put func(), err error) {
+	rt, err := s.blockState.GetRuntime(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.runtimePools.get(rt)
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_runtimePools_get(t *testing.T) {
+	t.Parallel()
+
+	runtimeMock := new(mocksruntime.Instance)
+
+	tests := map[string]struct {
+		pools *runtimePools
+		rt    runtime.Instance
+	}{
+		"nil pools": {
+			rt: runtimeMock,
+		},
+		"pooling disabled": {
+			pools: newRuntimePools(0),
+			rt:    &wasmer.Instance{},
+		},
+		"instance not poolable": {
+			pools: newRuntimePools(1),
+			rt:    runtimeMock,
+		},
+		"poolable instance without code hash": {
+			pools: newRuntimePools(1),
+			rt:    &wasmer.Instance{},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			instance, put, err := tt.pools.get(tt.rt)
+			require.NoError(t, err)
+			assert.Same(t, tt.rt, instance)
+			put()
+			tt.pools.close()
+		})
+	}
+}
+
+func Test_Service_getRuntimeInstance(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	hash := common.Hash{1}
+
+	t.Run("get runtime error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(&hash).Return(nil, errTest)
+
+		service := &Service{blockState: mockBlockState}
+		_, _, err := service.getRuntimeInstance(&hash)
+		assert.ErrorIs(t, err, errTest)
+	})
+
+	t.Run("not pooled", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(&hash).Return(runtimeMock, nil)
+
+		service := &Service{
+			blockState:   mockBlockState,
+			runtimePools: newRuntimePools(2),
+		}
+		instance, put, err := service.getRuntimeInstance(&hash)
+		require.NoError(t, err)
+		assert.Same(t, runtimeMock, instance)
+		put()
+	})
+}
@@ -9,6 +9,7 @@
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -18,10 +19,11 @@
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/executor"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
-	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
 	"github.com/ChainSafe/gossamer/lib/services"
 	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/ChainSafe/gossamer/pkg/scale"
 	cscale "github.com/centrifuge/go-substrate-rpc-client/v3/scale"
 	ctypes "github.com/centrifuge/go-substrate-rpc-client/v3/types"
 )
@@ -31,10 +33,21 @@
 	logger                  = log.NewFromGlobal(log.AddContext("pkg", "core"))
 )
 
-// QueryKeyValueChanges represents the key-value data inside a block storage
-type QueryKeyValueChanges map[string]string
+// MaxQueryStorageRange is the maximum number of blocks a storage query can span
+const MaxQueryStorageRange = 1000
 
-type wasmerInstanceFunc func(code []byte, cfg *wasmer.Config) (instance *wasmer.Instance, err error)
+// StorageChange is a hex encoded storage key with its hex encoded value,
+// the value being nil if the key has no value
+type StorageChange struct {
+	Key   string
+	Value *string
+}
+
+// StorageChangeSet holds the storage changes of a block
+type StorageChangeSet struct {
+	Block   common.Hash
+	Changes []StorageChange
+}
 
 // Service is an overhead layer that allows communication between the runtime,
 // BABE session, and network service. It deals with the validation of transactions
@@ -58,6 +71,21 @@ type Service struct {
 
 	// Keystore
 	keys *keystore.GlobalKeystore
+
+	// newRuntimeInstance instantiates the runtime instances executing the offchain workers,
+	// and the substituted runtime codes
+	newRuntimeInstance runtime.NewInstanceFunc
+
+	offchainWorkerMode    OffchainWorkerMode
+	offchainWorkerTimeout time.Duration
+	offchainWorkerSem     chan struct{} // held while the offchain workers are running
+
+	// last block of the best chain whose offchain index changes are written to the offchain storage
+	offchainIndexHead common.Hash
+	// number of the block finalised when offchainIndexHead was written, which is its ancestor
+	offchainIndexFinalised uint
+
+	runtimePools *runtimePools
 }
 
 // Config holds the configuration for the core Service.
@@ -74,6 +102,19 @@ type Config struct {
 
 	CodeSubstitutes      map[common.Hash]string
 	CodeSubstitutedState CodeSubstitutedState
+
+	// NewRuntimeInstance instantiates the runtime instances of the configured backend.
+	// It defaults to the default backend of the executor package if nil.
+	NewRuntimeInstance runtime.NewInstanceFunc
+
+	OffchainWorker OffchainWorkerMode
+	// OffchainWorkerTimeout is the duration after which the execution of the
+	// offchain workers is cancelled. It defaults to 30 seconds if zero.
+	OffchainWorkerTimeout time.Duration
+
+	// RuntimePoolSize is the number of instances of each runtime instance pool.
+	// The runtime instances are not pooled if it is zero.
+	RuntimePoolSize int
 }
 
 // NewService returns a new core service that connects the runtime, BABE
@@ -103,19 +144,34 @@ func NewService(cfg *Config) (*Service, error) {
 
 	blockAddCh := make(chan *types.Block, 256)
 
+	newRuntimeInstance := cfg.NewRuntimeInstance
+	if newRuntimeInstance == nil {
+		newRuntimeInstance = executor.NewInstance
+	}
+
+	offchainWorkerTimeout := cfg.OffchainWorkerTimeout
+	if offchainWorkerTimeout == 0 {
+		offchainWorkerTimeout = defaultOffchainWorkerTimeout
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	srv := &Service{
-		ctx:                  ctx,
-		cancel:               cancel,
-		keys:                 cfg.Keystore,
-		blockState:           cfg.BlockState,
-		epochState:           cfg.EpochState,
-		storageState:         cfg.StorageState,
-		transactionState:     cfg.TransactionState,
-		net:                  cfg.Network,
-		blockAddCh:           blockAddCh,
-		codeSubstitute:       cfg.CodeSubstitutes,
-		codeSubstitutedState: cfg.CodeSubstitutedState,
+		ctx:                   ctx,
+		cancel:                cancel,
+		keys:                  cfg.Keystore,
+		blockState:            cfg.BlockState,
+		epochState:            cfg.EpochState,
+		storageState:          cfg.StorageState,
+		transactionState:      cfg.TransactionState,
+		net:                   cfg.Network,
+		blockAddCh:            blockAddCh,
+		codeSubstitute:        cfg.CodeSubstitutes,
+		codeSubstitutedState:  cfg.CodeSubstitutedState,
+		newRuntimeInstance:    newRuntimeInstance,
+		offchainWorkerMode:    cfg.OffchainWorker,
+		offchainWorkerTimeout: offchainWorkerTimeout,
+		offchainWorkerSem:     make(chan struct{}, 1),
+		runtimePools:          newRuntimePools(cfg.RuntimePoolSize),
 	}
 
 	return srv, nil
@@ -123,6 +179,13 @@ func NewService(cfg *Config) (*Service, error) {
 
 // Start starts the core service
 func (s *Service) Start() error {
+	finalised, err := s.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return fmt.Errorf("cannot get highest finalised header: %w", err)
+	}
+
+	s.offchainIndexHead = s.blockState.BestBlockHash()
+	s.offchainIndexFinalised = finalised.Number
 	go s.handleBlocksAsync()
 	return nil
 }
@@ -134,6 +197,7 @@ func (s *Service) Stop() error {
 
 	s.cancel()
 	close(s.blockAddCh)
+	s.runtimePools.close()
 	return nil
 }
 
@@ -158,6 +222,23 @@ func (s *Service) HandleBlockImport(block *types.Block, state *rtstorage.TrieSta
 
 // HandleBlockProduced handles a block that was produced by us
 // It is handled the same as an imported block in terms of state updates; the only difference
+// transactionStoragePeriod returns the number of blocks during which the data indexed by the
+// transactions of a block is kept, as defined by the runtime in the state after the block.
+func transactionStoragePeriod(state *rtstorage.TrieState) (uint, error) {
+	enc := state.Get(runtime.TransactionStoragePeriodKey())
+	if enc == nil {
+		return runtime.DefaultTransactionStoragePeriod, nil
+	}
+
+	var period uint32
+	err := scale.Unmarshal(enc, &period)
+	if err != nil {
+		return 0, fmt.Errorf("cannot decode transaction storage period: %w", err)
+	}
+
+	return uint(period), nil
+}
+
 // is we send a BlockAnnounceMessage to our peers.
 func (s *Service) HandleBlockProduced(block *types.Block, state *rtstorage.TrieState) error {
 	if err := s.handleBlock(block, state); err != nil {
@@ -190,8 +271,18 @@ func (s *Service) handleBlock(block *types.Block, state *rtstorage.TrieState) er
 		return ErrNilBlockHandlerParameter
 	}
 
+	rt, err := s.blockState.GetRuntime(&block.Header.ParentHash)
+	if err != nil {
+		return err
+	}
+
+	stateVersion, err := rt.StateVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get state version: %w", err)
+	}
+
 	// store updates state trie nodes in database
-	err := s.storageState.StoreTrie(state, &block.Header)
+	err = s.storageState.StoreTrie(state, &block.Header, stateVersion)
 	if err != nil {
 		logger.Warnf("failed to store state trie for imported block %s: %s",
 			block.Header.Hash(), err)
@@ -212,9 +303,25 @@ func (s *Service) handleBlock(block *types.Block, state *rtstorage.TrieState) er
 	logger.Debugf("imported block %s and stored state trie with root %s",
 		block.Header.Hash(), state.MustRoot())
 
-	rt, err := s.blockState.GetRuntime(&block.Header.ParentHash)
-	if err != nil {
-		return err
+	// the offchain index changes are written to the offchain storage once the block is in the best chain
+	if changes := state.OffchainIndexChanges(); len(changes) > 0 {
+		err = s.blockState.SetOffchainIndexChanges(block.Header.Hash(), changes)
+		if err != nil {
+			return fmt.Errorf("failed to store offchain index changes: %w", err)
+		}
+	}
+
+	if operations := state.TransactionIndexOperations(); len(operations) > 0 {
+		var storagePeriod uint
+		storagePeriod, err = transactionStoragePeriod(state)
+		if err != nil {
+			return err
+		}
+
+		err = s.blockState.SetTransactionIndex(block, operations, storagePeriod)
+		if err != nil {
+			return fmt.Errorf("failed to store transaction index: %w", err)
+		}
 	}
 
 	// check for runtime changes
@@ -224,7 +331,7 @@ func (s *Service) handleBlock(block *types.Block, state *rtstorage.TrieState) er
 	}
 
 	// check if there was a runtime code substitution
-	if err := s.handleCodeSubstitution(block.Header.Hash(), state, wasmer.NewInstance); err != nil {
+	if err := s.handleCodeSubstitution(block.Header.Hash(), state, s.newRuntimeInstance); err != nil {
 		logger.Criticalf("failed to substitute runtime code: %s", err)
 		return err
 	}
@@ -245,7 +352,7 @@ func (s *Service) handleBlock(block *types.Block, state *rtstorage.TrieState) er
 func (s *Service) handleCodeSubstitution(
 	hash common.Hash,
 	state *rtstorage.TrieState,
-	instance wasmerInstanceFunc,
+	newInstance runtime.NewInstanceFunc,
 ) error {
 	value := s.codeSubstitute[hash]
 	if value == "" {
@@ -265,20 +372,19 @@ func (s *Service) handleCodeSubstitution(
 
 	// this needs to create a new runtime instance, otherwise it will update
 	// the blocks that reference the current runtime version to use the code substition
-	cfg := &wasmer.Config{
-		Imports: wasmer.ImportsNodeRuntime,
+	cfg := runtime.InstanceConfig{
+		Storage:              state,
+		Keystore:             rt.Keystore(),
+		NodeStorage:          rt.NodeStorage(),
+		Network:              rt.NetworkService(),
+		OffchainHTTPDisabled: rt.OffchainHTTPDisabled(),
 	}
 
-	cfg.Storage = state
-	cfg.Keystore = rt.Keystore()
-	cfg.NodeStorage = rt.NodeStorage()
-	cfg.Network = rt.NetworkService()
-
 	if rt.Validator() {
 		cfg.Role = 4
 	}
 
-	next, err := instance(code, cfg)
+	next, err := newInstance(code, cfg)
 	if err != nil {
 		return err
 	}
@@ -312,7 +418,12 @@ func (s *Service) handleBlocksAsync() {
 				logger.Warnf("failed to re-add transactions to chain upon re-org: %s", err)
 			}
 
+			if err := s.handleOffchainIndex(); err != nil {
+				logger.Warnf("failed to update offchain index: %s", err)
+			}
+
 			s.maintainTransactionPool(block)
+			s.handleOffchainWorker(block)
 		case <-s.ctx.Done():
 			return
 		}
@@ -363,6 +474,7 @@ func (s *Service) handleChainReorg(prev, curr common.Hash) error {
 			continue
 		}
 
+		retractedHash := hash
 		for _, ext := range *body {
 			logger.Tracef("validating transaction on re-org chain for extrinsic %s", ext)
 			decExt := &ctypes.Extrinsic{}
@@ -376,12 +488,18 @@ func (s *Service) handleChainReorg(prev, curr common.Hash) error {
 				continue
 			}
 
+			s.transactionState.NotifyStatus(ext, transaction.StatusNotification{
+				Status: transaction.Retracted,
+				Hash:   &retractedHash,
+			})
+
 			externalExt := make(types.Extrinsic, 0, 1+len(ext))
 			externalExt = append(externalExt, byte(types.TxnExternal))
 			externalExt = append(externalExt, ext...)
 			txv, err := rt.ValidateTransaction(externalExt)
 			if err != nil {
 				logger.Debugf("failed to validate transaction for extrinsic %s: %s", ext, err)
+				s.transactionState.NotifyStatus(ext, transaction.StatusNotification{Status: transaction.Invalid})
 				continue
 			}
 			vtx := transaction.NewValidTransaction(ext, txv)
@@ -398,8 +516,13 @@ func (s *Service) handleChainReorg(prev, curr common.Hash) error {
 // See https://github.com/paritytech/substrate/blob/74804b5649eccfb83c90aec87bdca58e5d5c8789/client/transaction-pool/src/lib.rs#L545
 func (s *Service) maintainTransactionPool(block *types.Block) {
 	// remove extrinsics included in a block
+	blockHash := block.Header.Hash()
 	for _, ext := range block.Body {
 		s.transactionState.RemoveExtrinsic(ext)
+		s.transactionState.NotifyStatus(ext, transaction.StatusNotification{
+			Status: transaction.InBlock,
+			Hash:   &blockHash,
+		})
 	}
 
 	// re-validate transactions in the pool and move them to the queue
@@ -415,6 +538,7 @@ func (s *Service) maintainTransactionPool(block *types.Block) {
 		txnValidity, err := rt.ValidateTransaction(tx.Extrinsic)
 		if err != nil {
 			s.transactionState.RemoveExtrinsic(tx.Extrinsic)
+			s.transactionState.NotifyStatus(tx.Extrinsic, transaction.StatusNotification{Status: transaction.Invalid})
 			continue
 		}
 
@@ -459,6 +583,31 @@ func (s *Service) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	return rt.DecodeSessionKeys(enc)
 }
 
+// GenerateSessionKeys generates new session keys using the runtime of the best block.
+// The private keys are stored in the node keystore, and the encoded public session keys are returned.
+func (s *Service) GenerateSessionKeys() ([]byte, error) {
+	bestBlockHash := s.blockState.BestBlockHash()
+
+	stateRoot, err := s.storageState.GetStateRootFromBlock(&bestBlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("could not get state root from block %s: %w", bestBlockHash, err)
+	}
+
+	ts, err := s.storageState.TrieState(stateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	rt, put, err := s.getRuntimeInstance(&bestBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	defer put()
+
+	rt.SetContextStorage(ts)
+	return rt.GenerateSessionKeys(nil)
+}
+
 // GetRuntimeVersion gets the current RuntimeVersion
 func (s *Service) GetRuntimeVersion(bhash *common.Hash) (runtime.Version, error) {
 	var stateRootHash *common.Hash
@@ -477,10 +626,11 @@ func (s *Service) GetRuntimeVersion(bhash *common.Hash) (runtime.Version, error)
 		return nil, err
 	}
 
-	rt, err := s.blockState.GetRuntime(bhash)
+	rt, put, err := s.getRuntimeInstance(bhash)
 	if err != nil {
 		return nil, err
 	}
+	defer put()
 
 	rt.SetContextStorage(ts)
 	return rt.Version()
@@ -508,7 +658,7 @@ func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
 		return err
 	}
 
-	rt, err := s.blockState.GetRuntime(&bestBlockHash)
+	rt, put, err := s.getRuntimeInstance(&bestBlockHash)
 	if err != nil {
 		logger.Critical("failed to get runtime")
 		return err
@@ -518,6 +668,7 @@ func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
 	// the transaction source is External
 	externalExt := types.Extrinsic(append([]byte{byte(types.TxnExternal)}, ext...))
 	txv, err := rt.ValidateTransaction(externalExt)
+	put()
 	if err != nil {
 		return err
 	}
@@ -526,13 +677,40 @@ func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
 	vtx := transaction.NewValidTransaction(ext, txv)
 	s.transactionState.AddToPool(vtx)
 
-	// broadcast transaction
+	s.broadcastExtrinsic(ext)
+	return nil
+}
+
+// BroadcastExtrinsic sends again a Transaction message containing the extrinsic,
+// if the extrinsic is still in the transaction pool. It returns false otherwise.
+func (s *Service) BroadcastExtrinsic(ext types.Extrinsic) (inPool bool) {
+	if !s.transactionState.Exists(ext) {
+		return false
+	}
+
+	if s.net != nil {
+		s.broadcastExtrinsic(ext)
+	}
+	return true
+}
+
+func (s *Service) broadcastExtrinsic(ext types.Extrinsic) {
 	msg := &network.TransactionMessage{Extrinsics: []types.Extrinsic{ext}}
 	s.net.GossipMessage(msg)
-	return nil
+
+	peers := s.net.Peers()
+	peerIDs := make([]string, len(peers))
+	for i, peer := range peers {
+		peerIDs[i] = peer.PeerID
+	}
+
+	s.transactionState.NotifyStatus(ext, transaction.StatusNotification{
+		Status:             transaction.Broadcast,
+		PeersBroadcastedTo: peerIDs,
+	})
 }
 
-//GetMetadata calls runtime Metadata_metadata function
+// GetMetadata calls runtime Metadata_metadata function
 func (s *Service) GetMetadata(bhash *common.Hash) ([]byte, error) {
 	var (
 		stateRootHash *common.Hash
@@ -551,50 +729,240 @@ func (s *Service) GetMetadata(bhash *common.Hash) ([]byte, error) {
 		return nil, err
 	}
 
-	rt, err := s.blockState.GetRuntime(bhash)
+	rt, put, err := s.getRuntimeInstance(bhash)
+	if err != nil {
+		return nil, err
+	}
+	defer put()
+
+	rt.SetContextStorage(ts)
+	metadata, err := rt.Metadata()
 	if err != nil {
 		return nil, err
 	}
 
+	// the returned slice points into the runtime memory, which is reset
+	// once the instance is put back in its pool.
+	result := make([]byte, len(metadata))
+	copy(result, metadata)
+	return result, nil
+}
+
+// CallRuntimeAPI executes the runtime API method with the given SCALE encoded
+// data on top of the state of the block with hash bhash, or the best block if
+// bhash is nil. Storage changes made during the call are discarded.
+func (s *Service) CallRuntimeAPI(bhash *common.Hash, method string, data []byte) ([]byte, error) {
+	if bhash == nil {
+		bestBlockHash := s.blockState.BestBlockHash()
+		bhash = &bestBlockHash
+	}
+
+	stateRootHash, err := s.storageState.GetStateRootFromBlock(bhash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get state root for block %s: %w", bhash, err)
+	}
+
+	// the trie state is a snapshot of the stored trie and is never written
+	// back to the storage state, so any changes made by the call are dropped.
+	ts, err := s.storageState.TrieState(stateRootHash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: for block %s: %s", ErrStateUnavailable, bhash, err)
+	}
+
+	rt, put, err := s.getRuntimeInstance(bhash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get runtime for block %s: %w", bhash, err)
+	}
+	defer put()
+
+	rt.SetContextStorage(ts)
+	ret, err := rt.Exec(method, data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute %s: %w", method, err)
+	}
+
+	// the returned slice points into the runtime memory, which may be
+	// overwritten by the next runtime call.
+	result := make([]byte, len(ret))
+	copy(result, ret)
+	return result, nil
+}
+
+// TraceBlock re-executes the block with the given hash on top of its parent state
+// and returns its header along with every storage access made by the runtime while executing it.
+func (s *Service) TraceBlock(hash common.Hash) (*types.Header, []rtstorage.TraceEvent, error) {
+	block, err := s.blockState.GetBlockByHash(hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get block %s: %w", hash, err)
+	}
+
+	parentHash := block.Header.ParentHash
+	stateRootHash, err := s.storageState.GetStateRootFromBlock(&parentHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get state root for block %s: %w", parentHash, err)
+	}
+
+	ts, err := s.storageState.TrieState(stateRootHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: for block %s: %s", ErrStateUnavailable, parentHash, err)
+	}
+
+	rt, put, err := s.getRuntimeInstance(&parentHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get runtime for block %s: %w", parentHash, err)
+	}
+	defer put()
+
+	// as for runtime calls, the traced trie state is never written back to
+	// the storage state.
+	tracingState := rtstorage.NewTracingTrieState(ts)
+	rt.SetContextStorage(tracingState)
+	_, err = rt.ExecuteBlock(block)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w %s: %s", ErrBlockExecution, hash, err)
+	}
+
+	return &block.Header, tracingState.Events(), nil
+}
+
+// DryRun applies the extrinsic on top of the block with the given hash, or of the best block if the hash is nil,
+// and returns the SCALE encoded ApplyExtrinsicResult. The state changes made by the extrinsic are never stored.
+func (s *Service) DryRun(ext types.Extrinsic, bhash *common.Hash) ([]byte, error) {
+	if bhash == nil {
+		bestBlockHash := s.blockState.BestBlockHash()
+		bhash = &bestBlockHash
+	}
+
+	parent, err := s.blockState.GetBlockByHash(*bhash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block %s: %w", bhash, err)
+	}
+
+	ts, err := s.storageState.TrieState(&parent.Header.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w: for block %s: %s", ErrStateUnavailable, bhash, err)
+	}
+
+	rt, put, err := s.getRuntimeInstance(bhash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get runtime for block %s: %w", bhash, err)
+	}
+	defer put()
+
 	rt.SetContextStorage(ts)
-	return rt.Metadata()
+
+	header, err := types.NewHeader(*bhash, common.Hash{}, common.Hash{}, parent.Header.Number+1, types.NewDigest())
+	if err != nil {
+		return nil, fmt.Errorf("cannot create block header: %w", err)
+	}
+
+	err = rt.InitializeBlock(header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot initialise block: %w", err)
+	}
+
+	ret, err := rt.ApplyExtrinsic(ext)
+	if err != nil {
+		return nil, fmt.Errorf("cannot apply extrinsic: %w", err)
+	}
+
+	result := make([]byte, len(ret))
+	copy(result, ret)
+	return result, nil
 }
 
-// QueryStorage returns the key-value data by block based on `keys` params
-// on every block starting `from` until `to` block, if `to` is not nil
-func (s *Service) QueryStorage(from, to common.Hash, keys ...string) (map[common.Hash]QueryKeyValueChanges, error) {
+// QueryStorage returns the changes of the values of the `keys` params, in chain order,
+// on every block starting `from` until `to` block, or the best block if `to` is empty.
+// The change set of the `from` block holds the values of all the keys, the change set of
+// each following block only holds the keys whose value changed compared to the previous
+// block, and blocks without any change are omitted.
+func (s *Service) QueryStorage(from, to common.Hash, keys ...string) ([]StorageChangeSet, error) {
 	if to.IsEmpty() {
 		to = s.blockState.BestBlockHash()
 	}
 
+	// the range is checked from the block numbers, before building the sub chain
+	fromHeader, err := s.blockState.GetHeader(from)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get header of block %s: %w", from, err)
+	}
+
+	toHeader, err := s.blockState.GetHeader(to)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get header of block %s: %w", to, err)
+	}
+
+	if toHeader.Number >= fromHeader.Number &&
+		toHeader.Number-fromHeader.Number >= MaxQueryStorageRange {
+		return nil, fmt.Errorf("%w: %d blocks from %s to %s, maximum is %d",
+			ErrQueryStorageRangeTooLarge, toHeader.Number-fromHeader.Number+1, from, to, MaxQueryStorageRange)
+	}
+
 	blocksToQuery, err := s.blockState.SubChain(from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	queries := make(map[common.Hash]QueryKeyValueChanges)
-
+	changeSets := make([]StorageChangeSet, 0, len(blocksToQuery))
+	lastValues := make(map[string]*string, len(keys))
 	for _, hash := range blocksToQuery {
-		changes, err := s.tryQueryStorage(hash, keys...)
+		values, err := s.tryQueryStorage(hash, keys...)
 		if err != nil {
 			return nil, err
 		}
 
-		queries[hash] = changes
+		var changes []StorageChange
+		for _, change := range values {
+			lastValue, ok := lastValues[change.Key]
+			if ok && equalStorageValues(lastValue, change.Value) {
+				continue
+			}
+
+			lastValues[change.Key] = change.Value
+			changes = append(changes, change)
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		changeSets = append(changeSets, StorageChangeSet{
+			Block:   hash,
+			Changes: changes,
+		})
+	}
+
+	return changeSets, nil
+}
+
+// QueryStorageAt returns the values of the `keys` params at the given block,
+// or at the best block if the block hash is empty
+func (s *Service) QueryStorageAt(at common.Hash, keys ...string) (StorageChangeSet, error) {
+	if at.IsEmpty() {
+		at = s.blockState.BestBlockHash()
+	}
+
+	changes, err := s.tryQueryStorage(at, keys...)
+	if err != nil {
+		return StorageChangeSet{}, err
 	}
 
-	return queries, nil
+	return StorageChangeSet{
+		Block:   at,
+		Changes: changes,
+	}, nil
 }
 
-// tryQueryStorage will try to get all the `keys` inside the block's current state
-func (s *Service) tryQueryStorage(block common.Hash, keys ...string) (QueryKeyValueChanges, error) {
+// tryQueryStorage will try to get all the `keys` inside the block's current state,
+// in the order of the `keys`
+func (s *Service) tryQueryStorage(block common.Hash, keys ...string) ([]StorageChange, error) {
 	stateRootHash, err := s.storageState.GetStateRootFromBlock(&block)
 	if err != nil {
 		return nil, err
 	}
 
-	changes := make(QueryKeyValueChanges)
-	for _, k := range keys {
+	changes := make([]StorageChange, len(keys))
+	for i, k := range keys {
 		keyBytes, err := common.HexToBytes(k)
 		if err != nil {
 			return nil, err
@@ -605,16 +973,24 @@ func (s *Service) tryQueryStorage(block common.Hash, keys ...string) (QueryKeyVa
 			return nil, err
 		}
 
-		if storedData == nil {
-			continue
+		changes[i].Key = k
+		if storedData != nil {
+			value := common.BytesToHex(storedData)
+			changes[i].Value = &value
 		}
-
-		changes[k] = common.BytesToHex(storedData)
 	}
 
 	return changes, nil
 }
 
+func equalStorageValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
 // GetReadProofAt will return an array with the proofs for the keys passed as params
 // based on the block hash passed as param as well, if block hash is nil then the current state will take place
 func (s *Service) GetReadProofAt(block common.Hash, keys [][]byte) (
@@ -635,3 +1011,24 @@ func (s *Service) GetReadProofAt(block common.Hash, keys [][]byte) (
 
 	return block, proofForKeys, nil
 }
+
+// GetChildReadProofAt will return an array with the proofs of the child trie root and of the keys
+// in the child trie passed as params based on the block hash also passed as param.
+func (s *Service) GetChildReadProofAt(block common.Hash, keyToChild []byte, keys [][]byte) (
+	hash common.Hash, proofForKeys [][]byte, err error) {
+	if block.IsEmpty() {
+		block = s.blockState.BestBlockHash()
+	}
+
+	stateRoot, err := s.blockState.GetBlockStateRoot(block)
+	if err != nil {
+		return hash, nil, err
+	}
+
+	proofForKeys, err = s.storageState.GenerateChildTrieProof(stateRoot, keyToChild, keys)
+	if err != nil {
+		return hash, nil, err
+	}
+
+	return block, proofForKeys, nil
+}
@@ -569,6 +569,7 @@ func TestService_HandleSubmittedExtrinsic(t *testing.T) {
 
 	net := NewMockNetwork(ctrl)
 	net.EXPECT().GossipMessage(gomock.AssignableToTypeOf(new(network.TransactionMessage)))
+	net.EXPECT().Peers()
 	cfg.Network = net
 	s := NewTestService(t, cfg)
 
@@ -702,7 +703,7 @@ func TestService_HandleCodeSubstitutes(t *testing.T) {
 	ts, err := rtstorage.NewTrieState(trie.NewEmptyTrie())
 	require.NoError(t, err)
 
-	err = s.handleCodeSubstitution(blockHash, ts, wasmer.NewInstance)
+	err = s.handleCodeSubstitution(blockHash, ts, wasmer.NewRuntimeInstance)
 	require.NoError(t, err)
 	codSub := s.codeSubstitutedState.LoadCodeSubstitutedBlockHash()
 	require.Equal(t, blockHash, codSub)
@@ -731,7 +732,7 @@ func TestService_HandleRuntimeChangesAfterCodeSubstitutes(t *testing.T) {
 	ts, err := rtstorage.NewTrieState(trie.NewEmptyTrie())
 	require.NoError(t, err)
 
-	err = s.handleCodeSubstitution(blockHash, ts, wasmer.NewInstance)
+	err = s.handleCodeSubstitution(blockHash, ts, wasmer.NewRuntimeInstance)
 	require.NoError(t, err)
 	require.Equal(t, codeHashBefore, parentRt.GetCodeHash()) // codeHash should remain unchanged after code substitute
 
@@ -773,7 +774,7 @@ func TestTryQueryStore_WhenThereIsDataToRetrieve(t *testing.T) {
 
 	require.NoError(t, err)
 
-	err = s.storageState.StoreTrie(storageStateTrie, header)
+	err = s.storageState.StoreTrie(storageStateTrie, header, trie.V0)
 	require.NoError(t, err)
 
 	testBlock := &types.Block{
@@ -791,7 +792,8 @@ func TestTryQueryStore_WhenThereIsDataToRetrieve(t *testing.T) {
 	changes, err := s.tryQueryStorage(blockhash, keys...)
 	require.NoError(t, err)
 
-	require.Equal(t, changes[hexKey], common.BytesToHex(testValue))
+	value := common.BytesToHex(testValue)
+	require.Equal(t, []StorageChange{{Key: hexKey, Value: &value}}, changes)
 }
 
 func TestTryQueryStore_WhenDoesNotHaveDataToRetrieve(t *testing.T) {
@@ -803,7 +805,7 @@ func TestTryQueryStore_WhenDoesNotHaveDataToRetrieve(t *testing.T) {
 	header, err := types.NewHeader(s.blockState.GenesisHash(), storageStateTrie.MustRoot(), common.Hash{}, 1, digest)
 	require.NoError(t, err)
 
-	err = s.storageState.StoreTrie(storageStateTrie, header)
+	err = s.storageState.StoreTrie(storageStateTrie, header, trie.V0)
 	require.NoError(t, err)
 
 	testBlock := &types.Block{
@@ -822,7 +824,7 @@ func TestTryQueryStore_WhenDoesNotHaveDataToRetrieve(t *testing.T) {
 	changes, err := s.tryQueryStorage(blockhash, keys...)
 	require.NoError(t, err)
 
-	require.Empty(t, changes)
+	require.Equal(t, []StorageChange{{Key: hexKey}}, changes)
 }
 
 func TestTryQueryState_WhenDoesNotHaveStateRoot(t *testing.T) {
@@ -879,31 +881,60 @@ func TestQueryStorate_WhenBlocksHasData(t *testing.T) {
 	from := firstBlock.Header.Hash()
 	data, err := s.QueryStorage(from, common.Hash{}, keys...)
 	require.NoError(t, err)
-	require.Len(t, data, 3)
 
-	require.Equal(t, data[firstBlock.Header.Hash()], QueryKeyValueChanges(
-		map[string]string{
-			common.BytesToHex(firstKey): common.BytesToHex(firstValue),
+	// each block state only holds the key set in that block, so the keys
+	// of the previous block are reported as removed
+	firstHexValue := common.BytesToHex(firstValue)
+	secondHexValue := common.BytesToHex(secondValue)
+	thirdHexValue := common.BytesToHex(thirdValue)
+	require.Equal(t, []StorageChangeSet{
+		{
+			Block: firstBlock.Header.Hash(),
+			Changes: []StorageChange{
+				{Key: common.BytesToHex(firstKey), Value: &firstHexValue},
+				{Key: common.BytesToHex(secondKey)},
+				{Key: common.BytesToHex(thirdKey)},
+			},
+		},
+		{
+			Block: secondBlock.Header.Hash(),
+			Changes: []StorageChange{
+				{Key: common.BytesToHex(firstKey)},
+				{Key: common.BytesToHex(secondKey), Value: &secondHexValue},
+			},
 		},
-	))
+		{
+			Block: thirdBlock.Header.Hash(),
+			Changes: []StorageChange{
+				{Key: common.BytesToHex(secondKey)},
+				{Key: common.BytesToHex(thirdKey), Value: &thirdHexValue},
+			},
+		},
+	}, data)
 
 	from = secondBlock.Header.Hash()
 	to := thirdBlock.Header.Hash()
 
 	data, err = s.QueryStorage(from, to, keys...)
 	require.NoError(t, err)
-	require.Len(t, data, 2)
 
-	require.Equal(t, data[secondBlock.Header.Hash()], QueryKeyValueChanges(
-		map[string]string{
-			common.BytesToHex(secondKey): common.BytesToHex(secondValue),
+	require.Equal(t, []StorageChangeSet{
+		{
+			Block: secondBlock.Header.Hash(),
+			Changes: []StorageChange{
+				{Key: common.BytesToHex(firstKey)},
+				{Key: common.BytesToHex(secondKey), Value: &secondHexValue},
+				{Key: common.BytesToHex(thirdKey)},
+			},
 		},
-	))
-	require.Equal(t, data[thirdBlock.Header.Hash()], QueryKeyValueChanges(
-		map[string]string{
-			common.BytesToHex(thirdKey): common.BytesToHex(thirdValue),
+		{
+			Block: thirdBlock.Header.Hash(),
+			Changes: []StorageChange{
+				{Key: common.BytesToHex(secondKey)},
+				{Key: common.BytesToHex(thirdKey), Value: &thirdHexValue},
+			},
 		},
-	))
+	}, data)
 }
 
 func createNewBlockAndStoreDataAtBlock(t *testing.T, s *Service,
@@ -919,7 +950,7 @@ func createNewBlockAndStoreDataAtBlock(t *testing.T, s *Service,
 	header, err := types.NewHeader(parentHash, storageStateTrie.MustRoot(), common.Hash{}, number, digest)
 	require.NoError(t, err)
 
-	err = s.storageState.StoreTrie(storageStateTrie, header)
+	err = s.storageState.StoreTrie(storageStateTrie, header, trie.V0)
 	require.NoError(t, err)
 
 	testBlock := &types.Block{
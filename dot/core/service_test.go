@@ -30,6 +30,7 @@
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -177,7 +178,7 @@ func Test_Service_StorageRoot(t *testing.T) {
 
 func Test_Service_handleCodeSubstitution(t *testing.T) {
 	t.Parallel()
-	newTestInstance := func(code []byte, cfg *wasmer.Config) (*wasmer.Instance, error) {
+	newTestInstance := func(code []byte, cfg runtime.InstanceConfig) (runtime.Instance, error) {
 		return &wasmer.Instance{}, nil
 	}
 
@@ -226,6 +227,7 @@ func Test_Service_handleCodeSubstitution(t *testing.T) {
 		runtimeMock.On("Keystore").Return(&keystore.GlobalKeystore{})
 		runtimeMock.On("NodeStorage").Return(runtime.NodeStorage{})
 		runtimeMock.On("NetworkService").Return(new(runtime.TestRuntimeNetwork))
+		runtimeMock.On("OffchainHTTPDisabled").Return(false)
 		runtimeMock.On("Validator").Return(true)
 
 		ctrl := gomock.NewController(t)
@@ -253,6 +255,7 @@ func Test_Service_handleCodeSubstitution(t *testing.T) {
 		runtimeMock.On("Keystore").Return(&keystore.GlobalKeystore{})
 		runtimeMock.On("NodeStorage").Return(runtime.NodeStorage{})
 		runtimeMock.On("NetworkService").Return(new(runtime.TestRuntimeNetwork))
+		runtimeMock.On("OffchainHTTPDisabled").Return(false)
 		runtimeMock.On("Validator").Return(true)
 
 		ctrl := gomock.NewController(t)
@@ -288,6 +291,24 @@ func Test_Service_handleBlock(t *testing.T) {
 		execTest(t, service, nil, nil, ErrNilBlockHandlerParameter)
 	})
 
+	t.Run("get runtime error", func(t *testing.T) {
+		t.Parallel()
+		emptyTrie := trie.NewEmptyTrie()
+		trieState, err := rtstorage.NewTrieState(emptyTrie)
+		require.NoError(t, err)
+
+		testHeader := types.NewEmptyHeader()
+		block := types.NewBlock(*testHeader, *types.NewBody([]types.Extrinsic{[]byte{21}}))
+		block.Header.Number = 21
+
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(nil, errTestDummyError)
+
+		service := &Service{blockState: mockBlockState}
+		execTest(t, service, &block, trieState, errTestDummyError)
+	})
+
 	t.Run("storeTrie error", func(t *testing.T) {
 		t.Parallel()
 		emptyTrie := trie.NewEmptyTrie()
@@ -299,10 +320,17 @@ func Test_Service_handleBlock(t *testing.T) {
 		block.Header.Number = 21
 
 		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("StateVersion").Return(trie.V0, nil)
 		mockStorageState := NewMockStorageState(ctrl)
-		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header).Return(errTestDummyError)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header, trie.V0).Return(errTestDummyError)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(runtimeMock, nil)
 
-		service := &Service{storageState: mockStorageState}
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
 		execTest(t, service, &block, trieState, errTestDummyError)
 	})
 
@@ -317,9 +345,12 @@ func Test_Service_handleBlock(t *testing.T) {
 		block.Header.Number = 21
 
 		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("StateVersion").Return(trie.V0, nil)
 		mockStorageState := NewMockStorageState(ctrl)
-		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header).Return(nil)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header, trie.V0).Return(nil)
 		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(runtimeMock, nil)
 		mockBlockState.EXPECT().AddBlock(&block).Return(errTestDummyError)
 
 		service := &Service{
@@ -340,9 +371,12 @@ func Test_Service_handleBlock(t *testing.T) {
 		block.Header.Number = 21
 
 		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("StateVersion").Return(trie.V0, nil)
 		mockStorageState := NewMockStorageState(ctrl)
-		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header).Return(nil)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header, trie.V0).Return(nil)
 		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(runtimeMock, nil)
 		mockBlockState.EXPECT().AddBlock(&block).Return(blocktree.ErrParentNotFound)
 
 		service := &Service{
@@ -352,7 +386,7 @@ func Test_Service_handleBlock(t *testing.T) {
 		execTest(t, service, &block, trieState, blocktree.ErrParentNotFound)
 	})
 
-	t.Run("addBlock error continue", func(t *testing.T) {
+	t.Run("handle runtime changes error", func(t *testing.T) {
 		t.Parallel()
 		emptyTrie := trie.NewEmptyTrie()
 		trieState, err := rtstorage.NewTrieState(emptyTrie)
@@ -363,11 +397,15 @@ func Test_Service_handleBlock(t *testing.T) {
 		block.Header.Number = 21
 
 		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("StateVersion").Return(trie.V0, nil)
 		mockStorageState := NewMockStorageState(ctrl)
-		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header).Return(nil)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header, trie.V0).Return(nil)
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().AddBlock(&block).Return(blocktree.ErrBlockExists)
-		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(nil, errTestDummyError)
+		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(runtimeMock, nil)
+		mockBlockState.EXPECT().HandleRuntimeChanges(trieState, runtimeMock, block.Header.Hash()).
+			Return(errTestDummyError)
 
 		service := &Service{
 			storageState: mockStorageState,
@@ -376,7 +414,7 @@ func Test_Service_handleBlock(t *testing.T) {
 		execTest(t, service, &block, trieState, errTestDummyError)
 	})
 
-	t.Run("handle runtime changes error", func(t *testing.T) {
+	t.Run("code substitution ok", func(t *testing.T) {
 		t.Parallel()
 		emptyTrie := trie.NewEmptyTrie()
 		trieState, err := rtstorage.NewTrieState(emptyTrie)
@@ -388,26 +426,28 @@ func Test_Service_handleBlock(t *testing.T) {
 
 		ctrl := gomock.NewController(t)
 		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("StateVersion").Return(trie.V0, nil)
 		mockStorageState := NewMockStorageState(ctrl)
-		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header).Return(nil)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header, trie.V0).Return(nil)
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().AddBlock(&block).Return(blocktree.ErrBlockExists)
 		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(runtimeMock, nil)
-		mockBlockState.EXPECT().HandleRuntimeChanges(trieState, runtimeMock, block.Header.Hash()).
-			Return(errTestDummyError)
+		mockBlockState.EXPECT().HandleRuntimeChanges(trieState, runtimeMock, block.Header.Hash()).Return(nil)
 
 		service := &Service{
 			storageState: mockStorageState,
 			blockState:   mockBlockState,
+			ctx:          context.Background(),
 		}
-		execTest(t, service, &block, trieState, errTestDummyError)
+		execTest(t, service, &block, trieState, nil)
 	})
 
-	t.Run("code substitution ok", func(t *testing.T) {
+	t.Run("offchain index changes", func(t *testing.T) {
 		t.Parallel()
 		emptyTrie := trie.NewEmptyTrie()
 		trieState, err := rtstorage.NewTrieState(emptyTrie)
 		require.NoError(t, err)
+		trieState.SetOffchainIndex([]byte("key"), []byte("value"))
 
 		testHeader := types.NewEmptyHeader()
 		block := types.NewBlock(*testHeader, *types.NewBody([]types.Extrinsic{[]byte{21}}))
@@ -415,10 +455,44 @@ func Test_Service_handleBlock(t *testing.T) {
 
 		ctrl := gomock.NewController(t)
 		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("StateVersion").Return(trie.V0, nil)
 		mockStorageState := NewMockStorageState(ctrl)
-		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header).Return(nil)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header, trie.V0).Return(nil)
 		mockBlockState := NewMockBlockState(ctrl)
-		mockBlockState.EXPECT().AddBlock(&block).Return(blocktree.ErrBlockExists)
+		mockBlockState.EXPECT().AddBlock(&block).Return(nil)
+		mockBlockState.EXPECT().SetOffchainIndexChanges(block.Header.Hash(), trieState.OffchainIndexChanges()).
+			Return(nil)
+		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(runtimeMock, nil)
+		mockBlockState.EXPECT().HandleRuntimeChanges(trieState, runtimeMock, block.Header.Hash()).Return(nil)
+
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+			ctx:          context.Background(),
+		}
+		execTest(t, service, &block, trieState, nil)
+	})
+
+	t.Run("transaction index operations", func(t *testing.T) {
+		t.Parallel()
+		emptyTrie := trie.NewEmptyTrie()
+		trieState, err := rtstorage.NewTrieState(emptyTrie)
+		require.NoError(t, err)
+		trieState.IndexTransaction(0, 1, common.Hash{1})
+
+		testHeader := types.NewEmptyHeader()
+		block := types.NewBlock(*testHeader, *types.NewBody([]types.Extrinsic{[]byte{21}}))
+		block.Header.Number = 21
+
+		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("StateVersion").Return(trie.V0, nil)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header, trie.V0).Return(nil)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().AddBlock(&block).Return(nil)
+		mockBlockState.EXPECT().SetTransactionIndex(&block, trieState.TransactionIndexOperations(),
+			uint(runtime.DefaultTransactionStoragePeriod)).Return(nil)
 		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(runtimeMock, nil)
 		mockBlockState.EXPECT().HandleRuntimeChanges(trieState, runtimeMock, block.Header.Hash()).Return(nil)
 
@@ -431,8 +505,25 @@ func Test_Service_handleBlock(t *testing.T) {
 	})
 }
 
+func Test_transactionStoragePeriod(t *testing.T) {
+	t.Parallel()
+
+	trieState, err := rtstorage.NewTrieState(trie.NewEmptyTrie())
+	require.NoError(t, err)
+
+	period, err := transactionStoragePeriod(trieState)
+	require.NoError(t, err)
+	assert.Equal(t, uint(runtime.DefaultTransactionStoragePeriod), period)
+
+	trieState.Set(runtime.TransactionStoragePeriodKey(), []byte{10, 0, 0, 0})
+	period, err = transactionStoragePeriod(trieState)
+	require.NoError(t, err)
+	assert.Equal(t, uint(10), period)
+}
+
 func Test_Service_HandleBlockProduced(t *testing.T) {
 	t.Parallel()
+
 	execTest := func(t *testing.T, s *Service, block *types.Block, trieState *rtstorage.TrieState, expErr error) {
 		err := s.HandleBlockProduced(block, trieState)
 		assert.ErrorIs(t, err, expErr)
@@ -475,8 +566,9 @@ func Test_Service_HandleBlockProduced(t *testing.T) {
 
 		ctrl := gomock.NewController(t)
 		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("StateVersion").Return(trie.V0, nil)
 		mockStorageState := NewMockStorageState(ctrl)
-		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header).Return(nil)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header, trie.V0).Return(nil)
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().AddBlock(&block).Return(blocktree.ErrBlockExists)
 		mockBlockState.EXPECT().GetRuntime(&block.Header.ParentHash).Return(runtimeMock, nil)
@@ -521,6 +613,11 @@ func Test_Service_maintainTransactionPool(t *testing.T) {
 		mockTxnState := NewMockTransactionState(ctrl)
 		mockTxnState.EXPECT().RemoveExtrinsic(types.Extrinsic{21}).Times(2)
 		mockTxnState.EXPECT().PendingInPool().Return([]*transaction.ValidTransaction{vt})
+		blockHash := block.Header.Hash()
+		mockTxnState.EXPECT().NotifyStatus(types.Extrinsic{21},
+			transaction.StatusNotification{Status: transaction.InBlock, Hash: &blockHash})
+		mockTxnState.EXPECT().NotifyStatus(types.Extrinsic{21},
+			transaction.StatusNotification{Status: transaction.Invalid})
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().GetRuntime(nil).Return(runtimeMock, nil)
 		service := &Service{
@@ -557,6 +654,9 @@ func Test_Service_maintainTransactionPool(t *testing.T) {
 		mockTxnState := NewMockTransactionState(ctrl)
 		mockTxnState.EXPECT().RemoveExtrinsic(types.Extrinsic{21})
 		mockTxnState.EXPECT().PendingInPool().Return([]*transaction.ValidTransaction{vt})
+		blockHash := block.Header.Hash()
+		mockTxnState.EXPECT().NotifyStatus(types.Extrinsic{21},
+			transaction.StatusNotification{Status: transaction.InBlock, Hash: &blockHash})
 		mockTxnState.EXPECT().Push(tx).Return(common.Hash{}, nil)
 		mockTxnState.EXPECT().RemoveExtrinsicFromPool(types.Extrinsic{21})
 		mockBlockStateOk := NewMockBlockState(ctrl)
@@ -643,13 +743,18 @@ func Test_Service_handleBlocksAsync(t *testing.T) {
 		runtimeMock := new(mocksruntime.Instance)
 		runtimeMock.On("ValidateTransaction", types.Extrinsic{21}).Return(nil, errTestDummyError)
 		mockBlockState := NewMockBlockState(ctrl)
-		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{}).Times(2)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{}).Times(3)
 		mockBlockState.EXPECT().HighestCommonAncestor(common.Hash{}, block.Header.Hash()).
 			Return(common.Hash{}, errTestDummyError)
 		mockBlockState.EXPECT().GetRuntime(nil).Return(runtimeMock, nil)
 		mockTxnStateErr := NewMockTransactionState(ctrl)
 		mockTxnStateErr.EXPECT().RemoveExtrinsic(types.Extrinsic{21}).Times(2)
 		mockTxnStateErr.EXPECT().PendingInPool().Return([]*transaction.ValidTransaction{vt})
+		blockHash := block.Header.Hash()
+		mockTxnStateErr.EXPECT().NotifyStatus(types.Extrinsic{21},
+			transaction.StatusNotification{Status: transaction.InBlock, Hash: &blockHash})
+		mockTxnStateErr.EXPECT().NotifyStatus(types.Extrinsic{21},
+			transaction.StatusNotification{Status: transaction.Invalid})
 		blockAddChan := make(chan *types.Block)
 		go func() {
 			blockAddChan <- &block
@@ -779,9 +884,14 @@ func TestService_handleChainReorg(t *testing.T) {
 		mockBlockState.EXPECT().GetBlockBody(testCurrentHash).Return(nil, errDummyErr)
 		mockBlockState.EXPECT().GetBlockBody(testAncestorHash).Return(body, nil)
 		runtimeMockErr.On("ValidateTransaction", externExt).Return(nil, errTestDummyError)
+		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().NotifyStatus(ext,
+			transaction.StatusNotification{Status: transaction.Retracted, Hash: &testAncestorHash})
+		mockTxnState.EXPECT().NotifyStatus(ext, transaction.StatusNotification{Status: transaction.Invalid})
 
 		service := &Service{
-			blockState: mockBlockState,
+			blockState:       mockBlockState,
+			transactionState: mockTxnState,
 		}
 		execTest(t, service, testPrevHash, testCurrentHash, nil)
 	})
@@ -800,6 +910,8 @@ func TestService_handleChainReorg(t *testing.T) {
 		runtimeMockOk.On("ValidateTransaction", externExt).
 			Return(testValidity, nil)
 		mockTxnStateOk := NewMockTransactionState(ctrl)
+		mockTxnStateOk.EXPECT().NotifyStatus(ext,
+			transaction.StatusNotification{Status: transaction.Retracted, Hash: &testAncestorHash})
 		mockTxnStateOk.EXPECT().AddToPool(vtx).Return(common.Hash{})
 
 		service := &Service{
@@ -1154,6 +1266,11 @@ func TestServiceHandleSubmittedExtrinsic(t *testing.T) {
 		mockTxnState.EXPECT().AddToPool(transaction.NewValidTransaction(ext, &transaction.Validity{Propagate: true}))
 		mockNetState := NewMockNetwork(ctrl)
 		mockNetState.EXPECT().GossipMessage(&network.TransactionMessage{Extrinsics: []types.Extrinsic{ext}})
+		mockNetState.EXPECT().Peers().Return([]common.PeerInfo{{PeerID: "peer1"}, {PeerID: "peer2"}})
+		mockTxnState.EXPECT().NotifyStatus(ext, transaction.StatusNotification{
+			Status:             transaction.Broadcast,
+			PeersBroadcastedTo: []string{"peer1", "peer2"},
+		})
 		service := &Service{
 			storageState:     mockStorageState,
 			transactionState: mockTxnState,
@@ -1164,6 +1281,47 @@ func TestServiceHandleSubmittedExtrinsic(t *testing.T) {
 	})
 }
 
+func TestService_BroadcastExtrinsic(t *testing.T) {
+	t.Parallel()
+
+	ext := types.Extrinsic{1, 2, 3}
+
+	t.Run("not in pool", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().Exists(ext).Return(false)
+		service := &Service{
+			transactionState: mockTxnState,
+			net:              NewMockNetwork(ctrl),
+		}
+
+		inPool := service.BroadcastExtrinsic(ext)
+		assert.False(t, inPool)
+	})
+
+	t.Run("in pool", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().Exists(ext).Return(true)
+		mockTxnState.EXPECT().NotifyStatus(ext, transaction.StatusNotification{
+			Status:             transaction.Broadcast,
+			PeersBroadcastedTo: []string{"peer1"},
+		})
+		mockNetState := NewMockNetwork(ctrl)
+		mockNetState.EXPECT().GossipMessage(&network.TransactionMessage{Extrinsics: []types.Extrinsic{ext}})
+		mockNetState.EXPECT().Peers().Return([]common.PeerInfo{{PeerID: "peer1"}})
+		service := &Service{
+			transactionState: mockTxnState,
+			net:              mockNetState,
+		}
+
+		inPool := service.BroadcastExtrinsic(ext)
+		assert.True(t, inPool)
+	})
+}
+
 func TestServiceGetMetadata(t *testing.T) {
 	t.Parallel()
 	execTest := func(t *testing.T, s *Service, bhash *common.Hash, exp []byte, expErr error) {
@@ -1229,9 +1387,325 @@ func TestServiceGetMetadata(t *testing.T) {
 	})
 }
 
+func TestService_CallRuntimeAPI(t *testing.T) {
+	t.Parallel()
+	execTest := func(t *testing.T, s *Service, bhash *common.Hash, exp []byte, expErr error, expErrMsg string) {
+		res, err := s.CallRuntimeAPI(bhash, "Core_version", []byte{1})
+		assert.ErrorIs(t, err, expErr)
+		if expErr != nil {
+			assert.EqualError(t, err, expErrMsg)
+		}
+		assert.Equal(t, exp, res)
+	}
+
+	blockHash := common.Hash{1}
+	stateRoot := common.Hash{2}
+
+	t.Run("get state root error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&blockHash).Return(nil, errDummyErr)
+		service := &Service{
+			storageState: mockStorageState,
+		}
+		const expErrMsg = "cannot get state root for block " +
+			"0x0100000000000000000000000000000000000000000000000000000000000000: dummy error for testing"
+		execTest(t, service, &blockHash, nil, errDummyErr, expErrMsg)
+	})
+
+	t.Run("trie state error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(blockHash)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&blockHash).Return(&stateRoot, nil)
+		mockStorageState.EXPECT().TrieState(&stateRoot).Return(nil, errDummyErr)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+		const expErrMsg = "state is not available: for block " +
+			"0x0100000000000000000000000000000000000000000000000000000000000000: dummy error for testing"
+		execTest(t, service, nil, nil, ErrStateUnavailable, expErrMsg)
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&blockHash).Return(&stateRoot, nil)
+		mockStorageState.EXPECT().TrieState(&stateRoot).Return(&rtstorage.TrieState{}, nil)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", &rtstorage.TrieState{})
+		runtimeMock.On("Exec", "Core_version", []byte{1}).Return(nil, runtime.ErrExportFunctionNotFound)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(&blockHash).Return(runtimeMock, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+		const expErrMsg = "cannot execute Core_version: could not find exported function"
+		execTest(t, service, &blockHash, nil, runtime.ErrExportFunctionNotFound, expErrMsg)
+	})
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&blockHash).Return(&stateRoot, nil)
+		mockStorageState.EXPECT().TrieState(&stateRoot).Return(&rtstorage.TrieState{}, nil)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", &rtstorage.TrieState{})
+		runtimeMock.On("Exec", "Core_version", []byte{1}).Return([]byte{1, 2, 3}, nil)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetRuntime(&blockHash).Return(runtimeMock, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+		execTest(t, service, &blockHash, []byte{1, 2, 3}, nil, "")
+	})
+}
+
+func TestService_TraceBlock(t *testing.T) {
+	t.Parallel()
+
+	parentHash := common.Hash{1}
+	stateRoot := common.Hash{2}
+	block := &types.Block{
+		Header: types.Header{
+			ParentHash: parentHash,
+			Number:     2,
+			Digest:     types.NewDigest(),
+		},
+		Body: types.Body{},
+	}
+	blockHash := block.Header.Hash()
+
+	t.Run("get block error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetBlockByHash(blockHash).Return(nil, errDummyErr)
+		service := &Service{
+			blockState: mockBlockState,
+		}
+
+		header, events, err := service.TraceBlock(blockHash)
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.Nil(t, header)
+		assert.Nil(t, events)
+	})
+
+	t.Run("execute block error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&parentHash).Return(&stateRoot, nil)
+		mockStorageState.EXPECT().TrieState(&stateRoot).Return(&rtstorage.TrieState{}, nil)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", mock.AnythingOfType("*storage.TracingTrieState"))
+		runtimeMock.On("ExecuteBlock", block).Return(nil, errDummyErr)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetBlockByHash(blockHash).Return(block, nil)
+		mockBlockState.EXPECT().GetRuntime(&parentHash).Return(runtimeMock, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+
+		header, events, err := service.TraceBlock(blockHash)
+		assert.ErrorIs(t, err, ErrBlockExecution)
+		assert.Nil(t, header)
+		assert.Nil(t, events)
+	})
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		ts, err := rtstorage.NewTrieState(nil)
+		require.NoError(t, err)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&parentHash).Return(&stateRoot, nil)
+		mockStorageState.EXPECT().TrieState(&stateRoot).Return(ts, nil)
+
+		var storage runtime.Storage
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", mock.AnythingOfType("*storage.TracingTrieState")).
+			Run(func(args mock.Arguments) {
+				storage = args.Get(0).(runtime.Storage)
+			})
+		runtimeMock.On("ExecuteBlock", block).
+			Run(func(args mock.Arguments) {
+				storage.Set([]byte("key"), []byte("value"))
+			}).
+			Return(nil, nil)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetBlockByHash(blockHash).Return(block, nil)
+		mockBlockState.EXPECT().GetRuntime(&parentHash).Return(runtimeMock, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+
+		header, events, err := service.TraceBlock(blockHash)
+		require.NoError(t, err)
+		assert.Equal(t, &block.Header, header)
+		expected := []rtstorage.TraceEvent{
+			{Method: rtstorage.TraceMethodPut, Key: []byte("key"), Value: []byte("value")},
+		}
+		assert.Equal(t, expected, events)
+	})
+}
+
+func TestService_DryRun(t *testing.T) {
+	t.Parallel()
+
+	ext := types.Extrinsic{1, 2, 3}
+	parent := &types.Block{
+		Header: types.Header{
+			Number:    1,
+			StateRoot: common.Hash{2},
+			Digest:    types.NewDigest(),
+		},
+	}
+	parentHash := common.Hash{1}
+	header, err := types.NewHeader(parentHash, common.Hash{}, common.Hash{}, 2, types.NewDigest())
+	require.NoError(t, err)
+
+	t.Run("get block error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(parentHash)
+		mockBlockState.EXPECT().GetBlockByHash(parentHash).Return(nil, errDummyErr)
+		service := &Service{
+			blockState: mockBlockState,
+		}
+
+		res, err := service.DryRun(ext, nil)
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.Nil(t, res)
+	})
+
+	t.Run("initialise block error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(&parent.Header.StateRoot).Return(&rtstorage.TrieState{}, nil)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", &rtstorage.TrieState{})
+		runtimeMock.On("InitializeBlock", header).Return(errDummyErr)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetBlockByHash(parentHash).Return(parent, nil)
+		mockBlockState.EXPECT().GetRuntime(&parentHash).Return(runtimeMock, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+
+		res, err := service.DryRun(ext, &parentHash)
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.EqualError(t, err, "cannot initialise block: dummy error for testing")
+		assert.Nil(t, res)
+	})
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(&parent.Header.StateRoot).Return(&rtstorage.TrieState{}, nil)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", &rtstorage.TrieState{})
+		runtimeMock.On("InitializeBlock", header).Return(nil)
+		runtimeMock.On("ApplyExtrinsic", ext).Return([]byte{0, 0}, nil)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetBlockByHash(parentHash).Return(parent, nil)
+		mockBlockState.EXPECT().GetRuntime(&parentHash).Return(runtimeMock, nil)
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+
+		res, err := service.DryRun(ext, &parentHash)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0, 0}, res)
+	})
+}
+
+func TestService_GenerateSessionKeys(t *testing.T) {
+	t.Parallel()
+
+	bestBlockHash := common.Hash{1}
+	stateRoot := common.Hash{2}
+
+	t.Run("get state root error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(bestBlockHash)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&bestBlockHash).Return(nil, errDummyErr)
+		service := &Service{
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
+		}
+
+		keys, err := service.GenerateSessionKeys()
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("generate session keys error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", &rtstorage.TrieState{})
+		runtimeMock.On("GenerateSessionKeys", (*[]byte)(nil)).Return(nil, errDummyErr)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(bestBlockHash)
+		mockBlockState.EXPECT().GetRuntime(&bestBlockHash).Return(runtimeMock, nil)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&bestBlockHash).Return(&stateRoot, nil)
+		mockStorageState.EXPECT().TrieState(&stateRoot).Return(&rtstorage.TrieState{}, nil)
+		service := &Service{
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
+		}
+
+		keys, err := service.GenerateSessionKeys()
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", &rtstorage.TrieState{})
+		runtimeMock.On("GenerateSessionKeys", (*[]byte)(nil)).Return([]byte{1, 2, 3}, nil)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(bestBlockHash)
+		mockBlockState.EXPECT().GetRuntime(&bestBlockHash).Return(runtimeMock, nil)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&bestBlockHash).Return(&stateRoot, nil)
+		mockStorageState.EXPECT().TrieState(&stateRoot).Return(&rtstorage.TrieState{}, nil)
+		service := &Service{
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
+		}
+
+		keys, err := service.GenerateSessionKeys()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3}, keys)
+	})
+}
+
 func TestService_tryQueryStorage(t *testing.T) {
 	t.Parallel()
-	execTest := func(t *testing.T, s *Service, block common.Hash, keys []string, exp QueryKeyValueChanges, expErr error) {
+	execTest := func(t *testing.T, s *Service, block common.Hash, keys []string, exp []StorageChange, expErr error) {
 		res, err := s.tryQueryStorage(block, keys...)
 		assert.ErrorIs(t, err, expErr)
 		if expErr != nil {
@@ -1270,19 +1744,23 @@ func TestService_tryQueryStorage(t *testing.T) {
 		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{}).Return(&common.Hash{}, nil)
 		mockStorageState.EXPECT().GetStorage(&common.Hash{}, common.MustHexToBytes("0x01")).
 			Return([]byte{1, 2, 3}, nil)
-		expChanges := make(QueryKeyValueChanges)
-		expChanges["0x01"] = common.BytesToHex([]byte{1, 2, 3})
+		mockStorageState.EXPECT().GetStorage(&common.Hash{}, common.MustHexToBytes("0x02")).
+			Return(nil, nil)
+		expChanges := []StorageChange{
+			{Key: "0x01", Value: stringPtr("0x010203")},
+			{Key: "0x02"},
+		}
 		service := &Service{
 			storageState: mockStorageState,
 		}
-		execTest(t, service, common.Hash{}, []string{"0x01"}, expChanges, nil)
+		execTest(t, service, common.Hash{}, []string{"0x01", "0x02"}, expChanges, nil)
 	})
 }
 
 func TestService_QueryStorage(t *testing.T) {
 	t.Parallel()
 	execTest := func(t *testing.T, s *Service, from common.Hash, to common.Hash,
-		keys []string, exp map[common.Hash]QueryKeyValueChanges, expErr error) {
+		keys []string, exp []StorageChangeSet, expErr error) {
 		res, err := s.QueryStorage(from, to, keys...)
 		assert.ErrorIs(t, err, expErr)
 		if expErr != nil {
@@ -1291,11 +1769,28 @@ func TestService_QueryStorage(t *testing.T) {
 		assert.Equal(t, exp, res)
 	}
 
+	t.Run("get header error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetHeader(common.Hash{1}).Return(nil, errDummyErr)
+		service := &Service{
+			blockState: mockBlockState,
+		}
+		res, err := service.QueryStorage(common.Hash{1}, common.Hash{2})
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.EqualError(t, err, "cannot get header of block "+
+			"0x0100000000000000000000000000000000000000000000000000000000000000: dummy error for testing")
+		assert.Nil(t, res)
+	})
+
 	t.Run("subchain error", func(t *testing.T) {
 		t.Parallel()
 		ctrl := gomock.NewController(t)
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{2})
+		mockBlockState.EXPECT().GetHeader(common.Hash{1}).Return(&types.Header{Number: 1}, nil)
+		mockBlockState.EXPECT().GetHeader(common.Hash{2}).Return(&types.Header{Number: 2}, nil)
 		mockBlockState.EXPECT().SubChain(common.Hash{1}, common.Hash{2}).Return(nil, errDummyErr)
 		service := &Service{
 			blockState: mockBlockState,
@@ -1303,25 +1798,113 @@ func TestService_QueryStorage(t *testing.T) {
 		execTest(t, service, common.Hash{1}, common.Hash{}, nil, nil, errDummyErr)
 	})
 
+	t.Run("range too large", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetHeader(common.Hash{1}).Return(&types.Header{Number: 1}, nil)
+		mockBlockState.EXPECT().GetHeader(common.Hash{2}).
+			Return(&types.Header{Number: MaxQueryStorageRange + 1}, nil)
+		service := &Service{
+			blockState: mockBlockState,
+		}
+		res, err := service.QueryStorage(common.Hash{1}, common.Hash{2})
+		assert.ErrorIs(t, err, ErrQueryStorageRangeTooLarge)
+		assert.EqualError(t, err, "query storage block range is too large: 1001 blocks from "+
+			"0x0100000000000000000000000000000000000000000000000000000000000000 to "+
+			"0x0200000000000000000000000000000000000000000000000000000000000000, maximum is 1000")
+		assert.Nil(t, res)
+	})
+
 	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{4})
+		mockBlockState.EXPECT().GetHeader(common.Hash{1}).Return(&types.Header{Number: 1}, nil)
+		mockBlockState.EXPECT().GetHeader(common.Hash{4}).Return(&types.Header{Number: 4}, nil)
+		mockBlockState.EXPECT().SubChain(common.Hash{1}, common.Hash{4}).
+			Return([]common.Hash{{1}, {2}, {3}, {4}}, nil)
+		mockStorageState := NewMockStorageState(ctrl)
+		storageValues := map[common.Hash][][]byte{
+			{1}: {{1}, nil},
+			{2}: {{1}, {2}},
+			{3}: {{1}, {2}},
+			{4}: {nil, {3}},
+		}
+		for block, values := range storageValues {
+			block, values := block, values
+			stateRoot := common.Hash{0xff, block[0]}
+			mockStorageState.EXPECT().GetStateRootFromBlock(&block).Return(&stateRoot, nil)
+			mockStorageState.EXPECT().GetStorage(&stateRoot, []byte{0x0a}).Return(values[0], nil)
+			mockStorageState.EXPECT().GetStorage(&stateRoot, []byte{0x0b}).Return(values[1], nil)
+		}
+		expChangeSets := []StorageChangeSet{
+			{
+				Block: common.Hash{1},
+				Changes: []StorageChange{
+					{Key: "0x0a", Value: stringPtr("0x01")},
+					{Key: "0x0b"},
+				},
+			},
+			{
+				Block:   common.Hash{2},
+				Changes: []StorageChange{{Key: "0x0b", Value: stringPtr("0x02")}},
+			},
+			{
+				Block: common.Hash{4},
+				Changes: []StorageChange{
+					{Key: "0x0a"},
+					{Key: "0x0b", Value: stringPtr("0x03")},
+				},
+			},
+		}
+		service := &Service{
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
+		}
+		execTest(t, service, common.Hash{1}, common.Hash{}, []string{"0x0a", "0x0b"}, expChangeSets, nil)
+	})
+}
+
+func TestService_QueryStorageAt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("query storage error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(nil, errDummyErr)
+		service := &Service{
+			storageState: mockStorageState,
+		}
+		res, err := service.QueryStorageAt(common.Hash{1}, "0x01")
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.Equal(t, StorageChangeSet{}, res)
+	})
+
+	t.Run("best block", func(t *testing.T) {
 		t.Parallel()
 		ctrl := gomock.NewController(t)
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{2})
-		mockBlockState.EXPECT().SubChain(common.Hash{1}, common.Hash{2}).Return([]common.Hash{{0x01}}, nil)
 		mockStorageState := NewMockStorageState(ctrl)
-		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{0x01}).Return(&common.Hash{}, nil)
-		mockStorageState.EXPECT().GetStorage(&common.Hash{}, common.MustHexToBytes("0x01")).
-			Return([]byte{1, 2, 3}, nil)
-		expChanges := make(QueryKeyValueChanges)
-		expChanges["0x01"] = common.BytesToHex([]byte{1, 2, 3})
-		expQueries := make(map[common.Hash]QueryKeyValueChanges)
-		expQueries[common.Hash{0x01}] = expChanges
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{2}).Return(&common.Hash{3}, nil)
+		mockStorageState.EXPECT().GetStorage(&common.Hash{3}, []byte{1}).Return([]byte{4}, nil)
+		mockStorageState.EXPECT().GetStorage(&common.Hash{3}, []byte{2}).Return(nil, nil)
 		service := &Service{
 			blockState:   mockBlockState,
 			storageState: mockStorageState,
 		}
-		execTest(t, service, common.Hash{1}, common.Hash{}, []string{"0x01"}, expQueries, nil)
+		res, err := service.QueryStorageAt(common.Hash{}, "0x01", "0x02")
+		assert.NoError(t, err)
+		assert.Equal(t, StorageChangeSet{
+			Block: common.Hash{2},
+			Changes: []StorageChange{
+				{Key: "0x01", Value: stringPtr("0x04")},
+				{Key: "0x02"},
+			},
+		}, res)
 	})
 }
 
@@ -1382,3 +1965,60 @@ func TestService_GetReadProofAt(t *testing.T) {
 		execTest(t, service, common.Hash{}, [][]byte{{1}}, common.Hash{2}, [][]byte{{2}}, nil)
 	})
 }
+
+func TestService_GetChildReadProofAt(t *testing.T) {
+	t.Parallel()
+	execTest := func(t *testing.T, s *Service, block common.Hash, keyToChild []byte, keys [][]byte,
+		expHash common.Hash, expProofForKeys [][]byte, expErr error) {
+		resHash, resProofForKeys, err := s.GetChildReadProofAt(block, keyToChild, keys)
+		assert.ErrorIs(t, err, expErr)
+		if expErr != nil {
+			assert.EqualError(t, err, expErr.Error())
+		}
+		assert.Equal(t, expHash, resHash)
+		assert.Equal(t, expProofForKeys, resProofForKeys)
+	}
+
+	t.Run("get block state root error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetBlockStateRoot(common.Hash{2}).Return(common.Hash{}, errDummyErr)
+		service := &Service{
+			blockState: mockBlockState,
+		}
+		execTest(t, service, common.Hash{2}, []byte{9}, nil, common.Hash{}, nil, errDummyErr)
+	})
+
+	t.Run("generate child trie proof error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{2})
+		mockBlockState.EXPECT().GetBlockStateRoot(common.Hash{2}).Return(common.Hash{3}, nil)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GenerateChildTrieProof(common.Hash{3}, []byte{9}, [][]byte{{1}}).
+			Return(nil, errDummyErr)
+		service := &Service{
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
+		}
+		execTest(t, service, common.Hash{}, []byte{9}, [][]byte{{1}}, common.Hash{}, nil, errDummyErr)
+	})
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{2})
+		mockBlockState.EXPECT().GetBlockStateRoot(common.Hash{2}).Return(common.Hash{3}, nil)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().GenerateChildTrieProof(common.Hash{3}, []byte{9}, [][]byte{{1}}).
+			Return([][]byte{{2}, {3}}, nil)
+		service := &Service{
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
+		}
+		execTest(t, service, common.Hash{}, []byte{9}, [][]byte{{1}}, common.Hash{2}, [][]byte{{2}, {3}}, nil)
+	})
+}
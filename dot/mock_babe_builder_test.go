@@ -7,6 +7,8 @@
 import (
 	reflect "reflect"
 
+	babe "github.com/ChainSafe/gossamer/lib/babe"
+	sr25519 "github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -33,6 +35,21 @@ func (m *MockServiceIFace) EXPECT() *MockServiceIFaceMockRecorder {
 	return m.recorder
 }
 
+// EpochAuthorship mocks base method.
+func (m *MockServiceIFace) EpochAuthorship(arg0 []*sr25519.Keypair) (map[string]*babe.EpochAuthorship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EpochAuthorship", arg0)
+	ret0, _ := ret[0].(map[string]*babe.EpochAuthorship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EpochAuthorship indicates an expected call of EpochAuthorship.
+func (mr *MockServiceIFaceMockRecorder) EpochAuthorship(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EpochAuthorship", reflect.TypeOf((*MockServiceIFace)(nil).EpochAuthorship), arg0)
+}
+
 // EpochLength mocks base method.
 func (m *MockServiceIFace) EpochLength() uint64 {
 	m.ctrl.T.Helper()
@@ -183,18 +183,18 @@ func (mr *MocknodeBuilderIfaceMockRecorder) createStateService(config interface{
 }
 
 // createSystemService mocks base method.
-func (m *MocknodeBuilderIface) createSystemService(cfg *types.SystemInfo, stateSrvc *state.Service) (*system.Service, error) {
+func (m *MocknodeBuilderIface) createSystemService(cfg *types.SystemInfo, logFilter *system.LogFilter, stateSrvc *state.Service) (*system.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "createSystemService", cfg, stateSrvc)
+	ret := m.ctrl.Call(m, "createSystemService", cfg, logFilter, stateSrvc)
 	ret0, _ := ret[0].(*system.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // createSystemService indicates an expected call of createSystemService.
-func (mr *MocknodeBuilderIfaceMockRecorder) createSystemService(cfg, stateSrvc interface{}) *gomock.Call {
+func (mr *MocknodeBuilderIfaceMockRecorder) createSystemService(cfg, logFilter, stateSrvc interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createSystemService", reflect.TypeOf((*MocknodeBuilderIface)(nil).createSystemService), cfg, stateSrvc)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createSystemService", reflect.TypeOf((*MocknodeBuilderIface)(nil).createSystemService), cfg, logFilter, stateSrvc)
 }
 
 // initNode mocks base method.
@@ -47,6 +47,7 @@ type NotificationsMessage interface {
 	RequestedDataReceipt       = byte(4)
 	RequestedDataMessageQueue  = byte(8)
 	RequestedDataJustification = byte(16)
+	RequestedDataIndexedBody   = byte(32)
 )
 
 var _ Message = &BlockRequestMessage{}
@@ -301,6 +302,10 @@ func blockDataToProtobuf(bd *types.BlockData) (*pb.BlockData, error) {
 		}
 	}
 
+	if bd.IndexedBody != nil {
+		p.IndexedBody = *bd.IndexedBody
+	}
+
 	return p, nil
 }
 
@@ -352,6 +357,10 @@ func protobufToBlockData(pbd *pb.BlockData) (*types.BlockData, error) {
 		bd.Justification = &[]byte{}
 	}
 
+	if pbd.IndexedBody != nil {
+		bd.IndexedBody = &pbd.IndexedBody
+	}
+
 	return bd, nil
 }
 
@@ -266,6 +266,28 @@ func TestEncodeBlockResponseMessage_WithAll(t *testing.T) {
 	require.Equal(t, bm, act)
 }
 
+func TestEncodeBlockResponseMessage_WithIndexedBody(t *testing.T) {
+	t.Parallel()
+
+	exp := common.MustHexToBytes("0x0a290a2000000000000000000000000000000000000000000000000000000000000000004a0201024a0103") //nolint:lll
+
+	bm := &BlockResponseMessage{
+		BlockData: []*types.BlockData{{
+			Hash:        common.Hash{},
+			IndexedBody: &[][]byte{{1, 2}, {3}},
+		}},
+	}
+
+	enc, err := bm.Encode()
+	require.NoError(t, err)
+	require.Equal(t, exp, enc)
+
+	act := new(BlockResponseMessage)
+	err = act.Decode(enc)
+	require.NoError(t, err)
+	require.Equal(t, bm, act)
+}
+
 func TestEncodeBlockAnnounceMessage(t *testing.T) {
 	/* this value is a concatenation of:
 	 *  ParentHash: Hash: 0x4545454545454545454545454545454545454545454545454545454545454545
@@ -45,6 +45,7 @@ type Node struct {
 	wg              sync.WaitGroup
 	started         chan struct{}
 	metricsServer   *metrics.Server
+	logFilter       *system.LogFilter
 }
 
 //go:generate mockgen -source=node.go -destination=mock_node_builder_test.go -package=$GOPACKAGE
@@ -68,7 +69,8 @@ type nodeBuilderIface interface {
 		cs *core.Service, net *network.Service, telemetryMailer telemetry.Client) (*dotsync.Service, error)
 	createBABEService(cfg *Config, st *state.Service, ks keystore.Keystore, cs *core.Service,
 		telemetryMailer telemetry.Client) (babe.ServiceIFace, error)
-	createSystemService(cfg *types.SystemInfo, stateSrvc *state.Service) (*system.Service, error)
+	createSystemService(cfg *types.SystemInfo, logFilter *system.LogFilter,
+		stateSrvc *state.Service) (*system.Service, error)
 	createRPCService(params rpcServiceSettings) (*rpc.HTTPServer, error)
 }
 
@@ -160,6 +162,11 @@ func (*nodeBuilder) initNode(cfg *Config) error {
 		return fmt.Errorf("cannot setup telemetry mailer: %w", err)
 	}
 
+	newInstance, err := newRuntimeInstanceFunc(cfg)
+	if err != nil {
+		return err
+	}
+
 	config := state.Config{
 		Path:     cfg.Global.BasePath,
 		LogLevel: cfg.Global.LogLvl,
@@ -167,8 +174,9 @@ func (*nodeBuilder) initNode(cfg *Config) error {
 			Mode:           cfg.Global.Pruning,
 			RetainedBlocks: cfg.Global.RetainBlocks,
 		},
-		Telemetry: telemetryMailer,
-		Metrics:   metrics.NewIntervalConfig(cfg.Global.PublishMetrics),
+		Telemetry:          telemetryMailer,
+		Metrics:            metrics.NewIntervalConfig(cfg.Global.PublishMetrics),
+		NewRuntimeInstance: newInstance,
 	}
 
 	// create new state service
@@ -277,7 +285,8 @@ func newNode(cfg *Config,
 		return nil, fmt.Errorf("cannot start state service: %w", err)
 	}
 
-	sysSrvc, err := builder.createSystemService(&cfg.System, stateSrvc)
+	logFilter := system.NewLogFilter(cfg.Global.LogLvl, cfg.Log.targetLevels())
+	sysSrvc, err := builder.createSystemService(&cfg.System, logFilter, stateSrvc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create system service: %s", err)
 	}
@@ -365,7 +374,7 @@ func newNode(cfg *Config,
 	nodeSrvcs = append(nodeSrvcs, bp)
 
 	// check if rpc service is enabled
-	if enabled := cfg.RPC.isRPCEnabled() || cfg.RPC.isWSEnabled(); enabled {
+	if enabled := cfg.RPC.isRPCEnabled() || cfg.RPC.isWSEnabled() || cfg.RPC.isIPCEnabled(); enabled {
 		var rpcSrvc *rpc.HTTPServer
 		cRPCParams := rpcServiceSettings{
 			config:        cfg,
@@ -374,6 +383,7 @@ func newNode(cfg *Config,
 			core:          coreSrvc,
 			network:       networkSrvc,
 			blockProducer: bp,
+			babeKeystore:  ks.Babe,
 			system:        sysSrvc,
 			blockFinality: fg,
 			syncer:        syncer,
@@ -394,6 +404,7 @@ func newNode(cfg *Config,
 		Name:            cfg.Global.Name,
 		ServiceRegistry: serviceRegistry,
 		started:         make(chan struct{}),
+		logFilter:       logFilter,
 	}
 
 	for _, srvc := range nodeSrvcs {
@@ -487,6 +498,12 @@ func (n *Node) Stop() {
 	}
 }
 
+// ReloadLogConfig replaces the log levels of the node configuration
+// and resets the levels of the node loggers to these levels.
+func (n *Node) ReloadLogConfig(globalLevel log.Level, logConfig LogConfig) {
+	n.logFilter.Reload(globalLevel, logConfig.targetLevels())
+}
+
 func (n *nodeBuilder) loadRuntime(cfg *Config, ns *runtime.NodeStorage,
 	stateSrvc *state.Service, ks *keystore.GlobalKeystore,
 	net *network.Service) error {
@@ -207,10 +207,12 @@ func TestNewNode(t *testing.T) {
 	m.EXPECT().createBABEService(dotConfig, gomock.AssignableToTypeOf(&state.Service{}), ks.Babe,
 		&core.Service{}, gomock.AssignableToTypeOf(&telemetry.Mailer{})).
 		Return(&babe.Service{}, nil)
-	m.EXPECT().createSystemService(&dotConfig.System, gomock.AssignableToTypeOf(&state.Service{})).
-		DoAndReturn(func(cfg *types.SystemInfo, stateSrvc *state.Service) (*system.Service, error) {
+	m.EXPECT().createSystemService(&dotConfig.System, gomock.AssignableToTypeOf(&system.LogFilter{}),
+		gomock.AssignableToTypeOf(&state.Service{})).
+		DoAndReturn(func(cfg *types.SystemInfo, logFilter *system.LogFilter,
+			stateSrvc *state.Service) (*system.Service, error) {
 			gd, err := stateSrvc.Base.LoadGenesisData()
-			systemService := system.NewService(cfg, gd)
+			systemService := system.NewService(cfg, gd, logFilter)
 			return systemService, err
 		})
 	m.EXPECT().createNetworkService(dotConfig, gomock.AssignableToTypeOf(&state.Service{}),
@@ -0,0 +1,50 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package access
+
+// MethodFilter decides which RPC methods can be called, based on a list of
+// allowed methods and a list of denied methods. Methods are named as in the
+// JSON-RPC requests, for example `author_insertKey`.
+type MethodFilter struct {
+	allowed map[string]struct{}
+	denied  map[string]struct{}
+}
+
+// NewMethodFilter returns a filter allowing all the methods if `allowed` is empty,
+// or only the methods in `allowed` otherwise, except for the methods in `denied`.
+func NewMethodFilter(allowed, denied []string) *MethodFilter {
+	return &MethodFilter{
+		allowed: toSet(allowed),
+		denied:  toSet(denied),
+	}
+}
+
+// Allowed returns true if the method can be called. A nil filter allows all the methods.
+func (f *MethodFilter) Allowed(method string) bool {
+	if f == nil {
+		return true
+	}
+
+	if _, denied := f.denied[method]; denied {
+		return false
+	}
+
+	if len(f.allowed) == 0 {
+		return true
+	}
+
+	_, allowed := f.allowed[method]
+	return allowed
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if value != "" {
+			set[value] = struct{}{}
+		}
+	}
+
+	return set
+}
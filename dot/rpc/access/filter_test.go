@@ -0,0 +1,67 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package access
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MethodFilter_Allowed(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		filter  *MethodFilter
+		method  string
+		allowed bool
+	}{
+		"nil filter": {
+			method:  "author_insertKey",
+			allowed: true,
+		},
+		"empty filter": {
+			filter:  NewMethodFilter(nil, nil),
+			method:  "author_insertKey",
+			allowed: true,
+		},
+		"denied method": {
+			filter: NewMethodFilter(nil, []string{"author_insertKey", "offchain_localStorageSet"}),
+			method: "offchain_localStorageSet",
+		},
+		"method not denied": {
+			filter:  NewMethodFilter(nil, []string{"author_insertKey"}),
+			method:  "chain_getBlock",
+			allowed: true,
+		},
+		"allowed method": {
+			filter:  NewMethodFilter([]string{"chain_getBlock"}, nil),
+			method:  "chain_getBlock",
+			allowed: true,
+		},
+		"method not allowed": {
+			filter: NewMethodFilter([]string{"chain_getBlock"}, nil),
+			method: "author_insertKey",
+		},
+		"allowed and denied method": {
+			filter: NewMethodFilter([]string{"chain_getBlock"}, []string{"chain_getBlock"}),
+			method: "chain_getBlock",
+		},
+		"empty names are ignored": {
+			filter:  NewMethodFilter([]string{""}, []string{""}),
+			method:  "chain_getBlock",
+			allowed: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			allowed := testCase.filter.Allowed(testCase.method)
+			assert.Equal(t, testCase.allowed, allowed)
+		})
+	}
+}
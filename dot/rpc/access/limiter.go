@@ -0,0 +1,164 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package access
+
+import (
+	"sync"
+	"time"
+)
+
+// cleanupInterval is the interval at which a KeyedLimiter forgets the
+// limiters of the clients which did not make any recent request.
+const cleanupInterval = time.Minute
+
+// Limiter is a token bucket rate limiter allowing a number of requests per second,
+// with bursts of at most that same number of requests.
+type Limiter struct {
+	mu       sync.Mutex
+	rate     float64
+	tokens   float64
+	lastSeen time.Time
+	now      func() time.Time
+}
+
+// NewLimiter returns a limiter allowing `requestsPerSecond` requests per second,
+// or nil if `requestsPerSecond` is zero, in which case all the requests are allowed.
+func NewLimiter(requestsPerSecond uint32) *Limiter {
+	if requestsPerSecond == 0 {
+		return nil
+	}
+
+	return newLimiter(requestsPerSecond, time.Now)
+}
+
+func newLimiter(requestsPerSecond uint32, now func() time.Time) *Limiter {
+	return &Limiter{
+		rate:     float64(requestsPerSecond),
+		tokens:   float64(requestsPerSecond),
+		lastSeen: now(),
+		now:      now,
+	}
+}
+
+// Allow returns true and consumes a token if a request can be made now.
+func (l *Limiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN returns true and consumes `n` tokens if `n` requests, such as the requests
+// of a batch, can be made now. No token is consumed if they cannot all be made.
+// Requests exceeding the burst size are charged the burst size, so a batch larger
+// than the burst is allowed once the limiter is full instead of never.
+func (l *Limiter) AllowN(n uint32) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cost := float64(n)
+	if cost > l.rate {
+		cost = l.rate
+	}
+
+	l.refill()
+	if l.tokens < cost {
+		return false
+	}
+
+	l.tokens -= cost
+	return true
+}
+
+// full returns true if the limiter has all its tokens, in
+// which case it behaves as a newly created limiter.
+func (l *Limiter) full() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	return l.tokens >= l.rate
+}
+
+func (l *Limiter) refill() {
+	now := l.now()
+	elapsed := now.Sub(l.lastSeen)
+	if elapsed <= 0 {
+		return
+	}
+
+	l.tokens += elapsed.Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.lastSeen = now
+}
+
+// KeyedLimiter rate limits the requests of each client, identified by a key
+// such as its IP address, independently.
+type KeyedLimiter struct {
+	mu                sync.Mutex
+	requestsPerSecond uint32
+	limiters          map[string]*Limiter
+	lastCleanup       time.Time
+	now               func() time.Time
+}
+
+// NewKeyedLimiter returns a limiter allowing `requestsPerSecond` requests per second to each
+// client, or nil if `requestsPerSecond` is zero, in which case all the requests are allowed.
+func NewKeyedLimiter(requestsPerSecond uint32) *KeyedLimiter {
+	if requestsPerSecond == 0 {
+		return nil
+	}
+
+	return newKeyedLimiter(requestsPerSecond, time.Now)
+}
+
+func newKeyedLimiter(requestsPerSecond uint32, now func() time.Time) *KeyedLimiter {
+	return &KeyedLimiter{
+		requestsPerSecond: requestsPerSecond,
+		limiters:          make(map[string]*Limiter),
+		lastCleanup:       now(),
+		now:               now,
+	}
+}
+
+// Allow returns true if the client with the given key can make a request now.
+func (k *KeyedLimiter) Allow(key string) bool {
+	return k.AllowN(key, 1)
+}
+
+// AllowN returns true if the client with the given key can make `n` requests now.
+func (k *KeyedLimiter) AllowN(key string, n uint32) bool {
+	if k == nil {
+		return true
+	}
+
+	k.mu.Lock()
+	k.cleanup()
+	limiter, ok := k.limiters[key]
+	if !ok {
+		limiter = newLimiter(k.requestsPerSecond, k.now)
+		k.limiters[key] = limiter
+	}
+	k.mu.Unlock()
+
+	return limiter.AllowN(n)
+}
+
+// cleanup forgets the limiters of the clients which did not make any recent request,
+// so the memory used does not grow with the number of clients ever seen.
+func (k *KeyedLimiter) cleanup() {
+	if k.now().Sub(k.lastCleanup) < cleanupInterval {
+		return
+	}
+
+	for key, limiter := range k.limiters {
+		if limiter.full() {
+			delete(k.limiters, key)
+		}
+	}
+	k.lastCleanup = k.now()
+}
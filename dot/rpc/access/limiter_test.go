@@ -0,0 +1,92 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package access
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func Test_NewLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewLimiter(0)
+	assert.Nil(t, limiter)
+	assert.True(t, limiter.Allow())
+
+	assert.NotNil(t, NewLimiter(1))
+}
+
+func Test_Limiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newLimiter(2, clock.Now)
+
+	// burst of two requests
+	assert.True(t, limiter.Allow())
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+
+	clock.advance(500 * time.Millisecond)
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+
+	// tokens do not accumulate over the burst size
+	clock.advance(time.Hour)
+	assert.True(t, limiter.Allow())
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+}
+
+func Test_Limiter_AllowN(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newLimiter(3, clock.Now)
+
+	// no token is consumed if the requests cannot all be made
+	assert.True(t, limiter.AllowN(2))
+	assert.False(t, limiter.AllowN(2))
+	assert.True(t, limiter.AllowN(1))
+	assert.False(t, limiter.Allow())
+
+	// requests exceeding the burst size are charged the burst size
+	assert.False(t, limiter.AllowN(4))
+	clock.advance(time.Second)
+	assert.True(t, limiter.AllowN(4))
+	assert.False(t, limiter.Allow())
+}
+
+func Test_KeyedLimiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, NewKeyedLimiter(0))
+	var nilLimiter *KeyedLimiter
+	assert.True(t, nilLimiter.Allow("127.0.0.1"))
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newKeyedLimiter(1, clock.Now)
+
+	assert.True(t, limiter.Allow("127.0.0.1"))
+	assert.False(t, limiter.Allow("127.0.0.1"))
+	assert.True(t, limiter.Allow("10.0.0.1"))
+	require.Len(t, limiter.limiters, 2)
+
+	// the limiters which are full again are forgotten
+	clock.advance(cleanupInterval)
+	assert.True(t, limiter.Allow("127.0.0.1"))
+	assert.Len(t, limiter.limiters, 1)
+}
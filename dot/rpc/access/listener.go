@@ -0,0 +1,89 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package access
+
+import (
+	"net"
+	"sync"
+)
+
+// limitListener is a net.Listener closing the accepted connections
+// exceeding its maximum number of concurrent connections.
+type limitListener struct {
+	net.Listener
+	onReject func()
+
+	mu             sync.Mutex
+	maxConnections uint32
+	connections    uint32
+}
+
+// LimitListener returns a listener accepting at most `maxConnections` concurrent
+// connections from the given listener, or the given listener if `maxConnections`
+// is zero. The connections exceeding the maximum are closed right after being
+// accepted, and `onReject` is called for each of them.
+func LimitListener(listener net.Listener, maxConnections uint32, onReject func()) net.Listener {
+	if maxConnections == 0 {
+		return listener
+	}
+
+	return &limitListener{
+		Listener:       listener,
+		onReject:       onReject,
+		maxConnections: maxConnections,
+	}
+}
+
+// Accept waits for and returns the next connection which does
+// not exceed the maximum number of concurrent connections.
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if l.acquire() {
+			return &limitConn{Conn: conn, release: l.release}, nil
+		}
+
+		_ = conn.Close()
+		if l.onReject != nil {
+			l.onReject()
+		}
+	}
+}
+
+func (l *limitListener) acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.connections >= l.maxConnections {
+		return false
+	}
+
+	l.connections++
+	return true
+}
+
+func (l *limitListener) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.connections--
+}
+
+// limitConn releases its slot of the listener when closed.
+type limitConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+// Close closes the connection and releases its slot of the listener.
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package access
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LimitListener(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	assert.Equal(t, listener, LimitListener(listener, 0, nil))
+
+	rejected := make(chan struct{}, 1)
+	limited := LimitListener(listener, 1, func() { rejected <- struct{}{} })
+
+	accepted := make(chan net.Conn)
+	go func() {
+		for {
+			conn, err := limited.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	first, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer first.Close()
+	firstAccepted := <-accepted
+
+	// the second connection exceeds the maximum and is closed
+	second, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer second.Close()
+	<-rejected
+
+	err = second.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, err)
+	_, err = second.Read(make([]byte, 1))
+	require.Error(t, err)
+
+	// closing the first connection releases its slot
+	err = firstAccepted.Close()
+	require.NoError(t, err)
+	err = firstAccepted.Close()
+	require.Error(t, err)
+
+	third, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer third.Close()
+	thirdAccepted := <-accepted
+	require.NoError(t, thirdAccepted.Close())
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package access
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons for which a call or a connection is rejected, used as label of the rejected calls metric.
+const (
+	ReasonMethodDenied      = "method_denied"
+	ReasonRateLimited       = "rate_limited"
+	ReasonSubscriptionQuota = "subscription_quota"
+	ReasonConnectionLimit   = "connection_limit"
+)
+
+// Transports on which a call or a connection is rejected, used as label of the rejected calls metric.
+const (
+	TransportHTTP = "http"
+	TransportWS   = "ws"
+)
+
+// InternalRequestHeader is the HTTP header holding the token of the HTTP-RPC requests made
+// by the websocket server on behalf of its connections, which are rate limited per connection.
+const InternalRequestHeader = "X-Gossamer-Internal-Token"
+
+// LimitExceededCode is the JSON-RPC error code returned when a call is rejected because
+// a request rate or subscription limit is exceeded, as defined in EIP-1474.
+const LimitExceededCode = -32005
+
+var rejectedCallsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gossamer_rpc",
+	Name:      "rejected_calls_total",
+	Help:      "total number of RPC calls and connections rejected by the access rules",
+}, []string{"transport", "reason"})
+
+// RecordRejected records a call or a connection rejected for the given reason on the given transport.
+func RecordRejected(transport, reason string) {
+	rejectedCallsCounter.WithLabelValues(transport, reason).Inc()
+}
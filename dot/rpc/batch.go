@@ -0,0 +1,157 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/json2"
+	gorillajson2 "github.com/gorilla/rpc/v2/json2"
+)
+
+// DefaultMaxBatchSize is the maximum number of requests in a batch
+// if no maximum batch size is configured.
+const DefaultMaxBatchSize = 100
+
+// batchHandler handles JSON-RPC batch requests by running each request of the
+// batch concurrently against the wrapped handler, and responding with the array
+// of their responses, in the order of the requests. Single requests are passed
+// as they are to the wrapped handler.
+type batchHandler struct {
+	handler      http.Handler
+	maxBatchSize uint32
+}
+
+func newBatchHandler(handler http.Handler, maxBatchSize uint32) *batchHandler {
+	if maxBatchSize == 0 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+
+	return &batchHandler{
+		handler:      handler,
+		maxBatchSize: maxBatchSize,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (b *batchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot read request body: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if !json2.IsBatch(body) {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		b.handler.ServeHTTP(w, r)
+		return
+	}
+
+	var requests []json.RawMessage
+	err = json.Unmarshal(body, &requests)
+	if err != nil {
+		writeJSON(w, json2.EncodeErrorResponse(nil, gorillajson2.E_PARSE, err.Error()))
+		return
+	}
+
+	if len(requests) == 0 {
+		writeJSON(w, json2.EncodeErrorResponse(nil, gorillajson2.E_INVALID_REQ, "empty batch"))
+		return
+	}
+
+	if len(requests) > int(b.maxBatchSize) {
+		message := fmt.Sprintf("batch of %d requests exceeds the maximum batch size of %d",
+			len(requests), b.maxBatchSize)
+		writeJSON(w, json2.EncodeErrorResponse(nil, gorillajson2.E_INVALID_REQ, message))
+		return
+	}
+
+	responses := make([]json.RawMessage, len(requests))
+	var wg sync.WaitGroup
+	wg.Add(len(requests))
+	for i, request := range requests {
+		go func(i int, request json.RawMessage) {
+			defer wg.Done()
+			responses[i] = b.serveRequest(r, request)
+		}(i, request)
+	}
+	wg.Wait()
+
+	// notifications do not have a response, and nothing is returned
+	// if the batch only contains notifications.
+	results := make([]json.RawMessage, 0, len(responses))
+	for _, response := range responses {
+		if response != nil {
+			results = append(results, response)
+		}
+	}
+
+	if len(results) == 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(results)
+	if err != nil {
+		writeJSON(w, json2.EncodeErrorResponse(nil, gorillajson2.E_INTERNAL, err.Error()))
+		return
+	}
+
+	writeJSON(w, encoded)
+}
+
+// serveRequest serves a single request of a batch and returns its response,
+// or nil if the request is a notification.
+func (b *batchHandler) serveRequest(batch *http.Request, request json.RawMessage) json.RawMessage {
+	r := batch.Clone(batch.Context())
+	r.Body = io.NopCloser(bytes.NewReader(request))
+	r.ContentLength = int64(len(request))
+
+	recorder := &responseRecorder{header: make(http.Header)}
+	b.handler.ServeHTTP(recorder, r)
+
+	response := bytes.TrimSpace(recorder.body.Bytes())
+	if len(response) == 0 {
+		return nil
+	}
+
+	if !json.Valid(response) {
+		// the handler failed before the request could be decoded by
+		// the codec, and responded with a plain text error message.
+		var req struct {
+			ID *json.RawMessage `json:"id"`
+		}
+		_ = json.Unmarshal(request, &req)
+		return json2.EncodeErrorResponse(req.ID, gorillajson2.E_INVALID_REQ, string(response))
+	}
+
+	return response
+}
+
+func writeJSON(w http.ResponseWriter, encoded []byte) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(encoded)
+}
+
+// responseRecorder is a http.ResponseWriter recording the response body of
+// a single request of a batch, or of a request made by the IPC server.
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(int) {}
@@ -0,0 +1,95 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchTestService struct{}
+
+type BatchTestRequest struct {
+	Value string
+}
+
+func (batchTestService) Echo(_ *http.Request, req *BatchTestRequest, res *string) error {
+	*res = req.Value
+	return nil
+}
+
+func Test_batchHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	rpcServer := rpc.NewServer()
+	rpcServer.RegisterCodec(NewDotUpCodec(), "application/json")
+	err := rpcServer.RegisterService(batchTestService{}, "test")
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		maxBatchSize uint32
+		body         string
+		response     string
+	}{
+		"single request": {
+			body:     `{"jsonrpc":"2.0","method":"test_echo","params":["a"],"id":1}`,
+			response: `{"jsonrpc":"2.0","result":"a","id":1}`,
+		},
+		"batch": {
+			body: `[{"jsonrpc":"2.0","method":"test_echo","params":["a"],"id":1},` +
+				`{"jsonrpc":"2.0","method":"test_echo","params":["b"],"id":"2"},` +
+				`{"jsonrpc":"2.0","method":"test_unknown","params":[],"id":3}]`,
+			response: `[{"jsonrpc":"2.0","result":"a","id":1},` +
+				`{"jsonrpc":"2.0","result":"b","id":"2"},` +
+				`{"jsonrpc":"2.0","error":{"code":-32000,"message":"rpc: can't find method \"test.Unknown\"","data":null},"id":3}]`,
+		},
+		"batch with notification": {
+			body: `[{"jsonrpc":"2.0","method":"test_echo","params":["a"]},` +
+				`{"jsonrpc":"2.0","method":"test_echo","params":["b"],"id":2}]`,
+			response: `[{"jsonrpc":"2.0","result":"b","id":2}]`,
+		},
+		"batch of notifications": {
+			body: `[{"jsonrpc":"2.0","method":"test_echo","params":["a"]}]`,
+		},
+		"invalid batch": {
+			body: `[{"jsonrpc":"2.0",]`,
+			response: `{"jsonrpc":"2.0","error":{"code":-32700,` +
+				`"message":"invalid character ']' looking for beginning of object key string","data":null},"id":null}`,
+		},
+		"empty batch": {
+			body:     `[]`,
+			response: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"empty batch","data":null},"id":null}`,
+		},
+		"batch too large": {
+			maxBatchSize: 1,
+			body: `[{"jsonrpc":"2.0","method":"test_echo","params":["a"],"id":1},` +
+				`{"jsonrpc":"2.0","method":"test_echo","params":["b"],"id":2}]`,
+			response: `{"jsonrpc":"2.0","error":{"code":-32600,` +
+				`"message":"batch of 2 requests exceeds the maximum batch size of 1","data":null},"id":null}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := newBatchHandler(rpcServer, testCase.maxBatchSize)
+
+			request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.response, strings.TrimSpace(recorder.Body.String()))
+		})
+	}
+}
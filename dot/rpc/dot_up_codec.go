@@ -69,6 +69,11 @@ func (c *DotUpCodecRequest) Method() (string, error) {
 			return "", fmt.Errorf("rpc error method %s not found", m)
 		}
 		service, method := parts[0], parts[1]
+		if len(parts) == 3 && isVersion(parts[1]) {
+			// versioned methods such as chainSpec_v1_chainName belong
+			// to the versioned service chainSpec_v1.
+			service, method = parts[0]+"_"+parts[1], parts[2]
+		}
 		r, n := utf8.DecodeRuneInString(method) // get the first rune, and it's length
 		if unicode.IsLower(r) {
 			upMethod := service + "." + string(unicode.ToUpper(r)) + method[n:]
@@ -77,3 +82,19 @@ func (c *DotUpCodecRequest) Method() (string, error) {
 	}
 	return m, err
 }
+
+// isVersion returns true if the given method name part is
+// a version such as v1.
+func isVersion(part string) bool {
+	if len(part) < 2 || part[0] != 'v' {
+		return false
+	}
+
+	for _, r := range part[1:] {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
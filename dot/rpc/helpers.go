@@ -9,6 +9,7 @@
 	"net"
 	"strings"
 
+	"github.com/ChainSafe/gossamer/dot/rpc/access"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/rpc/v2"
@@ -49,6 +50,8 @@ funcName = strings.ToLower(string(funcName[0])) + funcName[1:]
 }
 
 func rpcValidator(cfg *HTTPServerConfig, validate *validator.Validate) func(r *rpc.RequestInfo, i interface{}) error {
+	filter := access.NewMethodFilter(cfg.MethodsAllowed, cfg.MethodsDenied)
+
 	return func(r *rpc.RequestInfo, v interface{}) error {
 		var (
 			err       error
@@ -59,6 +62,15 @@ func rpcValidator(cfg *HTTPServerConfig, validate *validator.Validate) func(r *r
 			return err
 		}
 
+		if !filter.Allowed(rpcmethod) {
+			transport := access.TransportHTTP
+			if r.Request.Header.Get(access.InternalRequestHeader) != "" {
+				transport = access.TransportWS
+			}
+			access.RecordRejected(transport, access.ReasonMethodDenied)
+			return fmt.Errorf("rpc method %s is not allowed", rpcmethod)
+		}
+
 		isUnsafe := modules.IsUnsafe(rpcmethod)
 		if isUnsafe && !cfg.rpcUnsafeEnabled() {
 			return fmt.Errorf("unsafe rpc method %s cannot be reachable", rpcmethod)
@@ -4,15 +4,22 @@
 package rpc
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/ChainSafe/gossamer/dot/rpc/access"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/ChainSafe/gossamer/dot/rpc/subscription"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
@@ -25,7 +32,12 @@ type HTTPServer struct {
 	logger       *log.Logger
 	rpcServer    *rpc.Server // Actual RPC call handler
 	serverConfig *HTTPServerConfig
+	wsConnsMu    sync.Mutex
 	wsConns      []*subscription.WSConn
+	ipcListener  net.Listener
+	// internalToken identifies the HTTP-RPC requests made by the
+	// websocket server on behalf of its connections.
+	internalToken string
 }
 
 // HTTPServerConfig configures the HTTPServer
@@ -36,6 +48,7 @@ type HTTPServerConfig struct {
 	NetworkAPI          modules.NetworkAPI
 	CoreAPI             modules.CoreAPI
 	BlockProducerAPI    modules.BlockProducerAPI
+	BabeKeystore        keystore.Keystore
 	BlockFinalityAPI    modules.BlockFinalityAPI
 	TransactionQueueAPI modules.TransactionStateAPI
 	RPCAPI              modules.RPCAPI
@@ -55,6 +68,27 @@ type HTTPServerConfig struct {
 	WSUnsafeExternal    bool
 	WSPort              uint32
 	Modules             []string
+	// MaxBatchSize is the maximum number of requests in a batch,
+	// DefaultMaxBatchSize is used if it is zero.
+	MaxBatchSize uint32
+	// MethodsAllowed are the only RPC methods which can be called if it is not empty.
+	MethodsAllowed []string
+	// MethodsDenied are the RPC methods which cannot be called.
+	MethodsDenied []string
+	// MaxRequestsPerSecond is the maximum number of requests per second of each HTTP-RPC
+	// client IP address and of each websocket connection, there is no limit if it is zero.
+	MaxRequestsPerSecond uint32
+	// MaxSubscriptionsPerConnection is the maximum number of active subscriptions
+	// of each websocket connection, there is no limit if it is zero.
+	MaxSubscriptionsPerConnection uint32
+	// MaxConnections is the maximum number of concurrent connections to each of the
+	// HTTP-RPC and websocket servers, there is no limit if it is zero.
+	MaxConnections uint32
+	// IPCPath is the path of the Unix domain socket of the IPC server,
+	// which is not started if it is empty.
+	IPCPath string
+	// IPCPermissions are the file permissions of the Unix domain socket.
+	IPCPermissions os.FileMode
 }
 
 func (h *HTTPServerConfig) rpcUnsafeEnabled() bool {
@@ -73,6 +107,12 @@ func (h *HTTPServerConfig) exposeRPC() bool {
 	return h.RPCExternal || h.RPCUnsafeExternal
 }
 
+// ipcOnly returns true if the IPC server is the only server enabled,
+// in which case the HTTP-RPC server is not needed.
+func (h *HTTPServerConfig) ipcOnly() bool {
+	return h.IPCPath != "" && !h.RPC && !h.WS
+}
+
 var logger *log.Logger
 
 // NewHTTPServer creates a new http server and registers an associated rpc server
@@ -81,9 +121,10 @@ func NewHTTPServer(cfg *HTTPServerConfig) *HTTPServer {
 	logger.Patch(log.SetLevel(cfg.LogLvl))
 
 	server := &HTTPServer{
-		logger:       logger,
-		rpcServer:    rpc.NewServer(),
-		serverConfig: cfg,
+		logger:        logger,
+		rpcServer:     rpc.NewServer(),
+		serverConfig:  cfg,
+		internalToken: newInternalToken(),
 	}
 
 	server.RegisterModules(cfg.Modules)
@@ -95,6 +136,9 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 	for _, mod := range mods {
 		h.logger.Debug("Enabling rpc module " + mod)
 		var srvc interface{}
+		// name is the name of the registered service, which is the module
+		// name suffixed with its version for the versioned modules.
+		name := mod
 		switch mod {
 		case "system":
 			srvc = modules.NewSystemModule(h.serverConfig.NetworkAPI, h.serverConfig.SystemAPI,
@@ -118,19 +162,27 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 			srvc = modules.NewChildStateModule(h.serverConfig.StorageAPI, h.serverConfig.BlockAPI)
 		case "syncstate":
 			srvc = modules.NewSyncStateModule(h.serverConfig.SyncStateAPI)
+		case "babe":
+			srvc = modules.NewBabeModule(h.serverConfig.BlockProducerAPI, h.serverConfig.BabeKeystore)
+		case "chainSpec":
+			srvc = modules.NewChainSpecModule(h.serverConfig.SystemAPI, h.serverConfig.BlockAPI)
+			name = "chainSpec_v1"
+		case "transaction":
+			srvc = modules.NewTransactionModule()
+			name = "transaction_v1"
 		case "payment":
-			srvc = modules.NewPaymentModule(h.serverConfig.BlockAPI)
+			srvc = modules.NewPaymentModule(h.serverConfig.CoreAPI)
 		default:
 			h.logger.Warn("Unrecognised module: " + mod)
 			continue
 		}
 
-		err := h.rpcServer.RegisterService(srvc, mod)
+		err := h.rpcServer.RegisterService(srvc, name)
 		if err != nil {
 			h.logger.Warnf("Failed to register module %s: %s", mod, err)
 		}
 
-		h.serverConfig.RPCAPI.BuildMethodNames(srvc, mod)
+		h.serverConfig.RPCAPI.BuildMethodNames(srvc, name)
 	}
 }
 
@@ -142,9 +194,9 @@ func (h *HTTPServer) Start() error {
 	h.rpcServer.RegisterCodec(NewDotUpCodec(), "application/json")
 	h.rpcServer.RegisterCodec(NewDotUpCodec(), "application/json;charset=UTF-8")
 
-	h.logger.Infof("Starting HTTP Server on host %s and port %d...", h.serverConfig.Host, h.serverConfig.RPCPort)
 	r := mux.NewRouter()
-	r.Handle("/", h.rpcServer)
+	r.Handle("/", newRateLimitHandler(newBatchHandler(h.rpcServer, h.serverConfig.MaxBatchSize),
+		h.serverConfig.MaxRequestsPerSecond, h.internalToken))
 
 	validate := validator.New()
 	// Add custom validator for `common.Hash`
@@ -152,12 +204,19 @@ func (h *HTTPServer) Start() error {
 
 	h.rpcServer.RegisterValidateRequestFunc(rpcValidator(h.serverConfig, validate))
 
-	go func() {
-		err := http.ListenAndServe(fmt.Sprintf(":%d", h.serverConfig.RPCPort), r)
+	if h.serverConfig.IPCPath != "" {
+		err := h.startIPC()
 		if err != nil {
-			h.logger.Errorf("http error: %s", err)
+			return fmt.Errorf("cannot start IPC server: %w", err)
 		}
-	}()
+	}
+
+	if h.serverConfig.ipcOnly() {
+		return nil
+	}
+
+	h.logger.Infof("Starting HTTP Server on host %s and port %d...", h.serverConfig.Host, h.serverConfig.RPCPort)
+	go h.serve(h.serverConfig.RPCPort, r, access.TransportHTTP)
 
 	if !h.serverConfig.WS {
 		return nil
@@ -167,39 +226,73 @@ func (h *HTTPServer) Start() error {
 		h.serverConfig.Host, h.serverConfig.WSPort)
 	ws := mux.NewRouter()
 	ws.Handle("/", h)
-	go func() {
-		err := http.ListenAndServe(fmt.Sprintf(":%d", h.serverConfig.WSPort), ws)
-		if err != nil {
-			h.logger.Errorf("http error: %s", err)
-		}
-	}()
+	go h.serve(h.serverConfig.WSPort, ws, access.TransportWS)
 
 	return nil
 }
 
+// serve serves the given handler on the given port, accepting at
+// most the configured maximum number of concurrent connections.
+func (h *HTTPServer) serve(port uint32, handler http.Handler, transport string) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		h.logger.Errorf("http error: %s", err)
+		return
+	}
+
+	listener = access.LimitListener(listener, h.serverConfig.MaxConnections, func() {
+		access.RecordRejected(transport, access.ReasonConnectionLimit)
+	})
+
+	err = http.Serve(listener, handler)
+	if err != nil {
+		h.logger.Errorf("http error: %s", err)
+	}
+}
+
 // Stop stops the server
 func (h *HTTPServer) Stop() error {
-	if h.serverConfig.WS {
-		// close all channels and websocket connections
-		for _, conn := range h.wsConns {
-			for _, sub := range conn.Subscriptions {
-				switch v := sub.(type) {
-				case *subscription.StorageObserver:
-					h.serverConfig.StorageAPI.UnregisterStorageObserver(v)
-				case *subscription.BlockListener:
-					h.serverConfig.BlockAPI.FreeImportedBlockNotifierChannel(v.Channel)
-				}
-			}
+	if h.ipcListener != nil {
+		err := h.ipcListener.Close()
+		if err != nil {
+			h.logger.Errorf("error closing IPC listener: %s", err)
+		}
 
-			err := conn.Wsconn.Close()
-			if err != nil {
-				h.logger.Errorf("error closing websocket connection: %s", err)
-			}
+		err = os.Remove(h.serverConfig.IPCPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			h.logger.Errorf("error removing IPC socket: %s", err)
 		}
 	}
+
+	if h.serverConfig.WS || h.serverConfig.IPCPath != "" {
+		// close all channels and websocket and IPC connections
+		h.wsConnsMu.Lock()
+		defer h.wsConnsMu.Unlock()
+		for _, conn := range h.wsConns {
+			h.closeConn(conn)
+		}
+		h.wsConns = nil
+	}
 	return nil
 }
 
+// closeConn releases the subscriptions of the given connection and closes it.
+func (h *HTTPServer) closeConn(conn *subscription.WSConn) {
+	for _, sub := range conn.Subscriptions {
+		switch v := sub.(type) {
+		case *subscription.StorageObserver:
+			h.serverConfig.StorageAPI.UnregisterStorageObserver(v)
+		case *subscription.BlockListener:
+			h.serverConfig.BlockAPI.FreeImportedBlockNotifierChannel(v.Channel)
+		}
+	}
+
+	err := conn.Wsconn.Close()
+	if err != nil {
+		h.logger.Errorf("error closing connection: %s", err)
+	}
+}
+
 // ServeHTTP implemented to handle WebSocket connections
 func (h *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var upg = websocket.Upgrader{
@@ -231,13 +324,19 @@ func (h *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	// create wsConn
 	wsc := NewWSConn(ws, h.serverConfig)
+	wsc.InternalToken = h.internalToken
+	h.wsConnsMu.Lock()
 	h.wsConns = append(h.wsConns, wsc)
+	h.wsConnsMu.Unlock()
 
-	go wsc.HandleConn()
+	go func() {
+		wsc.HandleConn()
+		h.removeConn(wsc)
+	}()
 }
 
 // NewWSConn to create new WebSocket Connection struct
-func NewWSConn(conn *websocket.Conn, cfg *HTTPServerConfig) *subscription.WSConn {
+func NewWSConn(conn subscription.Conn, cfg *HTTPServerConfig) *subscription.WSConn {
 	c := &subscription.WSConn{
 		UnsafeEnabled: cfg.wsUnsafeEnabled(),
 		Wsconn:        conn,
@@ -250,6 +349,22 @@ func NewWSConn(conn *websocket.Conn, cfg *HTTPServerConfig) *subscription.WSConn
 		HTTP: &http.Client{
 			Timeout: time.Second * 30,
 		},
+		MethodFilter:     access.NewMethodFilter(cfg.MethodsAllowed, cfg.MethodsDenied),
+		RateLimiter:      access.NewLimiter(cfg.MaxRequestsPerSecond),
+		MaxSubscriptions: cfg.MaxSubscriptionsPerConnection,
 	}
 	return c
 }
+
+// newInternalToken returns a random token, which cannot be guessed by the RPC clients.
+func newInternalToken() string {
+	token := make([]byte, 16)
+	_, err := rand.Read(token)
+	if err != nil {
+		// without a token, the requests made by the websocket
+		// server are rate limited as any other request.
+		return ""
+	}
+
+	return hex.EncodeToString(token)
+}
@@ -67,7 +67,7 @@ func TestNewHTTPServer(t *testing.T) {
 	si := &types.SystemInfo{
 		SystemName: "gossamer",
 	}
-	sysAPI := system.NewService(si, nil)
+	sysAPI := system.NewService(si, nil, nil)
 	cfg := &HTTPServerConfig{
 		Modules:   []string{"system"},
 		RPCPort:   8545,
@@ -128,7 +128,7 @@ func TestNewHTTPServer(t *testing.T) {
 
 func TestUnsafeRPCProtection(t *testing.T) {
 	cfg := &HTTPServerConfig{
-		Modules:           []string{"system", "author", "chain", "state", "rpc", "grandpa", "dev", "syncstate"},
+		Modules:           []string{"system", "author", "chain", "state", "rpc", "grandpa", "dev", "syncstate", "babe", "transaction"},
 		RPCPort:           7878,
 		RPCAPI:            NewService(),
 		RPCUnsafe:         false,
@@ -167,6 +167,35 @@ func TestUnsafeRPCProtection(t *testing.T) {
 		})
 	}
 }
+func TestRPCMethodsDenied(t *testing.T) {
+	cfg := &HTTPServerConfig{
+		Modules:       []string{"system", "rpc"},
+		RPCPort:       7881,
+		RPCAPI:        NewService(),
+		SystemAPI:     system.NewService(&types.SystemInfo{SystemName: "gossamer"}, nil, nil),
+		MethodsDenied: []string{"system_version"},
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.NoError(t, err)
+
+	time.Sleep(time.Second)
+	defer s.Stop()
+
+	url := fmt.Sprintf("http://localhost:%v/", cfg.RPCPort)
+
+	_, resBody := PostRequest(t, url,
+		bytes.NewBufferString(`{"jsonrpc":"2.0","method":"system_version","params":[],"id":1}`))
+	expected := `{"jsonrpc":"2.0","error":{"code":-32000,` +
+		`"message":"rpc method system_version is not allowed","data":null},"id":1}` + "\n"
+	require.Equal(t, expected, string(resBody))
+
+	_, resBody = PostRequest(t, url,
+		bytes.NewBufferString(`{"jsonrpc":"2.0","method":"system_name","params":[],"id":2}`))
+	require.Equal(t, `{"jsonrpc":"2.0","result":"gossamer","id":2}`+"\n", string(resBody))
+}
+
 func TestRPCUnsafeExpose(t *testing.T) {
 	data := []byte(fmt.Sprintf(
 		`{"jsonrpc":"2.0","method":"%s","params":["%s"],"id":1}`,
@@ -436,6 +465,7 @@ func newCoreServiceTest(t *testing.T) *core.Service {
 		GossipMessage(gomock.AssignableToTypeOf(new(network.TransactionMessage))).
 		AnyTimes()
 	net.EXPECT().IsSynced().Return(true).AnyTimes()
+	net.EXPECT().Peers().AnyTimes()
 	net.EXPECT().ReportPeer(
 		gomock.AssignableToTypeOf(new(peerset.ReputationChange)),
 		gomock.AssignableToTypeOf(peer.ID(""))).
@@ -0,0 +1,189 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/subscription"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultIPCPermissions are the file permissions of the IPC socket
+// if no permissions are configured, only allowing the node user.
+const DefaultIPCPermissions os.FileMode = 0600
+
+// ipcLocalAddress is the remote address of the requests received by the IPC server,
+// so the unsafe methods policy is the same as for local HTTP-RPC requests.
+const ipcLocalAddress = "127.0.0.1:0"
+
+// startIPC starts serving the RPC modules and the subscriptions on the configured Unix socket.
+func (h *HTTPServer) startIPC() error {
+	path := h.serverConfig.IPCPath
+
+	// remove the socket left over by a node which did not stop gracefully
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot remove existing socket: %w", err)
+	}
+
+	permissions := h.serverConfig.IPCPermissions
+	if permissions == 0 {
+		permissions = DefaultIPCPermissions
+	}
+
+	listener, err := listenUnix(path, permissions)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Infof("Starting IPC Server on socket %s...", path)
+	h.ipcListener = listener
+
+	handler := newBatchHandler(h.rpcServer, h.serverConfig.MaxBatchSize)
+	go h.serveIPC(listener, handler)
+	return nil
+}
+
+// listenUnix listens on a Unix socket at the given path with the given permissions.
+// The socket is created in a directory only accessible to the node user, and is moved
+// to the given path once its permissions are set, so no other user can connect to it
+// in between.
+func listenUnix(path string, permissions os.FileMode) (listener *net.UnixListener, err error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".ipc")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create socket directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tmpPath := filepath.Join(dir, "socket")
+	listener, err = net.ListenUnix("unix", &net.UnixAddr{Name: tmpPath, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// the socket is removed from its final path when stopping the server
+	listener.SetUnlinkOnClose(false)
+
+	err = os.Chmod(tmpPath, permissions)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("cannot set socket permissions: %w", err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("cannot move socket: %w", err)
+	}
+
+	return listener, nil
+}
+
+func (h *HTTPServer) serveIPC(listener net.Listener, handler http.Handler) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				h.logger.Errorf("IPC error: %s", err)
+			}
+			return
+		}
+
+		wsc := NewWSConn(newIPCConn(conn), h.serverConfig)
+		wsc.UnsafeEnabled = h.serverConfig.rpcUnsafeEnabled()
+		wsc.RPCHost = "http://ipc/"
+		wsc.HTTP = &handlerClient{handler: handler}
+
+		h.wsConnsMu.Lock()
+		h.wsConns = append(h.wsConns, wsc)
+		h.wsConnsMu.Unlock()
+
+		go func() {
+			wsc.HandleConn()
+			h.removeConn(wsc)
+		}()
+	}
+}
+
+// removeConn releases the subscriptions of the given connection, closes it
+// and forgets it, unless it was already closed when stopping the server.
+func (h *HTTPServer) removeConn(conn *subscription.WSConn) {
+	h.wsConnsMu.Lock()
+	defer h.wsConnsMu.Unlock()
+
+	for i, wsc := range h.wsConns {
+		if wsc == conn {
+			h.wsConns = append(h.wsConns[:i], h.wsConns[i+1:]...)
+			h.closeConn(conn)
+			return
+		}
+	}
+}
+
+// ipcConn is a connection to the IPC server, carrying a stream of JSON-RPC messages,
+// each message being a JSON value. The messages written are separated by new lines.
+type ipcConn struct {
+	conn    net.Conn
+	decoder *json.Decoder
+	encoder *json.Encoder
+}
+
+func newIPCConn(conn net.Conn) *ipcConn {
+	return &ipcConn{
+		conn:    conn,
+		decoder: json.NewDecoder(conn),
+		encoder: json.NewEncoder(conn),
+	}
+}
+
+// ReadMessage reads the next JSON value of the stream.
+func (c *ipcConn) ReadMessage() (messageType int, p []byte, err error) {
+	var message json.RawMessage
+	err = c.decoder.Decode(&message)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return websocket.TextMessage, message, nil
+}
+
+// WriteJSON writes the JSON encoding of v followed by a new line.
+func (c *ipcConn) WriteJSON(v interface{}) error {
+	return c.encoder.Encode(v)
+}
+
+// Close closes the connection.
+func (c *ipcConn) Close() error {
+	return c.conn.Close()
+}
+
+// handlerClient executes the HTTP requests against a handler in the same
+// process, so the IPC server does not depend on the HTTP-RPC server.
+type handlerClient struct {
+	handler http.Handler
+}
+
+// Do serves the request and returns the response of the handler.
+func (c *handlerClient) Do(r *http.Request) (*http.Response, error) {
+	r.RemoteAddr = ipcLocalAddress
+
+	recorder := &responseRecorder{header: make(http.Header)}
+	c.handler.ServeHTTP(recorder, r)
+
+	return &http.Response{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Header:     recorder.header,
+		Body:       io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Request:    r,
+	}, nil
+}
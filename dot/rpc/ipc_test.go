@@ -0,0 +1,102 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules"
+	"github.com/ChainSafe/gossamer/dot/system"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPServer_IPC(t *testing.T) {
+	ipcDir := t.TempDir()
+	ipcPath := filepath.Join(ipcDir, "gossamer.ipc")
+
+	cfg := &HTTPServerConfig{
+		Modules:        []string{"system", "state", "rpc"},
+		RPCAPI:         NewService(),
+		SystemAPI:      system.NewService(&types.SystemInfo{SystemName: "gossamer"}, nil, nil),
+		StorageAPI:     modules.NewMockStorageAPI(),
+		IPCPath:        ipcPath,
+		IPCPermissions: 0660,
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := s.Stop()
+		require.NoError(t, err)
+		_, err = os.Stat(ipcPath)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	info, err := os.Stat(ipcPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0660), info.Mode().Perm())
+	assert.NotZero(t, info.Mode()&os.ModeSocket)
+
+	// the directory the socket is created in is removed
+	entries, err := os.ReadDir(ipcDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "gossamer.ipc", entries[0].Name())
+
+	conn, err := net.Dial("unix", ipcPath)
+	require.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	testCases := []struct {
+		request  string
+		response string
+	}{
+		{
+			request:  `{"jsonrpc":"2.0","method":"system_name","params":[],"id":1}`,
+			response: `{"id":1,"jsonrpc":"2.0","result":"gossamer"}`,
+		},
+		{
+			request: `{"jsonrpc":"2.0","method":"system_addReservedPeer","params":["peer"],"id":2}`,
+			response: `{"error":{"code":-32000,"data":null,` +
+				`"message":"unsafe rpc method system_addReservedPeer cannot be reachable"},"id":2,"jsonrpc":"2.0"}`,
+		},
+		{
+			request:  `{"jsonrpc":"2.0","method":"state_subscribeStorage","params":[],"id":3}`,
+			response: `{"jsonrpc":"2.0","result":1,"id":3}`,
+		},
+		{
+			request: `[{"jsonrpc":"2.0","method":"system_name","params":[],"id":4},` +
+				`{"jsonrpc":"2.0","method":"system_name","params":[],"id":5}]`,
+			response: `[{"id":4,"jsonrpc":"2.0","result":"gossamer"},{"id":5,"jsonrpc":"2.0","result":"gossamer"}]`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, err = conn.Write([]byte(testCase.request))
+		require.NoError(t, err)
+
+		response, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, testCase.response+"\n", response)
+	}
+	// a parse error is sent before closing the connection on malformed JSON
+	_, err = conn.Write([]byte(`{"jsonrpc":"2.0","method"}`))
+	require.NoError(t, err)
+
+	response, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":0}`+"\n", response)
+
+	_, err = reader.ReadString('\n')
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -4,6 +4,7 @@
 package json2
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -241,6 +242,48 @@ func (c *CodecRequest) writeServerResponse(w http.ResponseWriter, res *serverRes
 	}
 }
 
+// EncodeErrorResponse returns the JSON encoding of an error response to the request
+// with the given id. The id is nil when the request it responds to is not known, for
+// example when a whole batch of requests is rejected.
+func EncodeErrorResponse(id *json.RawMessage, code json2.ErrorCode, message string) []byte {
+	res := &serverResponse{
+		Version: version,
+		Error: &json2.Error{
+			Code:    code,
+			Message: message,
+		},
+		ID: id,
+	}
+
+	// encoding cannot fail since the response only contains strings and numbers
+	encoded, _ := json.Marshal(res)
+	return encoded
+}
+
+// IsBatch returns true if the given request body is a batch, that is a JSON array
+// of request objects as opposed to a single request object.
+func IsBatch(body []byte) bool {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	return len(body) > 0 && body[0] == '['
+}
+
+// RequestsCount returns the number of requests of the given request body, that is the
+// length of the array if it is a batch or one otherwise. Empty and invalid batches are
+// counted as a single request.
+func RequestsCount(body []byte) uint32 {
+	if !IsBatch(body) {
+		return 1
+	}
+
+	var requests []json.RawMessage
+	err := json.Unmarshal(body, &requests)
+	if err != nil || len(requests) == 0 {
+		return 1
+	}
+
+	return uint32(len(requests))
+}
+
 func isParseErrorResponse(res *serverResponse) bool {
 	return res != nil && res.Error != nil && res.Error.Code == json2.E_PARSE
 }
@@ -0,0 +1,72 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/access"
+	"github.com/ChainSafe/gossamer/dot/rpc/json2"
+	gorillajson2 "github.com/gorilla/rpc/v2/json2"
+)
+
+// rateLimitHandler rejects the HTTP-RPC requests of the clients, identified by their IP
+// address, exceeding the configured request rate. The requests made by the websocket server
+// on behalf of its connections are already rate limited per connection, and are passed as
+// they are to the wrapped handler. Each request of a batch counts as a request, and a batch
+// larger than the rate limit counts as many requests as the rate limit.
+type rateLimitHandler struct {
+	handler       http.Handler
+	limiter       *access.KeyedLimiter
+	internalToken string
+}
+
+func newRateLimitHandler(handler http.Handler, requestsPerSecond uint32, internalToken string) http.Handler {
+	limiter := access.NewKeyedLimiter(requestsPerSecond)
+	if limiter == nil {
+		return handler
+	}
+
+	return &rateLimitHandler{
+		handler:       handler,
+		limiter:       limiter,
+		internalToken: internalToken,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *rateLimitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.internalToken != "" && r.Header.Get(access.InternalRequestHeader) == h.internalToken {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot read request body: %s", err), http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !h.limiter.AllowN(ip, json2.RequestsCount(body)) {
+		access.RecordRejected(access.TransportHTTP, access.ReasonRateLimited)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write(json2.EncodeErrorResponse(nil,
+			gorillajson2.ErrorCode(access.LimitExceededCode), "too many requests"))
+		return
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
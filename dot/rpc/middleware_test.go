@@ -0,0 +1,106 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/access"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rateLimitHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	_, isRateLimited := newRateLimitHandler(okHandler, 0, "token").(*rateLimitHandler)
+	assert.False(t, isRateLimited)
+
+	handler := newRateLimitHandler(okHandler, 1, "token")
+
+	serve := func(remoteAddr, token string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		request.RemoteAddr = remoteAddr
+		if token != "" {
+			request.Header.Set(access.InternalRequestHeader, token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	response := serve("127.0.0.1:1000", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "ok", response.Body.String())
+
+	// the rate is limited per IP address, whatever the port
+	response = serve("127.0.0.1:1001", "")
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32005,"message":"too many requests","data":null},"id":null}`,
+		response.Body.String())
+
+	response = serve("10.0.0.1:1000", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// requests forwarded by the websocket server are not limited
+	response = serve("127.0.0.1:1000", "token")
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	response = serve("127.0.0.1:1000", "wrong")
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+}
+
+func Test_rateLimitHandler_ServeHTTP_batch(t *testing.T) {
+	t.Parallel()
+
+	echoHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	})
+
+	handler := newRateLimitHandler(echoHandler, 2, "")
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		request.RemoteAddr = "127.0.0.1:1000"
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	const request = `{"jsonrpc":"2.0","method":"system_health","params":[],"id":1}`
+
+	// each request of a batch costs a token, and the body is passed as it is to the wrapped handler
+	batch := "[" + request + "]"
+	response := serve(batch)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, batch, response.Body.String())
+
+	batch = "[" + request + "," + request + "]"
+	response = serve(batch)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+
+	response = serve(request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = serve(request)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+
+	// a batch larger than the rate limit is charged the rate limit,
+	// so it is allowed once the client is within the rate limit again.
+	handler = newRateLimitHandler(echoHandler, 2, "")
+	batch = "[" + request + "," + request + "," + request + "]"
+	response = serve(batch)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, batch, response.Body.String())
+
+	response = serve(request)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+}
@@ -7,12 +7,15 @@
 	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/lib/trie"
 )
@@ -49,9 +52,13 @@ type BlockAPI interface {
 	GetFinalisedNotifierChannel() chan *types.FinalisationInfo
 	FreeFinalisedNotifierChannel(ch chan *types.FinalisationInfo)
 	SubChain(start, end common.Hash) ([]common.Hash, error)
+	Leaves() []common.Hash
 	RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error)
 	UnregisterRuntimeUpdatedChannel(id uint32) bool
 	GetRuntime(hash *common.Hash) (runtime.Instance, error)
+	GetIndexedTransaction(hash common.Hash) ([]byte, error)
+	PinBlock(hash common.Hash) error
+	UnpinBlock(hash common.Hash)
 }
 
 //go:generate mockery --name NetworkAPI --structname NetworkAPI --case underscore --keeptree
@@ -78,6 +85,7 @@ type BlockProducerAPI interface {
 	Resume() error
 	EpochLength() uint64
 	SlotDuration() uint64
+	EpochAuthorship(keypairs []*sr25519.Keypair) (map[string]*babe.EpochAuthorship, error)
 }
 
 //go:generate mockery --name TransactionStateAPI --structname TransactionStateAPI --case underscore --keeptree
@@ -88,8 +96,9 @@ type TransactionStateAPI interface {
 	Pop() *transaction.ValidTransaction
 	Peek() *transaction.ValidTransaction
 	Pending() []*transaction.ValidTransaction
-	GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status
-	FreeStatusNotifierChannel(ch chan transaction.Status)
+	GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.StatusNotification
+	FreeStatusNotifierChannel(ch chan transaction.StatusNotification)
+	RemoveExtrinsicByHash(hash common.Hash) bool
 }
 
 //go:generate mockery --name CoreAPI --structname CoreAPI --case underscore --keeptree
@@ -100,10 +109,17 @@ type CoreAPI interface {
 	HasKey(pubKeyStr string, keyType string) (bool, error)
 	GetRuntimeVersion(bhash *common.Hash) (runtime.Version, error)
 	HandleSubmittedExtrinsic(types.Extrinsic) error
+	BroadcastExtrinsic(types.Extrinsic) (inPool bool)
 	GetMetadata(bhash *common.Hash) ([]byte, error)
-	QueryStorage(from, to common.Hash, keys ...string) (map[common.Hash]core.QueryKeyValueChanges, error)
+	QueryStorage(from, to common.Hash, keys ...string) ([]core.StorageChangeSet, error)
+	QueryStorageAt(at common.Hash, keys ...string) (core.StorageChangeSet, error)
 	DecodeSessionKeys(enc []byte) ([]byte, error)
+	GenerateSessionKeys() ([]byte, error)
 	GetReadProofAt(block common.Hash, keys [][]byte) (common.Hash, [][]byte, error)
+	GetChildReadProofAt(block common.Hash, keyToChild []byte, keys [][]byte) (common.Hash, [][]byte, error)
+	CallRuntimeAPI(bhash *common.Hash, method string, data []byte) ([]byte, error)
+	TraceBlock(hash common.Hash) (*types.Header, []rtstorage.TraceEvent, error)
+	DryRun(ext types.Extrinsic, bhash *common.Hash) ([]byte, error)
 }
 
 //go:generate mockery --name RPCAPI --structname RPCAPI --case underscore --keeptree
@@ -123,6 +139,8 @@ type SystemAPI interface {
 	Properties() map[string]interface{}
 	ChainType() string
 	ChainName() string
+	AddLogFilter(filter string) error
+	ResetLogFilter() error
 }
 
 //go:generate mockery --name BlockFinalityAPI --structname BlockFinalityAPI --case underscore --keeptree
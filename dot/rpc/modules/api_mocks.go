@@ -52,8 +52,9 @@ func NewMockBlockAPI() *modulesmocks.BlockAPI {
 // NewMockTransactionStateAPI creates and return an rpc TransactionStateAPI interface mock
 func NewMockTransactionStateAPI() *modulesmocks.TransactionStateAPI {
 	m := new(modulesmocks.TransactionStateAPI)
-	m.On("FreeStatusNotifierChannel", mock.AnythingOfType("chan transaction.Status"))
-	m.On("GetStatusNotifierChannel", mock.AnythingOfType("types.Extrinsic")).Return(make(chan transaction.Status))
+	m.On("FreeStatusNotifierChannel", mock.AnythingOfType("chan transaction.StatusNotification"))
+	m.On("GetStatusNotifierChannel", mock.AnythingOfType("types.Extrinsic")).
+		Return(make(chan transaction.StatusNotification))
 	m.On("AddToPool", mock.AnythingOfType("transaction.ValidTransaction")).Return(common.Hash{})
 	return m
 }
@@ -79,6 +80,7 @@ func NewMockVersion() *runtimemocks.Version {
 	m.On("SpecVersion").Return(uint32(0))
 	m.On("ImplVersion").Return(uint32(0))
 	m.On("TransactionVersion").Return(uint32(0))
+	m.On("StateVersion").Return(uint8(0))
 	m.On("APIItems").Return(nil)
 	return m
 }
@@ -17,6 +17,8 @@
 
 var ErrProvidedKeyDoesNotMatch = errors.New("generated public key does not equal provide public key")
 
+var errHashOrExtrinsicRequired = errors.New("either an extrinsic hash or an extrinsic is required")
+
 // AuthorModule holds a pointer to the API
 type AuthorModule struct {
 	logger     log.LeveledLogger
@@ -41,10 +43,10 @@ type Extrinsic struct {
 	Data string
 }
 
-// ExtrinsicOrHash is a type for Hash and Extrinsic array of bytes
+// ExtrinsicOrHash is either the hash of an extrinsic, or a hex-encoded extrinsic
 type ExtrinsicOrHash struct {
-	Hash      common.Hash
-	Extrinsic []byte
+	Hash      *common.Hash `json:"hash,omitempty"`
+	Extrinsic string       `json:"extrinsic,omitempty"`
 }
 
 // ExtrinsicOrHashRequest is a array of ExtrinsicOrHash
@@ -59,8 +61,8 @@ type ExtrinsicOrHash struct {
 // RemoveExtrinsicsResponse is a array of hash used to Remove extrinsics
 type RemoveExtrinsicsResponse []common.Hash
 
-// KeyRotateResponse is a byte array used to rotate
-type KeyRotateResponse []byte
+// KeyRotateResponse is the hex encoded public session keys returned by author_rotateKeys
+type KeyRotateResponse string
 
 // HasSessionKeyResponse is the response to the RPC call author_hasSessionKeys
 type HasSessionKeyResponse bool
@@ -192,19 +194,50 @@ func (am *AuthorModule) PendingExtrinsics(r *http.Request, req *EmptyRequest, re
 	return nil
 }
 
-// RemoveExtrinsic Remove given extrinsic from the pool and temporarily ban it to prevent reimporting
-func (am *AuthorModule) RemoveExtrinsic(r *http.Request, _ *ExtrinsicOrHashRequest, _ *RemoveExtrinsicsResponse) error {
+// RemoveExtrinsic removes the given extrinsics from the transaction queue and pool,
+// and returns the hashes of the extrinsics that were removed.
+func (am *AuthorModule) RemoveExtrinsic(r *http.Request, req *ExtrinsicOrHashRequest,
+	res *RemoveExtrinsicsResponse) error {
+	removed := RemoveExtrinsicsResponse{}
+	for _, extOrHash := range *req {
+		var hash common.Hash
+		switch {
+		case extOrHash.Hash != nil:
+			hash = *extOrHash.Hash
+		case extOrHash.Extrinsic != "":
+			extBytes, err := common.HexToBytes(extOrHash.Extrinsic)
+			if err != nil {
+				return err
+			}
+			hash = types.Extrinsic(extBytes).Hash()
+		default:
+			return errHashOrExtrinsicRequired
+		}
+
+		if am.txStateAPI.RemoveExtrinsicByHash(hash) {
+			removed = append(removed, hash)
+		}
+	}
+
+	*res = removed
 	return nil
 }
 
 // RotateKeys Generate new session keys and returns the corresponding public keys
 func (am *AuthorModule) RotateKeys(r *http.Request, req *EmptyRequest, res *KeyRotateResponse) error {
+	keys, err := am.coreAPI.GenerateSessionKeys()
+	if err != nil {
+		return err
+	}
+
+	*res = KeyRotateResponse(common.BytesToHex(keys))
 	return nil
 }
 
-// SubmitAndWatchExtrinsic Submit and subscribe to watch an extrinsic until unsubscribed
+// SubmitAndWatchExtrinsic handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
 func (am *AuthorModule) SubmitAndWatchExtrinsic(r *http.Request, req *Extrinsic, res *ExtrinsicStatus) error {
-	return nil
+	return ErrSubscriptionTransport
 }
 
 // SubmitExtrinsic Submit a fully formatted extrinsic for block inclusion
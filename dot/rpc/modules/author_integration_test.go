@@ -127,6 +127,7 @@ func TestAuthorModule_SubmitExtrinsic_Integration(t *testing.T) {
 
 	net2test := coremocks.NewMockNetwork(ctrl)
 	net2test.EXPECT().GossipMessage(&network.TransactionMessage{Extrinsics: []types.Extrinsic{extBytes}})
+	net2test.EXPECT().Peers()
 	integrationTestController.network = net2test
 
 	// setup auth module
@@ -529,6 +530,7 @@ func TestAuthorModule_SubmitExtrinsic_WithVersion_V0910(t *testing.T) {
 
 	net2test := coremocks.NewMockNetwork(ctrl)
 	net2test.EXPECT().GossipMessage(&network.TransactionMessage{Extrinsics: []types.Extrinsic{extBytes}})
+	net2test.EXPECT().Peers()
 	integrationTestController.network = net2test
 
 	// setup auth module
@@ -696,7 +698,7 @@ func setupStateAndPopulateTrieState(t *testing.T, basepath string,
 		err = state2test.Block.AddBlock(b)
 		require.NoError(t, err)
 
-		err = state2test.Storage.StoreTrie(rtStorage, &b.Header)
+		err = state2test.Storage.StoreTrie(rtStorage, &b.Header, trie.V0)
 		require.NoError(t, err)
 
 		state2test.Block.StoreRuntime(b.Header.Hash(), rt)
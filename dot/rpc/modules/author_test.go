@@ -294,6 +294,73 @@ type args struct {
 	}
 }
 
+func TestAuthorModule_RemoveExtrinsic(t *testing.T) {
+	ext := types.Extrinsic{1, 2, 3}
+	extHash := ext.Hash()
+	unknownHash := common.MustHexToHash("0x01")
+
+	mockTransactionStateAPI := &mocks.TransactionStateAPI{}
+	mockTransactionStateAPI.On("RemoveExtrinsicByHash", extHash).Return(true)
+	mockTransactionStateAPI.On("RemoveExtrinsicByHash", unknownHash).Return(false)
+
+	tests := []struct {
+		name    string
+		req     ExtrinsicOrHashRequest
+		expErr  error
+		wantRes RemoveExtrinsicsResponse
+	}{
+		{
+			name:    "by hash",
+			req:     ExtrinsicOrHashRequest{{Hash: &extHash}},
+			wantRes: RemoveExtrinsicsResponse{extHash},
+		},
+		{
+			name:    "by extrinsic",
+			req:     ExtrinsicOrHashRequest{{Extrinsic: common.BytesToHex(ext)}},
+			wantRes: RemoveExtrinsicsResponse{extHash},
+		},
+		{
+			name:    "unknown hash",
+			req:     ExtrinsicOrHashRequest{{Hash: &unknownHash}, {Hash: &extHash}},
+			wantRes: RemoveExtrinsicsResponse{extHash},
+		},
+		{
+			name:    "invalid extrinsic",
+			req:     ExtrinsicOrHashRequest{{Extrinsic: "0xzz"}},
+			expErr:  errors.New("encoding/hex: invalid byte: U+007A 'z': 0xzz"),
+			wantRes: RemoveExtrinsicsResponse{},
+		},
+		{
+			name:    "empty entry",
+			req:     ExtrinsicOrHashRequest{{}},
+			expErr:  errHashOrExtrinsicRequired,
+			wantRes: RemoveExtrinsicsResponse{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := &AuthorModule{
+				logger:     log.New(log.SetWriter(io.Discard)),
+				txStateAPI: mockTransactionStateAPI,
+			}
+			res := RemoveExtrinsicsResponse{}
+			err := am.RemoveExtrinsic(nil, &tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRes, res)
+		})
+	}
+}
+
+func TestAuthorModule_SubmitAndWatchExtrinsic(t *testing.T) {
+	am := NewAuthorModule(log.New(log.SetWriter(io.Discard)), nil, nil)
+	err := am.SubmitAndWatchExtrinsic(nil, &Extrinsic{}, &ExtrinsicStatus{})
+	require.ErrorIs(t, err, ErrSubscriptionTransport)
+}
+
 func TestAuthorModule_InsertKey(t *testing.T) {
 	kp1, err := sr25519.NewKeypairFromSeed(
 		common.MustHexToBytes("0x6246ddf254e0b4b4e7dffefc8adf69d212b98ac2b579c362b473fec8c40b4c0a"))
@@ -489,3 +556,46 @@ type args struct {
 		})
 	}
 }
+
+func TestAuthorModule_RotateKeys(t *testing.T) {
+	keys := common.MustHexToBytes("0x0102030405")
+
+	mockCoreAPI := &mocks.CoreAPI{}
+	mockCoreAPI.On("GenerateSessionKeys").Return(keys, nil)
+
+	mockCoreAPIErr := &mocks.CoreAPI{}
+	mockCoreAPIErr.On("GenerateSessionKeys").Return(nil, fmt.Errorf("some error"))
+
+	tests := []struct {
+		name    string
+		coreAPI CoreAPI
+		expErr  error
+		wantRes KeyRotateResponse
+	}{
+		{
+			name:    "RotateKeys OK",
+			coreAPI: mockCoreAPI,
+			wantRes: KeyRotateResponse("0x0102030405"),
+		},
+		{
+			name:    "RotateKeys error",
+			coreAPI: mockCoreAPIErr,
+			expErr:  fmt.Errorf("some error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := &AuthorModule{
+				coreAPI: tt.coreAPI,
+			}
+			var res KeyRotateResponse
+			err := am.RotateKeys(nil, nil, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRes, res)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+)
+
+var errNotBlockProducer = errors.New("not a block producer")
+
+// EpochAuthorship holds the slots of the current epoch a BABE key can claim
+type EpochAuthorship struct {
+	Primary      []uint64 `json:"primary"`
+	Secondary    []uint64 `json:"secondary"`
+	SecondaryVRF []uint64 `json:"secondary_vrf"`
+}
+
+// EpochAuthorshipResponse maps the SS58 address of each local BABE key to its epoch authorship
+type EpochAuthorshipResponse map[string]*EpochAuthorship
+
+// BabeModule is an RPC module providing access to BABE related information
+type BabeModule struct {
+	blockProducerAPI BlockProducerAPI
+	keystore         keystore.Keystore
+}
+
+// NewBabeModule creates a new BABE module.
+func NewBabeModule(bp BlockProducerAPI, ks keystore.Keystore) *BabeModule {
+	return &BabeModule{
+		blockProducerAPI: bp,
+		keystore:         ks,
+	}
+}
+
+// EpochAuthorship returns, for each local BABE key, the primary, secondary and
+// secondary VRF slots it can claim in the current epoch.
+func (bm *BabeModule) EpochAuthorship(_ *http.Request, _ *EmptyRequest, res *EpochAuthorshipResponse) error {
+	if bm.blockProducerAPI == nil {
+		return errNotBlockProducer
+	}
+
+	var keypairs []*sr25519.Keypair
+	if bm.keystore != nil {
+		for _, kp := range bm.keystore.Keypairs() {
+			if srKp, ok := kp.(*sr25519.Keypair); ok {
+				keypairs = append(keypairs, srKp)
+			}
+		}
+	}
+
+	authorships, err := bm.blockProducerAPI.EpochAuthorship(keypairs)
+	if err != nil {
+		return fmt.Errorf("cannot get epoch authorship: %w", err)
+	}
+
+	response := make(EpochAuthorshipResponse, len(authorships))
+	for address, authorship := range authorships {
+		response[address] = &EpochAuthorship{
+			Primary:      authorship.Primary,
+			Secondary:    authorship.Secondary,
+			SecondaryVRF: authorship.SecondaryVRF,
+		}
+	}
+
+	*res = response
+	return nil
+}
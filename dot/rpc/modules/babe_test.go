@@ -0,0 +1,97 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/lib/babe"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBabeModule_EpochAuthorship(t *testing.T) {
+	t.Parallel()
+
+	srKeyring, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	alice := srKeyring.Alice().(*sr25519.Keypair)
+
+	edKeyring, err := keystore.NewEd25519Keyring()
+	require.NoError(t, err)
+
+	ks := keystore.NewGenericKeystore("babe")
+	err = ks.Insert(alice)
+	require.NoError(t, err)
+	err = ks.Insert(edKeyring.Bob().(*ed25519.Keypair))
+	require.NoError(t, err)
+
+	aliceAddress := string(alice.Public().(*sr25519.PublicKey).Address())
+
+	testCases := map[string]struct {
+		blockProducerAPI func() BlockProducerAPI
+		response         EpochAuthorshipResponse
+		errMessage       string
+	}{
+		"not a block producer": {
+			blockProducerAPI: func() BlockProducerAPI { return nil },
+			errMessage:       "not a block producer",
+		},
+		"epoch authorship error": {
+			blockProducerAPI: func() BlockProducerAPI {
+				blockProducerAPI := new(mocks.BlockProducerAPI)
+				blockProducerAPI.On("EpochAuthorship", []*sr25519.Keypair{alice}).
+					Return(nil, errors.New("test error"))
+				return blockProducerAPI
+			},
+			errMessage: "cannot get epoch authorship: test error",
+		},
+		"success": {
+			blockProducerAPI: func() BlockProducerAPI {
+				blockProducerAPI := new(mocks.BlockProducerAPI)
+				blockProducerAPI.On("EpochAuthorship", []*sr25519.Keypair{alice}).
+					Return(map[string]*babe.EpochAuthorship{
+						aliceAddress: {
+							Primary:      []uint64{1, 3},
+							Secondary:    []uint64{2},
+							SecondaryVRF: []uint64{},
+						},
+					}, nil)
+				return blockProducerAPI
+			},
+			response: EpochAuthorshipResponse{
+				aliceAddress: {
+					Primary:      []uint64{1, 3},
+					Secondary:    []uint64{2},
+					SecondaryVRF: []uint64{},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			module := NewBabeModule(testCase.blockProducerAPI(), ks)
+
+			var response EpochAuthorshipResponse
+			err := module.EpochAuthorship(nil, &EmptyRequest{}, &response)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.response, response)
+		})
+	}
+}
@@ -4,11 +4,13 @@
 package modules
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
 	"strconv"
 
+	"github.com/ChainSafe/chaindb"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -30,6 +32,11 @@ type ChainFinalizedHeadRequest struct {
 	SetID uint64
 }
 
+// ChainIndexedTransactionRequest holds the hash of the data indexed by a transaction
+type ChainIndexedTransactionRequest struct {
+	Hash common.Hash
+}
+
 // ChainBlockHeaderResponse struct
 type ChainBlockHeaderResponse struct {
 	ParentHash     string                 `json:"parentHash"`
@@ -58,6 +65,9 @@ type ChainBlockResponse struct {
 // ChainHashResponse interface to handle response
 type ChainHashResponse interface{}
 
+// ChainIndexedTransactionResponse is the hex encoded data indexed by a transaction, or nil if it is not found
+type ChainIndexedTransactionResponse interface{}
+
 // ChainModule is an RPC module providing access to storage API points.
 type ChainModule struct {
 	blockAPI BlockAPI
@@ -151,6 +161,22 @@ func (cm *ChainModule) GetHeader(r *http.Request, req *ChainHashRequest, res *Ch
 	return err
 }
 
+// GetIndexedTransaction returns the data indexed by a transaction given its hash, or null if
+//  the data is not indexed or was pruned.
+func (cm *ChainModule) GetIndexedTransaction(_ *http.Request, req *ChainIndexedTransactionRequest,
+	res *ChainIndexedTransactionResponse) error {
+	data, err := cm.blockAPI.GetIndexedTransaction(req.Hash)
+	if errors.Is(err, chaindb.ErrKeyNotFound) {
+		*res = nil
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	*res = common.BytesToHex(data)
+	return nil
+}
+
 // SubscribeFinalizedHeads handled by websocket handler, but this func should remain
 //  here so it's added to rpc_methods list
 func (cm *ChainModule) SubscribeFinalizedHeads(_ *http.Request, _ *EmptyRequest, _ *ChainBlockHeaderResponse) error {
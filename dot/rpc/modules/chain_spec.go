@@ -0,0 +1,47 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ChainSpecModule is an RPC module providing the chainSpec_v1 methods,
+// which return static information about the chain specification.
+type ChainSpecModule struct {
+	systemAPI SystemAPI
+	blockAPI  BlockAPI
+}
+
+// NewChainSpecModule creates a new chainSpec_v1 module.
+func NewChainSpecModule(systemAPI SystemAPI, blockAPI BlockAPI) *ChainSpecModule {
+	return &ChainSpecModule{
+		systemAPI: systemAPI,
+		blockAPI:  blockAPI,
+	}
+}
+
+// ChainName returns the name of the chain
+func (cm *ChainSpecModule) ChainName(_ *http.Request, _ *EmptyRequest, res *string) error {
+	*res = cm.systemAPI.ChainName()
+	return nil
+}
+
+// GenesisHash returns the hash of the genesis block
+func (cm *ChainSpecModule) GenesisHash(_ *http.Request, _ *EmptyRequest, res *string) error {
+	hash, err := cm.blockAPI.GetHashByNumber(0)
+	if err != nil {
+		return fmt.Errorf("cannot get genesis hash: %w", err)
+	}
+
+	*res = hash.String()
+	return nil
+}
+
+// Properties returns the properties of the chain specification
+func (cm *ChainSpecModule) Properties(_ *http.Request, _ *EmptyRequest, res *interface{}) error {
+	*res = cm.systemAPI.Properties()
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainSpecModule(t *testing.T) {
+	t.Parallel()
+
+	systemAPI := new(mocks.SystemAPI)
+	systemAPI.On("ChainName").Return("Gossamer")
+	systemAPI.On("Properties").Return(map[string]interface{}{"ss58Format": 42})
+
+	blockAPI := new(mocks.BlockAPI)
+	blockAPI.On("GetHashByNumber", uint(0)).Return(common.Hash{1}, nil)
+
+	module := NewChainSpecModule(systemAPI, blockAPI)
+
+	var name string
+	err := module.ChainName(nil, &EmptyRequest{}, &name)
+	require.NoError(t, err)
+	assert.Equal(t, "Gossamer", name)
+
+	var genesisHash string
+	err = module.GenesisHash(nil, &EmptyRequest{}, &genesisHash)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{1}.String(), genesisHash)
+
+	var properties interface{}
+	err = module.Properties(nil, &EmptyRequest{}, &properties)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ss58Format": 42}, properties)
+
+	errBlockAPI := new(mocks.BlockAPI)
+	errBlockAPI.On("GetHashByNumber", uint(0)).Return(common.Hash{}, errors.New("test error"))
+	module = NewChainSpecModule(systemAPI, errBlockAPI)
+	err = module.GenesisHash(nil, &EmptyRequest{}, &genesisHash)
+	assert.EqualError(t, err, "cannot get genesis hash: test error")
+}
@@ -8,6 +8,7 @@
 	"net/http"
 	"testing"
 
+	"github.com/ChainSafe/chaindb"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -350,6 +351,55 @@ type args struct {
 	}
 }
 
+func TestChainModule_GetIndexedTransaction(t *testing.T) {
+	testHash := common.Hash{1}
+	mockBlockAPI := new(mocks.BlockAPI)
+	mockBlockAPI.On("GetIndexedTransaction", testHash).Return([]byte{1, 2}, nil)
+
+	mockBlockAPINotFound := new(mocks.BlockAPI)
+	mockBlockAPINotFound.On("GetIndexedTransaction", testHash).Return(nil, chaindb.ErrKeyNotFound)
+
+	mockBlockAPIErr := new(mocks.BlockAPI)
+	mockBlockAPIErr.On("GetIndexedTransaction", testHash).Return(nil, errors.New("GetIndexedTransaction Error"))
+
+	tests := []struct {
+		name     string
+		blockAPI BlockAPI
+		expErr   error
+		exp      ChainIndexedTransactionResponse
+	}{
+		{
+			name:     "GetIndexedTransaction OK",
+			blockAPI: mockBlockAPI,
+			exp:      "0x0102",
+		},
+		{
+			name:     "GetIndexedTransaction not found",
+			blockAPI: mockBlockAPINotFound,
+		},
+		{
+			name:     "GetIndexedTransaction ERR",
+			blockAPI: mockBlockAPIErr,
+			expErr:   errors.New("GetIndexedTransaction Error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &ChainModule{
+				blockAPI: tt.blockAPI,
+			}
+			res := ChainIndexedTransactionResponse(nil)
+			err := cm.GetIndexedTransaction(nil, &ChainIndexedTransactionRequest{Hash: testHash}, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
+
 func TestChainModule_GetHeader(t *testing.T) {
 	emptyHeader := types.NewEmptyHeader()
 	testHash := common.NewHash([]byte{0x01, 0x02})
@@ -4,9 +4,12 @@
 package modules
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
 )
 
 // GetKeysRequest represents the request to retrieve the keys of a child storage
@@ -16,6 +19,22 @@ type GetKeysRequest struct {
 	Hash   *common.Hash
 }
 
+// GetKeysPagedRequest represents the request to retrieve a page of the keys of a child storage
+type GetKeysPagedRequest struct {
+	Key      []byte
+	Prefix   []byte
+	Qty      uint32
+	StartKey []byte
+	Hash     *common.Hash
+}
+
+// GetStorageEntriesRequest represents the request to retrieve multiple child storage entries
+type GetStorageEntriesRequest struct {
+	Key  []byte
+	Keys [][]byte
+	Hash *common.Hash
+}
+
 // ChildStateStorageRequest holds json fields
 type ChildStateStorageRequest struct {
 	ChildStorageKey []byte       `json:"childStorageKey"`
@@ -81,6 +100,56 @@ func (cs *ChildStateModule) GetKeys(_ *http.Request, req *GetKeysRequest, res *[
 	return nil
 }
 
+// GetKeysPaged returns at most Qty keys from the specified child storage, starting after StartKey.
+// The keys can also be filtered based on a prefix.
+func (cs *ChildStateModule) GetKeysPaged(_ *http.Request, req *GetKeysPagedRequest, res *[]string) error {
+	childTrie, err := cs.getStorageChild(req.Hash, req.Key)
+	if err != nil {
+		return err
+	}
+
+	hexKeys := make([]string, 0, req.Qty)
+	for _, k := range childTrie.GetKeysWithPrefix(req.Prefix) {
+		if uint32(len(hexKeys)) >= req.Qty {
+			break
+		}
+
+		// keys are sorted in lexicographical order, so we skip
+		// the keys up to and including the start key.
+		if bytes.Compare(k, req.StartKey) <= 0 {
+			continue
+		}
+
+		hexKeys = append(hexKeys, common.BytesToHex(k))
+	}
+
+	*res = hexKeys
+	return nil
+}
+
+// GetStorageEntries returns the child storage entries for the given keys,
+// with a nil entry for each key not present in the child storage.
+func (cs *ChildStateModule) GetStorageEntries(_ *http.Request, req *GetStorageEntriesRequest, res *[]*string) error {
+	childTrie, err := cs.getStorageChild(req.Hash, req.Key)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*string, len(req.Keys))
+	for i, key := range req.Keys {
+		item := childTrie.Get(key)
+		if item == nil {
+			continue
+		}
+
+		entry := common.BytesToHex(item)
+		entries[i] = &entry
+	}
+
+	*res = entries
+	return nil
+}
+
 // GetStorageSize returns the size of a child storage entry.
 func (cs *ChildStateModule) GetStorageSize(_ *http.Request, req *GetChildStorageRequest, res *uint64) error {
 	var hash common.Hash
@@ -166,3 +235,28 @@ func (cs *ChildStateModule) GetStorage(
 
 	return nil
 }
+
+// getStorageChild returns the child trie at the given child storage key in the state
+// of the given block, or of the best block if no block is given.
+func (cs *ChildStateModule) getStorageChild(hash *common.Hash, keyToChild []byte) (*trie.Trie, error) {
+	if hash == nil {
+		bestHash := cs.blockAPI.BestBlockHash()
+		hash = &bestHash
+	}
+
+	stateRoot, err := cs.storageAPI.GetStateRootFromBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	childTrie, err := cs.storageAPI.GetStorageChild(stateRoot, keyToChild)
+	if err != nil {
+		return nil, err
+	}
+
+	if childTrie == nil {
+		return nil, fmt.Errorf("%w at key 0x%x%x", trie.ErrChildTrieDoesNotExist, trie.ChildStorageKeyPrefix, keyToChild)
+	}
+
+	return childTrie, nil
+}
@@ -261,7 +261,7 @@ func setupChildStateStorage(t *testing.T) (*ChildStateModule, common.Hash) {
 	bb, err := st.Block.BestBlock()
 	require.NoError(t, err)
 
-	err = st.Storage.StoreTrie(tr, nil)
+	err = st.Storage.StoreTrie(tr, nil, trie.V0)
 	require.NoError(t, err)
 
 	digest := types.NewDigest()
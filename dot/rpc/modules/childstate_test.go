@@ -527,3 +527,174 @@ type args struct {
 		})
 	}
 }
+
+func TestChildStateModule_GetKeysPaged(t *testing.T) {
+	childTr := trie.NewEmptyTrie()
+	childTr.Put([]byte(":child_first"), []byte(":child_first_value"))
+	childTr.Put([]byte(":child_second"), []byte(":child_second_value"))
+	childTr.Put([]byte(":another_child"), []byte("value"))
+	childTr.Put([]byte("other"), []byte("value"))
+
+	hash := common.Hash{1}
+	stateRoot := common.Hash{2}
+
+	mockBlockAPI := new(apimocks.BlockAPI)
+	mockBlockAPI.On("BestBlockHash").Return(hash)
+
+	mockStorageAPI := new(apimocks.StorageAPI)
+	mockStorageAPI.On("GetStateRootFromBlock", &hash).Return(&stateRoot, nil)
+	mockStorageAPI.On("GetStorageChild", &stateRoot, []byte(":child_storage_key")).Return(childTr, nil)
+
+	mockErrorStorageAPI := new(apimocks.StorageAPI)
+	mockErrorStorageAPI.On("GetStateRootFromBlock", &hash).Return(&stateRoot, nil)
+	mockErrorStorageAPI.On("GetStorageChild", &stateRoot, []byte(":child_storage_key")).
+		Return(nil, errors.New("GetStorageChild error"))
+
+	tests := []struct {
+		name       string
+		storageAPI StorageAPI
+		req        *GetKeysPagedRequest
+		expErr     error
+		exp        []string
+	}{
+		{
+			name:       "all keys",
+			storageAPI: mockStorageAPI,
+			req: &GetKeysPagedRequest{
+				Key: []byte(":child_storage_key"),
+				Qty: 10,
+			},
+			exp: []string{
+				common.BytesToHex([]byte(":another_child")),
+				common.BytesToHex([]byte(":child_first")),
+				common.BytesToHex([]byte(":child_second")),
+				common.BytesToHex([]byte("other")),
+			},
+		},
+		{
+			name:       "keys with prefix after start key",
+			storageAPI: mockStorageAPI,
+			req: &GetKeysPagedRequest{
+				Key:      []byte(":child_storage_key"),
+				Prefix:   []byte(":"),
+				Qty:      10,
+				StartKey: []byte(":another_child"),
+				Hash:     &hash,
+			},
+			exp: []string{
+				common.BytesToHex([]byte(":child_first")),
+				common.BytesToHex([]byte(":child_second")),
+			},
+		},
+		{
+			name:       "quantity limit",
+			storageAPI: mockStorageAPI,
+			req: &GetKeysPagedRequest{
+				Key:      []byte(":child_storage_key"),
+				Qty:      2,
+				StartKey: []byte(":b"),
+			},
+			exp: []string{
+				common.BytesToHex([]byte(":child_first")),
+				common.BytesToHex([]byte(":child_second")),
+			},
+		},
+		{
+			name:       "GetStorageChild error",
+			storageAPI: mockErrorStorageAPI,
+			req: &GetKeysPagedRequest{
+				Key: []byte(":child_storage_key"),
+				Qty: 10,
+			},
+			exp:    []string{},
+			expErr: errors.New("GetStorageChild error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewChildStateModule(tt.storageAPI, mockBlockAPI)
+			res := []string{}
+			err := cs.GetKeysPaged(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
+
+func TestChildStateModule_GetStorageEntries(t *testing.T) {
+	childTr := trie.NewEmptyTrie()
+	childTr.Put([]byte(":child_first"), []byte(":child_first_value"))
+	childTr.Put([]byte(":child_second"), []byte(":child_second_value"))
+
+	hash := common.Hash{1}
+	stateRoot := common.Hash{2}
+
+	mockBlockAPI := new(apimocks.BlockAPI)
+	mockBlockAPI.On("BestBlockHash").Return(hash)
+
+	mockStorageAPI := new(apimocks.StorageAPI)
+	mockStorageAPI.On("GetStateRootFromBlock", &hash).Return(&stateRoot, nil)
+	mockStorageAPI.On("GetStorageChild", &stateRoot, []byte(":child_storage_key")).Return(childTr, nil)
+	mockStorageAPI.On("GetStorageChild", &stateRoot, []byte(":unknown")).Return(nil, nil)
+
+	mockErrorStorageAPI := new(apimocks.StorageAPI)
+	mockErrorStorageAPI.On("GetStateRootFromBlock", &hash).Return(nil, errors.New("GetStateRootFromBlock error"))
+
+	firstValue := common.BytesToHex([]byte(":child_first_value"))
+	secondValue := common.BytesToHex([]byte(":child_second_value"))
+
+	tests := []struct {
+		name       string
+		storageAPI StorageAPI
+		req        *GetStorageEntriesRequest
+		expErr     error
+		exp        []*string
+	}{
+		{
+			name:       "existing and missing keys",
+			storageAPI: mockStorageAPI,
+			req: &GetStorageEntriesRequest{
+				Key:  []byte(":child_storage_key"),
+				Keys: [][]byte{[]byte(":child_second"), []byte(":missing"), []byte(":child_first")},
+				Hash: &hash,
+			},
+			exp: []*string{&secondValue, nil, &firstValue},
+		},
+		{
+			name:       "child trie does not exist",
+			storageAPI: mockStorageAPI,
+			req: &GetStorageEntriesRequest{
+				Key:  []byte(":unknown"),
+				Keys: [][]byte{[]byte(":child_first")},
+			},
+			expErr: errors.New("child trie does not exist at key " +
+				"0x3a6368696c645f73746f726167653a64656661756c743a3a756e6b6e6f776e"),
+		},
+		{
+			name:       "GetStateRootFromBlock error",
+			storageAPI: mockErrorStorageAPI,
+			req: &GetStorageEntriesRequest{
+				Key:  []byte(":child_storage_key"),
+				Keys: [][]byte{[]byte(":child_first")},
+			},
+			expErr: errors.New("GetStateRootFromBlock error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewChildStateModule(tt.storageAPI, mockBlockAPI)
+			var res []*string
+			err := cs.GetStorageEntries(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
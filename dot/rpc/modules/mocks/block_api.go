@@ -189,6 +189,29 @@ func (_m *BlockAPI) GetImportedBlockNotifierChannel() chan *types.Block {
 	return r0
 }
 
+// GetIndexedTransaction provides a mock function with given fields: hash
+func (_m *BlockAPI) GetIndexedTransaction(hash common.Hash) ([]byte, error) {
+	ret := _m.Called(hash)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(common.Hash) []byte); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetJustification provides a mock function with given fields: hash
 func (_m *BlockAPI) GetJustification(hash common.Hash) ([]byte, error) {
 	ret := _m.Called(hash)
@@ -256,6 +279,36 @@ func (_m *BlockAPI) HasJustification(hash common.Hash) (bool, error) {
 	return r0, r1
 }
 
+// Leaves provides a mock function with given fields:
+func (_m *BlockAPI) Leaves() []common.Hash {
+	ret := _m.Called()
+
+	var r0 []common.Hash
+	if rf, ok := ret.Get(0).(func() []common.Hash); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	return r0
+}
+
+// PinBlock provides a mock function with given fields: hash
+func (_m *BlockAPI) PinBlock(hash common.Hash) error {
+	ret := _m.Called(hash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Hash) error); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RegisterRuntimeUpdatedChannel provides a mock function with given fields: ch
 func (_m *BlockAPI) RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error) {
 	ret := _m.Called(ch)
@@ -300,6 +353,11 @@ func (_m *BlockAPI) SubChain(start common.Hash, end common.Hash) ([]common.Hash,
 	return r0, r1
 }
 
+// UnpinBlock provides a mock function with given fields: hash
+func (_m *BlockAPI) UnpinBlock(hash common.Hash) {
+	_m.Called(hash)
+}
+
 // UnregisterRuntimeUpdatedChannel provides a mock function with given fields: id
 func (_m *BlockAPI) UnregisterRuntimeUpdatedChannel(id uint32) bool {
 	ret := _m.Called(id)
@@ -2,13 +2,41 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	babe "github.com/ChainSafe/gossamer/lib/babe"
+	mock "github.com/stretchr/testify/mock"
+
+	sr25519 "github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+)
 
 // BlockProducerAPI is an autogenerated mock type for the BlockProducerAPI type
 type BlockProducerAPI struct {
 	mock.Mock
 }
 
+// EpochAuthorship provides a mock function with given fields: keypairs
+func (_m *BlockProducerAPI) EpochAuthorship(keypairs []*sr25519.Keypair) (map[string]*babe.EpochAuthorship, error) {
+	ret := _m.Called(keypairs)
+
+	var r0 map[string]*babe.EpochAuthorship
+	if rf, ok := ret.Get(0).(func([]*sr25519.Keypair) map[string]*babe.EpochAuthorship); ok {
+		r0 = rf(keypairs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*babe.EpochAuthorship)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]*sr25519.Keypair) error); ok {
+		r1 = rf(keypairs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EpochLength provides a mock function with given fields:
 func (_m *BlockProducerAPI) EpochLength() uint64 {
 	ret := _m.Called()
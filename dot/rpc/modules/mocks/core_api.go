@@ -12,6 +12,8 @@
 
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
 
+	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+
 	types "github.com/ChainSafe/gossamer/dot/types"
 )
 
@@ -20,6 +22,43 @@ type CoreAPI struct {
 	mock.Mock
 }
 
+// BroadcastExtrinsic provides a mock function with given fields: _a0
+func (_m *CoreAPI) BroadcastExtrinsic(_a0 types.Extrinsic) bool {
+	ret := _m.Called(_a0)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(types.Extrinsic) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// CallRuntimeAPI provides a mock function with given fields: bhash, method, data
+func (_m *CoreAPI) CallRuntimeAPI(bhash *common.Hash, method string, data []byte) ([]byte, error) {
+	ret := _m.Called(bhash, method, data)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(*common.Hash, string, []byte) []byte); ok {
+		r0 = rf(bhash, method, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*common.Hash, string, []byte) error); ok {
+		r1 = rf(bhash, method, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DecodeSessionKeys provides a mock function with given fields: enc
 func (_m *CoreAPI) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	ret := _m.Called(enc)
@@ -43,6 +82,84 @@ func (_m *CoreAPI) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	return r0, r1
 }
 
+// DryRun provides a mock function with given fields: ext, bhash
+func (_m *CoreAPI) DryRun(ext types.Extrinsic, bhash *common.Hash) ([]byte, error) {
+	ret := _m.Called(ext, bhash)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(types.Extrinsic, *common.Hash) []byte); ok {
+		r0 = rf(ext, bhash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(types.Extrinsic, *common.Hash) error); ok {
+		r1 = rf(ext, bhash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateSessionKeys provides a mock function with given fields:
+func (_m *CoreAPI) GenerateSessionKeys() ([]byte, error) {
+	ret := _m.Called()
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func() []byte); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetChildReadProofAt provides a mock function with given fields: block, keyToChild, keys
+func (_m *CoreAPI) GetChildReadProofAt(block common.Hash, keyToChild []byte, keys [][]byte) (common.Hash, [][]byte, error) {
+	ret := _m.Called(block, keyToChild, keys)
+
+	var r0 common.Hash
+	if rf, ok := ret.Get(0).(func(common.Hash, []byte, [][]byte) common.Hash); ok {
+		r0 = rf(block, keyToChild, keys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	var r1 [][]byte
+	if rf, ok := ret.Get(1).(func(common.Hash, []byte, [][]byte) [][]byte); ok {
+		r1 = rf(block, keyToChild, keys)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([][]byte)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(common.Hash, []byte, [][]byte) error); ok {
+		r2 = rf(block, keyToChild, keys)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetMetadata provides a mock function with given fields: bhash
 func (_m *CoreAPI) GetMetadata(bhash *common.Hash) ([]byte, error) {
 	ret := _m.Called(bhash)
@@ -171,7 +288,7 @@ func (_m *CoreAPI) InsertKey(kp crypto.Keypair, keystoreType string) error {
 }
 
 // QueryStorage provides a mock function with given fields: from, to, keys
-func (_m *CoreAPI) QueryStorage(from common.Hash, to common.Hash, keys ...string) (map[common.Hash]core.QueryKeyValueChanges, error) {
+func (_m *CoreAPI) QueryStorage(from common.Hash, to common.Hash, keys ...string) ([]core.StorageChangeSet, error) {
 	_va := make([]interface{}, len(keys))
 	for _i := range keys {
 		_va[_i] = keys[_i]
@@ -181,12 +298,12 @@ func (_m *CoreAPI) QueryStorage(from common.Hash, to common.Hash, keys ...string
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 map[common.Hash]core.QueryKeyValueChanges
-	if rf, ok := ret.Get(0).(func(common.Hash, common.Hash, ...string) map[common.Hash]core.QueryKeyValueChanges); ok {
+	var r0 []core.StorageChangeSet
+	if rf, ok := ret.Get(0).(func(common.Hash, common.Hash, ...string) []core.StorageChangeSet); ok {
 		r0 = rf(from, to, keys...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[common.Hash]core.QueryKeyValueChanges)
+			r0 = ret.Get(0).([]core.StorageChangeSet)
 		}
 	}
 
@@ -199,3 +316,63 @@ func (_m *CoreAPI) QueryStorage(from common.Hash, to common.Hash, keys ...string
 
 	return r0, r1
 }
+
+// QueryStorageAt provides a mock function with given fields: at, keys
+func (_m *CoreAPI) QueryStorageAt(at common.Hash, keys ...string) (core.StorageChangeSet, error) {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, at)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 core.StorageChangeSet
+	if rf, ok := ret.Get(0).(func(common.Hash, ...string) core.StorageChangeSet); ok {
+		r0 = rf(at, keys...)
+	} else {
+		r0 = ret.Get(0).(core.StorageChangeSet)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash, ...string) error); ok {
+		r1 = rf(at, keys...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TraceBlock provides a mock function with given fields: hash
+func (_m *CoreAPI) TraceBlock(hash common.Hash) (*types.Header, []storage.TraceEvent, error) {
+	ret := _m.Called(hash)
+
+	var r0 *types.Header
+	if rf, ok := ret.Get(0).(func(common.Hash) *types.Header); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Header)
+		}
+	}
+
+	var r1 []storage.TraceEvent
+	if rf, ok := ret.Get(1).(func(common.Hash) []storage.TraceEvent); ok {
+		r1 = rf(hash)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]storage.TraceEvent)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(common.Hash) error); ok {
+		r2 = rf(hash)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
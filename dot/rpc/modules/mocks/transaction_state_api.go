@@ -33,20 +33,20 @@ func (_m *TransactionStateAPI) AddToPool(_a0 *transaction.ValidTransaction) comm
 }
 
 // FreeStatusNotifierChannel provides a mock function with given fields: ch
-func (_m *TransactionStateAPI) FreeStatusNotifierChannel(ch chan transaction.Status) {
+func (_m *TransactionStateAPI) FreeStatusNotifierChannel(ch chan transaction.StatusNotification) {
 	_m.Called(ch)
 }
 
 // GetStatusNotifierChannel provides a mock function with given fields: ext
-func (_m *TransactionStateAPI) GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status {
+func (_m *TransactionStateAPI) GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.StatusNotification {
 	ret := _m.Called(ext)
 
-	var r0 chan transaction.Status
-	if rf, ok := ret.Get(0).(func(types.Extrinsic) chan transaction.Status); ok {
+	var r0 chan transaction.StatusNotification
+	if rf, ok := ret.Get(0).(func(types.Extrinsic) chan transaction.StatusNotification); ok {
 		r0 = rf(ext)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(chan transaction.Status)
+			r0 = ret.Get(0).(chan transaction.StatusNotification)
 		}
 	}
 
@@ -100,3 +100,17 @@ func (_m *TransactionStateAPI) Pop() *transaction.ValidTransaction {
 
 	return r0
 }
+
+// RemoveExtrinsicByHash provides a mock function with given fields: hash
+func (_m *TransactionStateAPI) RemoveExtrinsicByHash(hash common.Hash) bool {
+	ret := _m.Called(hash)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(common.Hash) bool); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
@@ -4,9 +4,15 @@
 package modules
 
 import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
 	"net/http"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
 // PaymentQueryInfoRequest represents the request to get the fee of an extrinsic in a given block
@@ -24,49 +30,96 @@ type PaymentQueryInfoResponse struct {
 	PartialFee string `json:"partialFee"`
 }
 
+// PaymentQueryFeeDetailsResponse holds the response fields to the query fee details RPC method
+type PaymentQueryFeeDetailsResponse struct {
+	// InclusionFee is nil for unsigned extrinsics
+	InclusionFee *InclusionFee `json:"inclusionFee"`
+}
+
+// InclusionFee holds the hex encoded parts of the fee paid for an extrinsic to be included in a block
+type InclusionFee struct {
+	BaseFee           string `json:"baseFee"`
+	LenFee            string `json:"lenFee"`
+	AdjustedWeightFee string `json:"adjustedWeightFee"`
+}
+
 // PaymentModule holds all the RPC implementation of polkadot payment rpc api
 type PaymentModule struct {
-	blockAPI BlockAPI
+	coreAPI CoreAPI
 }
 
 // NewPaymentModule returns a pointer to PaymentModule
-func NewPaymentModule(blockAPI BlockAPI) *PaymentModule {
+func NewPaymentModule(coreAPI CoreAPI) *PaymentModule {
 	return &PaymentModule{
-		blockAPI: blockAPI,
+		coreAPI: coreAPI,
 	}
 }
 
 // QueryInfo query the known data about the fee of an extrinsic at the given block
 func (p *PaymentModule) QueryInfo(_ *http.Request, req *PaymentQueryInfoRequest, res *PaymentQueryInfoResponse) error {
-	var hash common.Hash
-	if req.Hash == nil {
-		hash = p.blockAPI.BestBlockHash()
-	} else {
-		hash = *req.Hash
+	encQueryInfo, err := p.callPaymentAPI(runtime.TransactionPaymentAPIQueryInfo, req)
+	if err != nil {
+		return err
 	}
 
-	r, err := p.blockAPI.GetRuntime(&hash)
+	queryInfo := new(types.TransactionPaymentQueryInfo)
+	err = scale.Unmarshal(encQueryInfo, queryInfo)
 	if err != nil {
-		return err
+		return fmt.Errorf("cannot decode query info: %w", err)
 	}
 
-	ext, err := common.HexToBytes(req.Ext)
+	*res = PaymentQueryInfoResponse{
+		Weight:     queryInfo.Weight,
+		Class:      queryInfo.Class,
+		PartialFee: queryInfo.PartialFee.String(),
+	}
+
+	return nil
+}
+
+// QueryFeeDetails query the detailed fee of an extrinsic at the given block
+func (p *PaymentModule) QueryFeeDetails(_ *http.Request, req *PaymentQueryInfoRequest,
+	res *PaymentQueryFeeDetailsResponse) error {
+	encFeeDetails, err := p.callPaymentAPI(runtime.TransactionPaymentAPIQueryFeeDetails, req)
 	if err != nil {
 		return err
 	}
 
-	encQueryInfo, err := r.PaymentQueryInfo(ext)
+	feeDetails := new(types.TransactionPaymentFeeDetails)
+	err = scale.Unmarshal(encFeeDetails, feeDetails)
 	if err != nil {
-		return err
+		return fmt.Errorf("cannot decode fee details: %w", err)
 	}
 
-	if encQueryInfo != nil {
-		*res = PaymentQueryInfoResponse{
-			Weight:     encQueryInfo.Weight,
-			Class:      encQueryInfo.Class,
-			PartialFee: encQueryInfo.PartialFee.String(),
+	*res = PaymentQueryFeeDetailsResponse{}
+	if feeDetails.InclusionFee != nil {
+		res.InclusionFee = &InclusionFee{
+			BaseFee:           uint128ToHex(feeDetails.InclusionFee.BaseFee),
+			LenFee:            uint128ToHex(feeDetails.InclusionFee.LenFee),
+			AdjustedWeightFee: uint128ToHex(feeDetails.InclusionFee.AdjustedWeightFee),
 		}
 	}
 
 	return nil
 }
+
+// callPaymentAPI calls the given transaction payment runtime API with the runtime
+// and the state of the requested block, or of the best block if no block is given.
+func (p *PaymentModule) callPaymentAPI(method string, req *PaymentQueryInfoRequest) ([]byte, error) {
+	ext, err := common.HexToBytes(req.Ext)
+	if err != nil {
+		return nil, err
+	}
+
+	encLen, err := scale.Marshal(uint32(len(ext)))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.coreAPI.CallRuntimeAPI(req.Hash, method, append(ext, encLen...))
+}
+
+// uint128ToHex returns the hex encoding of the number without leading zeros, as in Substrate
+func uint128ToHex(u *scale.Uint128) string {
+	return fmt.Sprintf("%#x", new(big.Int).SetBytes(u.Bytes(binary.BigEndian)))
+}
@@ -12,7 +12,7 @@
 	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
-	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 
 	"github.com/stretchr/testify/assert"
@@ -24,36 +24,28 @@ func TestPaymentModule_QueryInfo(t *testing.T) {
 	u, err := scale.NewUint128(new(big.Int).SetBytes([]byte{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6}))
 	require.NoError(t, err)
 
-	runtimeMock := new(mocksruntime.Instance)
-	runtimeMock2 := new(mocksruntime.Instance)
-	runtimeErrorMock := new(mocksruntime.Instance)
-
-	blockAPIMock := new(mocks.BlockAPI)
-	blockAPIMock2 := new(mocks.BlockAPI)
-	blockErrorAPIMock1 := new(mocks.BlockAPI)
-	blockErrorAPIMock2 := new(mocks.BlockAPI)
-
-	blockAPIMock.On("BestBlockHash").Return(testHash, nil)
-	blockAPIMock.On("GetRuntime", &testHash).Return(runtimeMock, nil)
-
-	blockAPIMock2.On("GetRuntime", &testHash).Return(runtimeMock2, nil)
+	encQueryInfo, err := scale.Marshal(types.TransactionPaymentQueryInfo{
+		Weight:     uint64(21),
+		Class:      1,
+		PartialFee: u,
+	})
+	require.NoError(t, err)
 
-	blockErrorAPIMock1.On("GetRuntime", &testHash).Return(runtimeErrorMock, nil)
+	// the extrinsic is followed by its length as a SCALE encoded u32
+	callData := common.MustHexToBytes("0x000002000000")
 
-	blockErrorAPIMock2.On("GetRuntime", &testHash).Return(nil, errors.New("GetRuntime error"))
+	coreAPIMock := new(mocks.CoreAPI)
+	coreAPIMock.On("CallRuntimeAPI", &testHash, runtime.TransactionPaymentAPIQueryInfo, callData).
+		Return(encQueryInfo, nil)
+	coreAPIMock.On("CallRuntimeAPI", (*common.Hash)(nil), runtime.TransactionPaymentAPIQueryInfo, callData).
+		Return([]byte{1}, nil)
 
-	runtimeMock.On("PaymentQueryInfo", common.MustHexToBytes("0x0000")).Return(nil, nil)
-	runtimeMock2.On("PaymentQueryInfo", common.MustHexToBytes("0x0000")).Return(&types.TransactionPaymentQueryInfo{
-		Weight:     uint64(21),
-		Class:      21,
-		PartialFee: u,
-	}, nil)
-	runtimeErrorMock.On("PaymentQueryInfo", common.MustHexToBytes("0x0000")).
-		Return(nil, errors.New("PaymentQueryInfo error"))
+	coreAPIErrorMock := new(mocks.CoreAPI)
+	coreAPIErrorMock.On("CallRuntimeAPI", &testHash, runtime.TransactionPaymentAPIQueryInfo, callData).
+		Return(nil, errors.New("CallRuntimeAPI error"))
 
-	paymentModule := NewPaymentModule(blockAPIMock)
 	type fields struct {
-		blockAPI BlockAPI
+		coreAPI CoreAPI
 	}
 	type args struct {
 		in0 *http.Request
@@ -67,22 +59,9 @@ type args struct {
 		exp    PaymentQueryInfoResponse
 	}{
 		{
-			name: "Nil Query Info",
-			fields: fields{
-				paymentModule.blockAPI,
-			},
-			args: args{
-				req: &PaymentQueryInfoRequest{
-					Ext:  "0x0000",
-					Hash: &testHash,
-				},
-			},
-			exp: PaymentQueryInfoResponse{},
-		},
-		{
-			name: "Not Nil Query Info",
+			name: "Query Info at block",
 			fields: fields{
-				blockAPIMock2,
+				coreAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -91,17 +70,15 @@ type args struct {
 				},
 			},
 			exp: PaymentQueryInfoResponse{
-				Weight: uint64(21),
-				Class:  21,
-				PartialFee: scale.MustNewUint128(new(big.Int).SetBytes(
-					[]byte{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6}),
-				).String(),
+				Weight:     uint64(21),
+				Class:      1,
+				PartialFee: "20441799243135961127713927070982",
 			},
 		},
 		{
-			name: "Nil Hash",
+			name: "Invalid Ext",
 			fields: fields{
-				paymentModule.blockAPI,
+				coreAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -111,21 +88,21 @@ type args struct {
 			expErr: errors.New("encoding/hex: odd length hex string: 0x0"),
 		},
 		{
-			name: "Invalid Ext",
+			name: "Invalid Query Info",
 			fields: fields{
-				paymentModule.blockAPI,
+				coreAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
 					Ext: "0x0000",
 				},
 			},
-			exp: PaymentQueryInfoResponse{},
+			expErr: errors.New("cannot decode query info: EOF, field: 0"),
 		},
 		{
-			name: "PaymentQueryInfo error",
+			name: "CallRuntimeAPI error",
 			fields: fields{
-				blockErrorAPIMock1,
+				coreAPIErrorMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -133,29 +110,98 @@ type args struct {
 					Hash: &testHash,
 				},
 			},
-			expErr: errors.New("PaymentQueryInfo error"),
+			expErr: errors.New("CallRuntimeAPI error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPaymentModule(tt.fields.coreAPI)
+			res := PaymentQueryInfoResponse{}
+			err := p.QueryInfo(tt.args.in0, tt.args.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
+
+func TestPaymentModule_QueryFeeDetails(t *testing.T) {
+	testHash := common.NewHash([]byte{0x01, 0x02})
+	callData := common.MustHexToBytes("0x000002000000")
+
+	encFeeDetails, err := scale.Marshal(types.TransactionPaymentFeeDetails{
+		InclusionFee: &types.TransactionPaymentInclusionFee{
+			BaseFee:           scale.MustNewUint128(big.NewInt(125000000)),
+			LenFee:            scale.MustNewUint128(big.NewInt(0)),
+			AdjustedWeightFee: scale.MaxUint128,
 		},
+		Tip: scale.MustNewUint128(big.NewInt(1)),
+	})
+	require.NoError(t, err)
+
+	encUnsignedFeeDetails, err := scale.Marshal(types.TransactionPaymentFeeDetails{
+		Tip: scale.MustNewUint128(big.NewInt(0)),
+	})
+	require.NoError(t, err)
+
+	coreAPIMock := new(mocks.CoreAPI)
+	coreAPIMock.On("CallRuntimeAPI", &testHash, runtime.TransactionPaymentAPIQueryFeeDetails, callData).
+		Return(encFeeDetails, nil)
+	coreAPIMock.On("CallRuntimeAPI", (*common.Hash)(nil), runtime.TransactionPaymentAPIQueryFeeDetails, callData).
+		Return(encUnsignedFeeDetails, nil)
+
+	coreAPIErrorMock := new(mocks.CoreAPI)
+	coreAPIErrorMock.On("CallRuntimeAPI", &testHash, runtime.TransactionPaymentAPIQueryFeeDetails, callData).
+		Return(nil, errors.New("CallRuntimeAPI error"))
+
+	tests := []struct {
+		name    string
+		coreAPI CoreAPI
+		req     *PaymentQueryInfoRequest
+		expErr  error
+		exp     PaymentQueryFeeDetailsResponse
+	}{
 		{
-			name: "GetRuntime error",
-			fields: fields{
-				blockErrorAPIMock2,
+			name:    "signed extrinsic",
+			coreAPI: coreAPIMock,
+			req: &PaymentQueryInfoRequest{
+				Ext:  "0x0000",
+				Hash: &testHash,
 			},
-			args: args{
-				req: &PaymentQueryInfoRequest{
-					Ext:  "0x0000",
-					Hash: &testHash,
+			exp: PaymentQueryFeeDetailsResponse{
+				InclusionFee: &InclusionFee{
+					BaseFee:           "0x7735940",
+					LenFee:            "0x0",
+					AdjustedWeightFee: "0xffffffffffffffffffffffffffffffff",
 				},
 			},
-			expErr: errors.New("GetRuntime error"),
+		},
+		{
+			name:    "unsigned extrinsic at best block",
+			coreAPI: coreAPIMock,
+			req: &PaymentQueryInfoRequest{
+				Ext: "0x0000",
+			},
+			exp: PaymentQueryFeeDetailsResponse{},
+		},
+		{
+			name:    "CallRuntimeAPI error",
+			coreAPI: coreAPIErrorMock,
+			req: &PaymentQueryInfoRequest{
+				Ext:  "0x0000",
+				Hash: &testHash,
+			},
+			expErr: errors.New("CallRuntimeAPI error"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := &PaymentModule{
-				blockAPI: tt.fields.blockAPI,
-			}
-			res := PaymentQueryInfoResponse{}
-			err := p.QueryInfo(tt.args.in0, tt.args.req, &res)
+			p := NewPaymentModule(tt.coreAPI)
+			res := PaymentQueryFeeDetailsResponse{}
+			err := p.QueryFeeDetails(nil, tt.req, &res)
 			if tt.expErr != nil {
 				assert.EqualError(t, err, tt.expErr.Error())
 			} else {
@@ -12,13 +12,20 @@
 	UnsafeMethods = []string{
 		"system_addReservedPeer",
 		"system_removeReservedPeer",
+		"system_dryRun",
+		"system_addLogFilter",
+		"system_resetLogFilter",
 		"author_submitExtrinsic",
 		"author_removeExtrinsic",
 		"author_insertKey",
 		"author_rotateKeys",
+		"babe_epochAuthorship",
+		"transaction_v1_broadcast",
+		"transaction_v1_stop",
 		"state_getPairs",
 		"state_getKeysPaged",
 		"state_queryStorage",
+		"state_traceBlock",
 	}
 
 	// AliasesMethods is a map that links the original methods to their aliases
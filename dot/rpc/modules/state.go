@@ -10,6 +10,7 @@
 	"net/http"
 	"strings"
 
+	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -21,10 +22,17 @@ type StateGetReadProofRequest struct {
 	Hash common.Hash
 }
 
+// StateGetChildReadProofRequest json fields
+type StateGetChildReadProofRequest struct {
+	ChildStorageKey string
+	Keys            []string
+	Hash            common.Hash
+}
+
 // StateCallRequest holds json fields
 type StateCallRequest struct {
 	Method string       `json:"method"`
-	Data   []byte       `json:"data"`
+	Data   string       `json:"data"`
 	Block  *common.Hash `json:"block"`
 }
 
@@ -77,11 +85,17 @@ type StateStorageQueryRangeRequest struct {
 	EndBlock   common.Hash `json:"block"`
 }
 
+// StateStorageQueryAtRequest holds json fields
+type StateStorageQueryAtRequest struct {
+	Keys []string     `json:"keys" validate:"required"`
+	At   *common.Hash `json:"at"`
+}
+
 // StateStorageKeysQuery field to store storage keys
 type StateStorageKeysQuery [][]byte
 
-// StateCallResponse holds json fields
-type StateCallResponse []byte
+// StateCallResponse is the hex encoded result of a runtime API call
+type StateCallResponse string
 
 // StateKeysResponse field to store the state keys
 type StateKeysResponse [][]byte
@@ -119,10 +133,11 @@ type StateGetReadProofResponse struct {
 	Proof []string    `json:"proof"`
 }
 
-// StorageChangeSetResponse is the struct that holds the block and changes
+// StorageChangeSetResponse is the struct that holds the block and changes,
+// each change being a key and its value, which is nil if the key was removed
 type StorageChangeSetResponse struct {
 	Block   *common.Hash `json:"block"`
-	Changes [][]string   `json:"changes"`
+	Changes [][]*string  `json:"changes"`
 }
 
 // KeyValueOption struct holds json fields
@@ -211,10 +226,28 @@ func (sm *StateModule) GetPairs(_ *http.Request, req *StatePairRequest, res *Sta
 	return nil
 }
 
-// Call isn't implemented properly yet.
-func (sm *StateModule) Call(_ *http.Request, _ *StateCallRequest, _ *StateCallResponse) error {
-	_ = sm.networkAPI
-	_ = sm.storageAPI
+// Call executes a runtime API method at the given block's state and returns the SCALE encoded result.
+// If no block hash is provided, the best block is used. Storage changes made by the call are not stored.
+func (sm *StateModule) Call(_ *http.Request, req *StateCallRequest, res *StateCallResponse) error {
+	if req.Method == "" {
+		return errors.New("method cannot be empty")
+	}
+
+	var data []byte
+	if req.Data != "" {
+		var err error
+		data, err = common.HexToBytes(req.Data)
+		if err != nil {
+			return fmt.Errorf("cannot convert hex data %s to bytes: %w", req.Data, err)
+		}
+	}
+
+	ret, err := sm.coreAPI.CallRuntimeAPI(req.Block, req.Method, data)
+	if err != nil {
+		return err
+	}
+
+	*res = StateCallResponse(common.BytesToHex(ret))
 	return nil
 }
 
@@ -291,6 +324,42 @@ func (sm *StateModule) GetReadProof(
 	return nil
 }
 
+// GetChildReadProof returns the proof of the child trie root and of the received child storage keys
+func (sm *StateModule) GetChildReadProof(
+	_ *http.Request, req *StateGetChildReadProofRequest, res *StateGetReadProofResponse) error {
+	childStorageKey, err := common.HexToBytes(req.ChildStorageKey)
+	if err != nil {
+		return err
+	}
+
+	keys := make([][]byte, len(req.Keys))
+	for i, hexKey := range req.Keys {
+		bKey, err := common.HexToBytes(hexKey)
+		if err != nil {
+			return err
+		}
+
+		keys[i] = bKey
+	}
+
+	block, proofs, err := sm.coreAPI.GetChildReadProofAt(req.Hash, childStorageKey, keys)
+	if err != nil {
+		return err
+	}
+
+	decProof := make([]string, len(proofs))
+	for i, p := range proofs {
+		decProof[i] = common.BytesToHex(p)
+	}
+
+	*res = StateGetReadProofResponse{
+		At:    block,
+		Proof: decProof,
+	}
+
+	return nil
+}
+
 // GetRuntimeVersion Get the runtime version at a given block.
 //  If no block hash is provided, the latest version gets returned.
 func (sm *StateModule) GetRuntimeVersion(
@@ -403,38 +472,61 @@ func (sm *StateModule) GetStorageSize(
 	return nil
 }
 
-// QueryStorage isn't implemented properly yet.
+// QueryStorage returns the changes of the values of the given keys, in chain order,
+// from the start block until the given block or the best block. Only the keys whose
+// value changed compared to the previous block are returned, except for the start
+// block for which the values of all the keys are returned.
 func (sm *StateModule) QueryStorage(
 	_ *http.Request, req *StateStorageQueryRangeRequest, res *[]StorageChangeSetResponse) error {
 	if req.StartBlock.IsEmpty() {
 		return errors.New("the start block hash cannot be an empty value")
 	}
 
-	changesByBlock, err := sm.coreAPI.QueryStorage(req.StartBlock, req.EndBlock, req.Keys...)
+	changeSets, err := sm.coreAPI.QueryStorage(req.StartBlock, req.EndBlock, req.Keys...)
 	if err != nil {
 		return err
 	}
 
-	response := make([]StorageChangeSetResponse, 0, len(changesByBlock))
+	response := make([]StorageChangeSetResponse, len(changeSets))
+	for i, changeSet := range changeSets {
+		response[i] = newStorageChangeSetResponse(changeSet)
+	}
 
-	for block, c := range changesByBlock {
-		var changes [][]string
+	*res = response
+	return nil
+}
 
-		for key, value := range c {
-			changes = append(changes, []string{key, value})
-		}
+// QueryStorageAt returns the values of the given keys at the given block or at the best block.
+func (sm *StateModule) QueryStorageAt(
+	_ *http.Request, req *StateStorageQueryAtRequest, res *[]StorageChangeSetResponse) error {
+	var at common.Hash
+	if req.At != nil {
+		at = *req.At
+	}
 
-		blochHash := block
-		response = append(response, StorageChangeSetResponse{
-			Block:   &blochHash,
-			Changes: changes,
-		})
+	changeSet, err := sm.coreAPI.QueryStorageAt(at, req.Keys...)
+	if err != nil {
+		return err
 	}
 
-	*res = response
+	*res = []StorageChangeSetResponse{newStorageChangeSetResponse(changeSet)}
 	return nil
 }
 
+func newStorageChangeSetResponse(changeSet core.StorageChangeSet) StorageChangeSetResponse {
+	changes := make([][]*string, len(changeSet.Changes))
+	for i, change := range changeSet.Changes {
+		key := change.Key
+		changes[i] = []*string{&key, change.Value}
+	}
+
+	block := changeSet.Block
+	return StorageChangeSetResponse{
+		Block:   &block,
+		Changes: changes,
+	}
+}
+
 // SubscribeRuntimeVersion initialised a runtime version subscription and returns the current version
 // See dot/rpc/subscription
 func (sm *StateModule) SubscribeRuntimeVersion(
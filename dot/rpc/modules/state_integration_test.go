@@ -18,6 +18,7 @@
 	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -336,12 +337,14 @@ func TestStateModule_QueryStorage(t *testing.T) {
 	t.Run("When QueryStorage returns data", func(t *testing.T) {
 		blockhash := common.NewHash([]byte{123})
 
-		changes := map[common.Hash]core.QueryKeyValueChanges{
-			blockhash: core.QueryKeyValueChanges(map[string]string{
-				"0x80": "value",
-				"0x90": "another value",
-			}),
-		}
+		value, anotherValue := "0x01", "0x02"
+		changes := []core.StorageChangeSet{{
+			Block: blockhash,
+			Changes: []core.StorageChange{
+				{Key: "0x90", Value: &anotherValue},
+				{Key: "0x80", Value: &value},
+			},
+		}}
 		coreapimock := new(mocks.CoreAPI)
 		coreapimock.On("QueryStorage",
 			mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash"), "0x90", "0x80").Return(changes, nil)
@@ -525,7 +528,7 @@ func setupStateModule(t *testing.T) (*StateModule, *common.Hash, *common.Hash) {
 
 	sr1, err := ts.Root()
 	require.NoError(t, err)
-	err = chain.Storage.StoreTrie(ts, nil)
+	err = chain.Storage.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	digest := types.NewDigest()
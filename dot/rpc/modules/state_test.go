@@ -278,14 +278,84 @@ type args struct {
 	}
 }
 
-// Implement Tests once function is implemented
-func TestCall(t *testing.T) {
-	mockNetworkAPI := new(mocks.NetworkAPI)
-	mockStorageAPI := new(mocks.StorageAPI)
-	sm := NewStateModule(mockNetworkAPI, mockStorageAPI, nil)
+func TestStateModuleCall(t *testing.T) {
+	hash := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
 
-	err := sm.Call(nil, nil, nil)
-	assert.NoError(t, err)
+	mockCoreAPI := new(mocks.CoreAPI)
+	mockCoreAPI.On("CallRuntimeAPI", &hash, "AccountNonceApi_account_nonce", []byte{1, 2}).
+		Return([]byte{3, 0, 0, 0}, nil)
+	mockCoreAPI.On("CallRuntimeAPI", (*common.Hash)(nil), "Core_version", []byte{}).
+		Return([]byte{4}, nil)
+
+	mockCoreAPIErr := new(mocks.CoreAPI)
+	mockCoreAPIErr.On("CallRuntimeAPI", &hash, "Unknown_method", []byte(nil)).
+		Return(nil, runtime.ErrExportFunctionNotFound)
+
+	tests := []struct {
+		name    string
+		coreAPI CoreAPI
+		req     *StateCallRequest
+		exp     StateCallResponse
+		expErr  error
+	}{
+		{
+			name:    "OK Case",
+			coreAPI: mockCoreAPI,
+			req: &StateCallRequest{
+				Method: "AccountNonceApi_account_nonce",
+				Data:   "0x0102",
+				Block:  &hash,
+			},
+			exp: StateCallResponse("0x03000000"),
+		},
+		{
+			name:    "OK Case best block",
+			coreAPI: mockCoreAPI,
+			req: &StateCallRequest{
+				Method: "Core_version",
+				Data:   "0x",
+			},
+			exp: StateCallResponse("0x04"),
+		},
+		{
+			name:   "empty method",
+			req:    &StateCallRequest{},
+			exp:    StateCallResponse(""),
+			expErr: errors.New("method cannot be empty"),
+		},
+		{
+			name: "invalid data",
+			req: &StateCallRequest{
+				Method: "Core_version",
+				Data:   "0102",
+			},
+			exp:    StateCallResponse(""),
+			expErr: errors.New("cannot convert hex data 0102 to bytes: could not byteify non 0x prefixed string: 0102"),
+		},
+		{
+			name:    "unknown method",
+			coreAPI: mockCoreAPIErr,
+			req: &StateCallRequest{
+				Method: "Unknown_method",
+				Block:  &hash,
+			},
+			exp:    StateCallResponse(""),
+			expErr: runtime.ErrExportFunctionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := NewStateModule(nil, nil, tt.coreAPI)
+			var res StateCallResponse
+			err := sm.Call(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
 }
 
 func TestStateModuleGetMetadata(t *testing.T) {
@@ -445,6 +515,88 @@ type args struct {
 	}
 }
 
+func TestStateModuleGetChildReadProof(t *testing.T) {
+	hash := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
+	childStorageKey := []byte(":child_storage_key")
+	keys := []string{"0x1111", "0x2222"}
+	expKeys := [][]byte{{0x11, 0x11}, {0x22, 0x22}}
+
+	mockCoreAPI := new(mocks.CoreAPI)
+	mockCoreAPI.On("GetChildReadProofAt", hash, childStorageKey, expKeys).
+		Return(hash, [][]byte{{1, 1, 1}, {2, 2, 2}}, nil)
+
+	mockCoreAPIErr := new(mocks.CoreAPI)
+	mockCoreAPIErr.On("GetChildReadProofAt", hash, childStorageKey, expKeys).
+		Return(nil, nil, errors.New("GetChildReadProofAt Error"))
+
+	tests := []struct {
+		name    string
+		coreAPI CoreAPI
+		req     *StateGetChildReadProofRequest
+		expErr  error
+		exp     StateGetReadProofResponse
+	}{
+		{
+			name:    "OK Case",
+			coreAPI: mockCoreAPI,
+			req: &StateGetChildReadProofRequest{
+				ChildStorageKey: common.BytesToHex(childStorageKey),
+				Keys:            keys,
+				Hash:            hash,
+			},
+			exp: StateGetReadProofResponse{
+				At:    hash,
+				Proof: []string{"0x010101", "0x020202"},
+			},
+		},
+		{
+			name:    "GetChildReadProofAt Error",
+			coreAPI: mockCoreAPIErr,
+			req: &StateGetChildReadProofRequest{
+				ChildStorageKey: common.BytesToHex(childStorageKey),
+				Keys:            keys,
+				Hash:            hash,
+			},
+			expErr: errors.New("GetChildReadProofAt Error"),
+		},
+		{
+			name:    "Invalid child storage key",
+			coreAPI: mockCoreAPI,
+			req: &StateGetChildReadProofRequest{
+				ChildStorageKey: "0x0",
+				Keys:            keys,
+				Hash:            hash,
+			},
+			expErr: errors.New("encoding/hex: odd length hex string: 0x0"),
+		},
+		{
+			name:    "Invalid keys",
+			coreAPI: mockCoreAPI,
+			req: &StateGetChildReadProofRequest{
+				ChildStorageKey: common.BytesToHex(childStorageKey),
+				Keys:            []string{"0x1"},
+				Hash:            hash,
+			},
+			expErr: errors.New("encoding/hex: odd length hex string: 0x1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := &StateModule{
+				coreAPI: tt.coreAPI,
+			}
+			res := StateGetReadProofResponse{}
+			err := sm.GetChildReadProof(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
+
 func TestStateModuleGetRuntimeVersion(t *testing.T) {
 	hash := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
 	testAPIItem := runtime.APIItem{
@@ -797,23 +949,20 @@ type args struct {
 }
 
 func TestStateModuleQueryStorage(t *testing.T) {
-	qkvc1 := core.QueryKeyValueChanges{}
-	qkvc1["p1"] = "jimmy"
-	qkvc2 := core.QueryKeyValueChanges{}
-	qkvc2["p2"] = "jimbo"
-
 	hash1 := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
-	hash2 := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
+	hash2 := common.MustHexToHash("0x4aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
+	key, value := "0x01", "0x02"
 
-	m := map[common.Hash]core.QueryKeyValueChanges{}
-	m[hash1] = qkvc1
-	m[hash2] = qkvc2
+	changeSets := []core.StorageChangeSet{
+		{Block: hash1, Changes: []core.StorageChange{{Key: key, Value: &value}}},
+		{Block: hash2, Changes: []core.StorageChange{{Key: key}}},
+	}
 
 	mockCoreAPI := new(mocks.CoreAPI)
-	mockCoreAPI.On("QueryStorage", hash1, hash2, "jimbo").Return(m, nil)
+	mockCoreAPI.On("QueryStorage", hash1, hash2, key).Return(changeSets, nil)
 
 	mockCoreAPIErr := new(mocks.CoreAPI)
-	mockCoreAPIErr.On("QueryStorage", hash1, hash2, "jimbo").Return(nil, errors.New("QueryStorage Error"))
+	mockCoreAPIErr.On("QueryStorage", hash1, hash2, key).Return(nil, errors.New("QueryStorage Error"))
 
 	type fields struct {
 		networkAPI NetworkAPI
@@ -836,19 +985,22 @@ type args struct {
 			fields: fields{nil, nil, mockCoreAPI},
 			args: args{
 				req: &StateStorageQueryRangeRequest{
-					Keys:       []string{"jimbo"},
+					Keys:       []string{key},
 					StartBlock: hash1,
 					EndBlock:   hash2,
 				},
 			},
-			exp: []StorageChangeSetResponse{{Block: &hash1, Changes: [][]string{{"p2", "jimbo"}}}},
+			exp: []StorageChangeSetResponse{
+				{Block: &hash1, Changes: [][]*string{{&key, &value}}},
+				{Block: &hash2, Changes: [][]*string{{&key, nil}}},
+			},
 		},
 		{
 			name:   "QueryStorage Error",
 			fields: fields{nil, nil, mockCoreAPIErr},
 			args: args{
 				req: &StateStorageQueryRangeRequest{
-					Keys:       []string{"jimbo"},
+					Keys:       []string{key},
 					StartBlock: hash1,
 					EndBlock:   hash2,
 				},
@@ -861,7 +1013,7 @@ type args struct {
 			fields: fields{nil, nil, mockCoreAPI},
 			args: args{
 				req: &StateStorageQueryRangeRequest{
-					Keys:     []string{"jimbo"},
+					Keys:     []string{key},
 					EndBlock: hash2,
 				},
 			},
@@ -887,3 +1039,65 @@ type args struct {
 		})
 	}
 }
+
+func TestStateModuleQueryStorageAt(t *testing.T) {
+	hash := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
+	key1, key2, value := "0x01", "0x02", "0x03"
+
+	changeSet := core.StorageChangeSet{
+		Block: hash,
+		Changes: []core.StorageChange{
+			{Key: key1, Value: &value},
+			{Key: key2},
+		},
+	}
+
+	mockCoreAPI := new(mocks.CoreAPI)
+	mockCoreAPI.On("QueryStorageAt", hash, key1, key2).Return(changeSet, nil)
+	mockCoreAPI.On("QueryStorageAt", common.Hash{}, key1, key2).Return(changeSet, nil)
+
+	mockCoreAPIErr := new(mocks.CoreAPI)
+	mockCoreAPIErr.On("QueryStorageAt", hash, key1, key2).
+		Return(core.StorageChangeSet{}, errors.New("QueryStorageAt Error"))
+
+	expected := []StorageChangeSetResponse{
+		{Block: &hash, Changes: [][]*string{{&key1, &value}, {&key2, nil}}},
+	}
+
+	tests := map[string]struct {
+		coreAPI CoreAPI
+		req     *StateStorageQueryAtRequest
+		expErr  error
+		exp     []StorageChangeSetResponse
+	}{
+		"at block": {
+			coreAPI: mockCoreAPI,
+			req:     &StateStorageQueryAtRequest{Keys: []string{key1, key2}, At: &hash},
+			exp:     expected,
+		},
+		"at best block": {
+			coreAPI: mockCoreAPI,
+			req:     &StateStorageQueryAtRequest{Keys: []string{key1, key2}},
+			exp:     expected,
+		},
+		"QueryStorageAt error": {
+			coreAPI: mockCoreAPIErr,
+			req:     &StateStorageQueryAtRequest{Keys: []string{key1, key2}, At: &hash},
+			expErr:  errors.New("QueryStorageAt Error"),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			sm := &StateModule{coreAPI: tt.coreAPI}
+			var res []StorageChangeSetResponse
+			err := sm.QueryStorageAt(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
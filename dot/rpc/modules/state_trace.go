@@ -0,0 +1,250 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/dot/core"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+)
+
+const (
+	// defaultTraceBlockTargets are the targets traced when none are given, as in Substrate
+	defaultTraceBlockTargets = "pallet,frame,state"
+
+	// traceBlockStateTarget is the target of the block execution spans and of the storage events
+	traceBlockStateTarget = "state"
+
+	traceBlockSpanID uint64 = 1
+)
+
+// StateTraceBlockRequest holds json fields
+type StateTraceBlockRequest struct {
+	Block       common.Hash `json:"block"`
+	Targets     *string     `json:"targets"`
+	StorageKeys *string     `json:"storageKeys"`
+	Methods     *string     `json:"methods"`
+}
+
+// TraceBlockResponse is the response of state_traceBlock, only one of its fields is set
+type TraceBlockResponse struct {
+	TraceError *TraceError `json:"traceError,omitempty"`
+	BlockTrace *BlockTrace `json:"blockTrace,omitempty"`
+}
+
+// TraceError is the error returned when the block cannot be re-executed
+type TraceError struct {
+	Error string `json:"error"`
+}
+
+// BlockTrace holds the spans and events recorded while re-executing a block
+type BlockTrace struct {
+	BlockHash      string       `json:"blockHash"`
+	ParentHash     string       `json:"parentHash"`
+	TracingTargets string       `json:"tracingTargets"`
+	StorageKeys    string       `json:"storageKeys"`
+	Methods        string       `json:"methods"`
+	Spans          []TraceSpan  `json:"spans"`
+	Events         []TraceEvent `json:"events"`
+}
+
+// TraceSpan is either the execution of the block or the application of one of its extrinsics
+type TraceSpan struct {
+	ID       uint64  `json:"id"`
+	ParentID *uint64 `json:"parentId"`
+	Name     string  `json:"name"`
+	Target   string  `json:"target"`
+	Wasm     bool    `json:"wasm"`
+}
+
+// TraceEvent is a storage access made during the block execution
+type TraceEvent struct {
+	Target   string         `json:"target"`
+	Data     TraceEventData `json:"data"`
+	ParentID *uint64        `json:"parentId"`
+}
+
+// TraceEventData holds the values of a trace event
+type TraceEventData struct {
+	StringValues map[string]string `json:"stringValues"`
+}
+
+// TraceBlock re-executes the given block and returns the storage accesses made while executing it,
+// filtered by tracing targets, storage key prefixes and storage methods.
+func (sm *StateModule) TraceBlock(_ *http.Request, req *StateTraceBlockRequest, res *TraceBlockResponse) error {
+	blockHash := req.Block
+	targets := defaultTraceBlockTargets
+	if req.Targets != nil {
+		targets = *req.Targets
+	}
+
+	var storageKeys, methods string
+	if req.StorageKeys != nil {
+		storageKeys = *req.StorageKeys
+	}
+	if req.Methods != nil {
+		methods = *req.Methods
+	}
+
+	header, events, err := sm.coreAPI.TraceBlock(blockHash)
+	if errors.Is(err, core.ErrBlockExecution) {
+		*res = TraceBlockResponse{
+			TraceError: &TraceError{Error: err.Error()},
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("cannot trace block %s: %w", blockHash, err)
+	}
+
+	blockTrace := &BlockTrace{
+		BlockHash:      blockHash.String(),
+		ParentHash:     header.ParentHash.String(),
+		TracingTargets: targets,
+		StorageKeys:    storageKeys,
+		Methods:        methods,
+		Spans:          []TraceSpan{},
+		Events:         []TraceEvent{},
+	}
+
+	if !matchesTraceTargets(splitTraceFilter(targets), traceBlockStateTarget) {
+		*res = TraceBlockResponse{BlockTrace: blockTrace}
+		return nil
+	}
+
+	blockTrace.Spans = append(blockTrace.Spans, TraceSpan{
+		ID:     traceBlockSpanID,
+		Name:   "execute_block",
+		Target: traceBlockStateTarget,
+	})
+
+	keyPrefixes := splitTraceFilter(storageKeys)
+	for i, prefix := range keyPrefixes {
+		keyPrefixes[i] = strings.ToLower(strings.TrimPrefix(prefix, "0x"))
+	}
+	methodNames := splitTraceFilter(methods)
+
+	extrinsicSpans := make(map[uint32]uint64)
+	for _, event := range events {
+		parentID := traceBlockSpanID
+		if event.ExtrinsicIndex != nil {
+			spanID, ok := extrinsicSpans[*event.ExtrinsicIndex]
+			if !ok {
+				spanID = traceBlockSpanID + uint64(len(extrinsicSpans)) + 1
+				extrinsicSpans[*event.ExtrinsicIndex] = spanID
+				blockTrace.Spans = append(blockTrace.Spans, TraceSpan{
+					ID:       spanID,
+					ParentID: uint64Ptr(traceBlockSpanID),
+					Name:     "apply_extrinsic",
+					Target:   traceBlockStateTarget,
+				})
+			}
+			parentID = spanID
+		}
+
+		if !matchesTraceFilter(methodNames, event.Method) ||
+			!matchesTraceKeyPrefixes(keyPrefixes, event) {
+			continue
+		}
+
+		blockTrace.Events = append(blockTrace.Events, TraceEvent{
+			Target:   traceBlockStateTarget,
+			Data:     TraceEventData{StringValues: traceEventValues(event)},
+			ParentID: uint64Ptr(parentID),
+		})
+	}
+
+	*res = TraceBlockResponse{BlockTrace: blockTrace}
+	return nil
+}
+
+func traceEventValues(event rtstorage.TraceEvent) map[string]string {
+	values := map[string]string{
+		"method": event.Method,
+		"key":    fmt.Sprintf("%x", event.Key),
+	}
+
+	if event.ChildKey != nil {
+		values["child_key"] = fmt.Sprintf("%x", event.ChildKey)
+	}
+
+	if event.Value != nil {
+		values["value"] = fmt.Sprintf("%x", event.Value)
+	}
+
+	if event.ExtrinsicIndex != nil {
+		values["extrinsic_index"] = strconv.FormatUint(uint64(*event.ExtrinsicIndex), 10)
+	}
+
+	return values
+}
+
+// splitTraceFilter splits a comma separated filter, ignoring empty entries
+func splitTraceFilter(filter string) []string {
+	var entries []string
+	for _, entry := range strings.Split(filter, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// matchesTraceTargets returns true if one of the targets, optionally followed
+// by a log level as in `state=trace`, is a prefix of the given target.
+func matchesTraceTargets(targets []string, target string) bool {
+	for _, t := range targets {
+		name := strings.SplitN(t, "=", 2)[0]
+		if strings.HasPrefix(target, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesTraceFilter returns true if the filter is empty or contains the value
+func matchesTraceFilter(filter []string, value string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, entry := range filter {
+		if entry == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesTraceKeyPrefixes returns true if there are no prefixes or if the
+// hex encoded key or child key of the event starts with one of them.
+func matchesTraceKeyPrefixes(prefixes []string, event rtstorage.TraceEvent) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+
+	key := fmt.Sprintf("%x", event.Key)
+	childKey := fmt.Sprintf("%x", event.ChildKey)
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) ||
+			(event.ChildKey != nil && strings.HasPrefix(childKey, prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func uint64Ptr(n uint64) *uint64 {
+	return &n
+}
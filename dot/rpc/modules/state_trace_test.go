@@ -0,0 +1,186 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/core"
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateModuleTraceBlock(t *testing.T) {
+	t.Parallel()
+
+	blockHash := common.Hash{1}
+	header := &types.Header{ParentHash: common.Hash{2}}
+	zero, one := uint32(0), uint32(1)
+	events := []rtstorage.TraceEvent{
+		{Method: rtstorage.TraceMethodGet, Key: []byte{0xaa, 1}, Value: []byte{1}},
+		{ExtrinsicIndex: &zero, Method: rtstorage.TraceMethodPut, Key: []byte{0xaa, 2}, Value: []byte{2}},
+		{ExtrinsicIndex: &one, Method: rtstorage.TraceMethodGet, Key: []byte{0xbb, 1}},
+		{ExtrinsicIndex: &one, Method: rtstorage.TraceMethodPutChild, ChildKey: []byte{0xcc}, Key: []byte{3},
+			Value: []byte{3}},
+	}
+
+	blockSpan := TraceSpan{ID: 1, Name: "execute_block", Target: "state"}
+	extrinsicSpans := []TraceSpan{
+		{ID: 2, ParentID: uint64Ptr(1), Name: "apply_extrinsic", Target: "state"},
+		{ID: 3, ParentID: uint64Ptr(1), Name: "apply_extrinsic", Target: "state"},
+	}
+
+	emptyString := ""
+	storageKeys := "0xAA,cc"
+	methods := "Put,ChildPut"
+	testCases := map[string]struct {
+		coreAPI    func() CoreAPI
+		req        *StateTraceBlockRequest
+		res        TraceBlockResponse
+		errMessage string
+	}{
+		"trace block error": {
+			coreAPI: func() CoreAPI {
+				coreAPI := new(mocks.CoreAPI)
+				coreAPI.On("TraceBlock", blockHash).Return(nil, nil, errors.New("test error"))
+				return coreAPI
+			},
+			req: &StateTraceBlockRequest{Block: blockHash},
+			errMessage: "cannot trace block " +
+				"0x0100000000000000000000000000000000000000000000000000000000000000: test error",
+		},
+		"block execution error": {
+			coreAPI: func() CoreAPI {
+				coreAPI := new(mocks.CoreAPI)
+				coreAPI.On("TraceBlock", blockHash).
+					Return(nil, nil, fmt.Errorf("%w: test error", core.ErrBlockExecution))
+				return coreAPI
+			},
+			req: &StateTraceBlockRequest{Block: blockHash},
+			res: TraceBlockResponse{
+				TraceError: &TraceError{Error: "cannot execute block: test error"},
+			},
+		},
+		"default filters": {
+			coreAPI: func() CoreAPI {
+				coreAPI := new(mocks.CoreAPI)
+				coreAPI.On("TraceBlock", blockHash).Return(header, events, nil)
+				return coreAPI
+			},
+			req: &StateTraceBlockRequest{Block: blockHash},
+			res: TraceBlockResponse{
+				BlockTrace: &BlockTrace{
+					BlockHash:      blockHash.String(),
+					ParentHash:     header.ParentHash.String(),
+					TracingTargets: "pallet,frame,state",
+					Spans:          append([]TraceSpan{blockSpan}, extrinsicSpans...),
+					Events: []TraceEvent{
+						{
+							Target:   "state",
+							Data:     TraceEventData{StringValues: map[string]string{"method": "Get", "key": "aa01", "value": "01"}},
+							ParentID: uint64Ptr(1),
+						},
+						{
+							Target: "state",
+							Data: TraceEventData{StringValues: map[string]string{
+								"method": "Put", "key": "aa02", "value": "02", "extrinsic_index": "0"}},
+							ParentID: uint64Ptr(2),
+						},
+						{
+							Target: "state",
+							Data: TraceEventData{StringValues: map[string]string{
+								"method": "Get", "key": "bb01", "extrinsic_index": "1"}},
+							ParentID: uint64Ptr(3),
+						},
+						{
+							Target: "state",
+							Data: TraceEventData{StringValues: map[string]string{
+								"method": "ChildPut", "child_key": "cc", "key": "03", "value": "03", "extrinsic_index": "1"}},
+							ParentID: uint64Ptr(3),
+						},
+					},
+				},
+			},
+		},
+		"storage keys and methods filters": {
+			coreAPI: func() CoreAPI {
+				coreAPI := new(mocks.CoreAPI)
+				coreAPI.On("TraceBlock", blockHash).Return(header, events, nil)
+				return coreAPI
+			},
+			req: &StateTraceBlockRequest{
+				Block:       blockHash,
+				StorageKeys: &storageKeys,
+				Methods:     &methods,
+			},
+			res: TraceBlockResponse{
+				BlockTrace: &BlockTrace{
+					BlockHash:      blockHash.String(),
+					ParentHash:     header.ParentHash.String(),
+					TracingTargets: "pallet,frame,state",
+					StorageKeys:    storageKeys,
+					Methods:        methods,
+					Spans:          append([]TraceSpan{blockSpan}, extrinsicSpans...),
+					Events: []TraceEvent{
+						{
+							Target: "state",
+							Data: TraceEventData{StringValues: map[string]string{
+								"method": "Put", "key": "aa02", "value": "02", "extrinsic_index": "0"}},
+							ParentID: uint64Ptr(2),
+						},
+						{
+							Target: "state",
+							Data: TraceEventData{StringValues: map[string]string{
+								"method": "ChildPut", "child_key": "cc", "key": "03", "value": "03", "extrinsic_index": "1"}},
+							ParentID: uint64Ptr(3),
+						},
+					},
+				},
+			},
+		},
+		"state target not traced": {
+			coreAPI: func() CoreAPI {
+				coreAPI := new(mocks.CoreAPI)
+				coreAPI.On("TraceBlock", blockHash).Return(header, events, nil)
+				return coreAPI
+			},
+			req: &StateTraceBlockRequest{
+				Block:   blockHash,
+				Targets: &emptyString,
+			},
+			res: TraceBlockResponse{
+				BlockTrace: &BlockTrace{
+					BlockHash:  blockHash.String(),
+					ParentHash: header.ParentHash.String(),
+					Spans:      []TraceSpan{},
+					Events:     []TraceEvent{},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sm := NewStateModule(nil, nil, testCase.coreAPI())
+
+			var res TraceBlockResponse
+			err := sm.TraceBlock(nil, testCase.req, &res)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.res, res)
+		})
+	}
+}
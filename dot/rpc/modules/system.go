@@ -6,10 +6,13 @@
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"net/http"
 	"strings"
 
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -66,6 +69,31 @@ type SyncStateResponse struct {
 	StartingBlock uint32 `json:"startingBlock"`
 }
 
+// SystemDryRunRequest holds the hex encoded extrinsic to dry run and the block to apply it at
+type SystemDryRunRequest struct {
+	Extrinsic string       `json:"extrinsic"`
+	At        *common.Hash `json:"at"`
+}
+
+// SystemDryRunResponse holds the hex encoded SCALE ApplyExtrinsicResult of a dry run,
+// along with the decoded error if the extrinsic could not be applied or its dispatch failed
+type SystemDryRunResponse struct {
+	Result string       `json:"result"`
+	Error  *DryRunError `json:"error,omitempty"`
+}
+
+// DryRunError is the decoded error of an ApplyExtrinsicResult
+type DryRunError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Types of DryRunError
+const (
+	DryRunDispatchError            = "dispatchError"
+	DryRunTransactionValidityError = "transactionValidityError"
+)
+
 // NewSystemModule creates a new API instance
 func NewSystemModule(net NetworkAPI, sys SystemAPI, core CoreAPI,
 	storage StorageAPI, txAPI TransactionStateAPI, blockAPI BlockAPI,
@@ -289,3 +317,55 @@ func (sm *SystemModule) RemoveReservedPeer(r *http.Request, req *StringRequest,
 
 	return sm.networkAPI.RemoveReservedPeers(req.String)
 }
+
+// AddLogFilter changes the levels of the node loggers with a comma separated list of
+// directives such as `sync=trace,network=debug`. A directive without target, such as
+// `debug`, changes the level of all the loggers.
+func (sm *SystemModule) AddLogFilter(r *http.Request, req *StringRequest, res *[]byte) error {
+	if strings.TrimSpace(req.String) == "" {
+		return errors.New("cannot add an empty log filter")
+	}
+
+	return sm.systemAPI.AddLogFilter(req.String)
+}
+
+// ResetLogFilter resets the levels of the node loggers to the levels of the node configuration.
+func (sm *SystemModule) ResetLogFilter(r *http.Request, req *EmptyRequest, res *[]byte) error {
+	return sm.systemAPI.ResetLogFilter()
+}
+
+// DryRun applies the extrinsic on top of the given block, or of the best block, without storing
+// any state change and returns the result of its application.
+func (sm *SystemModule) DryRun(r *http.Request, req *SystemDryRunRequest, res *SystemDryRunResponse) error {
+	ext, err := common.HexToBytes(req.Extrinsic)
+	if err != nil {
+		return fmt.Errorf("cannot decode extrinsic: %w", err)
+	}
+
+	ret, err := sm.coreAPI.DryRun(types.Extrinsic(ext), req.At)
+	if err != nil {
+		return err
+	}
+
+	response := SystemDryRunResponse{
+		Result: common.BytesToHex(ret),
+	}
+
+	var (
+		dispatchErr *babe.DispatchOutcomeError
+		validityErr *babe.TransactionValidityError
+	)
+	err = babe.DetermineErr(ret)
+	switch {
+	case err == nil:
+	case errors.As(err, &dispatchErr):
+		response.Error = &DryRunError{Type: DryRunDispatchError, Message: dispatchErr.Error()}
+	case errors.As(err, &validityErr):
+		response.Error = &DryRunError{Type: DryRunTransactionValidityError, Message: validityErr.Error()}
+	default:
+		return fmt.Errorf("cannot decode apply extrinsic result: %w", err)
+	}
+
+	*res = response
+	return nil
+}
@@ -310,7 +310,7 @@ func setupSystemModule(t *testing.T) *SystemModule {
 	require.NoError(t, err)
 	ts.Set(aliceAcctStoKey, aliceAcctEncoded)
 
-	err = chain.Storage.StoreTrie(ts, nil)
+	err = chain.Storage.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	digest := types.NewDigest()
@@ -374,6 +374,7 @@ func newCoreService(t *testing.T, srvc *state.Service) *core.Service {
 	mocknet.EXPECT().GossipMessage(
 		gomock.AssignableToTypeOf(new(network.TransactionMessage))).
 		AnyTimes()
+	mocknet.EXPECT().Peers().AnyTimes()
 
 	cfg := &core.Config{
 		Runtime:              rt,
@@ -605,3 +605,148 @@ type args struct {
 		})
 	}
 }
+
+func TestSystemModule_AddLogFilter(t *testing.T) {
+	mockSystemAPI := new(mocks.SystemAPI)
+	mockSystemAPI.On("AddLogFilter", "sync=trace").Return(nil)
+
+	mockSystemAPIErr := new(mocks.SystemAPI)
+	mockSystemAPIErr.On("AddLogFilter", "sync=trace").Return(errors.New("addLogFilter error"))
+
+	tests := []struct {
+		name      string
+		sysModule *SystemModule
+		req       *StringRequest
+		expErr    error
+	}{
+		{
+			name:      "OK",
+			sysModule: NewSystemModule(nil, mockSystemAPI, nil, nil, nil, nil, nil),
+			req:       &StringRequest{"sync=trace"},
+		},
+		{
+			name:      "AddLogFilter Error",
+			sysModule: NewSystemModule(nil, mockSystemAPIErr, nil, nil, nil, nil, nil),
+			req:       &StringRequest{"sync=trace"},
+			expErr:    errors.New("addLogFilter error"),
+		},
+		{
+			name:      "Empty StringRequest Error",
+			sysModule: NewSystemModule(nil, mockSystemAPI, nil, nil, nil, nil, nil),
+			req:       &StringRequest{" "},
+			expErr:    errors.New("cannot add an empty log filter"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := []byte(nil)
+			err := tt.sysModule.AddLogFilter(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Nil(t, res)
+		})
+	}
+}
+
+func TestSystemModule_ResetLogFilter(t *testing.T) {
+	mockSystemAPI := new(mocks.SystemAPI)
+	mockSystemAPI.On("ResetLogFilter").Return(nil)
+
+	sm := NewSystemModule(nil, mockSystemAPI, nil, nil, nil, nil, nil)
+	res := []byte(nil)
+	err := sm.ResetLogFilter(nil, &EmptyRequest{}, &res)
+	assert.NoError(t, err)
+	mockSystemAPI.AssertExpectations(t)
+}
+
+func TestSystemModule_DryRun(t *testing.T) {
+	hash := common.Hash{1}
+	ext := types.Extrinsic{1, 2, 3}
+
+	mockCoreAPI := new(mocks.CoreAPI)
+	mockCoreAPI.On("DryRun", ext, (*common.Hash)(nil)).Return([]byte{0, 0}, nil)
+	mockCoreAPI.On("DryRun", ext, &hash).Return([]byte{0, 1, 2}, nil)
+
+	mockCoreAPIValidityErr := new(mocks.CoreAPI)
+	mockCoreAPIValidityErr.On("DryRun", ext, (*common.Hash)(nil)).Return([]byte{1, 0, 1}, nil)
+
+	mockCoreAPIErr := new(mocks.CoreAPI)
+	mockCoreAPIErr.On("DryRun", ext, (*common.Hash)(nil)).Return(nil, errors.New("dry run error"))
+
+	mockCoreAPIInvalidResult := new(mocks.CoreAPI)
+	mockCoreAPIInvalidResult.On("DryRun", ext, (*common.Hash)(nil)).Return([]byte{2}, nil)
+
+	tests := []struct {
+		name      string
+		sysModule *SystemModule
+		req       *SystemDryRunRequest
+		exp       SystemDryRunResponse
+		expErr    error
+	}{
+		{
+			name:      "OK",
+			sysModule: NewSystemModule(nil, nil, mockCoreAPI, nil, nil, nil, nil),
+			req:       &SystemDryRunRequest{Extrinsic: "0x010203"},
+			exp:       SystemDryRunResponse{Result: "0x0000"},
+		},
+		{
+			name:      "dispatch error at block",
+			sysModule: NewSystemModule(nil, nil, mockCoreAPI, nil, nil, nil, nil),
+			req:       &SystemDryRunRequest{Extrinsic: "0x010203", At: &hash},
+			exp: SystemDryRunResponse{
+				Result: "0x000102",
+				Error: &DryRunError{
+					Type:    DryRunDispatchError,
+					Message: "dispatch outcome error: bad origin",
+				},
+			},
+		},
+		{
+			name:      "transaction validity error",
+			sysModule: NewSystemModule(nil, nil, mockCoreAPIValidityErr, nil, nil, nil, nil),
+			req:       &SystemDryRunRequest{Extrinsic: "0x010203"},
+			exp: SystemDryRunResponse{
+				Result: "0x010001",
+				Error: &DryRunError{
+					Type:    DryRunTransactionValidityError,
+					Message: "transaction validity error: invalid payment",
+				},
+			},
+		},
+		{
+			name:      "invalid extrinsic",
+			sysModule: NewSystemModule(nil, nil, mockCoreAPI, nil, nil, nil, nil),
+			req:       &SystemDryRunRequest{Extrinsic: "010203"},
+			expErr: errors.New("cannot decode extrinsic: " +
+				"could not byteify non 0x prefixed string: 010203"),
+		},
+		{
+			name:      "dry run error",
+			sysModule: NewSystemModule(nil, nil, mockCoreAPIErr, nil, nil, nil, nil),
+			req:       &SystemDryRunRequest{Extrinsic: "0x010203"},
+			expErr:    errors.New("dry run error"),
+		},
+		{
+			name:      "invalid result",
+			sysModule: NewSystemModule(nil, nil, mockCoreAPIInvalidResult, nil, nil, nil, nil),
+			req:       &SystemDryRunRequest{Extrinsic: "0x010203"},
+			expErr: errors.New("cannot decode apply extrinsic result: " +
+				"unmarshal error: unsupported Result value: 2, bytes: []"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res SystemDryRunResponse
+			err := tt.sysModule.DryRun(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"net/http"
+)
+
+var errConnectionRequired = errors.New(
+	"transaction_v1 methods are only available on websocket and IPC connections")
+
+// TransactionStopRequest is the request to stop broadcasting a transaction
+type TransactionStopRequest struct {
+	OperationID string
+}
+
+// TransactionModule is an RPC module declaring the transaction_v1 methods.
+// The broadcast operations are bound to the connection starting them, and are
+// stopped when the connection is closed, so the methods are handled by the
+// websocket and IPC servers, and cannot be called over HTTP.
+type TransactionModule struct{}
+
+// NewTransactionModule creates a new transaction_v1 module.
+func NewTransactionModule() *TransactionModule {
+	return &TransactionModule{}
+}
+
+// Broadcast broadcasts the hex SCALE encoded transaction to the network
+// until it is finalised. It is only available on websocket and IPC connections.
+func (*TransactionModule) Broadcast(_ *http.Request, _ *Extrinsic, _ *interface{}) error {
+	return errConnectionRequired
+}
+
+// Stop stops the broadcast operation with the given id.
+// It is only available on websocket and IPC connections.
+func (*TransactionModule) Stop(_ *http.Request, _ *TransactionStopRequest, _ *interface{}) error {
+	return errConnectionRequired
+}
@@ -0,0 +1,23 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionModule_HTTP(t *testing.T) {
+	t.Parallel()
+
+	module := NewTransactionModule()
+
+	var res interface{}
+	err := module.Broadcast(nil, &Extrinsic{Data: "0x01"}, &res)
+	assert.ErrorIs(t, err, errConnectionRequired)
+
+	err = module.Stop(nil, &TransactionStopRequest{OperationID: "0"}, &res)
+	assert.ErrorIs(t, err, errConnectionRequired)
+}
@@ -0,0 +1,1138 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// chainHead_v1 methods called with a follow subscription
+const (
+	chainHeadUnfollow      = "chainHead_v1_unfollow"
+	chainHeadHeader        = "chainHead_v1_header"
+	chainHeadBody          = "chainHead_v1_body"
+	chainHeadStorage       = "chainHead_v1_storage"
+	chainHeadCall          = "chainHead_v1_call"
+	chainHeadUnpin         = "chainHead_v1_unpin"
+	chainHeadStopOperation = "chainHead_v1_stopOperation"
+)
+
+const (
+	chainHeadFollowEventMethod = "chainHead_v1_followEvent"
+
+	// maxChainHeadPinnedBlocks is the maximum number of blocks pinned by a follow
+	// subscription. The subscription is stopped if it is exceeded, which happens
+	// if the client falls behind and does not unpin the blocks it is done with.
+	maxChainHeadPinnedBlocks = 512
+	// maxChainHeadOperations is the maximum number of operations running
+	// at the same time for a follow subscription.
+	maxChainHeadOperations = 16
+	// chainHeadStorageItemsPerEvent is the maximum number of storage items
+	// sent in a single operationStorageItems event.
+	chainHeadStorageItemsPerEvent = 64
+)
+
+// storage query types of chainHead_v1_storage
+const (
+	storageQueryValue             = "value"
+	storageQueryHash              = "hash"
+	storageQueryDescendantsValues = "descendantsValues"
+	storageQueryDescendantsHashes = "descendantsHashes"
+)
+
+var (
+	errFollowSubscriptionNotFound = errors.New("follow subscription not found")
+	errFollowSubscriptionStopped  = errors.New("follow subscription stopped")
+	errBlockNotPinned             = errors.New("block is not pinned")
+	errTooManyPinnedBlocks        = errors.New("too many pinned blocks")
+	errBlockNotDescendant         = errors.New("block does not descend from the finalised block")
+	errOperationsLimitReached     = errors.New("operations limit reached")
+	errUnsupportedStorageQuery    = errors.New("unsupported storage query type")
+	errCoreAPINotSet              = errors.New("error CoreAPI not set")
+)
+
+type chainHeadEvent struct {
+	Event string `json:"event"`
+}
+
+type chainHeadInitializedEvent struct {
+	Event                string   `json:"event"`
+	FinalizedBlockHashes []string `json:"finalizedBlockHashes"`
+}
+
+type chainHeadInitializedWithRuntimeEvent struct {
+	chainHeadInitializedEvent
+	FinalizedBlockRuntime *chainHeadRuntime `json:"finalizedBlockRuntime"`
+}
+
+type chainHeadNewBlockEvent struct {
+	Event           string `json:"event"`
+	BlockHash       string `json:"blockHash"`
+	ParentBlockHash string `json:"parentBlockHash"`
+}
+
+type chainHeadNewBlockWithRuntimeEvent struct {
+	chainHeadNewBlockEvent
+	NewRuntime *chainHeadRuntime `json:"newRuntime"`
+}
+
+type chainHeadBestBlockChangedEvent struct {
+	Event         string `json:"event"`
+	BestBlockHash string `json:"bestBlockHash"`
+}
+
+type chainHeadFinalizedEvent struct {
+	Event                string   `json:"event"`
+	FinalizedBlockHashes []string `json:"finalizedBlockHashes"`
+	PrunedBlockHashes    []string `json:"prunedBlockHashes"`
+}
+
+type chainHeadRuntime struct {
+	Type  string                `json:"type"`
+	Spec  *chainHeadRuntimeSpec `json:"spec,omitempty"`
+	Error string                `json:"error,omitempty"`
+}
+
+type chainHeadRuntimeSpec struct {
+	SpecName           string            `json:"specName"`
+	ImplName           string            `json:"implName"`
+	SpecVersion        uint32            `json:"specVersion"`
+	ImplVersion        uint32            `json:"implVersion"`
+	TransactionVersion uint32            `json:"transactionVersion"`
+	Apis               map[string]uint32 `json:"apis"`
+}
+
+type chainHeadOperationStarted struct {
+	Result         string `json:"result"`
+	OperationID    string `json:"operationId,omitempty"`
+	DiscardedItems *int   `json:"discardedItems,omitempty"`
+}
+
+type chainHeadOperationEvent struct {
+	Event       string `json:"event"`
+	OperationID string `json:"operationId"`
+}
+
+type chainHeadOperationErrorEvent struct {
+	Event       string `json:"event"`
+	OperationID string `json:"operationId"`
+	Error       string `json:"error"`
+}
+
+type chainHeadBodyDoneEvent struct {
+	Event       string   `json:"event"`
+	OperationID string   `json:"operationId"`
+	Value       []string `json:"value"`
+}
+
+type chainHeadCallDoneEvent struct {
+	Event       string `json:"event"`
+	OperationID string `json:"operationId"`
+	Output      string `json:"output"`
+}
+
+type chainHeadStorageItemsEvent struct {
+	Event       string                 `json:"event"`
+	OperationID string                 `json:"operationId"`
+	Items       []chainHeadStorageItem `json:"items"`
+}
+
+type chainHeadStorageItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+	Hash  string `json:"hash,omitempty"`
+}
+
+type chainHeadStorageQuery struct {
+	key       []byte
+	queryType string
+}
+
+// ChainHeadFollowListener is the listener of a chainHead_v1_follow subscription.
+// It reports the imported, best and finalised blocks, and pins each reported block
+// until the client unpins it, so the block can be queried with the chainHead_v1
+// methods taking the follow subscription as first parameter.
+type ChainHeadFollowListener struct {
+	wsconn        *WSConn
+	subID         uint32
+	withRuntime   bool
+	importedChan  chan *types.Block
+	finalisedChan chan *types.FinalisationInfo
+
+	mutex   sync.Mutex
+	stopped bool
+	pinned  map[common.Hash]struct{}
+	// nonFinalised maps the hash of each reported block
+	// that is not finalised yet to its parent hash.
+	nonFinalised    map[common.Hash]common.Hash
+	finalisedHash   common.Hash
+	finalisedNumber uint
+	bestHash        common.Hash
+	nextOperationID uint64
+	// operations is the set of ids of the running operations.
+	operations map[string]struct{}
+
+	done          chan struct{}
+	cancel        chan struct{}
+	cancelTimeout time.Duration
+}
+
+func newChainHeadFollowListener(conn *WSConn, withRuntime bool) *ChainHeadFollowListener {
+	return &ChainHeadFollowListener{
+		wsconn:        conn,
+		withRuntime:   withRuntime,
+		pinned:        make(map[common.Hash]struct{}),
+		nonFinalised:  make(map[common.Hash]common.Hash),
+		operations:    make(map[string]struct{}),
+		cancel:        make(chan struct{}, 1),
+		done:          make(chan struct{}, 1),
+		cancelTimeout: defaultCancelTimeout,
+	}
+}
+
+func (c *WSConn) initChainHeadFollowListener(reqID float64, params interface{}) (Listener, error) {
+	if c.BlockAPI == nil {
+		c.safeSendError(reqID, nil, "error BlockAPI not set")
+		return nil, fmt.Errorf("error BlockAPI not set")
+	}
+
+	var withRuntime bool
+	if args, ok := params.([]interface{}); ok && len(args) > 0 {
+		withRuntime, ok = args[0].(bool)
+		if !ok {
+			c.safeSendError(reqID, big.NewInt(InvalidRequestCode), InvalidRequestMessage)
+			return nil, fmt.Errorf("%w: %T, expected type bool", errUnexpectedType, args[0])
+		}
+	}
+
+	if withRuntime && c.CoreAPI == nil {
+		c.safeSendError(reqID, nil, errCoreAPINotSet.Error())
+		return nil, errCoreAPINotSet
+	}
+
+	listener := newChainHeadFollowListener(c, withRuntime)
+	listener.importedChan = c.BlockAPI.GetImportedBlockNotifierChannel()
+	listener.finalisedChan = c.BlockAPI.GetFinalisedNotifierChannel()
+
+	c.mu.Lock()
+	listener.subID = atomic.AddUint32(&c.qtyListeners, 1)
+	c.Subscriptions[listener.subID] = listener
+	c.mu.Unlock()
+
+	c.safeSend(NewSubscriptionResponseJSON(listener.subID, reqID))
+
+	return listener, nil
+}
+
+// Listen starts a goroutine reporting the current state of the chain, followed
+// by the imported and finalised blocks. A stop event ends the subscription if
+// the listener cannot keep up with the chain.
+func (l *ChainHeadFollowListener) Listen() {
+	go func() {
+		defer func() {
+			l.wsconn.BlockAPI.FreeImportedBlockNotifierChannel(l.importedChan)
+			l.wsconn.BlockAPI.FreeFinalisedNotifierChannel(l.finalisedChan)
+
+			l.mutex.Lock()
+			l.stopped = true
+			l.operations = make(map[string]struct{})
+			// the blocks pinned by the subscription can be released by the block state
+			for hash := range l.pinned {
+				l.wsconn.BlockAPI.UnpinBlock(hash)
+			}
+			l.pinned = make(map[common.Hash]struct{})
+			l.mutex.Unlock()
+
+			close(l.done)
+		}()
+
+		err := l.initialise()
+		if err != nil {
+			logger.Debugf("stopping chainHead follow subscription %d: %s", l.subID, err)
+			l.send(chainHeadEvent{Event: "stop"})
+			return
+		}
+
+		for {
+			select {
+			case <-l.cancel:
+				return
+			case block, ok := <-l.importedChan:
+				if !ok {
+					l.send(chainHeadEvent{Event: "stop"})
+					return
+				}
+
+				if block == nil {
+					continue
+				}
+
+				err = l.handleImportedBlock(&block.Header)
+			case info, ok := <-l.finalisedChan:
+				if !ok {
+					l.send(chainHeadEvent{Event: "stop"})
+					return
+				}
+
+				if info == nil {
+					continue
+				}
+
+				err = l.handleFinalisedBlock(&info.Header)
+			}
+
+			if err != nil {
+				logger.Debugf("stopping chainHead follow subscription %d: %s", l.subID, err)
+				l.send(chainHeadEvent{Event: "stop"})
+				return
+			}
+		}
+	}()
+}
+
+// Stop cancels the goroutine of the listener
+func (l *ChainHeadFollowListener) Stop() error {
+	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
+}
+
+// initialise reports the finalised block, followed by all its descendants
+// and the best block.
+func (l *ChainHeadFollowListener) initialise() error {
+	finalisedHash, err := l.wsconn.BlockAPI.GetHighestFinalisedHash()
+	if err != nil {
+		return fmt.Errorf("cannot get highest finalised hash: %w", err)
+	}
+
+	header, err := l.wsconn.BlockAPI.GetHeader(finalisedHash)
+	if err != nil {
+		return fmt.Errorf("cannot get header of finalised block %s: %w", finalisedHash, err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	err = l.pin(finalisedHash)
+	if err != nil {
+		return err
+	}
+
+	l.finalisedHash = finalisedHash
+	l.finalisedNumber = header.Number
+
+	initialized := chainHeadInitializedEvent{
+		Event:                "initialized",
+		FinalizedBlockHashes: []string{finalisedHash.String()},
+	}
+	if l.withRuntime {
+		l.send(chainHeadInitializedWithRuntimeEvent{
+			chainHeadInitializedEvent: initialized,
+			FinalizedBlockRuntime:     l.runtime(finalisedHash),
+		})
+	} else {
+		l.send(initialized)
+	}
+
+	for _, leaf := range l.wsconn.BlockAPI.Leaves() {
+		err = l.reportNewBlocks(leaf)
+		if errors.Is(err, errBlockNotDescendant) {
+			// the block was finalised or pruned in the meantime,
+			// and is reported by the finalisation notifications.
+			continue
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return l.updateBestBlock()
+}
+
+func (l *ChainHeadFollowListener) handleImportedBlock(header *types.Header) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if header.Number <= l.finalisedNumber {
+		// the block is either already reported as finalised,
+		// or on a fork pruned by the finalised block.
+		return nil
+	}
+
+	err := l.reportNewBlocks(header.Hash())
+	if errors.Is(err, errBlockNotDescendant) {
+		logger.Debugf("ignoring imported block %s: %s", header.Hash(), err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return l.updateBestBlock()
+}
+
+func (l *ChainHeadFollowListener) handleFinalisedBlock(header *types.Header) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if header.Number <= l.finalisedNumber {
+		return nil
+	}
+
+	hash := header.Hash()
+	err := l.reportNewBlocks(hash)
+	if err != nil {
+		return err
+	}
+
+	var finalised []common.Hash
+	for h := hash; h != l.finalisedHash; h = l.nonFinalised[h] {
+		finalised = append([]common.Hash{h}, finalised...)
+	}
+
+	for _, h := range finalised {
+		delete(l.nonFinalised, h)
+	}
+	l.finalisedHash = hash
+	l.finalisedNumber = header.Number
+
+	var pruned []common.Hash
+	for h := range l.nonFinalised {
+		if !l.descendsFromFinalised(h) {
+			pruned = append(pruned, h)
+		}
+	}
+
+	for _, h := range pruned {
+		delete(l.nonFinalised, h)
+	}
+
+	// the best block must be updated before the finalized event
+	// in case the current best block is pruned.
+	err = l.updateBestBlock()
+	if err != nil {
+		return err
+	}
+
+	l.send(chainHeadFinalizedEvent{
+		Event:                "finalized",
+		FinalizedBlockHashes: hashesToStrings(finalised),
+		PrunedBlockHashes:    hashesToStrings(pruned),
+	})
+	return nil
+}
+
+// reportNewBlocks reports the given block and its ancestors which are not reported yet.
+// It must be called with the mutex locked.
+func (l *ChainHeadFollowListener) reportNewBlocks(hash common.Hash) error {
+	var unreported []*types.Header
+	for !l.isReported(hash) {
+		header, err := l.wsconn.BlockAPI.GetHeader(hash)
+		if err != nil {
+			return fmt.Errorf("cannot get header of block %s: %w", hash, err)
+		}
+
+		if header.Number <= l.finalisedNumber {
+			return fmt.Errorf("%w: block %s", errBlockNotDescendant, hash)
+		}
+
+		unreported = append(unreported, header)
+		if len(unreported)+len(l.pinned) > maxChainHeadPinnedBlocks {
+			return errTooManyPinnedBlocks
+		}
+
+		hash = header.ParentHash
+	}
+
+	for i := len(unreported) - 1; i >= 0; i-- {
+		err := l.reportNewBlock(unreported[i].Hash(), unreported[i].ParentHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *ChainHeadFollowListener) reportNewBlock(hash, parentHash common.Hash) error {
+	if len(l.pinned) >= maxChainHeadPinnedBlocks {
+		return errTooManyPinnedBlocks
+	}
+
+	err := l.pin(hash)
+	if err != nil {
+		return err
+	}
+
+	l.nonFinalised[hash] = parentHash
+
+	newBlock := chainHeadNewBlockEvent{
+		Event:           "newBlock",
+		BlockHash:       hash.String(),
+		ParentBlockHash: parentHash.String(),
+	}
+
+	if !l.withRuntime {
+		l.send(newBlock)
+		return nil
+	}
+
+	l.send(chainHeadNewBlockWithRuntimeEvent{
+		chainHeadNewBlockEvent: newBlock,
+		NewRuntime:             l.newRuntime(hash, parentHash),
+	})
+	return nil
+}
+
+// updateBestBlock reports the best block if it changed.
+// It must be called with the mutex locked.
+func (l *ChainHeadFollowListener) updateBestBlock() error {
+	bestHash := l.wsconn.BlockAPI.BestBlockHash()
+	if bestHash == l.bestHash {
+		return nil
+	}
+
+	err := l.reportNewBlocks(bestHash)
+	if errors.Is(err, errBlockNotDescendant) {
+		// the best block is behind the finalised block reported,
+		// which can happen when the best block has not been
+		// updated yet after the finalisation of a fork.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	l.bestHash = bestHash
+	l.send(chainHeadBestBlockChangedEvent{
+		Event:         "bestBlockChanged",
+		BestBlockHash: bestHash.String(),
+	})
+	return nil
+}
+
+func (l *ChainHeadFollowListener) isReported(hash common.Hash) bool {
+	if hash == l.finalisedHash {
+		return true
+	}
+
+	_, ok := l.nonFinalised[hash]
+	return ok
+}
+
+func (l *ChainHeadFollowListener) descendsFromFinalised(hash common.Hash) bool {
+	for {
+		parentHash, ok := l.nonFinalised[hash]
+		if !ok {
+			return false
+		}
+
+		if parentHash == l.finalisedHash {
+			return true
+		}
+
+		hash = parentHash
+	}
+}
+
+// newRuntime returns the runtime of the given block if it differs
+// from the runtime of its parent, and nil otherwise.
+func (l *ChainHeadFollowListener) newRuntime(hash, parentHash common.Hash) *chainHeadRuntime {
+	version, err := l.wsconn.CoreAPI.GetRuntimeVersion(&hash)
+	if err != nil {
+		return &chainHeadRuntime{Type: "invalid", Error: err.Error()}
+	}
+
+	parentVersion, err := l.wsconn.CoreAPI.GetRuntimeVersion(&parentHash)
+	if err == nil {
+		encoded, encodeErr := version.Encode()
+		parentEncoded, parentEncodeErr := parentVersion.Encode()
+		if encodeErr == nil && parentEncodeErr == nil && bytes.Equal(encoded, parentEncoded) {
+			return nil
+		}
+	}
+
+	return l.runtime(hash)
+}
+
+func (l *ChainHeadFollowListener) runtime(hash common.Hash) *chainHeadRuntime {
+	version, err := l.wsconn.CoreAPI.GetRuntimeVersion(&hash)
+	if err != nil {
+		return &chainHeadRuntime{Type: "invalid", Error: err.Error()}
+	}
+
+	apis := make(map[string]uint32, len(version.APIItems()))
+	for _, item := range version.APIItems() {
+		apis[common.BytesToHex(item.Name[:])] = item.Ver
+	}
+
+	return &chainHeadRuntime{
+		Type: "valid",
+		Spec: &chainHeadRuntimeSpec{
+			SpecName:           string(version.SpecName()),
+			ImplName:           string(version.ImplName()),
+			SpecVersion:        version.SpecVersion(),
+			ImplVersion:        version.ImplVersion(),
+			TransactionVersion: version.TransactionVersion(),
+			Apis:               apis,
+		},
+	}
+}
+
+func (l *ChainHeadFollowListener) send(event interface{}) {
+	l.wsconn.safeSend(newSubscriptionResponse(chainHeadFollowEventMethod, l.subID, event))
+}
+
+// chainHeadMethodHandler handles a chainHead_v1 method call for a follow subscription, given
+// the parameters of the call following the subscription id. It returns the result of the
+// call, and optionally an operation to run in the background once the result is sent.
+type chainHeadMethodHandler func(l *ChainHeadFollowListener, params []interface{}) (
+	result interface{}, operation func(), err error)
+
+func (*WSConn) getChainHeadMethodHandler(method string) chainHeadMethodHandler {
+	switch method {
+	case chainHeadUnfollow:
+		return (*ChainHeadFollowListener).unfollow
+	case chainHeadHeader:
+		return (*ChainHeadFollowListener).header
+	case chainHeadBody:
+		return (*ChainHeadFollowListener).body
+	case chainHeadStorage:
+		return (*ChainHeadFollowListener).storage
+	case chainHeadCall:
+		return (*ChainHeadFollowListener).call
+	case chainHeadUnpin:
+		return (*ChainHeadFollowListener).unpin
+	case chainHeadStopOperation:
+		return (*ChainHeadFollowListener).stopOperation
+	default:
+		return nil
+	}
+}
+
+func (c *WSConn) handleChainHeadMethod(reqID float64, params interface{}, handler chainHeadMethodHandler) {
+	result, operation, err := c.callChainHeadMethod(params, handler)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidRequestCode), err.Error())
+		return
+	}
+
+	c.safeSend(newResultResponseJSON(result, reqID))
+
+	if operation != nil {
+		go operation()
+	}
+}
+
+func (c *WSConn) callChainHeadMethod(params interface{}, handler chainHeadMethodHandler) (
+	result interface{}, operation func(), err error) {
+	subID, err := parseSubscribeID(params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	subscription := c.Subscriptions[subID]
+	c.mu.Unlock()
+
+	listener, ok := subscription.(*ChainHeadFollowListener)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %d", errFollowSubscriptionNotFound, subID)
+	}
+
+	return handler(listener, params.([]interface{})[1:])
+}
+
+func (l *ChainHeadFollowListener) unfollow(_ []interface{}) (interface{}, func(), error) {
+	err := l.Stop()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l.wsconn.mu.Lock()
+	delete(l.wsconn.Subscriptions, l.subID)
+	l.wsconn.mu.Unlock()
+
+	return nil, nil, nil
+}
+
+func (l *ChainHeadFollowListener) header(params []interface{}) (interface{}, func(), error) {
+	hash, err := parseHashParam(params, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = l.checkPinned(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header, err := l.wsconn.BlockAPI.GetHeader(hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get header of block %s: %w", hash, err)
+	}
+
+	encoded, err := scale.Marshal(*header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot encode header: %w", err)
+	}
+
+	return common.BytesToHex(encoded), nil, nil
+}
+
+func (l *ChainHeadFollowListener) body(params []interface{}) (interface{}, func(), error) {
+	hash, err := parseHashParam(params, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	operationID, err := l.startOperation(hash)
+	if errors.Is(err, errOperationsLimitReached) {
+		return chainHeadOperationStarted{Result: "limitReached"}, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	operation := func() {
+		block, err := l.wsconn.BlockAPI.GetBlockByHash(hash)
+		if err != nil {
+			l.endOperation(operationID, chainHeadOperationErrorEvent{
+				Event:       "operationError",
+				OperationID: operationID,
+				Error:       err.Error(),
+			})
+			return
+		}
+
+		value := make([]string, len(block.Body))
+		for i, ext := range block.Body {
+			value[i] = common.BytesToHex(ext)
+		}
+
+		l.endOperation(operationID, chainHeadBodyDoneEvent{
+			Event:       "operationBodyDone",
+			OperationID: operationID,
+			Value:       value,
+		})
+	}
+
+	return chainHeadOperationStarted{Result: "started", OperationID: operationID}, operation, nil
+}
+
+func (l *ChainHeadFollowListener) call(params []interface{}) (interface{}, func(), error) {
+	if len(params) < 3 {
+		return nil, nil, fmt.Errorf("%w: expected 4 params, got: %d", errUnexpectedParamLen, len(params)+1)
+	}
+
+	hash, err := parseHashParam(params, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	function, ok := params[1].(string)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %T, expected type string", errUnexpectedType, params[1])
+	}
+
+	callParameters, err := parseBytesParam(params[2])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if l.wsconn.CoreAPI == nil {
+		return nil, nil, errCoreAPINotSet
+	}
+
+	operationID, err := l.startOperation(hash)
+	if errors.Is(err, errOperationsLimitReached) {
+		return chainHeadOperationStarted{Result: "limitReached"}, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	operation := func() {
+		output, err := l.wsconn.CoreAPI.CallRuntimeAPI(&hash, function, callParameters)
+		if err != nil {
+			l.endOperation(operationID, chainHeadOperationErrorEvent{
+				Event:       "operationError",
+				OperationID: operationID,
+				Error:       err.Error(),
+			})
+			return
+		}
+
+		l.endOperation(operationID, chainHeadCallDoneEvent{
+			Event:       "operationCallDone",
+			OperationID: operationID,
+			Output:      common.BytesToHex(output),
+		})
+	}
+
+	return chainHeadOperationStarted{Result: "started", OperationID: operationID}, operation, nil
+}
+
+func (l *ChainHeadFollowListener) storage(params []interface{}) (interface{}, func(), error) {
+	if len(params) < 2 {
+		return nil, nil, fmt.Errorf("%w: expected at least 3 params, got: %d", errUnexpectedParamLen, len(params)+1)
+	}
+
+	hash, err := parseHashParam(params, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queries, err := parseStorageQueries(params[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var childKey []byte
+	if len(params) > 2 && params[2] != nil {
+		childKey, err = parseBytesParam(params[2])
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if l.wsconn.StorageAPI == nil {
+		return nil, nil, fmt.Errorf("error StorageAPI not set")
+	}
+
+	operationID, err := l.startOperation(hash)
+	if errors.Is(err, errOperationsLimitReached) {
+		return chainHeadOperationStarted{Result: "limitReached"}, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	operation := func() {
+		err := l.queryStorage(operationID, hash, queries, childKey)
+		if err != nil {
+			l.endOperation(operationID, chainHeadOperationErrorEvent{
+				Event:       "operationError",
+				OperationID: operationID,
+				Error:       err.Error(),
+			})
+			return
+		}
+
+		l.endOperation(operationID, chainHeadOperationEvent{
+			Event:       "operationStorageDone",
+			OperationID: operationID,
+		})
+	}
+
+	discardedItems := 0
+	started := chainHeadOperationStarted{
+		Result:         "started",
+		OperationID:    operationID,
+		DiscardedItems: &discardedItems,
+	}
+	return started, operation, nil
+}
+
+// queryStorage runs the storage queries at the given block, and sends the resulting
+// storage items in operationStorageItems events. It returns early if the operation
+// is stopped.
+func (l *ChainHeadFollowListener) queryStorage(operationID string, hash common.Hash,
+	queries []chainHeadStorageQuery, childKey []byte) error {
+	root, err := l.wsconn.StorageAPI.GetStateRootFromBlock(&hash)
+	if err != nil {
+		return fmt.Errorf("cannot get state root of block %s: %w", hash, err)
+	}
+
+	var childTrie *trie.Trie
+	if childKey != nil {
+		childTrie, err = l.wsconn.StorageAPI.GetStorageChild(root, childKey)
+		if err != nil {
+			return fmt.Errorf("cannot get child trie: %w", err)
+		}
+	}
+
+	get := func(key []byte) ([]byte, error) {
+		if childKey == nil {
+			return l.wsconn.StorageAPI.GetStorage(root, key)
+		}
+		if childTrie == nil {
+			return nil, nil
+		}
+		return childTrie.Get(key), nil
+	}
+
+	getKeysWithPrefix := func(prefix []byte) ([][]byte, error) {
+		if childKey == nil {
+			return l.wsconn.StorageAPI.GetKeysWithPrefix(root, prefix)
+		}
+		if childTrie == nil {
+			return nil, nil
+		}
+		return childTrie.GetKeysWithPrefix(prefix), nil
+	}
+
+	var items []chainHeadStorageItem
+	for _, query := range queries {
+		keys := [][]byte{query.key}
+		if query.queryType == storageQueryDescendantsValues || query.queryType == storageQueryDescendantsHashes {
+			keys, err = getKeysWithPrefix(query.key)
+			if err != nil {
+				return fmt.Errorf("cannot get keys with prefix %s: %w", common.BytesToHex(query.key), err)
+			}
+		}
+
+		for _, key := range keys {
+			value, err := get(key)
+			if err != nil {
+				return fmt.Errorf("cannot get storage value for key %s: %w", common.BytesToHex(key), err)
+			}
+
+			if value == nil {
+				continue
+			}
+
+			item := chainHeadStorageItem{Key: common.BytesToHex(key)}
+			switch query.queryType {
+			case storageQueryValue, storageQueryDescendantsValues:
+				item.Value = common.BytesToHex(value)
+			case storageQueryHash, storageQueryDescendantsHashes:
+				valueHash, err := common.Blake2bHash(value)
+				if err != nil {
+					return fmt.Errorf("cannot hash storage value: %w", err)
+				}
+				item.Hash = valueHash.String()
+			}
+			items = append(items, item)
+
+			if len(items) == chainHeadStorageItemsPerEvent {
+				if !l.sendStorageItems(operationID, items) {
+					return nil
+				}
+				items = nil
+			}
+		}
+	}
+
+	if len(items) > 0 {
+		l.sendStorageItems(operationID, items)
+	}
+
+	return nil
+}
+
+// sendStorageItems sends the storage items of the operation,
+// and returns false if the operation is stopped.
+func (l *ChainHeadFollowListener) sendStorageItems(operationID string, items []chainHeadStorageItem) bool {
+	l.mutex.Lock()
+	_, running := l.operations[operationID]
+	l.mutex.Unlock()
+
+	if !running {
+		return false
+	}
+
+	l.send(chainHeadStorageItemsEvent{
+		Event:       "operationStorageItems",
+		OperationID: operationID,
+		Items:       items,
+	})
+	return true
+}
+
+func (l *ChainHeadFollowListener) unpin(params []interface{}) (interface{}, func(), error) {
+	if len(params) < 1 {
+		return nil, nil, fmt.Errorf("%w: expected 2 params, got: %d", errUnexpectedParamLen, len(params)+1)
+	}
+
+	var hashes []common.Hash
+	switch hashOrHashes := params[0].(type) {
+	case string:
+		hash, err := common.HexToHash(hashOrHashes)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashes = append(hashes, hash)
+	case []interface{}:
+		for i := range hashOrHashes {
+			hash, err := parseHashParam(hashOrHashes, i)
+			if err != nil {
+				return nil, nil, err
+			}
+			hashes = append(hashes, hash)
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: %T, expected type string or []interface{}", errUnexpectedType, params[0])
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// no block is unpinned if any of the blocks is not pinned
+	for _, hash := range hashes {
+		if _, ok := l.pinned[hash]; !ok {
+			return nil, nil, fmt.Errorf("%w: %s", errBlockNotPinned, hash)
+		}
+	}
+
+	for _, hash := range hashes {
+		delete(l.pinned, hash)
+		l.wsconn.BlockAPI.UnpinBlock(hash)
+	}
+
+	return nil, nil, nil
+}
+
+func (l *ChainHeadFollowListener) stopOperation(params []interface{}) (interface{}, func(), error) {
+	if len(params) < 1 {
+		return nil, nil, fmt.Errorf("%w: expected 2 params, got: %d", errUnexpectedParamLen, len(params)+1)
+	}
+
+	operationID, ok := params[0].(string)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %T, expected type string", errUnexpectedType, params[0])
+	}
+
+	l.mutex.Lock()
+	delete(l.operations, operationID)
+	l.mutex.Unlock()
+
+	return nil, nil, nil
+}
+
+// pin pins the given block in the block state, so it is not released
+// until the subscription unpins it. It must be called with the mutex locked.
+func (l *ChainHeadFollowListener) pin(hash common.Hash) error {
+	err := l.wsconn.BlockAPI.PinBlock(hash)
+	if err != nil {
+		return fmt.Errorf("cannot pin block %s: %w", hash, err)
+	}
+
+	l.pinned[hash] = struct{}{}
+	return nil
+}
+
+func (l *ChainHeadFollowListener) checkPinned(hash common.Hash) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.stopped {
+		return errFollowSubscriptionStopped
+	}
+
+	if _, ok := l.pinned[hash]; !ok {
+		return fmt.Errorf("%w: %s", errBlockNotPinned, hash)
+	}
+
+	return nil
+}
+
+// startOperation registers a new operation on the given pinned block and returns its id.
+func (l *ChainHeadFollowListener) startOperation(hash common.Hash) (operationID string, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.stopped {
+		return "", errFollowSubscriptionStopped
+	}
+
+	if _, ok := l.pinned[hash]; !ok {
+		return "", fmt.Errorf("%w: %s", errBlockNotPinned, hash)
+	}
+
+	if len(l.operations) >= maxChainHeadOperations {
+		return "", errOperationsLimitReached
+	}
+
+	operationID = strconv.FormatUint(l.nextOperationID, 10)
+	l.nextOperationID++
+	l.operations[operationID] = struct{}{}
+
+	return operationID, nil
+}
+
+// endOperation sends the final event of the operation, unless it is stopped.
+func (l *ChainHeadFollowListener) endOperation(operationID string, event interface{}) {
+	l.mutex.Lock()
+	_, running := l.operations[operationID]
+	delete(l.operations, operationID)
+	l.mutex.Unlock()
+
+	if running {
+		l.send(event)
+	}
+}
+
+func parseHashParam(params []interface{}, index int) (common.Hash, error) {
+	if len(params) <= index {
+		return common.Hash{}, fmt.Errorf("%w: expected at least %d params, got: %d",
+			errUnexpectedParamLen, index+1, len(params))
+	}
+
+	hex, ok := params[index].(string)
+	if !ok {
+		return common.Hash{}, fmt.Errorf("%w: %T, expected type string", errUnexpectedType, params[index])
+	}
+
+	return common.HexToHash(hex)
+}
+
+func parseBytesParam(param interface{}) ([]byte, error) {
+	hex, ok := param.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T, expected type string", errUnexpectedType, param)
+	}
+
+	return common.HexToBytes(hex)
+}
+
+func parseStorageQueries(param interface{}) ([]chainHeadStorageQuery, error) {
+	items, ok := param.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %T, expected type []interface{}", errUnexpectedType, param)
+	}
+
+	queries := make([]chainHeadStorageQuery, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %T, expected type map[string]interface{}", errUnexpectedType, item)
+		}
+
+		key, err := parseBytesParam(fields["key"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage query key: %w", err)
+		}
+
+		queryType, ok := fields["type"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T, expected type string", errUnexpectedType, fields["type"])
+		}
+
+		switch queryType {
+		case storageQueryValue, storageQueryHash, storageQueryDescendantsValues, storageQueryDescendantsHashes:
+		default:
+			return nil, fmt.Errorf("%w: %s", errUnsupportedStorageQuery, queryType)
+		}
+
+		queries[i] = chainHeadStorageQuery{
+			key:       key,
+			queryType: queryType,
+		}
+	}
+
+	return queries, nil
+}
+
+func hashesToStrings(hashes []common.Hash) []string {
+	hexHashes := make([]string, len(hashes))
+	for i, hash := range hashes {
+		hexHashes[i] = hash.String()
+	}
+	return hexHashes
+}
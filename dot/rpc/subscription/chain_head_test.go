@@ -0,0 +1,276 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newChainHeadTestHeader(parent common.Hash, number uint, stateRoot byte) *types.Header {
+	return &types.Header{
+		ParentHash: parent,
+		Number:     number,
+		StateRoot:  common.Hash{stateRoot},
+		Digest:     types.NewDigest(),
+	}
+}
+
+func readFollowEvent(t *testing.T, ws *websocket.Conn) string {
+	t.Helper()
+
+	err := ws.SetReadDeadline(time.Now().Add(time.Second * 5))
+	require.NoError(t, err)
+
+	_, msg, err := ws.ReadMessage()
+	require.NoError(t, err)
+
+	var response struct {
+		Params struct {
+			Result json.RawMessage `json:"result"`
+		} `json:"params"`
+	}
+	err = json.Unmarshal(msg, &response)
+	require.NoError(t, err)
+
+	return string(response.Params.Result)
+}
+
+func TestChainHeadFollowListener_Listen(t *testing.T) {
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+
+	header1 := newChainHeadTestHeader(common.Hash{}, 1, 1)
+	hash1 := header1.Hash()
+	header2 := newChainHeadTestHeader(hash1, 2, 2)
+	hash2 := header2.Hash()
+	header2b := newChainHeadTestHeader(hash1, 2, 3)
+	hash2b := header2b.Hash()
+	header3 := newChainHeadTestHeader(hash2, 3, 4)
+	hash3 := header3.Hash()
+	header4 := newChainHeadTestHeader(hash3, 4, 5)
+	hash4 := header4.Hash()
+
+	blockAPI := new(mocks.BlockAPI)
+	blockAPI.On("GetHighestFinalisedHash").Return(hash1, nil)
+	for _, header := range []*types.Header{header1, header2, header2b, header3, header4} {
+		blockAPI.On("GetHeader", header.Hash()).Return(header, nil)
+	}
+	blockAPI.On("Leaves").Return([]common.Hash{hash3, hash2b})
+	blockAPI.On("BestBlockHash").Return(hash3).Twice()
+	blockAPI.On("BestBlockHash").Return(hash4)
+	blockAPI.On("PinBlock", mock.AnythingOfType("common.Hash")).Return(nil)
+	blockAPI.On("UnpinBlock", mock.AnythingOfType("common.Hash"))
+	blockAPI.On("FreeImportedBlockNotifierChannel", mock.AnythingOfType("chan *types.Block"))
+	blockAPI.On("FreeFinalisedNotifierChannel", mock.AnythingOfType("chan *types.FinalisationInfo"))
+	wsconn.BlockAPI = blockAPI
+
+	listener := newChainHeadFollowListener(wsconn, false)
+	listener.subID = 1
+	listener.importedChan = make(chan *types.Block)
+	listener.finalisedChan = make(chan *types.FinalisationInfo)
+
+	listener.Listen()
+
+	expectedEvents := []string{
+		fmt.Sprintf(`{"event":"initialized","finalizedBlockHashes":["%s"]}`, hash1),
+		fmt.Sprintf(`{"event":"newBlock","blockHash":"%s","parentBlockHash":"%s"}`, hash2, hash1),
+		fmt.Sprintf(`{"event":"newBlock","blockHash":"%s","parentBlockHash":"%s"}`, hash3, hash2),
+		fmt.Sprintf(`{"event":"newBlock","blockHash":"%s","parentBlockHash":"%s"}`, hash2b, hash1),
+		fmt.Sprintf(`{"event":"bestBlockChanged","bestBlockHash":"%s"}`, hash3),
+	}
+	for _, expected := range expectedEvents {
+		assert.Equal(t, expected, readFollowEvent(t, ws))
+	}
+
+	listener.finalisedChan <- &types.FinalisationInfo{Header: *header2}
+	assert.Equal(t,
+		fmt.Sprintf(`{"event":"finalized","finalizedBlockHashes":["%s"],"prunedBlockHashes":["%s"]}`, hash2, hash2b),
+		readFollowEvent(t, ws))
+
+	listener.importedChan <- &types.Block{Header: *header4}
+	assert.Equal(t,
+		fmt.Sprintf(`{"event":"newBlock","blockHash":"%s","parentBlockHash":"%s"}`, hash4, hash3),
+		readFollowEvent(t, ws))
+	assert.Equal(t,
+		fmt.Sprintf(`{"event":"bestBlockChanged","bestBlockHash":"%s"}`, hash4),
+		readFollowEvent(t, ws))
+
+	// all the reported blocks are pinned in the block state, and the pruned
+	// blocks stay pinned until they are unpinned
+	for _, hash := range []common.Hash{hash1, hash2, hash2b, hash3, hash4} {
+		blockAPI.AssertCalled(t, "PinBlock", hash)
+	}
+	blockAPI.AssertNotCalled(t, "UnpinBlock", hash2b)
+
+	_, _, err := listener.unpin([]interface{}{[]interface{}{hash1.String(), hash2b.String()}})
+	require.NoError(t, err)
+	blockAPI.AssertCalled(t, "UnpinBlock", hash1)
+	blockAPI.AssertCalled(t, "UnpinBlock", hash2b)
+	_, _, err = listener.unpin([]interface{}{hash2b.String()})
+	assert.ErrorIs(t, err, errBlockNotPinned)
+	blockAPI.AssertNumberOfCalls(t, "UnpinBlock", 2)
+
+	// the blocks still pinned are unpinned once the subscription stops
+	require.NoError(t, listener.Stop())
+	blockAPI.AssertCalled(t, "FreeImportedBlockNotifierChannel", mock.AnythingOfType("chan *types.Block"))
+	for _, hash := range []common.Hash{hash2, hash3, hash4} {
+		blockAPI.AssertCalled(t, "UnpinBlock", hash)
+	}
+	blockAPI.AssertNumberOfCalls(t, "UnpinBlock", 5)
+}
+
+func TestChainHeadFollowListener_ListenFallenBehind(t *testing.T) {
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+
+	header1 := newChainHeadTestHeader(common.Hash{}, 1, 1)
+	hash1 := header1.Hash()
+
+	blockAPI := new(mocks.BlockAPI)
+	blockAPI.On("GetHighestFinalisedHash").Return(hash1, nil)
+	blockAPI.On("GetHeader", hash1).Return(header1, nil)
+	blockAPI.On("Leaves").Return([]common.Hash{hash1})
+	blockAPI.On("BestBlockHash").Return(hash1)
+	blockAPI.On("PinBlock", mock.AnythingOfType("common.Hash")).Return(nil)
+	blockAPI.On("UnpinBlock", mock.AnythingOfType("common.Hash"))
+	blockAPI.On("FreeImportedBlockNotifierChannel", mock.AnythingOfType("chan *types.Block"))
+	blockAPI.On("FreeFinalisedNotifierChannel", mock.AnythingOfType("chan *types.FinalisationInfo"))
+
+	// each imported block has an unknown parent, which is fetched with
+	// its ancestors until the pinned blocks limit is reached.
+	parent := hash1
+	var last *types.Header
+	for number := uint(2); number <= maxChainHeadPinnedBlocks+2; number++ {
+		header := newChainHeadTestHeader(parent, number, 0)
+		blockAPI.On("GetHeader", header.Hash()).Return(header, nil)
+		parent = header.Hash()
+		last = header
+	}
+	wsconn.BlockAPI = blockAPI
+
+	listener := newChainHeadFollowListener(wsconn, false)
+	listener.subID = 1
+	listener.importedChan = make(chan *types.Block)
+	listener.finalisedChan = make(chan *types.FinalisationInfo)
+
+	listener.Listen()
+
+	assert.Equal(t, fmt.Sprintf(`{"event":"initialized","finalizedBlockHashes":["%s"]}`, hash1),
+		readFollowEvent(t, ws))
+	assert.Equal(t, fmt.Sprintf(`{"event":"bestBlockChanged","bestBlockHash":"%s"}`, hash1),
+		readFollowEvent(t, ws))
+
+	listener.importedChan <- &types.Block{Header: *last}
+	assert.Equal(t, `{"event":"stop"}`, readFollowEvent(t, ws))
+
+	<-listener.done
+	_, err := listener.startOperation(hash1)
+	assert.ErrorIs(t, err, errFollowSubscriptionStopped)
+}
+
+func TestChainHeadFollowListener_methods(t *testing.T) {
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+
+	header := newChainHeadTestHeader(common.Hash{}, 1, 1)
+	hash := header.Hash()
+	unpinnedHash := common.Hash{9}
+	stateRoot := common.Hash{1}
+
+	blockAPI := new(mocks.BlockAPI)
+	blockAPI.On("GetHeader", hash).Return(header, nil)
+	blockAPI.On("GetBlockByHash", hash).Return(&types.Block{
+		Header: *header,
+		Body:   types.Body{{1, 2}, {3}},
+	}, nil)
+	wsconn.BlockAPI = blockAPI
+
+	storageAPI := new(mocks.StorageAPI)
+	storageAPI.On("GetStateRootFromBlock", &hash).Return(&stateRoot, nil)
+	storageAPI.On("GetStorage", &stateRoot, []byte{1}).Return([]byte{0xaa}, nil)
+	storageAPI.On("GetStorage", &stateRoot, []byte{2}).Return(nil, nil)
+	storageAPI.On("GetKeysWithPrefix", &stateRoot, []byte{3}).Return([][]byte{{3, 1}, {3, 2}}, nil)
+	storageAPI.On("GetStorage", &stateRoot, []byte{3, 1}).Return([]byte{0xbb}, nil)
+	storageAPI.On("GetStorage", &stateRoot, []byte{3, 2}).Return([]byte{0xcc}, nil)
+	wsconn.StorageAPI = storageAPI
+
+	listener := newChainHeadFollowListener(wsconn, false)
+	listener.subID = 1
+	listener.pinned[hash] = struct{}{}
+
+	// header
+	result, _, err := listener.header([]interface{}{hash.String()})
+	require.NoError(t, err)
+	encodedHeader, err := scale.Marshal(*header)
+	require.NoError(t, err)
+	assert.Equal(t, common.BytesToHex(encodedHeader), result)
+
+	_, _, err = listener.header([]interface{}{unpinnedHash.String()})
+	assert.ErrorIs(t, err, errBlockNotPinned)
+
+	// body
+	result, operation, err := listener.body([]interface{}{hash.String()})
+	require.NoError(t, err)
+	assert.Equal(t, chainHeadOperationStarted{Result: "started", OperationID: "0"}, result)
+	operation()
+	assert.Equal(t, `{"event":"operationBodyDone","operationId":"0","value":["0x0102","0x03"]}`,
+		readFollowEvent(t, ws))
+
+	// storage
+	params := []interface{}{
+		hash.String(),
+		[]interface{}{
+			map[string]interface{}{"key": "0x01", "type": "value"},
+			map[string]interface{}{"key": "0x02", "type": "value"},
+			map[string]interface{}{"key": "0x01", "type": "hash"},
+			map[string]interface{}{"key": "0x03", "type": "descendantsValues"},
+		},
+	}
+	_, operation, err = listener.storage(params)
+	require.NoError(t, err)
+	operation()
+	valueHash, err := common.Blake2bHash([]byte{0xaa})
+	require.NoError(t, err)
+	assert.Equal(t, `{"event":"operationStorageItems","operationId":"1","items":[`+
+		`{"key":"0x01","value":"0xaa"},`+
+		fmt.Sprintf(`{"key":"0x01","hash":"%s"},`, valueHash)+
+		`{"key":"0x0301","value":"0xbb"},`+
+		`{"key":"0x0302","value":"0xcc"}]}`,
+		readFollowEvent(t, ws))
+	assert.Equal(t, `{"event":"operationStorageDone","operationId":"1"}`, readFollowEvent(t, ws))
+
+	_, _, err = listener.storage([]interface{}{
+		hash.String(),
+		[]interface{}{map[string]interface{}{"key": "0x01", "type": "closestDescendantMerkleValue"}},
+	})
+	assert.ErrorIs(t, err, errUnsupportedStorageQuery)
+
+	// operations limit
+	for i := 0; i < maxChainHeadOperations; i++ {
+		_, err = listener.startOperation(hash)
+		require.NoError(t, err)
+	}
+	result, operation, err = listener.body([]interface{}{hash.String()})
+	require.NoError(t, err)
+	assert.Nil(t, operation)
+	assert.Equal(t, chainHeadOperationStarted{Result: "limitReached"}, result)
+
+	// stopped operations do not send events
+	_, _, err = listener.stopOperation([]interface{}{"2"})
+	require.NoError(t, err)
+	listener.endOperation("2", chainHeadOperationEvent{Event: "operationStorageDone", OperationID: "2"})
+	assert.Len(t, listener.operations, maxChainHeadOperations-1)
+}
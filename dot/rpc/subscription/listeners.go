@@ -7,7 +7,6 @@
 	"errors"
 	"fmt"
 	"math/big"
-	"reflect"
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
@@ -290,19 +289,24 @@ func (l *AllBlocksListener) Stop() error {
 	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
 }
 
+// finalityTimeoutBlocks is the number of blocks imported on top of the block
+// including a watched extrinsic after which the watch ends with a finalityTimeout
+// status, if that block is still not finalised.
+const finalityTimeoutBlocks = 512
+
 // ExtrinsicSubmitListener to handle listening for extrinsic events
 type ExtrinsicSubmitListener struct {
-	wsconn        *WSConn
-	subID         uint32
-	extrinsic     types.Extrinsic
-	importedChan  chan *types.Block
-	importedHash  common.Hash
-	finalisedChan chan *types.FinalisationInfo
-	// txStatusChan is used to know when transaction/extrinsic becomes part of the
-	// ready queue or future queue.
-	// we are using transaction.PriorityQueue for ready queue and transaction.Pool
-	// for future queue.
-	txStatusChan  chan transaction.Status
+	wsconn         *WSConn
+	subID          uint32
+	extrinsic      types.Extrinsic
+	importedChan   chan *types.Block
+	importedHash   common.Hash
+	importedNumber uint
+	finalisedChan  chan *types.FinalisationInfo
+	// txStatusChan is used to receive the status updates of the extrinsic, from
+	// the transaction pool and queue as well as from the core service when the
+	// extrinsic is broadcast, included in a block or retracted.
+	txStatusChan  chan transaction.StatusNotification
 	done          chan struct{}
 	cancel        chan struct{}
 	cancelTimeout time.Duration
@@ -310,7 +314,7 @@ type ExtrinsicSubmitListener struct {
 
 // NewExtrinsicSubmitListener constructor to build new ExtrinsicSubmitListener
 func NewExtrinsicSubmitListener(conn *WSConn, extBytes []byte,
-	importedChan chan *types.Block, txStatusChan chan transaction.Status,
+	importedChan chan *types.Block, txStatusChan chan transaction.StatusNotification,
 	finalisedChan chan *types.FinalisationInfo) *ExtrinsicSubmitListener {
 	return &ExtrinsicSubmitListener{
 		wsconn:        conn,
@@ -324,9 +328,10 @@ func NewExtrinsicSubmitListener(conn *WSConn, extBytes []byte,
 	}
 }
 
-// Listen implementation of Listen interface to listen for importedChan changes
+// Listen implementation of Listen interface to listen for the extrinsic status updates.
+// The goroutine stops once the extrinsic reaches a final status, that is finalized,
+// finalityTimeout, usurped, dropped or invalid.
 func (l *ExtrinsicSubmitListener) Listen() {
-	// listen for imported blocks with extrinsic
 	go func() {
 		defer func() {
 			l.wsconn.BlockAPI.FreeImportedBlockNotifierChannel(l.importedChan)
@@ -346,43 +351,107 @@ func (l *ExtrinsicSubmitListener) Listen() {
 					return
 				}
 
-				if block == nil {
+				if block == nil || l.importedHash.IsEmpty() {
 					continue
 				}
-				bodyHasExtrinsic, err := block.Body.HasExtrinsic(l.extrinsic)
-				if err != nil {
-					fmt.Printf("error %v\n", err)
-				}
 
-				if bodyHasExtrinsic {
-					resM := make(map[string]interface{})
-					resM["inBlock"] = block.Header.Hash().String()
-
-					l.importedHash = block.Header.Hash()
-					l.wsconn.safeSend(newSubscriptionResponse(authorExtrinsicUpdatesMethod, l.subID, resM))
+				if block.Header.Number < l.importedNumber+finalityTimeoutBlocks {
+					continue
 				}
 
+				l.sendStatus(transaction.StatusNotification{
+					Status: transaction.FinalityTimeout,
+					Hash:   &l.importedHash,
+				})
+				return
 			case info, ok := <-l.finalisedChan:
 				if !ok {
 					return
 				}
 
-				if reflect.DeepEqual(l.importedHash, info.Header.Hash()) {
-					resM := make(map[string]interface{})
-					resM["finalised"] = info.Header.Hash().String()
-					l.wsconn.safeSend(newSubscriptionResponse(authorExtrinsicUpdatesMethod, l.subID, resM))
+				if info == nil || l.importedHash.IsEmpty() || info.Header.Number < l.importedNumber {
+					continue
+				}
+
+				// the block including the extrinsic is finalised if it is
+				// on the finalised chain, which is not necessarily the case
+				// when it is on a fork that is about to be retracted.
+				hash, err := l.wsconn.BlockAPI.GetHashByNumber(l.importedNumber)
+				if err != nil {
+					logger.Debugf("failed to get block hash for number %d: %s", l.importedNumber, err)
+					continue
+				}
+
+				if hash != l.importedHash {
+					continue
 				}
-			case txStatus, ok := <-l.txStatusChan:
+
+				l.sendStatus(transaction.StatusNotification{
+					Status: transaction.Finalized,
+					Hash:   &l.importedHash,
+				})
+				return
+			case notification, ok := <-l.txStatusChan:
 				if !ok {
 					return
 				}
 
-				l.wsconn.safeSend(newSubscriptionResponse(authorExtrinsicUpdatesMethod, l.subID, txStatus.String()))
+				l.sendStatus(notification)
+
+				switch notification.Status {
+				case transaction.InBlock:
+					l.setImportedBlock(*notification.Hash)
+				case transaction.Retracted:
+					if *notification.Hash == l.importedHash {
+						l.importedHash = common.Hash{}
+						l.importedNumber = 0
+					}
+				case transaction.Usurped, transaction.Dropped, transaction.Invalid:
+					return
+				}
 			}
 		}
 	}()
 }
 
+func (l *ExtrinsicSubmitListener) setImportedBlock(hash common.Hash) {
+	l.importedHash = hash
+	l.importedNumber = 0
+
+	header, err := l.wsconn.BlockAPI.GetHeader(hash)
+	if err != nil {
+		logger.Debugf("failed to get header for block %s: %s", hash, err)
+		return
+	}
+
+	if header != nil {
+		l.importedNumber = header.Number
+	}
+}
+
+func (l *ExtrinsicSubmitListener) sendStatus(notification transaction.StatusNotification) {
+	l.wsconn.safeSend(newSubscriptionResponse(authorExtrinsicUpdatesMethod, l.subID,
+		extrinsicStatusResult(notification)))
+}
+
+// extrinsicStatusResult returns the JSON representation of an extrinsic status, which
+// is either the status name or an object mapping the status name to its data.
+func extrinsicStatusResult(notification transaction.StatusNotification) interface{} {
+	switch notification.Status {
+	case transaction.Broadcast:
+		peers := notification.PeersBroadcastedTo
+		if peers == nil {
+			peers = []string{}
+		}
+		return map[string]interface{}{notification.Status.String(): peers}
+	case transaction.InBlock, transaction.Retracted, transaction.FinalityTimeout,
+		transaction.Finalized, transaction.Usurped:
+		return map[string]interface{}{notification.Status.String(): notification.Hash.String()}
+	default:
+		return notification.Status.String()
+	}
+}
+
 // Stop to cancel the running goroutines to this listener
 func (l *ExtrinsicSubmitListener) Stop() error {
 	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
@@ -181,11 +181,17 @@ func TestExtrinsicSubmitListener_Listen(t *testing.T) {
 
 	notifyImportedChan := make(chan *types.Block, 100)
 	notifyFinalizedChan := make(chan *types.FinalisationInfo, 100)
-	txStatusChan := make(chan transaction.Status)
+	txStatusChan := make(chan transaction.StatusNotification, 100)
+
+	header := types.NewEmptyHeader()
+	header.Number = 1
+	blockHash := header.Hash()
 
 	BlockAPI := new(mocks.BlockAPI)
 	BlockAPI.On("FreeImportedBlockNotifierChannel", mock.AnythingOfType("chan *types.Block"))
 	BlockAPI.On("FreeFinalisedNotifierChannel", mock.AnythingOfType("chan *types.FinalisationInfo"))
+	BlockAPI.On("GetHeader", blockHash).Return(header, nil)
+	BlockAPI.On("GetHashByNumber", uint(1)).Return(blockHash, nil)
 
 	wsconn.BlockAPI = BlockAPI
 
@@ -202,15 +208,6 @@ func TestExtrinsicSubmitListener_Listen(t *testing.T) {
 		done:          make(chan struct{}),
 		cancelTimeout: time.Second * 5,
 	}
-	header := types.NewEmptyHeader()
-	exts := []types.Extrinsic{{1, 2, 3}, {7, 8, 9, 0}, {0xa, 0xb}}
-
-	body := types.NewBody(exts)
-
-	block := &types.Block{
-		Header: *header,
-		Body:   *body,
-	}
 
 	esl.Listen()
 	defer func() {
@@ -221,29 +218,83 @@ func TestExtrinsicSubmitListener_Listen(t *testing.T) {
 		BlockAPI.AssertCalled(t, "FreeFinalisedNotifierChannel", mock.AnythingOfType("chan *types.FinalisationInfo"))
 	}()
 
-	notifyImportedChan <- block
-	time.Sleep(time.Second * 2)
+	readStatus := func(t *testing.T, expected interface{}) {
+		t.Helper()
+		_, msg, err := ws.ReadMessage()
+		require.NoError(t, err)
+		expectedBytes, err := json.Marshal(
+			newSubscriptionResponse(authorExtrinsicUpdatesMethod, esl.subID, expected))
+		require.NoError(t, err)
+		require.Equal(t, string(expectedBytes)+"\n", string(msg))
+	}
 
-	_, msg, err := ws.ReadMessage()
-	require.NoError(t, err)
-	resImported := map[string]interface{}{"inBlock": block.Header.Hash().String()}
-	expectedImportedBytes, err := json.Marshal(
-		newSubscriptionResponse(authorExtrinsicUpdatesMethod, esl.subID, resImported))
-	require.NoError(t, err)
-	require.Equal(t, string(expectedImportedBytes)+"\n", string(msg))
+	txStatusChan <- transaction.StatusNotification{Status: transaction.Ready}
+	readStatus(t, "ready")
+
+	txStatusChan <- transaction.StatusNotification{
+		Status:             transaction.Broadcast,
+		PeersBroadcastedTo: []string{"peer1", "peer2"},
+	}
+	readStatus(t, map[string]interface{}{"broadcast": []string{"peer1", "peer2"}})
+
+	txStatusChan <- transaction.StatusNotification{Status: transaction.InBlock, Hash: &blockHash}
+	readStatus(t, map[string]interface{}{"inBlock": blockHash.String()})
+
+	// a block imported before the finality timeout is ignored
+	notifyImportedChan <- &types.Block{Header: types.Header{Number: 2}}
 
 	notifyFinalizedChan <- &types.FinalisationInfo{
 		Header: *header,
 	}
-	time.Sleep(time.Second * 2)
+	readStatus(t, map[string]interface{}{"finalized": blockHash.String()})
 
-	_, msg, err = ws.ReadMessage()
+	select {
+	case <-esl.done:
+	case <-time.After(time.Second):
+		t.Fatal("listener should stop after the finalized status")
+	}
+}
+
+func TestExtrinsicSubmitListener_ListenFinalityTimeout(t *testing.T) {
+	wsconn, ws, cancel := setupWSConn(t)
+	defer cancel()
+
+	notifyImportedChan := make(chan *types.Block, 100)
+	notifyFinalizedChan := make(chan *types.FinalisationInfo, 100)
+	txStatusChan := make(chan transaction.StatusNotification, 100)
+
+	header := types.NewEmptyHeader()
+	header.Number = 1
+	blockHash := header.Hash()
+
+	BlockAPI := new(mocks.BlockAPI)
+	BlockAPI.On("FreeImportedBlockNotifierChannel", mock.AnythingOfType("chan *types.Block"))
+	BlockAPI.On("FreeFinalisedNotifierChannel", mock.AnythingOfType("chan *types.FinalisationInfo"))
+	BlockAPI.On("GetHeader", blockHash).Return(header, nil)
+	wsconn.BlockAPI = BlockAPI
+	wsconn.TxStateAPI = modules.NewMockTransactionStateAPI()
+
+	esl := NewExtrinsicSubmitListener(wsconn, types.Extrinsic{1, 2, 3},
+		notifyImportedChan, txStatusChan, notifyFinalizedChan)
+	esl.Listen()
+
+	txStatusChan <- transaction.StatusNotification{Status: transaction.InBlock, Hash: &blockHash}
+	_, _, err := ws.ReadMessage()
 	require.NoError(t, err)
-	resFinalised := map[string]interface{}{"finalised": block.Header.Hash().String()}
-	expectedFinalizedBytes, err := json.Marshal(
-		newSubscriptionResponse(authorExtrinsicUpdatesMethod, esl.subID, resFinalised))
+
+	notifyImportedChan <- &types.Block{Header: types.Header{Number: 1 + finalityTimeoutBlocks}}
+
+	_, msg, err := ws.ReadMessage()
 	require.NoError(t, err)
-	require.Equal(t, string(expectedFinalizedBytes)+"\n", string(msg))
+	expected := `{"jsonrpc":"2.0","method":"author_extrinsicUpdate","params":{"result":{"finalityTimeout":"%s"},"subscription":0}}` + "\n"
+	require.Equal(t, fmt.Sprintf(expected, blockHash), string(msg))
+
+	select {
+	case <-esl.done:
+	case <-time.After(time.Second):
+		t.Fatal("listener should stop after the finalityTimeout status")
+	}
+	require.NoError(t, esl.Stop())
 }
 
 func TestGrandpaJustification_Listen(t *testing.T) {
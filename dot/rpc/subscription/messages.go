@@ -16,12 +16,24 @@ type Params struct {
 	SubscriptionID uint32      `json:"subscription"`
 }
 
+// ParseErrorCode error code returned for messages which are not valid JSON
+const ParseErrorCode = -32700
+
+// ParseErrorMessage error message for messages which are not valid JSON
+const ParseErrorMessage = "Parse error"
+
 // InvalidRequestCode error code returned for invalid request parameters, value derived from Substrate node output
 const InvalidRequestCode = -32600
 
 // InvalidRequestMessage error message for invalid request parameters
 const InvalidRequestMessage = "Invalid request"
 
+// MethodNotFoundCode error code returned for methods which cannot be called
+const MethodNotFoundCode = -32601
+
+// LimitExceededMessage error message for calls exceeding the rate or subscription limits
+const LimitExceededMessage = "Limit exceeded"
+
 func newSubcriptionBaseResponseJSON() BaseResponseJSON {
 	return BaseResponseJSON{
 		Jsonrpc: "2.0",
@@ -55,6 +67,21 @@ func NewSubscriptionResponseJSON(subID uint32, reqID float64) ResponseJSON {
 	}
 }
 
+// ResultResponseJSON for responses to requests that are not subscriptions
+type ResultResponseJSON struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	Result  interface{} `json:"result"`
+	ID      float64     `json:"id"`
+}
+
+func newResultResponseJSON(result interface{}, reqID float64) ResultResponseJSON {
+	return ResultResponseJSON{
+		Jsonrpc: "2.0",
+		Result:  result,
+		ID:      reqID,
+	}
+}
+
 // BooleanResponse for responses that return boolean values
 type BooleanResponse struct {
 	JSONRPC string  `json:"jsonrpc"`
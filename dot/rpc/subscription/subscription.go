@@ -19,6 +19,7 @@
 	stateSubscribeStorage          string = "state_subscribeStorage"
 	stateSubscribeRuntimeVersion   string = "state_subscribeRuntimeVersion"
 	grandpaSubscribeJustifications string = "grandpa_subscribeJustifications"
+	chainHeadFollow                string = "chainHead_v1_follow"
 )
 
 type setupListener func(reqid float64, params interface{}) (Listener, error)
@@ -46,23 +47,25 @@ func (c *WSConn) getSetupListener(method string) setupListener {
 		return c.initRuntimeVersionListener
 	case grandpaSubscribeJustifications:
 		return c.initGrandpaJustificationListener
+	case chainHeadFollow:
+		return c.initChainHeadFollowListener
 	default:
 		return nil
 	}
 }
 
-func (c *WSConn) getUnsubListener(params interface{}) (Listener, error) {
+func (c *WSConn) getUnsubListener(params interface{}) (uint32, Listener, error) {
 	subscribeID, err := parseSubscribeID(params)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
 	listener, ok := c.Subscriptions[subscribeID]
 	if !ok {
-		return nil, fmt.Errorf("subscriber id %v: %w", subscribeID, errCannotFindListener)
+		return 0, nil, fmt.Errorf("subscriber id %v: %w", subscribeID, errCannotFindListener)
 	}
 
-	return listener, nil
+	return subscribeID, listener, nil
 }
 
 func parseSubscribeID(p interface{}) (uint32, error) {
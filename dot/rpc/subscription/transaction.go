@@ -0,0 +1,290 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+)
+
+// transaction_v1 methods, which are bound to the connection calling them
+const (
+	transactionBroadcast = "transaction_v1_broadcast"
+	transactionStop      = "transaction_v1_stop"
+)
+
+const (
+	// maxBroadcastOperations is the maximum number of transactions
+	// broadcast at the same time for a connection.
+	maxBroadcastOperations = 512
+	// rebroadcastInterval is the interval at which a transaction is broadcast
+	// again to the peers, until it is included in a block.
+	rebroadcastInterval = 6 * time.Second
+)
+
+var errInvalidOperationID = errors.New("invalid operation id")
+
+// transactionBroadcasts holds the broadcast operations of a connection.
+type transactionBroadcasts struct {
+	mutex           sync.Mutex
+	nextOperationID uint64
+	operations      map[string]*broadcastOperation
+}
+
+// broadcastOperation broadcasts a transaction until it is finalised, dropped
+// from the transaction pool or the operation is stopped.
+type broadcastOperation struct {
+	wsconn        *WSConn
+	id            string
+	extrinsic     types.Extrinsic
+	txStatusChan  chan transaction.StatusNotification
+	finalisedChan chan *types.FinalisationInfo
+	// inBlockHash is the hash of the block including the transaction, if any.
+	inBlockHash   common.Hash
+	done          chan struct{}
+	cancel        chan struct{}
+	stopOnce      sync.Once
+	cancelTimeout time.Duration
+}
+
+func isTransactionMethod(method string) bool {
+	return method == transactionBroadcast || method == transactionStop
+}
+
+func (c *WSConn) handleTransactionMethod(reqID float64, method string, params interface{}) {
+	if modules.IsUnsafe(method) && !c.UnsafeEnabled {
+		c.safeSendError(reqID, big.NewInt(MethodNotFoundCode),
+			fmt.Sprintf("unsafe rpc method %s cannot be reachable", method))
+		return
+	}
+
+	var (
+		result interface{}
+		err    error
+	)
+	switch method {
+	case transactionBroadcast:
+		result, err = c.broadcastTransaction(params)
+	case transactionStop:
+		err = c.stopTransactionBroadcast(params)
+	}
+
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidRequestCode), err.Error())
+		return
+	}
+
+	c.safeSend(newResultResponseJSON(result, reqID))
+}
+
+// broadcastTransaction submits the hex SCALE encoded transaction and broadcasts it to
+// the network. It returns the id of the broadcast operation, or nil if the maximum
+// number of broadcast operations of the connection is reached.
+// As for substrate, no error is returned if the transaction is invalid, in which
+// case the transaction is simply not broadcast.
+func (c *WSConn) broadcastTransaction(params interface{}) (operationID interface{}, err error) {
+	encodedExtrinsic, err := parseStringParam(params)
+	if err != nil {
+		return nil, err
+	}
+
+	extBytes, err := common.HexToBytes(encodedExtrinsic)
+	if err != nil {
+		return nil, err
+	}
+	ext := types.Extrinsic(extBytes)
+
+	if c.CoreAPI == nil || c.BlockAPI == nil || c.TxStateAPI == nil {
+		return nil, errors.New("error CoreAPI, BlockAPI or TxStateAPI not set")
+	}
+
+	c.broadcasts.mutex.Lock()
+	defer c.broadcasts.mutex.Unlock()
+
+	if c.broadcasts.operations == nil {
+		c.broadcasts.operations = make(map[string]*broadcastOperation)
+	}
+
+	if len(c.broadcasts.operations) >= maxBroadcastOperations {
+		return nil, nil
+	}
+
+	operation := &broadcastOperation{
+		wsconn:        c,
+		id:            strconv.FormatUint(c.broadcasts.nextOperationID, 10),
+		extrinsic:     ext,
+		txStatusChan:  c.TxStateAPI.GetStatusNotifierChannel(ext),
+		finalisedChan: c.BlockAPI.GetFinalisedNotifierChannel(),
+		done:          make(chan struct{}),
+		cancel:        make(chan struct{}),
+		cancelTimeout: defaultCancelTimeout,
+	}
+	c.broadcasts.nextOperationID++
+	c.broadcasts.operations[operation.id] = operation
+
+	err = c.CoreAPI.HandleSubmittedExtrinsic(ext)
+	if err != nil {
+		logger.Debugf("cannot broadcast transaction %s: %s", ext.Hash(), err)
+	}
+
+	go operation.run()
+
+	return operation.id, nil
+}
+
+// stopTransactionBroadcast stops the broadcast operation with the given id.
+// The transaction may still be included in a block if it was already broadcast.
+func (c *WSConn) stopTransactionBroadcast(params interface{}) error {
+	operationID, err := parseStringParam(params)
+	if err != nil {
+		return err
+	}
+
+	c.broadcasts.mutex.Lock()
+	operation, ok := c.broadcasts.operations[operationID]
+	c.broadcasts.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", errInvalidOperationID, operationID)
+	}
+
+	return operation.stop()
+}
+
+// stopTransactionBroadcasts stops all the broadcast operations of the connection.
+func (c *WSConn) stopTransactionBroadcasts() {
+	c.broadcasts.mutex.Lock()
+	operations := make([]*broadcastOperation, 0, len(c.broadcasts.operations))
+	for _, operation := range c.broadcasts.operations {
+		operations = append(operations, operation)
+	}
+	c.broadcasts.mutex.Unlock()
+
+	for _, operation := range operations {
+		err := operation.stop()
+		if err != nil {
+			logger.Debugf("failed to stop broadcast operation %s: %s", operation.id, err)
+		}
+	}
+}
+
+// run broadcasts the transaction again at each rebroadcast interval until it is
+// included in a block, and ends the operation once the transaction is finalised
+// or leaves the transaction pool without being included in a block.
+// The operation is removed from the operations of the connection once it ends.
+func (o *broadcastOperation) run() {
+	defer func() {
+		o.wsconn.TxStateAPI.FreeStatusNotifierChannel(o.txStatusChan)
+		o.wsconn.BlockAPI.FreeFinalisedNotifierChannel(o.finalisedChan)
+
+		o.wsconn.broadcasts.mutex.Lock()
+		delete(o.wsconn.broadcasts.operations, o.id)
+		o.wsconn.broadcasts.mutex.Unlock()
+
+		close(o.done)
+	}()
+
+	ticker := time.NewTicker(rebroadcastInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.cancel:
+			return
+		case <-ticker.C:
+			if !o.inBlockHash.IsEmpty() {
+				continue
+			}
+
+			inPool := o.wsconn.CoreAPI.BroadcastExtrinsic(o.extrinsic)
+			if !inPool {
+				// the transaction was invalid or left the pool
+				// before the status channel was registered.
+				return
+			}
+		case notification, ok := <-o.txStatusChan:
+			if !ok {
+				return
+			}
+
+			switch notification.Status {
+			case transaction.InBlock:
+				o.inBlockHash = *notification.Hash
+			case transaction.Retracted:
+				if *notification.Hash == o.inBlockHash {
+					o.inBlockHash = common.Hash{}
+				}
+			case transaction.Usurped, transaction.Dropped, transaction.Invalid:
+				return
+			}
+		case info, ok := <-o.finalisedChan:
+			if !ok {
+				return
+			}
+
+			if info != nil && o.isFinalised(&info.Header) {
+				return
+			}
+		}
+	}
+}
+
+// isFinalised returns true if the block including the transaction
+// is on the finalised chain ending with the given finalised header.
+func (o *broadcastOperation) isFinalised(finalised *types.Header) bool {
+	if o.inBlockHash.IsEmpty() {
+		return false
+	}
+
+	header, err := o.wsconn.BlockAPI.GetHeader(o.inBlockHash)
+	if err != nil || header.Number > finalised.Number {
+		return false
+	}
+
+	hash, err := o.wsconn.BlockAPI.GetHashByNumber(header.Number)
+	if err != nil {
+		logger.Debugf("failed to get block hash for number %d: %s", header.Number, err)
+		return false
+	}
+
+	return hash == o.inBlockHash
+}
+
+func (o *broadcastOperation) stop() (err error) {
+	o.stopOnce.Do(func() {
+		err = cancelWithTimeout(o.cancel, o.done, o.cancelTimeout)
+	})
+	return err
+}
+
+// parseStringParam returns the single string parameter of a request.
+func parseStringParam(params interface{}) (string, error) {
+	switch args := params.(type) {
+	case []string:
+		if len(args) != 1 {
+			return "", fmt.Errorf("%w: expected 1 param, got: %d", errUnexpectedParamLen, len(args))
+		}
+		return args[0], nil
+	case []interface{}:
+		if len(args) != 1 {
+			return "", fmt.Errorf("%w: expected 1 param, got: %d", errUnexpectedParamLen, len(args))
+		}
+
+		arg, ok := args[0].(string)
+		if !ok {
+			return "", fmt.Errorf("%w: %T, expected type string", errUnexpectedType, args[0])
+		}
+		return arg, nil
+	default:
+		return "", fmt.Errorf("%w: %T, expected type []string or []interface{}", errUnexpectedType, params)
+	}
+}
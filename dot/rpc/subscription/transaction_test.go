@@ -0,0 +1,159 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type broadcastTestChannels struct {
+	status    chan transaction.StatusNotification
+	finalised chan *types.FinalisationInfo
+}
+
+func newBroadcastTestConn(t *testing.T, ext types.Extrinsic,
+	submitErr error) (*WSConn, broadcastTestChannels) {
+	t.Helper()
+
+	channels := broadcastTestChannels{
+		status:    make(chan transaction.StatusNotification),
+		finalised: make(chan *types.FinalisationInfo),
+	}
+
+	coreAPI := new(mocks.CoreAPI)
+	coreAPI.On("HandleSubmittedExtrinsic", ext).Return(submitErr)
+	coreAPI.On("BroadcastExtrinsic", ext).Return(true).Maybe()
+
+	txStateAPI := new(mocks.TransactionStateAPI)
+	txStateAPI.On("GetStatusNotifierChannel", ext).Return(channels.status)
+	txStateAPI.On("FreeStatusNotifierChannel", channels.status)
+
+	blockAPI := new(mocks.BlockAPI)
+	blockAPI.On("GetFinalisedNotifierChannel").Return(channels.finalised)
+	blockAPI.On("FreeFinalisedNotifierChannel", channels.finalised)
+
+	conn := &WSConn{
+		CoreAPI:       coreAPI,
+		TxStateAPI:    txStateAPI,
+		BlockAPI:      blockAPI,
+		UnsafeEnabled: true,
+	}
+	return conn, channels
+}
+
+func waitOperationsEnded(t *testing.T, conn *WSConn) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		conn.broadcasts.mutex.Lock()
+		defer conn.broadcasts.mutex.Unlock()
+		return len(conn.broadcasts.operations) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWSConn_broadcastTransaction(t *testing.T) {
+	t.Parallel()
+
+	ext := types.Extrinsic{1, 2, 3}
+
+	t.Run("broadcast and stop", func(t *testing.T) {
+		t.Parallel()
+
+		conn, _ := newBroadcastTestConn(t, ext, nil)
+
+		operationID, err := conn.broadcastTransaction([]interface{}{common.BytesToHex(ext)})
+		require.NoError(t, err)
+		assert.Equal(t, "0", operationID)
+
+		err = conn.stopTransactionBroadcast([]interface{}{"0"})
+		require.NoError(t, err)
+		waitOperationsEnded(t, conn)
+
+		err = conn.stopTransactionBroadcast([]interface{}{"0"})
+		assert.ErrorIs(t, err, errInvalidOperationID)
+		assert.EqualError(t, err, "invalid operation id: 0")
+	})
+
+	t.Run("invalid transaction", func(t *testing.T) {
+		t.Parallel()
+
+		// no error is returned for invalid transactions
+		conn, _ := newBroadcastTestConn(t, ext, errors.New("invalid transaction"))
+
+		operationID, err := conn.broadcastTransaction([]interface{}{common.BytesToHex(ext)})
+		require.NoError(t, err)
+		assert.Equal(t, "0", operationID)
+
+		conn.stopTransactionBroadcasts()
+		waitOperationsEnded(t, conn)
+	})
+
+	t.Run("invalid hex", func(t *testing.T) {
+		t.Parallel()
+
+		conn, _ := newBroadcastTestConn(t, ext, nil)
+
+		_, err := conn.broadcastTransaction([]interface{}{"0xzz"})
+		assert.Error(t, err)
+	})
+
+	t.Run("dropped transaction", func(t *testing.T) {
+		t.Parallel()
+
+		conn, channels := newBroadcastTestConn(t, ext, nil)
+
+		_, err := conn.broadcastTransaction([]interface{}{common.BytesToHex(ext)})
+		require.NoError(t, err)
+
+		channels.status <- transaction.StatusNotification{Status: transaction.Dropped}
+		waitOperationsEnded(t, conn)
+	})
+
+	t.Run("finalised transaction", func(t *testing.T) {
+		t.Parallel()
+
+		conn, channels := newBroadcastTestConn(t, ext, nil)
+
+		header := &types.Header{Number: 2, Digest: types.NewDigest()}
+		hash := header.Hash()
+		blockAPI := conn.BlockAPI.(*mocks.BlockAPI)
+		blockAPI.On("GetHeader", hash).Return(header, nil)
+		blockAPI.On("GetHashByNumber", uint(2)).Return(hash, nil)
+
+		_, err := conn.broadcastTransaction([]interface{}{common.BytesToHex(ext)})
+		require.NoError(t, err)
+
+		channels.status <- transaction.StatusNotification{Status: transaction.InBlock, Hash: &hash}
+		channels.finalised <- &types.FinalisationInfo{Header: types.Header{Number: 1}}
+		channels.finalised <- &types.FinalisationInfo{Header: types.Header{Number: 3}}
+		waitOperationsEnded(t, conn)
+
+		blockAPI.AssertCalled(t, "FreeFinalisedNotifierChannel", mock.Anything)
+	})
+
+	t.Run("operations limit", func(t *testing.T) {
+		t.Parallel()
+
+		conn, _ := newBroadcastTestConn(t, ext, nil)
+		conn.broadcasts.operations = make(map[string]*broadcastOperation)
+		for i := 0; i < maxBroadcastOperations; i++ {
+			conn.broadcasts.operations[strconv.Itoa(i)] = nil
+		}
+
+		operationID, err := conn.broadcastTransaction([]interface{}{common.BytesToHex(ext)})
+		require.NoError(t, err)
+		assert.Nil(t, operationID)
+	})
+}
@@ -15,11 +15,12 @@
 	"sync"
 	"sync/atomic"
 
+	"github.com/ChainSafe/gossamer/dot/rpc/access"
+	"github.com/ChainSafe/gossamer/dot/rpc/json2"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
-	"github.com/gorilla/websocket"
 )
 
 type websocketMessage struct {
@@ -36,15 +37,25 @@ type httpclient interface {
 	errUnexpectedType          = errors.New("unexpected type")
 	errUnexpectedParamLen      = errors.New("unexpected params length")
 	errCannotReadFromWebsocket = errors.New("cannot read message from websocket")
+	errMalformedMessage        = errors.New("malformed message")
 	errEmptyMethod             = errors.New("empty method")
 )
 
 var logger = log.NewFromGlobal(log.AddContext("pkg", "rpc/subscription"))
 
+// Conn is a connection exchanging JSON-RPC messages, such as a websocket connection.
+// ReadMessage returns a *json.SyntaxError if the connection cannot be read from
+// anymore because the message read is not valid JSON.
+type Conn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteJSON(v interface{}) error
+	Close() error
+}
+
 // WSConn struct to hold WebSocket Connection references
 type WSConn struct {
 	UnsafeEnabled bool
-	Wsconn        *websocket.Conn
+	Wsconn        Conn
 	mu            sync.Mutex
 	qtyListeners  uint32
 	Subscriptions map[uint32]Listener
@@ -54,15 +65,37 @@ type WSConn struct {
 	TxStateAPI    modules.TransactionStateAPI
 	RPCHost       string
 	HTTP          httpclient
+	// InternalToken is sent with the requests forwarded to the HTTP-RPC server,
+	// so they are not rate limited again by the HTTP-RPC server.
+	InternalToken string
+	// MethodFilter decides which methods can be called, all of them if nil.
+	MethodFilter *access.MethodFilter
+	// RateLimiter limits the rate of the requests of the connection, no limit if nil.
+	RateLimiter *access.Limiter
+	// MaxSubscriptions is the maximum number of active subscriptions, no limit if zero.
+	MaxSubscriptions uint32
+	// stopped holds the ids of the subscriptions stopped by an unsubscribe call.
+	stopped map[uint32]struct{}
+	// broadcasts holds the transaction_v1 broadcast operations of the connection.
+	broadcasts transactionBroadcasts
 }
 
-// readWebsocketMessage will read and parse the message data to a string->interface{} data
+// readWebsocketMessage will read and parse the message data to a string->interface{} data.
+// The message is not parsed if it is a batch of requests, in which case wsMessage is nil.
 func (c *WSConn) readWebsocketMessage() (rawBytes []byte, wsMessage *websocketMessage, err error) {
 	_, rawBytes, err = c.Wsconn.ReadMessage()
 	if err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, nil, fmt.Errorf("%w: %s", errMalformedMessage, err)
+		}
 		return nil, nil, fmt.Errorf("%w: %s", errCannotReadFromWebsocket, err.Error())
 	}
 
+	if json2.IsBatch(rawBytes) {
+		return rawBytes, nil, nil
+	}
+
 	wsMessage = new(websocketMessage)
 	err = json.Unmarshal(rawBytes, wsMessage)
 	if err != nil {
@@ -77,7 +110,10 @@ func (c *WSConn) readWebsocketMessage() (rawBytes []byte, wsMessage *websocketMe
 }
 
 // HandleConn handles messages received on websocket connections
+// until the connection is closed.
 func (c *WSConn) HandleConn() {
+	defer c.stopTransactionBroadcasts()
+
 	for {
 		rawBytes, wsMessage, err := c.readWebsocketMessage()
 		if err != nil {
@@ -86,13 +122,58 @@ func (c *WSConn) HandleConn() {
 				return
 			}
 
+			if errors.Is(err, errMalformedMessage) {
+				// the stream of messages cannot be read past the malformed message,
+				// so the connection is closed once the client is told why.
+				c.safeSendError(0, big.NewInt(ParseErrorCode), ParseErrorMessage)
+				return
+			}
+
 			c.safeSendError(0, big.NewInt(InvalidRequestCode), InvalidRequestMessage)
 			continue
 		}
 
 		logger.Tracef("websocket message received: %s", string(rawBytes))
+
+		// each request of a batch counts as a request.
+		if !c.RateLimiter.AllowN(json2.RequestsCount(rawBytes)) {
+			access.RecordRejected(access.TransportWS, access.ReasonRateLimited)
+			var reqID float64
+			if wsMessage != nil {
+				reqID = wsMessage.ID
+			}
+			c.safeSendError(reqID, big.NewInt(access.LimitExceededCode), LimitExceededMessage)
+			continue
+		}
+
+		if wsMessage == nil {
+			// the requests of a batch are executed concurrently by the rpc server,
+			// which responds with the array of their responses.
+			c.executeRPCCall(rawBytes)
+			continue
+		}
+
 		logger.Debugf("ws method %s called with params %v", wsMessage.Method, wsMessage.Params)
 
+		if !c.MethodFilter.Allowed(wsMessage.Method) {
+			access.RecordRejected(access.TransportWS, access.ReasonMethodDenied)
+			c.safeSendError(wsMessage.ID, big.NewInt(MethodNotFoundCode),
+				fmt.Sprintf("rpc method %s is not allowed", wsMessage.Method))
+			continue
+		}
+
+		if handler := c.getChainHeadMethodHandler(wsMessage.Method); handler != nil {
+			// the chainHead_v1 methods are bound to a follow subscription of this connection.
+			c.handleChainHeadMethod(wsMessage.ID, wsMessage.Params, handler)
+			continue
+		}
+
+		if isTransactionMethod(wsMessage.Method) {
+			// the transaction_v1 broadcast operations are bound to this connection.
+			c.handleTransactionMethod(wsMessage.ID, wsMessage.Method, wsMessage.Params)
+			continue
+		}
+
 		if !strings.Contains(wsMessage.Method, "_unsubscribe") && !strings.Contains(wsMessage.Method, "_unwatch") {
 			setupListener := c.getSetupListener(wsMessage.Method)
 
@@ -101,6 +182,12 @@ func (c *WSConn) HandleConn() {
 				continue
 			}
 
+			if c.subscriptionQuotaReached() {
+				access.RecordRejected(access.TransportWS, access.ReasonSubscriptionQuota)
+				c.safeSendError(wsMessage.ID, big.NewInt(access.LimitExceededCode), LimitExceededMessage)
+				continue
+			}
+
 			listener, err := setupListener(wsMessage.ID, wsMessage.Params)
 			if err != nil {
 				logger.Warnf("failed to create listener (method=%s): %s", wsMessage.Method, err)
@@ -111,7 +198,7 @@ func (c *WSConn) HandleConn() {
 			continue
 		}
 
-		listener, err := c.getUnsubListener(wsMessage.Params)
+		subscribeID, listener, err := c.getUnsubListener(wsMessage.Params)
 		if err != nil {
 			logger.Warnf("failed to get unsubscriber (method=%s): %s", wsMessage.Method, err)
 
@@ -130,6 +217,8 @@ func (c *WSConn) HandleConn() {
 		if err != nil {
 			logger.Warnf("failed to stop listener goroutine (method=%s): %s", wsMessage.Method, err)
 			c.safeSend(newBooleanResponseJSON(false, wsMessage.ID))
+		} else {
+			c.markStopped(subscribeID)
 		}
 
 		c.safeSend(newBooleanResponseJSON(true, wsMessage.ID))
@@ -137,6 +226,36 @@ func (c *WSConn) HandleConn() {
 	}
 }
 
+// subscriptionQuotaReached returns true if the connection cannot have more active subscriptions.
+func (c *WSConn) subscriptionQuotaReached() bool {
+	if c.MaxSubscriptions == 0 {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var active uint32
+	for id := range c.Subscriptions {
+		if _, stopped := c.stopped[id]; !stopped {
+			active++
+		}
+	}
+
+	return active >= c.MaxSubscriptions
+}
+
+// markStopped records that the subscription with the given id is no longer active.
+func (c *WSConn) markStopped(subscribeID uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped == nil {
+		c.stopped = make(map[uint32]struct{})
+	}
+	c.stopped[subscribeID] = struct{}{}
+}
+
 func (c *WSConn) executeRPCCall(data []byte) {
 	request, err := c.prepareRequest(data)
 	if err != nil {
@@ -343,7 +462,6 @@ func (c *WSConn) initExtrinsicWatch(reqID float64, params interface{}) (Listener
 
 	c.safeSend(NewSubscriptionResponseJSON(extSubmitListener.subID, reqID))
 
-	// todo (ed) determine which peer extrinsic has been broadcast to, and set status (#1535)
 	return extSubmitListener, err
 }
 
@@ -445,6 +563,9 @@ func (c *WSConn) prepareRequest(b []byte) (*http.Request, error) {
 	}
 
 	req.Header.Set("Content-Type", "application/json;")
+	if c.InternalToken != "" {
+		req.Header.Set(access.InternalRequestHeader, c.InternalToken)
+	}
 	return req, nil
 }
 
@@ -5,9 +5,13 @@
 
 import (
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/ChainSafe/gossamer/dot/rpc/access"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 
@@ -422,3 +426,143 @@ func TestWSConn_CheckWebsocketInvalidData(t *testing.T) {
 		require.Equal(t, tt.expected, msg)
 	}
 }
+
+func TestWSConn_HandleConnBatch(t *testing.T) {
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	defer cancel()
+
+	const batch = `[{"jsonrpc":"2.0","method":"system_name","params":[],"id":1},` +
+		`{"jsonrpc":"2.0","method":"system_version","params":[],"id":2}]`
+
+	rpcServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, batch, string(body))
+		require.Equal(t, "token", r.Header.Get(access.InternalRequestHeader))
+
+		_, err = w.Write([]byte(`[{"jsonrpc":"2.0","result":"gossamer","id":1},` +
+			`{"jsonrpc":"2.0","result":"0.0.0","id":2}]`))
+		require.NoError(t, err)
+	}))
+	defer rpcServer.Close()
+
+	wsconn.RPCHost = rpcServer.URL
+	wsconn.HTTP = rpcServer.Client()
+	wsconn.InternalToken = "token"
+
+	go wsconn.HandleConn()
+
+	err := c.WriteMessage(websocket.TextMessage, []byte(batch))
+	require.NoError(t, err)
+
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, `[{"id":1,"jsonrpc":"2.0","result":"gossamer"},`+
+		`{"id":2,"jsonrpc":"2.0","result":"0.0.0"}]`+"\n", string(msg))
+}
+
+func TestWSConn_HandleConnAccessRules(t *testing.T) {
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	wsconn.StorageAPI = modules.NewMockStorageAPI()
+	wsconn.MethodFilter = access.NewMethodFilter(nil, []string{"author_insertKey"})
+	wsconn.MaxSubscriptions = 1
+	defer cancel()
+
+	go wsconn.HandleConn()
+
+	tests := []struct {
+		sentMessage string
+		expected    string
+	}{
+		{
+			sentMessage: `{"jsonrpc":"2.0","method":"author_insertKey","params":[],"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32601,` +
+				`"message":"rpc method author_insertKey is not allowed"},"id":1}`,
+		},
+		{
+			sentMessage: `{"jsonrpc":"2.0","method":"state_subscribeStorage","params":[],"id":2}`,
+			expected:    `{"jsonrpc":"2.0","result":1,"id":2}`,
+		},
+		{
+			sentMessage: `{"jsonrpc":"2.0","method":"state_subscribeStorage","params":[],"id":3}`,
+			expected:    `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Limit exceeded"},"id":3}`,
+		},
+		{
+			sentMessage: `{"jsonrpc":"2.0","method":"state_unsubscribeStorage","params":["1"],"id":4}`,
+			expected:    `{"jsonrpc":"2.0","result":true,"id":4}`,
+		},
+		{
+			sentMessage: `{"jsonrpc":"2.0","method":"state_subscribeStorage","params":[],"id":5}`,
+			expected:    `{"jsonrpc":"2.0","result":2,"id":5}`,
+		},
+	}
+
+	for _, tt := range tests {
+		err := c.WriteMessage(websocket.TextMessage, []byte(tt.sentMessage))
+		require.NoError(t, err)
+
+		_, msg, err := c.ReadMessage()
+		require.NoError(t, err)
+		require.Equal(t, tt.expected+"\n", string(msg))
+	}
+}
+
+func TestWSConn_HandleConnRateLimit(t *testing.T) {
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	wsconn.MethodFilter = access.NewMethodFilter(nil, []string{"author_insertKey"})
+	wsconn.RateLimiter = access.NewLimiter(1)
+	defer cancel()
+
+	go wsconn.HandleConn()
+
+	const message = `{"jsonrpc":"2.0","method":"author_insertKey","params":[],"id":1}`
+
+	err := c.WriteMessage(websocket.TextMessage, []byte(message))
+	require.NoError(t, err)
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32601,`+
+		`"message":"rpc method author_insertKey is not allowed"},"id":1}`+"\n", string(msg))
+
+	err = c.WriteMessage(websocket.TextMessage, []byte(message))
+	require.NoError(t, err)
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Limit exceeded"},"id":1}`+"\n", string(msg))
+}
+
+func TestWSConn_HandleConnRateLimitBatch(t *testing.T) {
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	wsconn.RateLimiter = access.NewLimiter(2)
+	defer cancel()
+
+	rpcServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`[{"jsonrpc":"2.0","result":{},"id":1}]`))
+		require.NoError(t, err)
+	}))
+	defer rpcServer.Close()
+
+	wsconn.RPCHost = rpcServer.URL
+	wsconn.HTTP = rpcServer.Client()
+
+	go wsconn.HandleConn()
+
+	// a batch larger than the rate limit is charged the rate limit,
+	// so it is allowed and consumes all the tokens.
+	const request = `{"jsonrpc":"2.0","method":"system_health","params":[],"id":1}`
+	err := c.WriteMessage(websocket.TextMessage, []byte("["+request+","+request+","+request+"]"))
+	require.NoError(t, err)
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, `[{"id":1,"jsonrpc":"2.0","result":{}}]`+"\n", string(msg))
+
+	err = c.WriteMessage(websocket.TextMessage, []byte("["+request+"]"))
+	require.NoError(t, err)
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Limit exceeded"},"id":0}`+"\n", string(msg))
+}
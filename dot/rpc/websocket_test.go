@@ -62,7 +62,7 @@ func TestHTTPServer_ServeHTTP(t *testing.T) {
 	si := &types.SystemInfo{
 		SystemName: "gossamer",
 	}
-	sysAPI := system.NewService(si, nil)
+	sysAPI := system.NewService(si, nil, nil)
 	bAPI := modules.NewMockBlockAPI()
 	sAPI := modules.NewMockStorageAPI()
 
@@ -6,6 +6,9 @@
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ChainSafe/chaindb"
@@ -30,8 +33,7 @@
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
-	"github.com/ChainSafe/gossamer/lib/runtime/life"
-	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/runtime/executor"
 	"github.com/ChainSafe/gossamer/lib/utils"
 )
 
@@ -42,6 +44,7 @@ type rpcServiceSettings struct {
 	core          *core.Service
 	network       *network.Service
 	blockProducer modules.BlockProducerAPI
+	babeKeystore  keystore.Keystore
 	system        *system.Service
 	blockFinality *grandpa.Service
 	syncer        *sync.Service
@@ -55,15 +58,21 @@ func newInMemoryDB() (chaindb.Database, error) {
 func (nodeBuilder) createStateService(cfg *Config) (*state.Service, error) {
 	logger.Debug("creating state service...")
 
+	newInstance, err := newRuntimeInstanceFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	config := state.Config{
-		Path:     cfg.Global.BasePath,
-		LogLevel: cfg.Log.StateLvl,
-		Metrics:  metrics.NewIntervalConfig(cfg.Global.PublishMetrics),
+		Path:               cfg.Global.BasePath,
+		LogLevel:           cfg.Log.StateLvl,
+		Metrics:            metrics.NewIntervalConfig(cfg.Global.PublishMetrics),
+		NewRuntimeInstance: newInstance,
 	}
 
 	stateSrvc := state.NewService(config)
 
-	err := stateSrvc.SetupBase()
+	err = stateSrvc.SetupBase()
 	if err != nil {
 		return nil, fmt.Errorf("cannot setup base: %w", err)
 	}
@@ -103,6 +112,15 @@ func (nodeBuilder) createRuntimeStorage(st *state.Service) (*runtime.NodeStorage
 	}, nil
 }
 
+// newRuntimeInstanceFunc returns the function instantiating the runtimes with the configured wasm interpreter
+func newRuntimeInstanceFunc(cfg *Config) (runtime.NewInstanceFunc, error) {
+	newInstance, ok := executor.Lookup(cfg.Core.WasmInterpreter)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrWasmInterpreterName, cfg.Core.WasmInterpreter)
+	}
+	return newInstance, nil
+}
+
 func createRuntime(cfg *Config, ns runtime.NodeStorage, st *state.Service,
 	ks *keystore.GlobalKeystore, net *network.Service, code []byte) (
 	runtime.Instance, error) {
@@ -132,44 +150,29 @@ func createRuntime(cfg *Config, ns runtime.NodeStorage, st *state.Service,
 		return nil, err
 	}
 
-	var rt runtime.Instance
-	switch cfg.Core.WasmInterpreter {
-	case wasmer.Name:
-		rtCfg := &wasmer.Config{
-			Imports: wasmer.ImportsNodeRuntime,
-		}
-		rtCfg.Storage = ts
-		rtCfg.Keystore = ks
-		rtCfg.LogLvl = cfg.Log.RuntimeLvl
-		rtCfg.NodeStorage = ns
-		rtCfg.Network = net
-		rtCfg.Role = cfg.Core.Roles
-		rtCfg.CodeHash = codeHash
-
-		// create runtime executor
-		rt, err = wasmer.NewInstance(code, rtCfg)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create runtime executor: %s", err)
-		}
-	case life.Name:
-		rtCfg := &life.Config{
-			Resolver: new(life.Resolver),
-		}
-		rtCfg.Storage = ts
-		rtCfg.Keystore = ks
-		rtCfg.LogLvl = cfg.Log.RuntimeLvl
-		rtCfg.NodeStorage = ns
-		rtCfg.Network = net
-		rtCfg.Role = cfg.Core.Roles
-		rtCfg.CodeHash = codeHash
-
-		// create runtime executor
-		rt, err = life.NewInstance(code, rtCfg)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create runtime executor: %s", err)
-		}
-	default:
-		return nil, fmt.Errorf("%w: %s", ErrWasmInterpreterName, cfg.Core.WasmInterpreter)
+	// the outbound HTTP requests can only be disabled for authority nodes
+	offchainHTTPDisabled := cfg.Core.OffchainHTTPDisabled && cfg.Core.Roles == types.AuthorityRole
+
+	newInstance, err := newRuntimeInstanceFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	rtCfg := runtime.InstanceConfig{
+		Storage:              ts,
+		Keystore:             ks,
+		LogLvl:               cfg.Log.RuntimeLvl,
+		NodeStorage:          ns,
+		Network:              net,
+		Role:                 cfg.Core.Roles,
+		CodeHash:             codeHash,
+		OffchainHTTPDisabled: offchainHTTPDisabled,
+	}
+
+	// create runtime executor
+	rt, err := newInstance(code, rtCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime executor: %s", err)
 	}
 
 	st.Block.StoreRuntime(st.Block.BestBlockHash(), rt)
@@ -250,6 +253,11 @@ func (nodeBuilder) createCoreService(cfg *Config, ks *keystore.GlobalKeystore,
 		return nil, err
 	}
 
+	newInstance, err := newRuntimeInstanceFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	codeSubs := make(map[common.Hash]string)
 	for k, v := range genesisData.CodeSubstitutes {
 		codeSubs[common.MustHexToHash(k)] = v
@@ -257,15 +265,19 @@ func (nodeBuilder) createCoreService(cfg *Config, ks *keystore.GlobalKeystore,
 
 	// set core configuration
 	coreConfig := &core.Config{
-		LogLvl:               cfg.Log.CoreLvl,
-		BlockState:           st.Block,
-		EpochState:           st.Epoch,
-		StorageState:         st.Storage,
-		TransactionState:     st.Transaction,
-		Keystore:             ks,
-		Network:              net,
-		CodeSubstitutes:      codeSubs,
-		CodeSubstitutedState: st.Base,
+		LogLvl:                cfg.Log.CoreLvl,
+		BlockState:            st.Block,
+		EpochState:            st.Epoch,
+		StorageState:          st.Storage,
+		TransactionState:      st.Transaction,
+		Keystore:              ks,
+		Network:               net,
+		CodeSubstitutes:       codeSubs,
+		CodeSubstitutedState:  st.Base,
+		NewRuntimeInstance:    newInstance,
+		OffchainWorker:        cfg.Core.OffchainWorker,
+		OffchainWorkerTimeout: cfg.Core.OffchainWorkerTimeout,
+		RuntimePoolSize:       cfg.Core.RuntimePoolSize,
 	}
 
 	// create new core service
@@ -340,6 +352,20 @@ func (nodeBuilder) createRPCService(params rpcServiceSettings) (*rpc.HTTPServer,
 	)
 	rpcService := rpc.NewService()
 
+	ipcPath := params.config.RPC.IPCPath
+	if ipcPath != "" && !filepath.IsAbs(ipcPath) {
+		ipcPath = filepath.Join(params.config.Global.BasePath, ipcPath)
+	}
+
+	var ipcPermissions os.FileMode
+	if params.config.RPC.IPCPermissions != "" {
+		permissions, err := strconv.ParseUint(params.config.RPC.IPCPermissions, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IPC socket permissions %q: %w", params.config.RPC.IPCPermissions, err)
+		}
+		ipcPermissions = os.FileMode(permissions) & os.ModePerm
+	}
+
 	genesisData, err := params.state.Base.LoadGenesisData()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load genesis data: %s", err)
@@ -351,44 +377,54 @@ func (nodeBuilder) createRPCService(params rpcServiceSettings) (*rpc.HTTPServer,
 	}
 
 	rpcConfig := &rpc.HTTPServerConfig{
-		LogLvl:              params.config.Log.RPCLvl,
-		BlockAPI:            params.state.Block,
-		StorageAPI:          params.state.Storage,
-		NetworkAPI:          params.network,
-		CoreAPI:             params.core,
-		NodeStorage:         params.nodeStorage,
-		BlockProducerAPI:    params.blockProducer,
-		BlockFinalityAPI:    params.blockFinality,
-		TransactionQueueAPI: params.state.Transaction,
-		RPCAPI:              rpcService,
-		SyncStateAPI:        syncStateSrvc,
-		SyncAPI:             params.syncer,
-		SystemAPI:           params.system,
-		RPC:                 params.config.RPC.Enabled,
-		RPCExternal:         params.config.RPC.External,
-		RPCUnsafe:           params.config.RPC.Unsafe,
-		RPCUnsafeExternal:   params.config.RPC.UnsafeExternal,
-		Host:                params.config.RPC.Host,
-		RPCPort:             params.config.RPC.Port,
-		WS:                  params.config.RPC.WS,
-		WSExternal:          params.config.RPC.WSExternal,
-		WSUnsafe:            params.config.RPC.WSUnsafe,
-		WSUnsafeExternal:    params.config.RPC.WSUnsafeExternal,
-		WSPort:              params.config.RPC.WSPort,
-		Modules:             params.config.RPC.Modules,
+		LogLvl:                        params.config.Log.RPCLvl,
+		BlockAPI:                      params.state.Block,
+		StorageAPI:                    params.state.Storage,
+		NetworkAPI:                    params.network,
+		CoreAPI:                       params.core,
+		NodeStorage:                   params.nodeStorage,
+		BlockProducerAPI:              params.blockProducer,
+		BabeKeystore:                  params.babeKeystore,
+		BlockFinalityAPI:              params.blockFinality,
+		TransactionQueueAPI:           params.state.Transaction,
+		RPCAPI:                        rpcService,
+		SyncStateAPI:                  syncStateSrvc,
+		SyncAPI:                       params.syncer,
+		SystemAPI:                     params.system,
+		RPC:                           params.config.RPC.isRPCEnabled(),
+		RPCExternal:                   params.config.RPC.External,
+		RPCUnsafe:                     params.config.RPC.Unsafe,
+		RPCUnsafeExternal:             params.config.RPC.UnsafeExternal,
+		Host:                          params.config.RPC.Host,
+		RPCPort:                       params.config.RPC.Port,
+		WS:                            params.config.RPC.WS,
+		WSExternal:                    params.config.RPC.WSExternal,
+		WSUnsafe:                      params.config.RPC.WSUnsafe,
+		WSUnsafeExternal:              params.config.RPC.WSUnsafeExternal,
+		WSPort:                        params.config.RPC.WSPort,
+		Modules:                       params.config.RPC.Modules,
+		MaxBatchSize:                  params.config.RPC.MaxBatchSize,
+		MethodsAllowed:                params.config.RPC.MethodsAllowed,
+		MethodsDenied:                 params.config.RPC.MethodsDenied,
+		MaxRequestsPerSecond:          params.config.RPC.MaxRequestsPerSecond,
+		MaxSubscriptionsPerConnection: params.config.RPC.MaxSubscriptionsPerConnection,
+		MaxConnections:                params.config.RPC.MaxConnections,
+		IPCPath:                       ipcPath,
+		IPCPermissions:                ipcPermissions,
 	}
 
 	return rpc.NewHTTPServer(rpcConfig), nil
 }
 
 // createSystemService creates a systemService for providing system related information
-func (nodeBuilder) createSystemService(cfg *types.SystemInfo, stateSrvc *state.Service) (*system.Service, error) {
+func (nodeBuilder) createSystemService(cfg *types.SystemInfo, logFilter *system.LogFilter,
+	stateSrvc *state.Service) (*system.Service, error) {
 	genesisData, err := stateSrvc.Base.LoadGenesisData()
 	if err != nil {
 		return nil, err
 	}
 
-	return system.NewService(cfg, genesisData), nil
+	return system.NewService(cfg, genesisData, logFilter), nil
 }
 
 // createGRANDPAService creates a new GRANDPA service
@@ -222,7 +222,7 @@ func TestCreateRPCService(t *testing.T) {
 	coreSrvc, err := builder.createCoreService(cfg, ks, stateSrvc, networkSrvc, dh)
 	require.NoError(t, err)
 
-	sysSrvc, err := builder.createSystemService(&cfg.System, stateSrvc)
+	sysSrvc, err := builder.createSystemService(&cfg.System, nil, stateSrvc)
 	require.NoError(t, err)
 
 	rpcSettings := rpcServiceSettings{
@@ -380,7 +380,7 @@ func TestNewWebSocketServer(t *testing.T) {
 	coreSrvc, err := builder.createCoreService(cfg, ks, stateSrvc, networkSrvc, dh)
 	require.NoError(t, err)
 
-	sysSrvc, err := builder.createSystemService(&cfg.System, stateSrvc)
+	sysSrvc, err := builder.createSystemService(&cfg.System, nil, stateSrvc)
 	require.NoError(t, err)
 
 	rpcSettings := rpcServiceSettings{
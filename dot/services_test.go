@@ -24,6 +24,7 @@
 	"github.com/ChainSafe/gossamer/lib/runtime/life"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/runtime/wazero"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -96,7 +97,7 @@ type args struct {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := builder.createSystemService(tt.args.cfg, tt.args.service)
+			got, err := builder.createSystemService(tt.args.cfg, nil, tt.args.service)
 			assert.ErrorIs(t, err, tt.err)
 
 			// TODO: change this check to assert.Equal after state.Service interface is implemented.
@@ -470,6 +471,9 @@ func Test_createRuntime(t *testing.T) {
 	cfgLife := NewTestConfig(t)
 	cfgLife.Core.WasmInterpreter = life.Name
 
+	cfgWazero := NewTestConfig(t)
+	cfgWazero.Core.WasmInterpreter = wazero.Name
+
 	type args struct {
 		cfg *Config
 		ns  runtime.NodeStorage
@@ -498,6 +502,15 @@ type args struct {
 			expectedType: &life.Instance{},
 			err:          nil,
 		},
+		{
+			name: "wazero runtime",
+			args: args{
+				cfg: cfgWazero,
+				ns:  runtime.NodeStorage{},
+			},
+			expectedType: &wazero.Instance{},
+			err:          nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -26,7 +26,7 @@ func TestTrie_StoreAndLoadFromDB(t *testing.T) {
 		tt.Put(key, value)
 	}
 
-	err := tt.Store(db)
+	err := tt.Store(db, trie.V0)
 	require.NoError(t, err)
 
 	encroot, err := tt.Hash()
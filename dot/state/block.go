@@ -21,8 +21,8 @@
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
+	"github.com/ChainSafe/gossamer/lib/runtime/executor"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
-	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
 )
 
 const (
@@ -37,6 +37,8 @@
 	receiptPrefix       = []byte("rcp") // receiptPrefix + hash -> receipt
 	messageQueuePrefix  = []byte("mqp") // messageQueuePrefix + hash -> message queue
 	justificationPrefix = []byte("jcp") // justificationPrefix + hash -> justification
+	offchainIndexPrefix = []byte("oci") // offchainIndexPrefix + hash -> offchain index changes
+	offchainUndoPrefix  = []byte("ocu") // offchainUndoPrefix + hash -> offchain index changes reverting the block
 
 	errNilBlockBody = errors.New("block body is nil")
 
@@ -59,6 +61,7 @@ type BlockState struct {
 	lastFinalised     common.Hash
 	unfinalisedBlocks *hashToBlockMap
 	tries             *Tries
+	pinned            pinnedBlocks
 
 	// block notifiers
 	imported                       map[chan *types.Block]struct{}
@@ -69,6 +72,9 @@ type BlockState struct {
 	runtimeUpdateSubscriptions     map[uint32]chan<- runtime.Version
 
 	telemetry telemetry.Client
+
+	// newRuntimeInstance instantiates the runtime codes set by the blocks
+	newRuntimeInstance runtime.NewInstanceFunc
 }
 
 // NewBlockState will create a new BlockState backed by the database located at basePath
@@ -83,6 +89,7 @@ func NewBlockState(db chaindb.Database, trs *Tries, telemetry telemetry.Client)
 		finalised:                  make(map[chan *types.FinalisationInfo]struct{}),
 		runtimeUpdateSubscriptions: make(map[uint32]chan<- runtime.Version),
 		telemetry:                  telemetry,
+		newRuntimeInstance:         executor.NewInstance,
 	}
 
 	gh, err := bs.db.Get(headerHashKey(0))
@@ -118,6 +125,7 @@ func NewBlockStateFromGenesis(db chaindb.Database, trs *Tries, header *types.Hea
 		genesisHash:                header.Hash(),
 		lastFinalised:              header.Hash(),
 		telemetry:                  telemetryMailer,
+		newRuntimeInstance:         executor.NewInstance,
 	}
 
 	if err := bs.setArrivalTime(header.Hash(), time.Now()); err != nil {
@@ -609,21 +617,20 @@ func (bs *BlockState) HandleRuntimeChanges(newState *rtstorage.TrieState,
 			bHash, codeHash, previousVersion.SpecVersion(), currCodeHash, newVersion.SpecVersion())
 	}
 
-	rtCfg := &wasmer.Config{
-		Imports: wasmer.ImportsNodeRuntime,
+	rtCfg := runtime.InstanceConfig{
+		Storage:              newState,
+		Keystore:             rt.Keystore(),
+		NodeStorage:          rt.NodeStorage(),
+		Network:              rt.NetworkService(),
+		CodeHash:             currCodeHash,
+		OffchainHTTPDisabled: rt.OffchainHTTPDisabled(),
 	}
 
-	rtCfg.Storage = newState
-	rtCfg.Keystore = rt.Keystore()
-	rtCfg.NodeStorage = rt.NodeStorage()
-	rtCfg.Network = rt.NetworkService()
-	rtCfg.CodeHash = currCodeHash
-
 	if rt.Validator() {
 		rtCfg.Role = 4
 	}
 
-	instance, err := wasmer.NewInstance(code, rtCfg)
+	instance, err := bs.newRuntimeInstance(code, rtCfg)
 	if err != nil {
 		return err
 	}
@@ -653,7 +660,14 @@ func (bs *BlockState) GetRuntime(hash *common.Hash) (runtime.Instance, error) {
 		return rt, nil
 	}
 
-	return bs.bt.GetBlockRuntime(*hash)
+	rt, err := bs.bt.GetBlockRuntime(*hash)
+	if err != nil {
+		if prunedRuntime, ok := bs.getPrunedRuntime(*hash); ok {
+			return prunedRuntime, nil
+		}
+		return nil, err
+	}
+	return rt, nil
 }
 
 // StoreRuntime stores the runtime for corresponding block hash.
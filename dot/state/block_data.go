@@ -4,7 +4,13 @@
 package state
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/chaindb"
 	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
 // prefixKey = prefix + hash
@@ -86,3 +92,156 @@ func (bs *BlockState) GetJustification(hash common.Hash) ([]byte, error) {
 
 	return data, nil
 }
+
+// SetOffchainIndexChanges sets the offchain index changes made by the block in the database
+func (bs *BlockState) SetOffchainIndexChanges(hash common.Hash, changes []rtstorage.OffchainIndexChange) error {
+	return bs.setOffchainIndexChanges(prefixKey(hash, offchainIndexPrefix), changes)
+}
+
+// GetOffchainIndexChanges retrieves the offchain index changes made by the block from the database.
+// It returns no change if the block did not change the offchain index.
+func (bs *BlockState) GetOffchainIndexChanges(hash common.Hash) ([]rtstorage.OffchainIndexChange, error) {
+	return bs.getOffchainIndexChanges(prefixKey(hash, offchainIndexPrefix))
+}
+
+// SetOffchainIndexRevert sets the offchain index changes reverting the changes made by the block in the database
+func (bs *BlockState) SetOffchainIndexRevert(hash common.Hash, changes []rtstorage.OffchainIndexChange) error {
+	return bs.setOffchainIndexChanges(prefixKey(hash, offchainUndoPrefix), changes)
+}
+
+// GetOffchainIndexRevert retrieves the offchain index changes reverting the changes made by the block
+// from the database. It returns no change if the changes of the block were not applied.
+func (bs *BlockState) GetOffchainIndexRevert(hash common.Hash) ([]rtstorage.OffchainIndexChange, error) {
+	return bs.getOffchainIndexChanges(prefixKey(hash, offchainUndoPrefix))
+}
+
+// DeleteOffchainIndexRevert deletes the offchain index changes reverting the changes made by the block
+// from the database, once the changes of the block are reverted.
+func (bs *BlockState) DeleteOffchainIndexRevert(hash common.Hash) error {
+	return bs.db.Del(prefixKey(hash, offchainUndoPrefix))
+}
+
+func (bs *BlockState) setOffchainIndexChanges(key []byte, changes []rtstorage.OffchainIndexChange) error {
+	data, err := scale.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	return bs.db.Put(key, data)
+}
+
+func (bs *BlockState) getOffchainIndexChanges(key []byte) ([]rtstorage.OffchainIndexChange, error) {
+	data, err := bs.db.Get(key)
+	if errors.Is(err, chaindb.ErrKeyNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var changes []rtstorage.OffchainIndexChange
+	err = scale.Unmarshal(data, &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// DeleteOffchainIndex deletes the offchain index changes made by the block, and the changes reverting them,
+// from the database.
+func (bs *BlockState) DeleteOffchainIndex(hash common.Hash) error {
+	batch := bs.db.NewBatch()
+	err := deleteOffchainIndex(batch, hash)
+	if err != nil {
+		return err
+	}
+
+	return batch.Flush()
+}
+
+func deleteOffchainIndex(batch chaindb.Batch, hash common.Hash) error {
+	err := batch.Del(prefixKey(hash, offchainIndexPrefix))
+	if err != nil {
+		return err
+	}
+
+	return batch.Del(prefixKey(hash, offchainUndoPrefix))
+}
+
+// pruneOffchainIndex deletes the offchain index changes of the pruned blocks, and the changes of
+// the newly finalised blocks which were written to the offchain storage, since they cannot be
+// reverted anymore. The changes of the pruned blocks written to the offchain storage are reverted
+// first, the pruned blocks being sorted from the highest to the lowest. The changes of the finalised
+// blocks which were not written yet are deleted once written.
+func (bs *BlockState) pruneOffchainIndex(pruned []common.Hash, finalisedHash common.Hash,
+	prevFinalised, finalised uint) error {
+	batch := bs.db.NewBatch()
+	for _, hash := range pruned {
+		revert, err := bs.GetOffchainIndexRevert(hash)
+		if err != nil {
+			return err
+		}
+
+		if len(revert) > 0 {
+			err = bs.revertOffchainIndex(finalisedHash, revert)
+			if err != nil {
+				return fmt.Errorf("cannot revert offchain index changes of pruned block %s: %w", hash, err)
+			}
+		}
+
+		err = deleteOffchainIndex(batch, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	for number := prevFinalised + 1; number <= finalised; number++ {
+		hash, err := bs.GetHashByNumber(number)
+		if err != nil {
+			return err
+		}
+
+		applied, err := bs.db.Has(prefixKey(hash, offchainUndoPrefix))
+		if err != nil {
+			return err
+		}
+
+		if !applied {
+			continue
+		}
+
+		err = deleteOffchainIndex(batch, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return batch.Flush()
+}
+
+// revertOffchainIndex writes the changes reverting the offchain index changes
+// of a block to the offchain storage of the runtime of the given block.
+func (bs *BlockState) revertOffchainIndex(hash common.Hash, revert []rtstorage.OffchainIndexChange) error {
+	rt, err := bs.GetRuntime(&hash)
+	if err != nil {
+		return fmt.Errorf("cannot get runtime: %w", err)
+	}
+
+	storage := rt.NodeStorage().PersistentStorage
+	if storage == nil {
+		return nil
+	}
+
+	for _, change := range revert {
+		if change.Value == nil {
+			err = storage.Del(change.Key)
+		} else {
+			err = storage.Put(change.Key, *change.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
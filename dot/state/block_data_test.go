@@ -6,8 +6,12 @@
 import (
 	"testing"
 
+	"github.com/ChainSafe/chaindb"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	runtimemocks "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/trie"
 
 	"github.com/stretchr/testify/require"
@@ -81,3 +85,139 @@ func TestGetSet_ReceiptMessageQueue_Justification(t *testing.T) {
 		}
 	}
 }
+
+func TestGetSet_OffchainIndexChanges(t *testing.T) {
+	s := newTestBlockState(t, nil, newTriesEmpty())
+
+	hash := common.Hash{1}
+	changes, err := s.GetOffchainIndexChanges(hash)
+	require.NoError(t, err)
+	require.Nil(t, changes)
+
+	value := []byte("value")
+	expected := []rtstorage.OffchainIndexChange{
+		{Key: []byte("key1"), Value: &value},
+		{Key: []byte("key2")},
+	}
+
+	err = s.SetOffchainIndexChanges(hash, expected)
+	require.NoError(t, err)
+
+	changes, err = s.GetOffchainIndexChanges(hash)
+	require.NoError(t, err)
+	require.Equal(t, expected, changes)
+
+	revert, err := s.GetOffchainIndexRevert(hash)
+	require.NoError(t, err)
+	require.Nil(t, revert)
+
+	err = s.SetOffchainIndexRevert(hash, expected[1:])
+	require.NoError(t, err)
+
+	revert, err = s.GetOffchainIndexRevert(hash)
+	require.NoError(t, err)
+	require.Equal(t, expected[1:], revert)
+}
+
+func TestBlockState_SetFinalisedHash_pruneOffchainIndex(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader, newTriesEmpty())
+
+	chain, _ := AddBlocksToState(t, bs, 2, false)
+
+	// the fork of two blocks from the genesis block is pruned on finalisation
+	var fork []common.Hash
+	parentHash := testGenesisHeader.Hash()
+	for number := uint(1); number <= 2; number++ {
+		digest := types.NewDigest()
+		preDigest, err := types.NewBabeSecondaryPlainPreDigest(1, uint64(number)).ToPreRuntimeDigest()
+		require.NoError(t, err)
+		err = digest.Add(*preDigest)
+		require.NoError(t, err)
+
+		branch := &types.Block{
+			Header: types.Header{
+				ParentHash: parentHash,
+				Number:     number,
+				StateRoot:  trie.EmptyHash,
+				Digest:     digest,
+			},
+			Body: types.Body{},
+		}
+		err = bs.AddBlock(branch)
+		require.NoError(t, err)
+
+		parentHash = branch.Header.Hash()
+		fork = append(fork, parentHash)
+	}
+
+	storage := runtime.NewInMemoryDB(t)
+	runtimeMock := new(runtimemocks.Instance)
+	runtimeMock.On("NodeStorage").Return(runtime.NodeStorage{PersistentStorage: storage})
+	bs.StoreRuntime(chain[1].Hash(), runtimeMock)
+
+	value := []byte("value")
+	changes := []rtstorage.OffchainIndexChange{{Key: []byte("key"), Value: &value}}
+	revert := []rtstorage.OffchainIndexChange{{Key: []byte("key")}}
+
+	applied, notApplied := chain[0].Hash(), chain[1].Hash()
+	for _, hash := range []common.Hash{applied, notApplied} {
+		err := bs.SetOffchainIndexChanges(hash, changes)
+		require.NoError(t, err)
+	}
+
+	err := bs.SetOffchainIndexRevert(applied, revert)
+	require.NoError(t, err)
+
+	// the changes of the fork were written to the offchain storage,
+	// and are reverted from the highest block to the lowest one.
+	old, forkValue1, forkValue2 := []byte("old"), []byte("fork1"), []byte("fork2")
+	err = bs.SetOffchainIndexChanges(fork[0], []rtstorage.OffchainIndexChange{{Key: []byte("fork"), Value: &forkValue1}})
+	require.NoError(t, err)
+	err = bs.SetOffchainIndexRevert(fork[0], []rtstorage.OffchainIndexChange{{Key: []byte("fork"), Value: &old}})
+	require.NoError(t, err)
+	err = bs.SetOffchainIndexChanges(fork[1], []rtstorage.OffchainIndexChange{
+		{Key: []byte("fork"), Value: &forkValue2},
+		{Key: []byte("new"), Value: &forkValue2},
+	})
+	require.NoError(t, err)
+	err = bs.SetOffchainIndexRevert(fork[1], []rtstorage.OffchainIndexChange{
+		{Key: []byte("fork"), Value: &forkValue1},
+		{Key: []byte("new")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, storage.Put([]byte("fork"), forkValue2))
+	require.NoError(t, storage.Put([]byte("new"), forkValue2))
+
+	err = bs.SetFinalisedHash(notApplied, 1, 0)
+	require.NoError(t, err)
+
+	stored, err := storage.Get([]byte("fork"))
+	require.NoError(t, err)
+	require.Equal(t, old, stored)
+	_, err = storage.Get([]byte("new"))
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+
+	// the changes of the pruned blocks and of the applied finalised block are deleted
+	for _, hash := range []common.Hash{applied, fork[0], fork[1]} {
+		var stored []rtstorage.OffchainIndexChange
+		stored, err = bs.GetOffchainIndexChanges(hash)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		stored, err = bs.GetOffchainIndexRevert(hash)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+	}
+
+	// the changes of the finalised block which were not applied yet are kept
+	storedChanges, err := bs.GetOffchainIndexChanges(notApplied)
+	require.NoError(t, err)
+	require.Equal(t, changes, storedChanges)
+
+	err = bs.DeleteOffchainIndex(notApplied)
+	require.NoError(t, err)
+
+	storedChanges, err = bs.GetOffchainIndexChanges(notApplied)
+	require.NoError(t, err)
+	require.Nil(t, storedChanges)
+}
@@ -142,16 +142,24 @@ func (bs *BlockState) SetFinalisedHash(hash common.Hash, round, setID uint64) er
 		bs.notifyFinalized(hash, round, setID)
 	}
 
-	pruned := bs.bt.Prune(hash)
-	for _, hash := range pruned {
-		blockHeader := bs.unfinalisedBlocks.delete(hash)
-		if blockHeader == nil {
-			continue
-		}
+	pruned := bs.pruneBlockTree(hash)
+
+	prevFinalised, err := bs.GetHeader(bs.lastFinalised)
+	if err != nil {
+		return fmt.Errorf("failed to get last finalised header: %w", err)
+	}
+
+	finalised, err := bs.GetHeader(hash)
+	if err != nil {
+		return fmt.Errorf("failed to get finalised header, hash: %s, error: %s", hash, err)
+	}
 
-		bs.tries.delete(blockHeader.StateRoot)
+	if err := bs.pruneTransactionIndex(pruned, prevFinalised.Number, finalised.Number); err != nil {
+		return fmt.Errorf("failed to prune transaction index: %w", err)
+	}
 
-		logger.Tracef("pruned block number %d with hash %s", blockHeader.Number, hash)
+	if err := bs.pruneOffchainIndex(pruned, hash, prevFinalised.Number, finalised.Number); err != nil {
+		return fmt.Errorf("failed to prune offchain index changes: %w", err)
 	}
 
 	// if nothing was previously finalised, set the first slot of the network to the
@@ -162,15 +170,10 @@ func (bs *BlockState) SetFinalisedHash(hash common.Hash, round, setID uint64) er
 		}
 	}
 
-	header, err := bs.GetHeader(hash)
-	if err != nil {
-		return fmt.Errorf("failed to get finalised header, hash: %s, error: %s", hash, err)
-	}
-
 	bs.telemetry.SendMessage(
 		telemetry.NewNotifyFinalized(
-			header.Hash(),
-			fmt.Sprint(header.Number),
+			finalised.Hash(),
+			fmt.Sprint(finalised.Number),
 		),
 	)
 
@@ -194,7 +197,7 @@ func (bs *BlockState) deleteFromTries(lastFinalised common.Hash) error {
 	}
 	stateRootTrie := bs.tries.get(lastFinalisedHeader.StateRoot)
 	if stateRootTrie != nil {
-		bs.tries.delete(lastFinalisedHeader.StateRoot)
+		bs.deleteFinalisedTrie(lastFinalised, lastFinalisedHeader.StateRoot)
 	} else {
 		return fmt.Errorf("unable to find trie with stateroot hash: %s", lastFinalisedHeader.StateRoot)
 	}
@@ -260,7 +263,7 @@ func (bs *BlockState) handleFinalisedBlock(curr common.Hash) error {
 			continue
 		}
 
-		bs.tries.delete(blockHeader.StateRoot)
+		bs.deleteFinalisedTrie(hash, blockHeader.StateRoot)
 
 		logger.Tracef("cleaned out finalised block from memory; block number %d with hash %s", blockHeader.Number, hash)
 	}
@@ -0,0 +1,163 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+)
+
+// pinnedBlocks is a reference counted set of pinned blocks.
+// The header, body, state trie and runtime of a pinned block are kept
+// until it is unpinned, even if the block is pruned or finalised meanwhile.
+// Its zero value is ready to use.
+type pinnedBlocks struct {
+	sync.Mutex
+	counts map[common.Hash]uint
+	// pruned maps the pinned blocks pruned from the blocktree to their runtime,
+	// since the blocktree no longer holds it.
+	pruned map[common.Hash]runtime.Instance
+	// finalisedRoots maps the pinned blocks written to the database on
+	// finalisation to the state root of their in-memory trie.
+	finalisedRoots map[common.Hash]common.Hash
+}
+
+func (p *pinnedBlocks) has(hash common.Hash) bool {
+	_, has := p.counts[hash]
+	return has
+}
+
+// PinBlock pins the block with the given hash, so its header, body, state and runtime
+// stay available until it is unpinned, even if its fork is pruned on finalisation.
+// A block pinned several times is released once it is unpinned as many times.
+func (bs *BlockState) PinBlock(hash common.Hash) error {
+	bs.pinned.Lock()
+	defer bs.pinned.Unlock()
+
+	has, err := bs.HasHeader(hash)
+	if err != nil {
+		return fmt.Errorf("could not check header for hash %s: %w", hash, err)
+	}
+	if !has {
+		return fmt.Errorf("cannot pin unknown block %s", hash)
+	}
+
+	if bs.pinned.counts == nil {
+		bs.pinned.counts = make(map[common.Hash]uint)
+	}
+	bs.pinned.counts[hash]++
+	return nil
+}
+
+// UnpinBlock unpins the block with the given hash. Once it is no longer pinned,
+// the block is released if it was pruned or finalised while it was pinned.
+func (bs *BlockState) UnpinBlock(hash common.Hash) {
+	bs.pinned.Lock()
+	defer bs.pinned.Unlock()
+
+	count := bs.pinned.counts[hash]
+	if count == 0 {
+		return
+	}
+	if count > 1 {
+		bs.pinned.counts[hash] = count - 1
+		return
+	}
+	delete(bs.pinned.counts, hash)
+
+	if _, has := bs.pinned.pruned[hash]; has {
+		delete(bs.pinned.pruned, hash)
+		blockHeader := bs.unfinalisedBlocks.delete(hash)
+		if blockHeader != nil {
+			bs.tries.delete(blockHeader.StateRoot)
+			logger.Tracef("released pruned block number %d with hash %s", blockHeader.Number, hash)
+		}
+	}
+
+	if stateRoot, has := bs.pinned.finalisedRoots[hash]; has {
+		delete(bs.pinned.finalisedRoots, hash)
+		bs.tries.delete(stateRoot)
+	}
+}
+
+// pruneBlockTree prunes the blocktree on the finalisation of the given block, and deletes
+// the pruned blocks from memory unless they are pinned. The pruned blocks are returned
+// from the highest to the lowest.
+func (bs *BlockState) pruneBlockTree(finalised common.Hash) (pruned []common.Hash) {
+	bs.pinned.Lock()
+	defer bs.pinned.Unlock()
+
+	// the blocktree drops the runtimes of the pruned blocks,
+	// so the runtimes of the pinned blocks are kept beforehand.
+	runtimes := make(map[common.Hash]runtime.Instance, len(bs.pinned.counts))
+	for hash := range bs.pinned.counts {
+		rt, err := bs.bt.GetBlockRuntime(hash)
+		if err == nil {
+			runtimes[hash] = rt
+		}
+	}
+
+	pruned = bs.bt.Prune(finalised)
+	numbers := make(map[common.Hash]uint, len(pruned))
+	for _, hash := range pruned {
+		if block := bs.unfinalisedBlocks.getBlock(hash); block != nil {
+			numbers[hash] = block.Header.Number
+		}
+	}
+	sort.Slice(pruned, func(i, j int) bool {
+		return numbers[pruned[i]] > numbers[pruned[j]]
+	})
+
+	for _, hash := range pruned {
+		if bs.pinned.has(hash) {
+			if bs.pinned.pruned == nil {
+				bs.pinned.pruned = make(map[common.Hash]runtime.Instance)
+			}
+			bs.pinned.pruned[hash] = runtimes[hash]
+			logger.Tracef("delaying the release of pinned pruned block %s", hash)
+			continue
+		}
+
+		blockHeader := bs.unfinalisedBlocks.delete(hash)
+		if blockHeader == nil {
+			continue
+		}
+
+		bs.tries.delete(blockHeader.StateRoot)
+
+		logger.Tracef("pruned block number %d with hash %s", blockHeader.Number, hash)
+	}
+
+	return pruned
+}
+
+// deleteFinalisedTrie deletes the in-memory trie of a block written to the
+// database on finalisation, or delays it until the block is unpinned.
+func (bs *BlockState) deleteFinalisedTrie(hash, stateRoot common.Hash) {
+	bs.pinned.Lock()
+	defer bs.pinned.Unlock()
+
+	if bs.pinned.has(hash) {
+		if bs.pinned.finalisedRoots == nil {
+			bs.pinned.finalisedRoots = make(map[common.Hash]common.Hash)
+		}
+		bs.pinned.finalisedRoots[hash] = stateRoot
+		return
+	}
+
+	bs.tries.delete(stateRoot)
+}
+
+// getPrunedRuntime returns the runtime kept for a pinned pruned block, if any.
+func (bs *BlockState) getPrunedRuntime(hash common.Hash) (rt runtime.Instance, ok bool) {
+	bs.pinned.Lock()
+	defer bs.pinned.Unlock()
+
+	rt = bs.pinned.pruned[hash]
+	return rt, rt != nil
+}
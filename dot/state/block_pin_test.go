@@ -0,0 +1,97 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	runtimemocks "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockState_PinBlock_finaliseOtherFork(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader, newTriesEmpty())
+
+	newHeader := func(parentHash common.Hash, number uint, slot uint64, stateRoot common.Hash) *types.Header {
+		digest := types.NewDigest()
+		preDigest, err := types.NewBabeSecondaryPlainPreDigest(0, slot).ToPreRuntimeDigest()
+		require.NoError(t, err)
+		err = digest.Add(*preDigest)
+		require.NoError(t, err)
+		return &types.Header{
+			ParentHash: parentHash,
+			Number:     number,
+			Digest:     digest,
+			StateRoot:  stateRoot,
+		}
+	}
+
+	// genesis <- a1 <- a2 is finalised, while genesis <- b1 is pinned
+	a1 := newHeader(testGenesisHeader.Hash(), 1, 1, common.Hash{1})
+	a2 := newHeader(a1.Hash(), 2, 2, common.Hash{2})
+	b1 := newHeader(testGenesisHeader.Hash(), 1, 3, common.Hash{3})
+	b1Body := types.Body{{1, 2, 3}}
+
+	for _, block := range []*types.Block{
+		{Header: *a1, Body: types.Body{}},
+		{Header: *a2, Body: types.Body{}},
+		{Header: *b1, Body: b1Body},
+	} {
+		err := bs.AddBlock(block)
+		require.NoError(t, err)
+		bs.tries.softSet(block.Header.StateRoot, trie.NewEmptyTrie())
+	}
+
+	b1Hash := b1.Hash()
+	b1Runtime := new(runtimemocks.Instance)
+	bs.StoreRuntime(b1Hash, b1Runtime)
+
+	err := bs.PinBlock(common.Hash{9})
+	require.Error(t, err)
+
+	// b1 is pinned twice, and only released once unpinned twice
+	err = bs.PinBlock(b1Hash)
+	require.NoError(t, err)
+	err = bs.PinBlock(b1Hash)
+	require.NoError(t, err)
+	// a2 is pinned, so its trie is kept once it is finalised
+	err = bs.PinBlock(a2.Hash())
+	require.NoError(t, err)
+
+	err = bs.SetFinalisedHash(a2.Hash(), 1, 0)
+	require.NoError(t, err)
+
+	header, err := bs.GetHeader(b1Hash)
+	require.NoError(t, err)
+	assert.Equal(t, b1, header)
+	body, err := bs.GetBlockBody(b1Hash)
+	require.NoError(t, err)
+	assert.Equal(t, &b1Body, body)
+	rt, err := bs.GetRuntime(&b1Hash)
+	require.NoError(t, err)
+	assert.Same(t, b1Runtime, rt)
+	assert.NotNil(t, bs.tries.get(b1.StateRoot))
+	assert.NotNil(t, bs.tries.get(a2.StateRoot))
+	assert.Nil(t, bs.tries.get(a1.StateRoot))
+
+	bs.UnpinBlock(b1Hash)
+	_, err = bs.GetHeader(b1Hash)
+	require.NoError(t, err)
+
+	bs.UnpinBlock(b1Hash)
+	_, err = bs.GetHeader(b1Hash)
+	assert.Error(t, err)
+	_, err = bs.GetRuntime(&b1Hash)
+	assert.Error(t, err)
+	assert.Nil(t, bs.tries.get(b1.StateRoot))
+
+	bs.UnpinBlock(a2.Hash())
+	assert.Nil(t, bs.tries.get(a2.StateRoot))
+	_, err = bs.GetHeader(a2.Hash())
+	require.NoError(t, err)
+}
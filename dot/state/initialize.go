@@ -13,13 +13,14 @@
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
-	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
 	"github.com/ChainSafe/gossamer/lib/trie"
 	"github.com/ChainSafe/gossamer/lib/utils"
 )
 
 // Initialise initialises the genesis state of the DB using the given storage trie.
 // The trie should be loaded with the genesis storage state.
+// The state root of the header is set to the root of the trie computed with the
+// state version of the genesis runtime.
 // This only needs to be called during genesis initialisation of the node;
 // it is not called during normal startup.
 func (s *Service) Initialise(gen *genesis.Genesis, header *types.Header, t *trie.Trie) error {
@@ -41,10 +42,6 @@ func (s *Service) Initialise(gen *genesis.Genesis, header *types.Header, t *trie
 		return fmt.Errorf("failed to clear database: %s", err)
 	}
 
-	if err = t.Store(chaindb.NewTable(db, storagePrefix)); err != nil {
-		return fmt.Errorf("failed to write genesis trie to database: %w", err)
-	}
-
 	s.Base = NewBaseState(db)
 
 	rt, err := s.CreateGenesisRuntime(t, gen)
@@ -52,13 +49,31 @@ func (s *Service) Initialise(gen *genesis.Genesis, header *types.Header, t *trie
 		return err
 	}
 
+	stateVersion, err := rt.StateVersion()
+	if err != nil {
+		rt.Stop()
+		return fmt.Errorf("failed to get genesis state version: %w", err)
+	}
+
+	// the genesis state root is computed with the state version of the genesis runtime
+	header.StateRoot, err = t.HashWithVersion(stateVersion)
+	if err != nil {
+		rt.Stop()
+		return fmt.Errorf("failed to compute genesis state root: %w", err)
+	}
+
+	if err = t.Store(chaindb.NewTable(db, storagePrefix), stateVersion); err != nil {
+		rt.Stop()
+		return fmt.Errorf("failed to write genesis trie to database: %w", err)
+	}
+
 	babeCfg, err := s.loadBabeConfigurationFromRuntime(rt)
 	if err != nil {
 		return err
 	}
 
 	// write initial genesis values to database
-	if err = s.storeInitialValues(gen.GenesisData(), t); err != nil {
+	if err = s.storeInitialValues(gen.GenesisData(), t, stateVersion); err != nil {
 		return fmt.Errorf("failed to write genesis values to database: %s", err)
 	}
 
@@ -72,6 +87,7 @@ func (s *Service) Initialise(gen *genesis.Genesis, header *types.Header, t *trie
 	if err != nil {
 		return fmt.Errorf("failed to create block state from genesis: %s", err)
 	}
+	blockState.newRuntimeInstance = s.newRuntimeInstance
 
 	// create storage state from genesis trie
 	storageState, err := NewStorageState(db, blockState, tries, pruner.Config{})
@@ -136,9 +152,9 @@ func loadGrandpaAuthorities(t *trie.Trie) ([]types.GrandpaVoter, error) {
 }
 
 // storeInitialValues writes initial genesis values to the state database
-func (s *Service) storeInitialValues(data *genesis.Data, t *trie.Trie) error {
+func (s *Service) storeInitialValues(data *genesis.Data, t *trie.Trie, stateVersion trie.Version) error {
 	// write genesis trie to database
-	if err := t.Store(chaindb.NewTable(s.db, storagePrefix)); err != nil {
+	if err := t.Store(chaindb.NewTable(s.db, storagePrefix), stateVersion); err != nil {
 		return fmt.Errorf("failed to write trie to database: %s", err)
 	}
 
@@ -162,12 +178,18 @@ func (s *Service) CreateGenesisRuntime(t *trie.Trie, gen *genesis.Genesis) (runt
 		return nil, fmt.Errorf("failed to instantiate TrieState: %w", err)
 	}
 
+	code := genTrie.LoadCode()
+	if len(code) == 0 {
+		return nil, fmt.Errorf("cannot find :code in genesis state")
+	}
+
 	// create genesis runtime
-	rtCfg := &wasmer.Config{}
-	rtCfg.Storage = genTrie
-	rtCfg.LogLvl = s.logLvl
+	rtCfg := runtime.InstanceConfig{
+		Storage: genTrie,
+		LogLvl:  s.logLvl,
+	}
 
-	r, err := wasmer.NewRuntimeFromGenesis(rtCfg)
+	r, err := s.newRuntimeInstance(code, rtCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create genesis runtime: %w", err)
 	}
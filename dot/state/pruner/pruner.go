@@ -173,6 +173,11 @@ func (p *FullNode) addDeathRow(jr *journalRecord, blockNum int64) {
 	// add deleted keys from journal to death index
 	deletedKeys := make(map[common.Hash]int64, len(jr.deletedHashesSet))
 	for k := range jr.deletedHashesSet {
+		if _, reinserted := jr.insertedHashesSet[k]; reinserted {
+			// the key is still used by the state trie of the block, which is
+			// the case for a node deleted and inserted back by the block.
+			continue
+		}
 		p.deathIndex[k] = blockNum
 		deletedKeys[k] = blockNum
 	}
@@ -0,0 +1,76 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package pruner
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FullNode_sameHashedValue(t *testing.T) {
+	t.Parallel()
+
+	db, err := chaindb.NewBadgerDB(&chaindb.Config{InMemory: true})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := db.Close()
+		assert.NoError(t, err)
+	})
+	storageDB := chaindb.NewTable(db, "storage")
+
+	// both keys hold the same value, which is hashed in the encodings of their nodes
+	key1, key2 := []byte{1, 2}, []byte{2, 3}
+	value := bytes.Repeat([]byte{1}, trie.V1MaxInlineValue+1)
+
+	parentTrie := trie.NewEmptyTrie()
+	parentTrie.Put(key1, value)
+	parentTrie.Put(key2, value)
+	err = parentTrie.Store(storageDB, trie.V1)
+	require.NoError(t, err)
+	parentRoot, err := parentTrie.HashWithVersion(trie.V1)
+	require.NoError(t, err)
+
+	// the block modifies the value of the first key only
+	blockTrie := parentTrie.Snapshot()
+	blockTrie.Put(key1, []byte{2})
+	insertedHashes, err := blockTrie.GetInsertedNodeHashes(trie.V1)
+	require.NoError(t, err)
+	deletedHashes := blockTrie.GetDeletedNodeHashes()
+	err = blockTrie.WriteDirty(storageDB, trie.V1)
+	require.NoError(t, err)
+	blockRoot, err := blockTrie.HashWithVersion(trie.V1)
+	require.NoError(t, err)
+
+	pruner, err := NewFullNode(db, storageDB, 0, log.New(log.SetWriter(io.Discard)))
+	require.NoError(t, err)
+
+	err = pruner.StoreJournalRecord(deletedHashes, insertedHashes, common.Hash{1}, 1)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		has, err := storageDB.Has(parentRoot.ToBytes())
+		require.NoError(t, err)
+		return !has
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the value is still stored for the node of the second key
+	storedValue, err := trie.GetFromDB(storageDB, blockRoot, key2)
+	require.NoError(t, err)
+	assert.Equal(t, value, storedValue)
+
+	loadedTrie := trie.NewEmptyTrie()
+	err = loadedTrie.Load(storageDB, blockRoot)
+	require.NoError(t, err)
+	assert.Equal(t, value, loadedTrie.Get(key2))
+	assert.Equal(t, []byte{2}, loadedTrie.Get(key1))
+}
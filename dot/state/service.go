@@ -13,6 +13,8 @@
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/internal/metrics"
 	"github.com/ChainSafe/gossamer/lib/blocktree"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/executor"
 	"github.com/ChainSafe/gossamer/lib/trie"
 	"github.com/ChainSafe/gossamer/lib/utils"
 
@@ -40,6 +42,8 @@ type Service struct {
 	PrunerCfg pruner.Config
 	Telemetry telemetry.Client
 
+	newRuntimeInstance runtime.NewInstanceFunc
+
 	// Below are for testing only.
 	BabeThresholdNumerator   uint64
 	BabeThresholdDenominator uint64
@@ -52,12 +56,20 @@ type Config struct {
 	PrunerCfg pruner.Config
 	Telemetry telemetry.Client
 	Metrics   metrics.IntervalConfig
+	// NewRuntimeInstance instantiates the runtime codes of the chain.
+	// It defaults to the default backend of the executor package if nil.
+	NewRuntimeInstance runtime.NewInstanceFunc
 }
 
 // NewService create a new instance of Service
 func NewService(config Config) *Service {
 	logger.Patch(log.SetLevel(config.LogLevel))
 
+	newRuntimeInstance := config.NewRuntimeInstance
+	if newRuntimeInstance == nil {
+		newRuntimeInstance = executor.NewInstance
+	}
+
 	return &Service{
 		dbPath:    config.Path,
 		logLvl:    config.LogLevel,
@@ -68,6 +80,8 @@ func NewService(config Config) *Service {
 		closeCh:   make(chan interface{}),
 		PrunerCfg: config.PrunerCfg,
 		Telemetry: config.Telemetry,
+
+		newRuntimeInstance: newRuntimeInstance,
 	}
 }
 
@@ -123,6 +137,7 @@ func (s *Service) Start() error {
 	if err != nil {
 		return fmt.Errorf("failed to create block state: %w", err)
 	}
+	s.Block.newRuntimeInstance = s.newRuntimeInstance
 
 	// retrieve latest header
 	bestHeader, err := s.Block.GetHighestFinalisedHeader()
@@ -314,7 +329,23 @@ func (s *Service) Import(header *types.Header, t *trie.Trie, firstSlot uint64) e
 		return err
 	}
 
-	root := t.MustHash()
+	// the state version is given by the runtime code of the imported state
+	rt, err := s.CreateGenesisRuntime(t, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create runtime from imported state: %w", err)
+	}
+
+	stateVersion, err := rt.StateVersion()
+	rt.Stop()
+	if err != nil {
+		return fmt.Errorf("cannot get state version: %w", err)
+	}
+
+	root, err := t.HashWithVersion(stateVersion)
+	if err != nil {
+		return fmt.Errorf("cannot compute state root: %w", err)
+	}
+
 	if root != header.StateRoot {
 		return fmt.Errorf("trie state root does not equal header state root")
 	}
@@ -322,7 +353,7 @@ func (s *Service) Import(header *types.Header, t *trie.Trie, firstSlot uint64) e
 	logger.Info("importing storage trie from base path " +
 		s.dbPath + " with root " + root.String() + "...")
 
-	if err := t.Store(storage.db); err != nil {
+	if err := t.Store(storage.db, stateVersion); err != nil {
 		return err
 	}
 
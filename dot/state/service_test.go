@@ -193,7 +193,7 @@ func TestService_StorageTriePruning(t *testing.T) {
 		err = serv.Storage.blockState.AddBlock(block)
 		require.NoError(t, err)
 
-		err = serv.Storage.StoreTrie(trieState, &block.Header)
+		err = serv.Storage.StoreTrie(trieState, &block.Header, trie.V0)
 		require.NoError(t, err)
 
 		blocks = append(blocks, block)
@@ -250,7 +250,7 @@ type prunedBlock struct {
 		err = serv.Storage.blockState.AddBlock(block)
 		require.NoError(t, err)
 
-		err = serv.Storage.StoreTrie(trieState, nil)
+		err = serv.Storage.StoreTrie(trieState, nil, trie.V0)
 		require.NoError(t, err)
 
 		// Only finalise a block at height 3
@@ -268,7 +268,7 @@ type prunedBlock struct {
 		err = serv.Storage.blockState.AddBlock(block)
 		require.NoError(t, err)
 
-		err = serv.Storage.StoreTrie(trieState, nil)
+		err = serv.Storage.StoreTrie(trieState, nil, trie.V0)
 		require.NoError(t, err)
 
 		// Store the other blocks that will be pruned.
@@ -380,6 +380,8 @@ func TestService_Import(t *testing.T) {
 	for _, tc := range testCases {
 		tr.Put([]byte(tc), []byte(tc))
 	}
+	// the imported state needs a runtime code giving its state version
+	tr.Put(common.CodeKey, genTrie.Get(common.CodeKey))
 
 	digest := types.NewDigest()
 	prd, err := types.NewBabeSecondaryPlainPreDigest(0, 177).ToPreRuntimeDigest()
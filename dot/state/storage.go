@@ -71,9 +71,14 @@ func NewStorageState(db chaindb.Database, blockState *BlockState,
 	}, nil
 }
 
-// StoreTrie stores the given trie in the StorageState and writes it to the database
-func (s *StorageState) StoreTrie(ts *rtstorage.TrieState, header *types.Header) error {
-	root := ts.MustRoot()
+// StoreTrie stores the given trie in the StorageState and writes it to the database.
+// The modified trie nodes are encoded with the state trie version given,
+// which is the state version of the runtime executing the block.
+func (s *StorageState) StoreTrie(ts *rtstorage.TrieState, header *types.Header, version trie.Version) error {
+	root, err := ts.RootWithVersion(version)
+	if err != nil {
+		return fmt.Errorf("cannot compute state root: %w", err)
+	}
 
 	s.tries.softSet(root, ts.Trie())
 
@@ -82,7 +87,8 @@ func (s *StorageState) StoreTrie(ts *rtstorage.TrieState, header *types.Header)
 	}
 
 	if header != nil {
-		insertedNodeHashes, err := ts.GetInsertedNodeHashes()
+		var insertedNodeHashes map[common.Hash]struct{}
+		insertedNodeHashes, err = ts.GetInsertedNodeHashes(version)
 		if err != nil {
 			return fmt.Errorf("failed to get state trie inserted keys: block %s %w", header.Hash(), err)
 		}
@@ -96,7 +102,8 @@ func (s *StorageState) StoreTrie(ts *rtstorage.TrieState, header *types.Header)
 
 	logger.Tracef("cached trie in storage state: %s", root)
 
-	if err := ts.Trie().WriteDirty(s.db); err != nil {
+	err = ts.Trie().WriteDirty(s.db, version)
+	if err != nil {
 		logger.Warnf("failed to write trie with root %s to database: %s", root, err)
 		return err
 	}
@@ -304,3 +311,31 @@ func (s *StorageState) LoadCodeHash(hash *common.Hash) (common.Hash, error) {
 func (s *StorageState) GenerateTrieProof(stateRoot common.Hash, keys [][]byte) ([][]byte, error) {
 	return trie.GenerateProof(stateRoot[:], keys, s.db)
 }
+
+// GenerateChildTrieProof returns the proofs of the child trie root in the state root trie
+// together with the proofs related to the keys in the child trie
+func (s *StorageState) GenerateChildTrieProof(stateRoot common.Hash, keyToChild []byte,
+	keys [][]byte) ([][]byte, error) {
+	childTrie, err := s.GetStorageChild(&stateRoot, keyToChild)
+	if err != nil {
+		return nil, err
+	}
+
+	childRoot, err := childTrie.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("cannot hash child trie: %w", err)
+	}
+
+	childKey := append(append([]byte{}, trie.ChildStorageKeyPrefix...), keyToChild...)
+	mainProof, err := trie.GenerateProof(stateRoot[:], [][]byte{childKey}, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate proof for child trie root: %w", err)
+	}
+
+	childProof, err := trie.GenerateProof(childRoot[:], keys, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate proof for child trie keys: %w", err)
+	}
+
+	return append(mainProof, childProof...), nil
+}
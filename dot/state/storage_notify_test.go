@@ -13,6 +13,7 @@
 
 	"github.com/ChainSafe/chaindb"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -34,7 +35,7 @@ func TestStorageState_RegisterStorageObserver(t *testing.T) {
 	defer ss.UnregisterStorageObserver(mockobs)
 
 	ts.Set([]byte("mackcom"), []byte("wuz here"))
-	err = ss.StoreTrie(ts, nil)
+	err = ss.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	expectedResult := &SubscriptionResult{
@@ -79,7 +80,7 @@ func TestStorageState_RegisterStorageObserver_Multi(t *testing.T) {
 
 	ts.Set(key1, value1)
 
-	err = ss.StoreTrie(ts, nil)
+	err = ss.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	time.Sleep(time.Millisecond * 10)
@@ -129,7 +130,7 @@ func TestStorageState_RegisterStorageObserver_Multi_Filter(t *testing.T) {
 	}
 
 	ts.Set(key1, value1)
-	err = ss.StoreTrie(ts, nil)
+	err = ss.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	time.Sleep(time.Millisecond * 10)
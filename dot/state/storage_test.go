@@ -38,7 +38,7 @@ func TestStorage_StoreAndLoadTrie(t *testing.T) {
 
 	root, err := ts.Root()
 	require.NoError(t, err)
-	err = storage.StoreTrie(ts, nil)
+	err = storage.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	time.Sleep(time.Millisecond * 100)
@@ -62,7 +62,7 @@ func TestStorage_GetStorageByBlockHash(t *testing.T) {
 
 	root, err := ts.Root()
 	require.NoError(t, err)
-	err = storage.StoreTrie(ts, nil)
+	err = storage.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	body, err := types.NewBodyFromBytes([]byte{})
@@ -94,7 +94,7 @@ func TestStorage_TrieState(t *testing.T) {
 
 	root, err := ts.Root()
 	require.NoError(t, err)
-	err = storage.StoreTrie(ts, nil)
+	err = storage.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	time.Sleep(time.Millisecond * 100)
@@ -128,7 +128,7 @@ func TestStorage_LoadFromDB(t *testing.T) {
 	require.NoError(t, err)
 
 	// Write trie to disk.
-	err = storage.StoreTrie(ts, nil)
+	err = storage.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 
 	// Clear trie from cache and fetch data from disk.
@@ -160,11 +160,52 @@ func TestStorage_StoreTrie_NotSyncing(t *testing.T) {
 	value := []byte("testvalue")
 	ts.Set(key, value)
 
-	err = storage.StoreTrie(ts, nil)
+	err = storage.StoreTrie(ts, nil, trie.V0)
 	require.NoError(t, err)
 	require.Equal(t, 2, storage.blockState.tries.len())
 }
 
+func TestStorage_GenerateChildTrieProof(t *testing.T) {
+	storage := newTestStorageState(t, newTriesEmpty())
+	ts, err := storage.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+
+	ts.Set([]byte("key1"), []byte("value1"))
+
+	childTrie := trie.NewEmptyTrie()
+	childTrie.Put([]byte("childKey1"), []byte("childValue1"))
+	childTrie.Put([]byte("childKey2"), []byte("childValue2"))
+	err = ts.SetChild([]byte("keyToChild"), childTrie)
+	require.NoError(t, err)
+
+	root, err := ts.Root()
+	require.NoError(t, err)
+
+	err = storage.StoreTrie(ts, nil, trie.V0)
+	require.NoError(t, err)
+
+	// Clear trie from cache so the child trie is loaded from disk.
+	storage.blockState.tries.delete(root)
+
+	proof, err := storage.GenerateChildTrieProof(root, []byte("keyToChild"), [][]byte{[]byte("childKey1")})
+	require.NoError(t, err)
+
+	childRoot, err := childTrie.Hash()
+	require.NoError(t, err)
+
+	childStorageKey := append(append([]byte{}, trie.ChildStorageKeyPrefix...), []byte("keyToChild")...)
+	ok, err := trie.VerifyProof(proof, root[:], []trie.Pair{{Key: childStorageKey, Value: childRoot[:]}})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = trie.VerifyProof(proof, childRoot[:], []trie.Pair{{Key: []byte("childKey1"), Value: []byte("childValue1")}})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = storage.GenerateChildTrieProof(root, []byte("unknown"), nil)
+	require.ErrorIs(t, err, trie.ErrChildTrieDoesNotExist)
+}
+
 func TestGetStorageChildAndGetStorageFromChild(t *testing.T) {
 	// initialise database using data directory
 	basepath := t.TempDir()
@@ -207,7 +248,7 @@ func TestGetStorageChildAndGetStorageFromChild(t *testing.T) {
 		common.Hash{}, 1, types.NewDigest())
 	require.NoError(t, err)
 
-	err = storage.StoreTrie(trieState, header)
+	err = storage.StoreTrie(trieState, header, trie.V0)
 	require.NoError(t, err)
 
 	rootHash, err := genTrie.Hash()
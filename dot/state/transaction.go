@@ -4,6 +4,7 @@
 package state
 
 import (
+	"bytes"
 	"sync"
 
 	"github.com/ChainSafe/gossamer/dot/telemetry"
@@ -20,7 +21,7 @@ type TransactionState struct {
 
 	// notifierChannels are used to notify transaction status. It maps a channel to
 	// hex string of the extrinsic it is supposed to notify about.
-	notifierChannels map[chan transaction.Status]string
+	notifierChannels map[chan transaction.StatusNotification]string
 	notifierLock     sync.RWMutex
 
 	telemetry telemetry.Client
@@ -31,17 +32,61 @@ func NewTransactionState(telemetry telemetry.Client) *TransactionState {
 	return &TransactionState{
 		queue:            transaction.NewPriorityQueue(),
 		pool:             transaction.NewPool(),
-		notifierChannels: make(map[chan transaction.Status]string),
+		notifierChannels: make(map[chan transaction.StatusNotification]string),
 		telemetry:        telemetry,
 	}
 }
 
-// Push pushes a transaction to the queue, ordered by priority
+// Push pushes a transaction to the queue, ordered by priority.
+// Transactions in the queue with a lower priority that provide any of the
+// tags provided by the new transaction are replaced by it.
 func (s *TransactionState) Push(vt *transaction.ValidTransaction) (common.Hash, error) {
-	s.notifyStatus(vt.Extrinsic, transaction.Ready)
+	s.removeUsurped(vt)
+	s.notifyStatus(vt.Extrinsic, transaction.StatusNotification{Status: transaction.Ready})
 	return s.queue.Push(vt)
 }
 
+// removeUsurped removes the transactions in the queue that are usurped by the given transaction,
+// that is transactions with a lower priority providing at least one of the same tags.
+func (s *TransactionState) removeUsurped(vt *transaction.ValidTransaction) {
+	if vt.Validity == nil || len(vt.Validity.Provides) == 0 {
+		return
+	}
+
+	hash := vt.Extrinsic.Hash()
+	for _, pending := range s.queue.Pending() {
+		if pending.Validity == nil || pending.Validity.Priority >= vt.Validity.Priority {
+			continue
+		}
+
+		if !providesAnyTag(pending.Validity.Provides, vt.Validity.Provides) {
+			continue
+		}
+
+		if pending.Extrinsic.Hash() == hash {
+			continue
+		}
+
+		s.queue.RemoveExtrinsic(pending.Extrinsic)
+		s.notifyStatus(pending.Extrinsic, transaction.StatusNotification{
+			Status: transaction.Usurped,
+			Hash:   &hash,
+		})
+	}
+}
+
+func providesAnyTag(provides, tags [][]byte) bool {
+	for _, provided := range provides {
+		for _, tag := range tags {
+			if bytes.Equal(provided, tag) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // Pop removes and returns the head of the queue
 func (s *TransactionState) Pop() *transaction.ValidTransaction {
 	return s.queue.Pop()
@@ -74,6 +119,24 @@ func (s *TransactionState) RemoveExtrinsic(ext types.Extrinsic) {
 	s.queue.RemoveExtrinsic(ext)
 }
 
+// RemoveExtrinsicByHash removes the extrinsic with the given hash from the queue and pool,
+// and notifies its watchers that it was dropped. It returns false if no pending extrinsic
+// has the given hash.
+func (s *TransactionState) RemoveExtrinsicByHash(hash common.Hash) bool {
+	tx := s.pool.Get(hash)
+	if tx == nil {
+		tx = s.queue.Get(hash)
+	}
+
+	if tx == nil {
+		return false
+	}
+
+	s.RemoveExtrinsic(tx.Extrinsic)
+	s.notifyStatus(tx.Extrinsic, transaction.StatusNotification{Status: transaction.Dropped})
+	return true
+}
+
 // RemoveExtrinsicFromPool removes an extrinsic from the pool
 func (s *TransactionState) RemoveExtrinsicFromPool(ext types.Extrinsic) {
 	s.pool.Remove(ext.Hash())
@@ -81,7 +144,7 @@ func (s *TransactionState) RemoveExtrinsicFromPool(ext types.Extrinsic) {
 
 // AddToPool adds a transaction to the pool
 func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) common.Hash {
-	s.notifyStatus(vt.Extrinsic, transaction.Future)
+	s.notifyStatus(vt.Extrinsic, transaction.StatusNotification{Status: transaction.Future})
 
 	hash := s.pool.Insert(vt)
 
@@ -93,24 +156,31 @@ func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) common.Ha
 }
 
 // GetStatusNotifierChannel creates and returns a status notifier channel.
-func (s *TransactionState) GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status {
+func (s *TransactionState) GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.StatusNotification {
 	s.notifierLock.Lock()
 	defer s.notifierLock.Unlock()
 
-	ch := make(chan transaction.Status, defaultBufferSize)
+	ch := make(chan transaction.StatusNotification, defaultBufferSize)
 	s.notifierChannels[ch] = ext.String()
 	return ch
 }
 
 // FreeStatusNotifierChannel deletes given status notifier channel from our map.
-func (s *TransactionState) FreeStatusNotifierChannel(ch chan transaction.Status) {
+func (s *TransactionState) FreeStatusNotifierChannel(ch chan transaction.StatusNotification) {
 	s.notifierLock.Lock()
 	defer s.notifierLock.Unlock()
 
 	delete(s.notifierChannels, ch)
 }
 
-func (s *TransactionState) notifyStatus(ext types.Extrinsic, status transaction.Status) {
+// NotifyStatus notifies the watchers of the given extrinsic of a status update
+// that happened outside of the transaction state, such as the extrinsic being
+// broadcast, included in a block or retracted.
+func (s *TransactionState) NotifyStatus(ext types.Extrinsic, notification transaction.StatusNotification) {
+	s.notifyStatus(ext, notification)
+}
+
+func (s *TransactionState) notifyStatus(ext types.Extrinsic, notification transaction.StatusNotification) {
 	s.notifierLock.Lock()
 	defer s.notifierLock.Unlock()
 
@@ -124,11 +194,11 @@ func (s *TransactionState) notifyStatus(ext types.Extrinsic, status transaction.
 			continue
 		}
 		wg.Add(1)
-		go func(ch chan transaction.Status) {
+		go func(ch chan transaction.StatusNotification) {
 			defer wg.Done()
 
 			select {
-			case ch <- status:
+			case ch <- notification:
 			default:
 			}
 		}(ch)
@@ -0,0 +1,329 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+var (
+	indexedTransactionPrefix = []byte("itx") // indexedTransactionPrefix + content hash -> indexed transaction
+	transactionIndexPrefix   = []byte("tix") // transactionIndexPrefix + hash -> content hashes referenced by the block
+	// transactionExpiryPrefix + number -> hashes of the blocks whose references expire once the number is finalised
+	transactionExpiryPrefix = []byte("txe")
+)
+
+// indexedTransaction is data indexed by a transaction, and the number of references to
+// the data by the blocks which indexed or renewed it.
+type indexedTransaction struct {
+	References uint32
+	Data       []byte
+}
+
+// GetIndexedTransaction returns the data indexed by a transaction, given the hash of the data
+func (bs *BlockState) GetIndexedTransaction(hash common.Hash) ([]byte, error) {
+	tx, err := bs.getIndexedTransaction(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx == nil {
+		return nil, chaindb.ErrKeyNotFound
+	}
+
+	return tx.Data, nil
+}
+
+// GetIndexedBody returns the data indexed or renewed by the transactions of the block, in the order
+// of the transactions. It returns no data if the block did not index any data, or if the references
+// of the block to the data expired.
+func (bs *BlockState) GetIndexedBody(hash common.Hash) ([][]byte, error) {
+	hashes, err := bs.getTransactionIndex(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	body := make([][]byte, 0, len(hashes))
+	for _, contentHash := range hashes {
+		tx, err := bs.getIndexedTransaction(contentHash)
+		if err != nil {
+			return nil, err
+		}
+
+		if tx == nil {
+			return nil, fmt.Errorf("indexed transaction %s of block %s not found", contentHash, hash)
+		}
+
+		body = append(body, tx.Data)
+	}
+
+	return body, nil
+}
+
+// SetTransactionIndex stores the data indexed by the transactions of the block, and references the
+// data renewed by them. The data is kept until the block is pruned, or until the block is finalised
+// since more than the storage period, which is the number of blocks defined by the runtime.
+func (bs *BlockState) SetTransactionIndex(block *types.Block, operations []rtstorage.TransactionIndexOperation,
+	storagePeriod uint) error {
+	bs.Lock()
+	defer bs.Unlock()
+
+	hash := block.Header.Hash()
+	has, err := bs.db.Has(prefixKey(hash, transactionIndexPrefix))
+	if err != nil {
+		return err
+	}
+
+	if has {
+		// the block was already imported
+		return nil
+	}
+
+	var operationHashes []common.Hash
+	references := make(map[common.Hash]uint32)
+	indexed := make(map[common.Hash][]byte)
+	for _, operation := range operations {
+		if int(operation.Extrinsic) >= len(block.Body) {
+			logger.Debugf("ignoring transaction index operation of block %s for non existent extrinsic %d",
+				hash, operation.Extrinsic)
+			continue
+		}
+
+		if !operation.Renew {
+			// the data is at the end of the extrinsic
+			extrinsic := block.Body[operation.Extrinsic]
+			if int(operation.Size) > len(extrinsic) {
+				logger.Debugf("ignoring transaction index operation of block %s indexing %d bytes of extrinsic %d",
+					hash, operation.Size, operation.Extrinsic)
+				continue
+			}
+
+			indexed[operation.Hash] = extrinsic[len(extrinsic)-int(operation.Size):]
+		}
+
+		operationHashes = append(operationHashes, operation.Hash)
+		references[operation.Hash]++
+	}
+
+	batch := bs.db.NewBatch()
+	unknown := make(map[common.Hash]struct{})
+	for contentHash, count := range references {
+		tx, err := bs.getIndexedTransaction(contentHash)
+		if err != nil {
+			return err
+		}
+
+		if tx == nil {
+			data, ok := indexed[contentHash]
+			if !ok {
+				logger.Debugf("ignoring renewal of unknown indexed transaction %s by block %s", contentHash, hash)
+				unknown[contentHash] = struct{}{}
+				continue
+			}
+
+			tx = &indexedTransaction{Data: data}
+		}
+
+		tx.References += count
+		err = putIndexedTransaction(batch, contentHash, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the content hashes are kept in the order of the transactions for the indexed body of the block
+	hashes := make([]common.Hash, 0, len(operationHashes))
+	for _, contentHash := range operationHashes {
+		if _, ok := unknown[contentHash]; !ok {
+			hashes = append(hashes, contentHash)
+		}
+	}
+
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	enc, err := scale.Marshal(hashes)
+	if err != nil {
+		return err
+	}
+
+	err = batch.Put(prefixKey(hash, transactionIndexPrefix), enc)
+	if err != nil {
+		return err
+	}
+
+	expiryKey := transactionExpiryKey(block.Header.Number + storagePeriod)
+	expiring, err := bs.getBlockHashes(expiryKey)
+	if err != nil {
+		return err
+	}
+
+	enc, err = scale.Marshal(append(expiring, hash))
+	if err != nil {
+		return err
+	}
+
+	err = batch.Put(expiryKey, enc)
+	if err != nil {
+		return err
+	}
+
+	return batch.Flush()
+}
+
+// releaseTransactionIndex releases the references of the blocks to the data indexed or renewed by
+// their transactions. The data is deleted once no block references it anymore. The references of a
+// block given several times are released once, and the references released by all the blocks are
+// accumulated, since the batch is only written after reading the references of each data.
+// It must be called with the block state lock held, as in SetTransactionIndex.
+func (bs *BlockState) releaseTransactionIndex(batch chaindb.Batch, blockHashes []common.Hash) error {
+	released := make(map[common.Hash]struct{}, len(blockHashes))
+	references := make(map[common.Hash]uint32)
+	for _, hash := range blockHashes {
+		if _, ok := released[hash]; ok {
+			continue
+		}
+		released[hash] = struct{}{}
+
+		hashes, err := bs.getTransactionIndex(hash)
+		if err != nil {
+			return err
+		}
+
+		if hashes == nil {
+			continue
+		}
+
+		for _, contentHash := range hashes {
+			references[contentHash]++
+		}
+
+		err = batch.Del(prefixKey(hash, transactionIndexPrefix))
+		if err != nil {
+			return err
+		}
+	}
+
+	for contentHash, count := range references {
+		tx, err := bs.getIndexedTransaction(contentHash)
+		if err != nil {
+			return err
+		}
+
+		if tx == nil {
+			continue
+		}
+
+		if tx.References <= count {
+			err = batch.Del(prefixKey(contentHash, indexedTransactionPrefix))
+		} else {
+			tx.References -= count
+			err = putIndexedTransaction(batch, contentHash, tx)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pruneTransactionIndex releases the references of the pruned blocks, and of the blocks finalised
+// since more than their storage period, to the data indexed by their transactions.
+// It must be called with the block state lock held.
+func (bs *BlockState) pruneTransactionIndex(pruned []common.Hash, prevFinalised, finalised uint) error {
+	batch := bs.db.NewBatch()
+	released := append([]common.Hash(nil), pruned...)
+	for number := prevFinalised + 1; number <= finalised; number++ {
+		expiryKey := transactionExpiryKey(number)
+		expired, err := bs.getBlockHashes(expiryKey)
+		if err != nil {
+			return err
+		}
+
+		// a pruned block may also expire, and is then only released once
+		released = append(released, expired...)
+
+		err = batch.Del(expiryKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := bs.releaseTransactionIndex(batch, released)
+	if err != nil {
+		return err
+	}
+
+	return batch.Flush()
+}
+
+// transactionExpiryKey = transactionExpiryPrefix + number (uint64 big endian)
+func transactionExpiryKey(number uint) []byte {
+	return append(transactionExpiryPrefix, encodeBlockNumber(uint64(number))...)
+}
+
+// getTransactionIndex returns the content hashes referenced by the transactions of the block,
+// or nil if the block does not reference any data.
+func (bs *BlockState) getTransactionIndex(hash common.Hash) ([]common.Hash, error) {
+	hashes, err := bs.getBlockHashes(prefixKey(hash, transactionIndexPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get transaction index of block %s: %w", hash, err)
+	}
+
+	return hashes, nil
+}
+
+// getBlockHashes returns the SCALE encoded hashes stored at the key, or nil if the key is not found.
+func (bs *BlockState) getBlockHashes(key []byte) ([]common.Hash, error) {
+	enc, err := bs.db.Get(key)
+	if errors.Is(err, chaindb.ErrKeyNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var hashes []common.Hash
+	err = scale.Unmarshal(enc, &hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+func (bs *BlockState) getIndexedTransaction(hash common.Hash) (*indexedTransaction, error) {
+	enc, err := bs.db.Get(prefixKey(hash, indexedTransactionPrefix))
+	if errors.Is(err, chaindb.ErrKeyNotFound) {
+		return nil, nil //nolint:nilnil
+	} else if err != nil {
+		return nil, err
+	}
+
+	tx := new(indexedTransaction)
+	err = scale.Unmarshal(enc, tx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode indexed transaction %s: %w", hash, err)
+	}
+
+	return tx, nil
+}
+
+func putIndexedTransaction(batch chaindb.Batch, hash common.Hash, tx *indexedTransaction) error {
+	enc, err := scale.Marshal(*tx)
+	if err != nil {
+		return err
+	}
+
+	return batch.Put(prefixKey(hash, indexedTransactionPrefix), enc)
+}
@@ -0,0 +1,261 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockState_SetTransactionIndex(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader, newTriesEmpty())
+
+	hash1, hash2 := common.Hash{1}, common.Hash{2}
+	block1 := &types.Block{
+		Header: types.Header{Number: 1},
+		Body:   types.Body{{1, 2, 3, 4}, {5, 6}},
+	}
+	block2 := &types.Block{
+		Header: types.Header{Number: 2},
+		Body:   types.Body{{7}},
+	}
+
+	_, err := bs.GetIndexedTransaction(hash1)
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+
+	err = bs.SetTransactionIndex(block1, []rtstorage.TransactionIndexOperation{
+		{Extrinsic: 0, Hash: hash1, Size: 2},
+		{Extrinsic: 1, Hash: hash2, Size: 3}, // larger than the extrinsic
+		{Extrinsic: 2, Hash: hash2, Size: 1}, // non existent extrinsic
+	}, 100)
+	require.NoError(t, err)
+
+	data, err := bs.GetIndexedTransaction(hash1)
+	require.NoError(t, err)
+	require.Equal(t, []byte{3, 4}, data)
+
+	body, err := bs.GetIndexedBody(block1.Header.Hash())
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{3, 4}}, body)
+
+	_, err = bs.GetIndexedTransaction(hash2)
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+
+	// the operations of an already imported block are ignored
+	err = bs.SetTransactionIndex(block1, []rtstorage.TransactionIndexOperation{
+		{Extrinsic: 1, Hash: hash2, Size: 2},
+	}, 100)
+	require.NoError(t, err)
+
+	_, err = bs.GetIndexedTransaction(hash2)
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+
+	err = bs.SetTransactionIndex(block2, []rtstorage.TransactionIndexOperation{
+		{Extrinsic: 0, Hash: hash1, Renew: true},
+		{Extrinsic: 0, Hash: hash2, Renew: true}, // unknown data
+	}, 100)
+	require.NoError(t, err)
+
+	body, err = bs.GetIndexedBody(block2.Header.Hash())
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{3, 4}}, body)
+
+	batch := bs.db.NewBatch()
+	err = bs.releaseTransactionIndex(batch, []common.Hash{block1.Header.Hash()})
+	require.NoError(t, err)
+	require.NoError(t, batch.Flush())
+
+	// the data is still referenced by the second block
+	data, err = bs.GetIndexedTransaction(hash1)
+	require.NoError(t, err)
+	require.Equal(t, []byte{3, 4}, data)
+
+	batch = bs.db.NewBatch()
+	err = bs.releaseTransactionIndex(batch, []common.Hash{block2.Header.Hash()})
+	require.NoError(t, err)
+	require.NoError(t, batch.Flush())
+
+	_, err = bs.GetIndexedTransaction(hash1)
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+
+	body, err = bs.GetIndexedBody(block2.Header.Hash())
+	require.NoError(t, err)
+	require.Empty(t, body)
+}
+
+func TestBlockState_SetFinalisedHash_pruneTransactionIndex(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader, newTriesEmpty())
+
+	chain, _ := AddBlocksToState(t, bs, 3, false)
+
+	digest := types.NewDigest()
+	preDigest, err := types.NewBabeSecondaryPlainPreDigest(1, 1).ToPreRuntimeDigest()
+	require.NoError(t, err)
+	err = digest.Add(*preDigest)
+	require.NoError(t, err)
+
+	branch := &types.Block{
+		Header: types.Header{
+			ParentHash: testGenesisHeader.Hash(),
+			Number:     1,
+			StateRoot:  trie.EmptyHash,
+			Digest:     digest,
+		},
+		Body: types.Body{{2}},
+	}
+	err = bs.AddBlock(branch)
+	require.NoError(t, err)
+
+	canonical, fork := common.Hash{1}, common.Hash{2}
+	err = bs.SetTransactionIndex(&types.Block{Header: *chain[0], Body: types.Body{{1}}},
+		[]rtstorage.TransactionIndexOperation{{Hash: canonical, Size: 1}}, 2)
+	require.NoError(t, err)
+
+	err = bs.SetTransactionIndex(branch, []rtstorage.TransactionIndexOperation{{Hash: fork, Size: 1}}, 2)
+	require.NoError(t, err)
+
+	err = bs.SetFinalisedHash(chain[1].Hash(), 1, 0)
+	require.NoError(t, err)
+
+	// the data of the pruned block is deleted
+	_, err = bs.GetIndexedTransaction(fork)
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+
+	data, err := bs.GetIndexedTransaction(canonical)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, data)
+
+	err = bs.SetFinalisedHash(chain[2].Hash(), 2, 0)
+	require.NoError(t, err)
+
+	// the block is finalised since more than its storage period
+	_, err = bs.GetIndexedTransaction(canonical)
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+}
+
+func TestBlockState_releaseTransactionIndex_sameData(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader, newTriesEmpty())
+
+	contentHash := common.Hash{1}
+	blocks := make([]*types.Block, 3)
+	for i := range blocks {
+		blocks[i] = &types.Block{
+			Header: types.Header{Number: uint(i + 1)},
+			Body:   types.Body{{1, 2}},
+		}
+		// the first block indexes the data, and the next blocks renew it
+		err := bs.SetTransactionIndex(blocks[i], []rtstorage.TransactionIndexOperation{
+			{Hash: contentHash, Size: 2, Renew: i > 0},
+		}, 100)
+		require.NoError(t, err)
+	}
+
+	// the references of both blocks are released in one batch, and the
+	// references of the block given twice are only released once
+	batch := bs.db.NewBatch()
+	err := bs.releaseTransactionIndex(batch, []common.Hash{
+		blocks[0].Header.Hash(), blocks[1].Header.Hash(), blocks[0].Header.Hash(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, batch.Flush())
+
+	tx, err := bs.getIndexedTransaction(contentHash)
+	require.NoError(t, err)
+	require.Equal(t, &indexedTransaction{References: 1, Data: []byte{1, 2}}, tx)
+
+	batch = bs.db.NewBatch()
+	err = bs.releaseTransactionIndex(batch, []common.Hash{blocks[2].Header.Hash()})
+	require.NoError(t, err)
+	require.NoError(t, batch.Flush())
+
+	_, err = bs.GetIndexedTransaction(contentHash)
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+}
+
+func TestBlockState_SetFinalisedHash_pruneTransactionIndex_renewedByFork(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader, newTriesEmpty())
+
+	chain, _ := AddBlocksToState(t, bs, 4, false)
+
+	newBranchBlock := func(parentHash common.Hash, number uint, slot uint64) *types.Block {
+		digest := types.NewDigest()
+		preDigest, err := types.NewBabeSecondaryPlainPreDigest(1, slot).ToPreRuntimeDigest()
+		require.NoError(t, err)
+		err = digest.Add(*preDigest)
+		require.NoError(t, err)
+
+		block := &types.Block{
+			Header: types.Header{
+				ParentHash: parentHash,
+				Number:     number,
+				StateRoot:  trie.EmptyHash,
+				Digest:     digest,
+			},
+			Body: types.Body{{1}},
+		}
+		err = bs.AddBlock(block)
+		require.NoError(t, err)
+		return block
+	}
+
+	// genesis <- branch1 <- branch2 both renew the data indexed by the first canonical block,
+	// and are both pruned and expired on the finalisation of the second canonical block.
+	branch1 := newBranchBlock(testGenesisHeader.Hash(), 1, 100)
+	branch2 := newBranchBlock(branch1.Header.Hash(), 2, 101)
+
+	contentHash := common.Hash{1}
+	err := bs.SetTransactionIndex(&types.Block{Header: *chain[0], Body: types.Body{{1}}},
+		[]rtstorage.TransactionIndexOperation{{Hash: contentHash, Size: 1}}, 3)
+	require.NoError(t, err)
+
+	err = bs.SetTransactionIndex(branch1, []rtstorage.TransactionIndexOperation{{Hash: contentHash, Renew: true}}, 1)
+	require.NoError(t, err)
+	err = bs.SetTransactionIndex(branch2, []rtstorage.TransactionIndexOperation{{Hash: contentHash, Renew: true}}, 0)
+	require.NoError(t, err)
+
+	err = bs.SetFinalisedHash(chain[1].Hash(), 1, 0)
+	require.NoError(t, err)
+
+	tx, err := bs.getIndexedTransaction(contentHash)
+	require.NoError(t, err)
+	require.Equal(t, &indexedTransaction{References: 1, Data: []byte{1}}, tx)
+
+	err = bs.SetFinalisedHash(chain[3].Hash(), 2, 0)
+	require.NoError(t, err)
+
+	// the first canonical block is finalised since more than its storage period
+	_, err = bs.GetIndexedTransaction(contentHash)
+	require.ErrorIs(t, err, chaindb.ErrKeyNotFound)
+}
+
+func TestBlockState_GetIndexedBody(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader, newTriesEmpty())
+
+	block := &types.Block{
+		Header: types.Header{Number: 1},
+		Body:   types.Body{{1, 2}, {3, 4}, {5, 6}},
+	}
+
+	// the indexed body follows the order of the transaction index operations
+	err := bs.SetTransactionIndex(block, []rtstorage.TransactionIndexOperation{
+		{Extrinsic: 2, Hash: common.Hash{3}, Size: 1},
+		{Extrinsic: 0, Hash: common.Hash{1}, Size: 2},
+		{Extrinsic: 1, Hash: common.Hash{2}, Size: 1},
+	}, 1)
+	require.NoError(t, err)
+
+	body, err := bs.GetIndexedBody(block.Header.Hash())
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{6}, {1, 2}, {4}}, body)
+
+	body, err = bs.GetIndexedBody(common.Hash{4})
+	require.NoError(t, err)
+	require.Empty(t, body)
+}
@@ -113,11 +113,11 @@ func TestTransactionState_NotifierChannels(t *testing.T) {
 	time.Sleep(1 * time.Second)
 	close(notifierChannel)
 
-	for status := range notifierChannel {
-		if status.String() == transaction.Future.String() {
+	for notification := range notifierChannel {
+		if notification.Status == transaction.Future {
 			futureCount++
 		}
-		if status.String() == transaction.Ready.String() {
+		if notification.Status == transaction.Ready {
 			readyCount++
 		}
 	}
@@ -125,3 +125,83 @@ func TestTransactionState_NotifierChannels(t *testing.T) {
 	require.Equal(t, expectedFutureCount, futureCount)
 	require.Equal(t, expectedReadyCount, readyCount)
 }
+
+func TestTransactionState_RemoveExtrinsicByHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	ts := NewTransactionState(telemetryMock)
+
+	inPool := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic("a"),
+		Validity:  &transaction.Validity{Priority: 1},
+	}
+	inQueue := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic("b"),
+		Validity:  &transaction.Validity{Priority: 1},
+	}
+
+	ts.AddToPool(inPool)
+	_, err := ts.Push(inQueue)
+	require.NoError(t, err)
+
+	notifierChannel := ts.GetStatusNotifierChannel(inQueue.Extrinsic)
+	defer ts.FreeStatusNotifierChannel(notifierChannel)
+
+	removed := ts.RemoveExtrinsicByHash(inPool.Extrinsic.Hash())
+	require.True(t, removed)
+	require.False(t, ts.Exists(inPool.Extrinsic))
+
+	removed = ts.RemoveExtrinsicByHash(inQueue.Extrinsic.Hash())
+	require.True(t, removed)
+	require.False(t, ts.Exists(inQueue.Extrinsic))
+
+	removed = ts.RemoveExtrinsicByHash(common.Hash{1})
+	require.False(t, removed)
+
+	expected := transaction.StatusNotification{Status: transaction.Dropped}
+	require.Equal(t, expected, <-notifierChannel)
+}
+
+func TestTransactionState_PushUsurped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	ts := NewTransactionState(telemetryMock)
+
+	usurped := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic("a"),
+		Validity:  &transaction.Validity{Priority: 1, Provides: [][]byte{{1}}},
+	}
+	unrelated := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic("b"),
+		Validity:  &transaction.Validity{Priority: 1, Provides: [][]byte{{2}}},
+	}
+	usurper := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic("c"),
+		Validity:  &transaction.Validity{Priority: 2, Provides: [][]byte{{1}}},
+	}
+
+	for _, tx := range []*transaction.ValidTransaction{usurped, unrelated} {
+		_, err := ts.Push(tx)
+		require.NoError(t, err)
+	}
+
+	notifierChannel := ts.GetStatusNotifierChannel(usurped.Extrinsic)
+	defer ts.FreeStatusNotifierChannel(notifierChannel)
+
+	usurperHash, err := ts.Push(usurper)
+	require.NoError(t, err)
+
+	require.False(t, ts.Exists(usurped.Extrinsic))
+	require.True(t, ts.Exists(unrelated.Extrinsic))
+	require.True(t, ts.Exists(usurper.Extrinsic))
+
+	expected := transaction.StatusNotification{
+		Status: transaction.Usurped,
+		Hash:   &usurperHash,
+	}
+	require.Equal(t, expected, <-notifierChannel)
+}
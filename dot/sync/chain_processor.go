@@ -215,17 +215,26 @@ func (s *chainProcessor) handleBlock(block *types.Block) error {
 		return err
 	}
 
-	root := ts.MustRoot()
-	if !bytes.Equal(parent.StateRoot[:], root[:]) {
-		panic("parent state root does not match snapshot state root")
-	}
-
 	hash := parent.Hash()
 	rt, err := s.blockState.GetRuntime(&hash)
 	if err != nil {
 		return err
 	}
 
+	stateVersion, err := rt.StateVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get state version: %w", err)
+	}
+
+	root, err := ts.RootWithVersion(stateVersion)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(parent.StateRoot[:], root[:]) {
+		panic("parent state root does not match snapshot state root")
+	}
+
 	rt.SetContextStorage(ts)
 
 	_, err = rt.ExecuteBlock(block)
@@ -81,6 +81,29 @@ func Test_chainProcessor_handleBlock(t *testing.T) {
 			},
 			wantErr: mockError,
 		},
+		"handle state version error": {
+			chainProcessorBuilder: func(ctrl *gomock.Controller) (chainProcessor chainProcessor) {
+				trieState := newTrieState(t)
+				mockBlockState := NewMockBlockState(ctrl)
+				mockBlockState.EXPECT().GetHeader(common.Hash{}).Return(&types.Header{
+					StateRoot: testHash,
+				}, nil)
+				mockInstance := NewMockInstance(ctrl)
+				mockInstance.EXPECT().StateVersion().Return(trie.V0, mockError)
+				mockBlockState.EXPECT().GetRuntime(&testParentHash).Return(mockInstance, nil)
+				chainProcessor.blockState = mockBlockState
+				mockStorageState := NewMockStorageState(ctrl)
+				mockStorageState.EXPECT().Lock()
+				mockStorageState.EXPECT().TrieState(&testHash).Return(trieState, nil)
+				mockStorageState.EXPECT().Unlock()
+				chainProcessor.storageState = mockStorageState
+				return
+			},
+			block: &types.Block{
+				Body: types.Body{},
+			},
+			wantErr: mockError,
+		},
 		"handle runtime ExecuteBlock error": {
 			chainProcessorBuilder: func(ctrl *gomock.Controller) (chainProcessor chainProcessor) {
 				trieState := newTrieState(t)
@@ -89,6 +112,7 @@ func Test_chainProcessor_handleBlock(t *testing.T) {
 					StateRoot: testHash,
 				}, nil)
 				mockInstance := NewMockInstance(ctrl)
+				mockInstance.EXPECT().StateVersion().Return(trie.V0, nil)
 				mockInstance.EXPECT().SetContextStorage(trieState)
 				mockInstance.EXPECT().ExecuteBlock(&types.Block{Body: types.Body{}}).Return(nil, mockError)
 				mockBlockState.EXPECT().GetRuntime(&testParentHash).Return(mockInstance, nil)
@@ -113,6 +137,7 @@ func Test_chainProcessor_handleBlock(t *testing.T) {
 					StateRoot: testHash,
 				}, nil)
 				mockInstance := NewMockInstance(ctrl)
+				mockInstance.EXPECT().StateVersion().Return(trie.V0, nil)
 				mockInstance.EXPECT().SetContextStorage(trieState)
 				mockInstance.EXPECT().ExecuteBlock(&types.Block{Body: types.Body{}}).Return(nil, nil)
 				mockBlockState.EXPECT().GetRuntime(&testParentHash).Return(mockInstance, nil)
@@ -147,6 +172,7 @@ func Test_chainProcessor_handleBlock(t *testing.T) {
 				mockHeaderHash := mockHeader.Hash()
 				mockBlockState.EXPECT().GetHeader(common.Hash{}).Return(mockHeader, nil)
 				mockInstance := NewMockInstance(ctrl)
+				mockInstance.EXPECT().StateVersion().Return(trie.V0, nil)
 				mockInstance.EXPECT().SetContextStorage(trieState)
 				mockInstance.EXPECT().ExecuteBlock(mockBlock)
 				mockBlockState.EXPECT().GetRuntime(&mockHeaderHash).Return(mockInstance, nil)
@@ -191,9 +217,14 @@ func Test_chainProcessor_handleBlock(t *testing.T) {
 				ParentHash: common.Hash{1},
 			},
 		}
+		parentHeader := &types.Header{StateRoot: common.Hash{2}}
+		parentHash := parentHeader.Hash()
+		instance := NewMockInstance(ctrl)
+		instance.EXPECT().StateVersion().Return(trie.V0, nil)
 		blockState := NewMockBlockState(ctrl)
 		blockState.EXPECT().GetHeader(common.Hash{1}).
-			Return(&types.Header{StateRoot: common.Hash{2}}, nil)
+			Return(parentHeader, nil)
+		blockState.EXPECT().GetRuntime(&parentHash).Return(instance, nil)
 		trieState := newTrieState(t)
 		storageState := NewMockStorageState(ctrl)
 		lockCall := storageState.EXPECT().Lock()
@@ -616,6 +647,7 @@ func Test_chainProcessor_processBlockData(t *testing.T) {
 				mockTrieState := newTrieState(t)
 				runtimeHash := common.MustHexToHash("0x7db9db5ed9967b80143100189ba69d9e4deab85ac3570e5df25686cabe32964a")
 				mockInstance := NewMockInstance(ctrl)
+				mockInstance.EXPECT().StateVersion().Return(trie.V0, nil)
 				mockInstance.EXPECT().SetContextStorage(mockTrieState)
 				mockInstance.EXPECT().ExecuteBlock(&types.Block{Header: types.Header{}, Body: types.Body{}})
 
@@ -660,6 +692,7 @@ func Test_chainProcessor_processBlockData(t *testing.T) {
 				runtimeHash := common.MustHexToHash("0x7db9db5ed9967b80143100189ba69d9e4deab85ac3570e5df25686cabe32964a")
 				mockTrieState, _ := storage.NewTrieState(nil)
 				mockInstance := NewMockInstance(ctrl)
+				mockInstance.EXPECT().StateVersion().Return(trie.V0, nil)
 				mockInstance.EXPECT().SetContextStorage(mockTrieState)
 				mockInstance.EXPECT().ExecuteBlock(&types.Block{Header: types.Header{}, Body: types.Body{}})
 
@@ -415,5 +415,14 @@ func (s *Service) getBlockData(hash common.Hash, requestedData byte) (*types.Blo
 		}
 	}
 
+	if (requestedData&network.RequestedDataIndexedBody)>>5 == 1 {
+		indexedBody, err := s.blockState.GetIndexedBody(hash)
+		if err != nil {
+			logger.Debugf("failed to get indexed body for block with hash %s: %s", hash, err)
+		} else if indexedBody != nil {
+			blockData.IndexedBody = &indexedBody
+		}
+	}
+
 	return blockData, nil
 }
@@ -409,6 +409,21 @@ type args struct {
 				Justification: &[]byte{3},
 			},
 		},
+		"requestedData RequestedDataIndexedBody": {
+			blockStateBuilder: func(ctrl *gomock.Controller) BlockState {
+				mockBlockState := NewMockBlockState(ctrl)
+				mockBlockState.EXPECT().GetIndexedBody(common.Hash{4}).Return([][]byte{{4}}, nil)
+				return mockBlockState
+			},
+			args: args{
+				hash:          common.Hash{4},
+				requestedData: network.RequestedDataIndexedBody,
+			},
+			want: &types.BlockData{
+				Hash:        common.Hash{4},
+				IndexedBody: &[][]byte{{4}},
+			},
+		},
 	}
 	for name, tt := range tests {
 		tt := tt
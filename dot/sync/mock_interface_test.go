@@ -260,6 +260,21 @@ func (mr *MockBlockStateMockRecorder) GetHighestFinalisedHeader() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestFinalisedHeader", reflect.TypeOf((*MockBlockState)(nil).GetHighestFinalisedHeader))
 }
 
+// GetIndexedBody mocks base method.
+func (m *MockBlockState) GetIndexedBody(arg0 common.Hash) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIndexedBody", arg0)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIndexedBody indicates an expected call of GetIndexedBody.
+func (mr *MockBlockStateMockRecorder) GetIndexedBody(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIndexedBody", reflect.TypeOf((*MockBlockState)(nil).GetIndexedBody), arg0)
+}
+
 // GetJustification mocks base method.
 func (m *MockBlockState) GetJustification(arg0 common.Hash) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -98,7 +98,7 @@ func newTestSyncer(t *testing.T) *Service {
 		"HandleBlockImport", mock.AnythingOfType("*types.Block"), mock.AnythingOfType("*storage.TrieState")).
 		Return(func(block *types.Block, ts *rtstorage.TrieState) error {
 			// store updates state trie nodes in database
-			if err = stateSrvc.Storage.StoreTrie(ts, &block.Header); err != nil {
+			if err = stateSrvc.Storage.StoreTrie(ts, &block.Header, trie.V0); err != nil {
 				logger.Warnf("failed to store state trie for imported block %s: %s", block.Header.Hash(), err)
 				return err
 			}
@@ -0,0 +1,81 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package system
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+)
+
+// LogFilter changes the levels of the node loggers at runtime,
+// and resets them to the levels of the node configuration.
+type LogFilter struct {
+	mu          sync.Mutex
+	globalLevel log.Level
+	levels      map[string]log.Level
+}
+
+// NewLogFilter creates a log filter resetting the loggers to the given global
+// level, and then to the given levels for the targets of the map, such as `sync`.
+func NewLogFilter(globalLevel log.Level, levels map[string]log.Level) *LogFilter {
+	return &LogFilter{
+		globalLevel: globalLevel,
+		levels:      levels,
+	}
+}
+
+// AddLogFilter patches the levels of the loggers with the given comma
+// separated list of directives, such as `sync=trace,network=debug`.
+func (f *LogFilter) AddLogFilter(filter string) error {
+	directives, err := log.ParseFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, directive := range directives {
+		log.PatchTarget(directive.Target, log.SetLevel(directive.Level))
+	}
+
+	return nil
+}
+
+// ResetLogFilter resets the levels of the loggers to the configured levels.
+func (f *LogFilter) ResetLogFilter() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.reset()
+}
+
+// Reload replaces the configured levels with the given levels,
+// and resets the levels of the loggers to these levels.
+func (f *LogFilter) Reload(globalLevel log.Level, levels map[string]log.Level) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.globalLevel = globalLevel
+	f.levels = levels
+	f.reset()
+}
+
+func (f *LogFilter) reset() {
+	log.PatchTarget("", log.SetLevel(f.globalLevel))
+
+	// patch the targets in order, so the loggers of a sub-package
+	// such as `rpc/subscription` are patched after the ones of `rpc`.
+	targets := make([]string, 0, len(f.levels))
+	for target := range f.levels {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		log.PatchTarget(target, log.SetLevel(f.levels[target]))
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package system
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFilter(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	logger := log.NewFromGlobal(log.AddContext("pkg", "logfiltertest"),
+		log.SetWriter(buffer), log.SetLevel(log.Info))
+
+	filter := NewLogFilter(log.Info, map[string]log.Level{"logfiltertest": log.Warn})
+
+	logger.Debug("hidden")
+	assert.Empty(t, buffer.String())
+
+	err := filter.AddLogFilter("logfiltertest=debug")
+	require.NoError(t, err)
+	logger.Debug("debug")
+	assert.Contains(t, buffer.String(), "debug")
+	buffer.Reset()
+
+	err = filter.AddLogFilter("logfiltertest=loud")
+	require.ErrorIs(t, err, log.ErrLevelNotRecognised)
+
+	filter.ResetLogFilter()
+	logger.Info("hidden")
+	assert.Empty(t, buffer.String())
+	logger.Warn("warn")
+	assert.Contains(t, buffer.String(), "warn")
+	buffer.Reset()
+
+	filter.Reload(log.Info, map[string]log.Level{"logfiltertest": log.Info})
+	logger.Info("info")
+	assert.Contains(t, buffer.String(), "info")
+}
+
+func TestService_LogFilter(t *testing.T) {
+	s := NewService(nil, nil, nil)
+	assert.ErrorIs(t, s.AddLogFilter("debug"), ErrNoLogFilter)
+	assert.ErrorIs(t, s.ResetLogFilter(), ErrNoLogFilter)
+}
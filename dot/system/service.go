@@ -4,21 +4,28 @@
 package system
 
 import (
+	"errors"
+
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 )
 
+// ErrNoLogFilter is returned when changing the log levels without log filter.
+var ErrNoLogFilter = errors.New("log filter is not available")
+
 // Service struct to hold rpc service data
 type Service struct {
 	systemInfo  *types.SystemInfo
 	genesisData *genesis.Data
+	logFilter   *LogFilter
 }
 
 // NewService create a new instance of Service
-func NewService(si *types.SystemInfo, gd *genesis.Data) *Service {
+func NewService(si *types.SystemInfo, gd *genesis.Data, lf *LogFilter) *Service {
 	return &Service{
 		systemInfo:  si,
 		genesisData: gd,
+		logFilter:   lf,
 	}
 }
 
@@ -47,6 +54,26 @@ func (s *Service) Properties() map[string]interface{} {
 	return s.genesisData.Properties
 }
 
+// AddLogFilter patches the levels of the node loggers with the given
+// comma separated list of directives, such as `sync=trace,network=debug`.
+func (s *Service) AddLogFilter(filter string) error {
+	if s.logFilter == nil {
+		return ErrNoLogFilter
+	}
+
+	return s.logFilter.AddLogFilter(filter)
+}
+
+// ResetLogFilter resets the levels of the node loggers to the configured levels.
+func (s *Service) ResetLogFilter() error {
+	if s.logFilter == nil {
+		return ErrNoLogFilter
+	}
+
+	s.logFilter.ResetLogFilter()
+	return nil
+}
+
 // Start implements Service interface
 func (s *Service) Start() error {
 	return nil
@@ -60,5 +60,5 @@ func newTestService() *Service {
 	genData := &genesis.Data{
 		Name: "gssmr",
 	}
-	return NewService(sysInfo, genData)
+	return NewService(sysInfo, genData, nil)
 }
@@ -40,3 +40,17 @@ type TransactionPaymentQueryInfo struct {
 	Class      int
 	PartialFee *scale.Uint128
 }
+
+// TransactionPaymentFeeDetails represents the fee details of a given encoded extrinsic
+type TransactionPaymentFeeDetails struct {
+	// InclusionFee is nil for unsigned extrinsics, which do not pay an inclusion fee
+	InclusionFee *TransactionPaymentInclusionFee
+	Tip          *scale.Uint128
+}
+
+// TransactionPaymentInclusionFee is the fee paid for an extrinsic to be included in a block
+type TransactionPaymentInclusionFee struct {
+	BaseFee           *scale.Uint128
+	LenFee            *scale.Uint128
+	AdjustedWeightFee *scale.Uint128
+}
@@ -125,6 +125,8 @@ type args struct {
 					GrandpaAuthority: true,
 					WasmInterpreter:  "wasmer",
 					GrandpaInterval:  1000000000,
+					OffchainWorker:   "when-validating",
+					RuntimePoolSize:  8,
 				},
 				Network: NetworkConfig{
 					Port:              7001,
@@ -0,0 +1,100 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// targetContextKey is the context key identifying the target of a logger.
+const targetContextKey = "pkg"
+
+// ErrFilterDirectiveMalformed is returned when a log filter directive cannot be parsed.
+var ErrFilterDirectiveMalformed = errors.New("log filter directive is malformed")
+
+// FilterDirective sets the level of the loggers of a target, such as `sync`,
+// or of all the loggers if the target is empty.
+type FilterDirective struct {
+	Target string
+	Level  Level
+}
+
+// ParseFilter parses a comma separated list of log filter directives such as
+// `sync=trace,network=debug`. A directive without target, such as `debug`,
+// applies to all the loggers.
+func ParseFilter(filter string) (directives []FilterDirective, err error) {
+	for _, directive := range strings.Split(filter, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+
+		var target, levelString string
+		parts := strings.Split(directive, "=")
+		switch len(parts) {
+		case 1:
+			levelString = parts[0]
+		case 2:
+			target, levelString = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			if target == "" {
+				return nil, fmt.Errorf("%w: %s", ErrFilterDirectiveMalformed, directive)
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrFilterDirectiveMalformed, directive)
+		}
+
+		level, err := ParseLevel(levelString)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse level of directive %s: %w", directive, err)
+		}
+
+		directives = append(directives, FilterDirective{Target: target, Level: level})
+	}
+
+	return directives, nil
+}
+
+// PatchTarget patches the global logger and its descendant loggers having the given
+// target as `pkg` context. The target also matches its sub-packages, for example
+// `rpc` matches `rpc/subscription`. All the loggers are patched if target is empty.
+func PatchTarget(target string, options ...Option) {
+	globalLogger.patchTarget(target, options...)
+}
+
+func (l *Logger) patchTarget(target string, options ...Option) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.patchTargetRecursive(target, options)
+}
+
+// patchTargetRecursive patches the logger and its descendants matching the target.
+// The mutex shared by the logger and its descendants must be locked by the caller.
+func (l *Logger) patchTargetRecursive(target string, options []Option) {
+	if target == "" || l.settings.hasTarget(target) {
+		l.patch(options...)
+	}
+
+	for _, child := range l.childs {
+		child.patchTargetRecursive(target, options)
+	}
+}
+
+func (s *settings) hasTarget(target string) bool {
+	for _, kvs := range s.context {
+		if kvs.key != targetContextKey {
+			continue
+		}
+
+		for _, value := range kvs.values {
+			if value == target || strings.HasPrefix(value, target+"/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseFilter(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		filter     string
+		directives []FilterDirective
+		errWrapped error
+		errMessage string
+	}{
+		"empty filter": {},
+		"targets": {
+			filter: "sync=trace, network=DEBUG,",
+			directives: []FilterDirective{
+				{Target: "sync", Level: Trace},
+				{Target: "network", Level: Debug},
+			},
+		},
+		"all targets": {
+			filter: "warn,rpc=1",
+			directives: []FilterDirective{
+				{Level: Warn},
+				{Target: "rpc", Level: Error},
+			},
+		},
+		"empty target": {
+			filter:     "=trace",
+			errWrapped: ErrFilterDirectiveMalformed,
+			errMessage: "log filter directive is malformed: =trace",
+		},
+		"too many equal signs": {
+			filter:     "sync=trace=debug",
+			errWrapped: ErrFilterDirectiveMalformed,
+			errMessage: "log filter directive is malformed: sync=trace=debug",
+		},
+		"bad level": {
+			filter:     "sync=loud",
+			errWrapped: ErrLevelNotRecognised,
+			errMessage: "cannot parse level of directive sync=loud: level is not recognised: loud",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			directives, err := ParseFilter(testCase.filter)
+
+			assert.Equal(t, testCase.directives, directives)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Logger_patchTarget(t *testing.T) {
+	t.Parallel()
+
+	parent := New(SetLevel(Info))
+	sync := parent.New(AddContext("pkg", "sync"))
+	syncChild := sync.New(AddContext("module", "chain"))
+	rpc := parent.New(AddContext("pkg", "rpc"))
+	subscription := parent.New(AddContext("pkg", "rpc/subscription"))
+	rpcx := parent.New(AddContext("pkg", "rpcx"))
+
+	parent.patchTarget("rpc", SetLevel(Trace))
+	assert.Equal(t, Info, *parent.settings.level)
+	assert.Equal(t, Info, *sync.settings.level)
+	assert.Equal(t, Trace, *rpc.settings.level)
+	assert.Equal(t, Trace, *subscription.settings.level)
+	assert.Equal(t, Info, *rpcx.settings.level)
+
+	parent.patchTarget("sync", SetLevel(Debug))
+	assert.Equal(t, Debug, *sync.settings.level)
+	assert.Equal(t, Debug, *syncChild.settings.level)
+
+	parent.patchTarget("", SetLevel(Warn))
+	for _, logger := range []*Logger{parent, sync, syncChild, rpc, subscription, rpcx} {
+		assert.Equal(t, Warn, *logger.settings.level)
+	}
+}
@@ -20,14 +20,14 @@ type encodingAsyncResult struct {
 	err    error
 }
 
-func runEncodeChild(child *Node, index int,
+func runEncodeChild(child *Node, index, maxInlineValue int,
 	results chan<- encodingAsyncResult, rateLimit <-chan struct{}) {
 	buffer := pools.EncodingBuffers.Get().(*bytes.Buffer)
 	buffer.Reset()
 	// buffer is put back in the pool after processing its
 	// data in the select block below.
 
-	err := encodeChild(child, buffer)
+	err := encodeChild(child, maxInlineValue, buffer)
 
 	results <- encodingAsyncResult{
 		index:  index,
@@ -50,14 +50,14 @@ func runEncodeChild(child *Node, index int,
 // goroutines IF they are less than the parallelLimit number of goroutines already
 // running. This is designed to limit the total number of goroutines in order to
 // avoid using too much memory on the stack.
-func encodeChildrenOpportunisticParallel(children []*Node, buffer io.Writer) (err error) {
+func encodeChildrenOpportunisticParallel(children []*Node, maxInlineValue int, buffer io.Writer) (err error) {
 	// Buffered channels since children might be encoded in this
 	// goroutine or another one.
 	resultsCh := make(chan encodingAsyncResult, ChildrenCapacity)
 
 	for i, child := range children {
 		if child == nil || child.Type() == Leaf {
-			runEncodeChild(child, i, resultsCh, nil)
+			runEncodeChild(child, i, maxInlineValue, resultsCh, nil)
 			continue
 		}
 
@@ -66,11 +66,11 @@ func encodeChildrenOpportunisticParallel(children []*Node, buffer io.Writer) (er
 		case parallelEncodingRateLimit <- struct{}{}:
 			// We have a goroutine available to encode
 			// the branch in parallel.
-			go runEncodeChild(child, i, resultsCh, parallelEncodingRateLimit)
+			go runEncodeChild(child, i, maxInlineValue, resultsCh, parallelEncodingRateLimit)
 		default:
 			// we reached the maximum parallel goroutines
 			// so encode this branch in this goroutine
-			runEncodeChild(child, i, resultsCh, nil)
+			runEncodeChild(child, i, maxInlineValue, resultsCh, nil)
 		}
 	}
 
@@ -115,9 +115,9 @@ func encodeChildrenOpportunisticParallel(children []*Node, buffer io.Writer) (er
 	return err
 }
 
-func encodeChildrenSequentially(children []*Node, buffer io.Writer) (err error) {
+func encodeChildrenSequentially(children []*Node, maxInlineValue int, buffer io.Writer) (err error) {
 	for i, child := range children {
-		err = encodeChild(child, buffer)
+		err = encodeChild(child, maxInlineValue, buffer)
 		if err != nil {
 			return fmt.Errorf("cannot encode child at index %d: %w", i, err)
 		}
@@ -125,12 +125,12 @@ func encodeChildrenSequentially(children []*Node, buffer io.Writer) (err error)
 	return nil
 }
 
-func encodeChild(child *Node, buffer io.Writer) (err error) {
+func encodeChild(child *Node, maxInlineValue int, buffer io.Writer) (err error) {
 	if child == nil {
 		return nil
 	}
 
-	scaleEncodedChildHash, err := scaleEncodeHash(child)
+	scaleEncodedChildHash, err := scaleEncodeHash(child, maxInlineValue)
 	if err != nil {
 		return fmt.Errorf("failed to hash and scale encode child: %w", err)
 	}
@@ -146,12 +146,12 @@ func encodeChild(child *Node, buffer io.Writer) (err error) {
 // scaleEncodeHash hashes the node (blake2b sum on encoded value)
 // and then SCALE encodes it. This is used to encode children
 // nodes of branches.
-func scaleEncodeHash(node *Node) (encoding []byte, err error) {
+func scaleEncodeHash(node *Node, maxInlineValue int) (encoding []byte, err error) {
 	buffer := pools.DigestBuffers.Get().(*bytes.Buffer)
 	buffer.Reset()
 	defer pools.DigestBuffers.Put(buffer)
 
-	err = hashNode(node, buffer)
+	err = hashNode(node, maxInlineValue, buffer)
 	if err != nil {
 		return nil, fmt.Errorf("cannot hash %s: %w", node.Type(), err)
 	}
@@ -164,12 +164,12 @@ func scaleEncodeHash(node *Node) (encoding []byte, err error) {
 	return encoding, nil
 }
 
-func hashNode(node *Node, digestWriter io.Writer) (err error) {
+func hashNode(node *Node, maxInlineValue int, digestWriter io.Writer) (err error) {
 	encodingBuffer := pools.EncodingBuffers.Get().(*bytes.Buffer)
 	encodingBuffer.Reset()
 	defer pools.EncodingBuffers.Put(encodingBuffer)
 
-	err = node.Encode(encodingBuffer)
+	err = node.Encode(encodingBuffer, maxInlineValue)
 	if err != nil {
 		return fmt.Errorf("cannot encode %s: %w", node.Type(), err)
 	}
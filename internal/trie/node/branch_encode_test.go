@@ -154,7 +154,7 @@ func Test_hashNode(t *testing.T) {
 			digestBuffer.EXPECT().Write(testCase.write.written).
 				Return(testCase.write.n, testCase.write.err)
 
-			err := hashNode(testCase.node, digestBuffer)
+			err := hashNode(testCase.node, NoMaxInlineValue, digestBuffer)
 
 			if testCase.errWrapped != nil {
 				assert.ErrorIs(t, err, testCase.errWrapped)
@@ -176,7 +176,7 @@ func Benchmark_encodeChildrenOpportunisticParallel(b *testing.B) {
 
 	b.Run("", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_ = encodeChildrenOpportunisticParallel(children, io.Discard)
+			_ = encodeChildrenOpportunisticParallel(children, NoMaxInlineValue, io.Discard)
 		}
 	})
 }
@@ -309,7 +309,7 @@ func Test_encodeChildrenOpportunisticParallel(t *testing.T) {
 				previousCall = call
 			}
 
-			err := encodeChildrenOpportunisticParallel(testCase.children, buffer)
+			err := encodeChildrenOpportunisticParallel(testCase.children, NoMaxInlineValue, buffer)
 
 			if testCase.wrappedErr != nil {
 				assert.ErrorIs(t, err, testCase.wrappedErr)
@@ -332,7 +332,7 @@ func Test_encodeChildrenOpportunisticParallel(t *testing.T) {
 
 		buffer := bytes.NewBuffer(nil)
 
-		err := encodeChildrenOpportunisticParallel(children, buffer)
+		err := encodeChildrenOpportunisticParallel(children, NoMaxInlineValue, buffer)
 
 		require.NoError(t, err)
 		expectedBytes := []byte{
@@ -434,7 +434,7 @@ func Test_encodeChildrenSequentially(t *testing.T) {
 				previousCall = call
 			}
 
-			err := encodeChildrenSequentially(testCase.children, buffer)
+			err := encodeChildrenSequentially(testCase.children, NoMaxInlineValue, buffer)
 
 			if testCase.wrappedErr != nil {
 				assert.ErrorIs(t, err, testCase.wrappedErr)
@@ -526,7 +526,7 @@ func Test_encodeChild(t *testing.T) {
 					Return(testCase.write.n, testCase.write.err)
 			}
 
-			err := encodeChild(testCase.child, buffer)
+			err := encodeChild(testCase.child, NoMaxInlineValue, buffer)
 
 			if testCase.wrappedErr != nil {
 				assert.ErrorIs(t, err, testCase.wrappedErr)
@@ -574,7 +574,7 @@ func Test_scaleEncodeHash(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			encoding, err := scaleEncodeHash(testCase.node)
+			encoding, err := scaleEncodeHash(testCase.node, NoMaxInlineValue)
 
 			if testCase.wrappedErr != nil {
 				assert.ErrorIs(t, err, testCase.wrappedErr)
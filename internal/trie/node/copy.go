@@ -91,6 +91,7 @@ func (n *Node) Copy(settings CopySettings) *Node {
 	if settings.CopyValue && n.Value != nil {
 		cpy.Value = make([]byte, len(n.Value))
 		copy(cpy.Value, n.Value)
+		cpy.HashedValue = n.HashedValue
 	}
 
 	if settings.CopyCached {
@@ -10,6 +10,7 @@
 	"io"
 
 	"github.com/ChainSafe/gossamer/internal/trie/pools"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
@@ -34,22 +35,24 @@ func Decode(reader io.Reader) (n *Node, err error) {
 	}
 	header := oneByteBuf[0]
 
-	nodeTypeHeaderByte := header >> 6
-	switch nodeTypeHeaderByte {
-	case leafHeader:
+	variant, err := decodeVariant(header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch variant {
+	case leafVariant, leafWithHashedValueVariant:
 		n, err = decodeLeaf(reader, header)
 		if err != nil {
 			return nil, fmt.Errorf("cannot decode leaf: %w", err)
 		}
 		return n, nil
-	case branchHeader, branchWithValueHeader:
+	default: // branch variants
 		n, err = decodeBranch(reader, header)
 		if err != nil {
 			return nil, fmt.Errorf("cannot decode branch: %w", err)
 		}
 		return n, nil
-	default:
-		return nil, fmt.Errorf("%w: %d", ErrUnknownNodeType, nodeTypeHeaderByte)
 	}
 }
 
@@ -64,8 +67,13 @@ func decodeBranch(reader io.Reader, header byte) (node *Node, err error) {
 		Children: make([]*Node, ChildrenCapacity),
 	}
 
-	keyLen := header & keyLenOffset
-	node.Key, err = decodeKey(reader, keyLen)
+	variant, err := decodeVariant(header)
+	if err != nil {
+		return nil, err
+	}
+
+	keyLengthMask := variant.keyLengthMask()
+	node.Key, err = decodeKey(reader, header&keyLengthMask, keyLengthMask)
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode key: %w", err)
 	}
@@ -78,8 +86,8 @@ func decodeBranch(reader io.Reader, header byte) (node *Node, err error) {
 
 	sd := scale.NewDecoder(reader)
 
-	nodeType := header >> 6
-	if nodeType == branchWithValueHeader {
+	switch variant {
+	case branchWithValueVariant:
 		var value []byte
 		// branch w/ value
 		err := sd.Decode(&value)
@@ -87,6 +95,12 @@ func decodeBranch(reader io.Reader, header byte) (node *Node, err error) {
 			return nil, fmt.Errorf("%w: %s", ErrDecodeValue, err)
 		}
 		node.Value = value
+	case branchWithHashedValueVariant:
+		node.Value, err = decodeHashedValue(reader)
+		if err != nil {
+			return nil, err
+		}
+		node.HashedValue = true
 	}
 
 	for i := 0; i < 16; i++ {
@@ -103,8 +117,8 @@ func decodeBranch(reader io.Reader, header byte) (node *Node, err error) {
 
 		// Handle inlined leaf nodes.
 		const hashLength = 32
-		nodeTypeHeaderByte := hash[0] >> 6
-		if nodeTypeHeaderByte == leafHeader && len(hash) < hashLength {
+		childVariant, _ := decodeVariant(hash[0])
+		if childVariant == leafVariant && len(hash) < hashLength {
 			leaf, err := decodeLeaf(bytes.NewReader(hash[1:]), hash[0])
 			if err != nil {
 				return nil, fmt.Errorf("%w: at index %d: %s",
@@ -130,12 +144,26 @@ func decodeLeaf(reader io.Reader, header byte) (node *Node, err error) {
 		Dirty: true,
 	}
 
-	keyLen := header & keyLenOffset
-	node.Key, err = decodeKey(reader, keyLen)
+	variant, err := decodeVariant(header)
+	if err != nil {
+		return nil, err
+	}
+
+	keyLengthMask := variant.keyLengthMask()
+	node.Key, err = decodeKey(reader, header&keyLengthMask, keyLengthMask)
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode key: %w", err)
 	}
 
+	if variant == leafWithHashedValueVariant {
+		node.Value, err = decodeHashedValue(reader)
+		if err != nil {
+			return nil, err
+		}
+		node.HashedValue = true
+		return node, nil
+	}
+
 	sd := scale.NewDecoder(reader)
 	var value []byte
 	err = sd.Decode(&value)
@@ -149,3 +177,14 @@ func decodeLeaf(reader io.Reader, header byte) (node *Node, err error) {
 
 	return node, nil
 }
+
+// decodeHashedValue reads the hash of the value stored
+// in the node encoding in place of the value.
+func decodeHashedValue(reader io.Reader) (hash []byte, err error) {
+	hash = make([]byte, common.HashLength)
+	_, err = io.ReadFull(reader, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read hashed value: %s", ErrDecodeValue, err)
+	}
+	return hash, nil
+}
@@ -13,7 +13,10 @@
 
 // Encode encodes the node to the buffer given.
 // The encoding format is documented in encode_doc.go.
-func (n *Node) Encode(buffer Buffer) (err error) {
+// Values larger than maxInlineValue bytes are replaced by their
+// hash in the encoding, and NoMaxInlineValue can be given to
+// always encode the values in the node.
+func (n *Node) Encode(buffer Buffer, maxInlineValue int) (err error) {
 	if !n.Dirty && n.Encoding != nil {
 		_, err = buffer.Write(n.Encoding)
 		if err != nil {
@@ -22,7 +25,7 @@ func (n *Node) Encode(buffer Buffer) (err error) {
 		return nil
 	}
 
-	err = encodeHeader(n, buffer)
+	err = encodeHeader(n, maxInlineValue, buffer)
 	if err != nil {
 		return fmt.Errorf("cannot encode header: %w", err)
 	}
@@ -45,19 +48,14 @@ func (n *Node) Encode(buffer Buffer) (err error) {
 	// leaf nodes always have a non-nil value.
 	if n.Type() == Leaf || n.Value != nil {
 		// TODO remove `n.Type() == Leaf` and update tests
-		encodedValue, err := scale.Marshal(n.Value) // TODO scale encoder to write to buffer
+		err = encodeValue(n, maxInlineValue, buffer)
 		if err != nil {
-			return fmt.Errorf("cannot scale encode value: %w", err)
-		}
-
-		_, err = buffer.Write(encodedValue)
-		if err != nil {
-			return fmt.Errorf("cannot write scale encoded value to buffer: %w", err)
+			return err
 		}
 	}
 
 	if n.Type() == Branch {
-		err = encodeChildrenOpportunisticParallel(n.Children, buffer)
+		err = encodeChildrenOpportunisticParallel(n.Children, maxInlineValue, buffer)
 		if err != nil {
 			return fmt.Errorf("cannot encode children of branch: %w", err)
 		}
@@ -73,3 +71,35 @@ func (n *Node) Encode(buffer Buffer) (err error) {
 
 	return nil
 }
+
+// encodeValue writes the value of the node to the buffer, either SCALE
+// encoded or as its hash if it is larger than maxInlineValue bytes.
+func encodeValue(n *Node, maxInlineValue int, buffer Buffer) (err error) {
+	if !n.hashesValue(maxInlineValue) {
+		encodedValue, err := scale.Marshal(n.Value) // TODO scale encoder to write to buffer
+		if err != nil {
+			return fmt.Errorf("cannot scale encode value: %w", err)
+		}
+
+		_, err = buffer.Write(encodedValue)
+		if err != nil {
+			return fmt.Errorf("cannot write scale encoded value to buffer: %w", err)
+		}
+		return nil
+	}
+
+	valueHash := n.Value
+	if !n.HashedValue {
+		hash, err := common.Blake2bHash(n.Value)
+		if err != nil {
+			return fmt.Errorf("cannot hash value: %w", err)
+		}
+		valueHash = hash[:]
+	}
+
+	_, err = buffer.Write(valueHash)
+	if err != nil {
+		return fmt.Errorf("cannot write hashed value to buffer: %w", err)
+	}
+	return nil
+}
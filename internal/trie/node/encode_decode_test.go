@@ -7,6 +7,7 @@
 	"bytes"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -124,7 +125,7 @@ func Test_Branch_Encode_Decode(t *testing.T) {
 
 			buffer := bytes.NewBuffer(nil)
 
-			err := testCase.branchToEncode.Encode(buffer)
+			err := testCase.branchToEncode.Encode(buffer, NoMaxInlineValue)
 			require.NoError(t, err)
 
 			oneBuffer := make([]byte, 1)
@@ -139,3 +140,133 @@ func Test_Branch_Encode_Decode(t *testing.T) {
 		})
 	}
 }
+
+func Test_Node_Encode_Decode_hashedValue(t *testing.T) {
+	t.Parallel()
+
+	const maxInlineValue = 32
+	inlineValue := repeatBytes(maxInlineValue, 1)
+	largeValue := repeatBytes(maxInlineValue+1, 1)
+	largeValueHash := common.MustBlake2bHash(largeValue)
+
+	testCases := map[string]struct {
+		nodeToEncode *Node
+		encoding     []byte
+		nodeDecoded  *Node
+	}{
+		"leaf with inline value": {
+			nodeToEncode: &Node{
+				Key:   []byte{1},
+				Value: inlineValue,
+			},
+			encoding: concatByteSlices([][]byte{
+				{0x41, 0x01},
+				scaleEncodeBytes(t, inlineValue...),
+			}),
+			nodeDecoded: &Node{
+				Key:   []byte{1},
+				Value: inlineValue,
+				Dirty: true,
+			},
+		},
+		"leaf with hashed value": {
+			nodeToEncode: &Node{
+				Key:   []byte{1},
+				Value: largeValue,
+			},
+			encoding: concatByteSlices([][]byte{
+				{0x21, 0x01},
+				largeValueHash[:],
+			}),
+			nodeDecoded: &Node{
+				Key:         []byte{1},
+				Value:       largeValueHash[:],
+				HashedValue: true,
+				Dirty:       true,
+			},
+		},
+		"leaf with hashed value and long key": {
+			nodeToEncode: &Node{
+				Key:   make([]byte, 40),
+				Value: largeValue,
+			},
+			encoding: concatByteSlices([][]byte{
+				{0x3f, 40 - 0x1f},
+				make([]byte, 20),
+				largeValueHash[:],
+			}),
+			nodeDecoded: &Node{
+				Key:         make([]byte, 40),
+				Value:       largeValueHash[:],
+				HashedValue: true,
+				Dirty:       true,
+			},
+		},
+		"leaf decoded with hashed value": {
+			nodeToEncode: &Node{
+				Key:         []byte{1},
+				Value:       largeValueHash[:],
+				HashedValue: true,
+			},
+			encoding: concatByteSlices([][]byte{
+				{0x21, 0x01},
+				largeValueHash[:],
+			}),
+			nodeDecoded: &Node{
+				Key:         []byte{1},
+				Value:       largeValueHash[:],
+				HashedValue: true,
+				Dirty:       true,
+			},
+		},
+		"branch with hashed value": {
+			nodeToEncode: &Node{
+				Key:   []byte{1},
+				Value: largeValue,
+				Children: padRightChildren([]*Node{
+					{
+						Key:   []byte{2},
+						Value: []byte{3},
+					},
+				}),
+			},
+			encoding: concatByteSlices([][]byte{
+				{0x11, 0x01},
+				{0x01, 0x00}, // children bitmap
+				largeValueHash[:],
+				scaleEncodeBytes(t, 0x41, 0x02, 0x04, 0x03),
+			}),
+			nodeDecoded: &Node{
+				Key:         []byte{1},
+				Value:       largeValueHash[:],
+				HashedValue: true,
+				Children: padRightChildren([]*Node{
+					{
+						Key:   []byte{2},
+						Value: []byte{3},
+						Dirty: true,
+					},
+				}),
+				Dirty:       true,
+				Descendants: 1,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+
+			err := testCase.nodeToEncode.Encode(buffer, maxInlineValue)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.encoding, buffer.Bytes())
+
+			nodeDecoded, err := Decode(buffer)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.nodeDecoded, nodeDecoded)
+		})
+	}
+}
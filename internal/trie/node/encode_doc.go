@@ -26,3 +26,10 @@
 // `Extra partial key length` is included if len(key) > 63 and consists of the remaining key length
 // `Partial Key` is the leaf's key
 // `Value` is the leaf's SCALE encoded value
+//
+// Hashed value encoding (state trie version 1):
+// Values larger than 32 bytes are replaced by their 32 bytes blake2b hash, which is not SCALE encoded.
+// The value itself is stored in the database with its hash as key.
+// most significant three bits of a leaf `NodeHeader`: 001, and the five remaining bits store the key length
+// most significant four bits of a branch `NodeHeader`: 0001, and the four remaining bits store the key length
+// `Extra partial key length` is included if len(key) is larger than or equal to the key length mask of the header
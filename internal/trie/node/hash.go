@@ -14,7 +14,9 @@
 // the blake2b hash digest of the encoding of the node.
 // If the encoding is less than 32 bytes, the hash returned
 // is the encoding and not the hash of the encoding.
-func (n *Node) EncodeAndHash(isRoot bool) (encoding, hash []byte, err error) {
+// Values larger than maxInlineValue bytes are replaced by
+// their hash in the encoding of the node.
+func (n *Node) EncodeAndHash(isRoot bool, maxInlineValue int) (encoding, hash []byte, err error) {
 	if !n.Dirty && n.Encoding != nil && n.HashDigest != nil {
 		return n.Encoding, n.HashDigest, nil
 	}
@@ -23,7 +25,7 @@ func (n *Node) EncodeAndHash(isRoot bool) (encoding, hash []byte, err error) {
 	buffer.Reset()
 	defer pools.EncodingBuffers.Put(buffer)
 
-	err = n.Encode(buffer)
+	err = n.Encode(buffer, maxInlineValue)
 	if err != nil {
 		return nil, nil, err
 	}
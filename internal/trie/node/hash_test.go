@@ -205,7 +205,7 @@ func Test_Node_EncodeAndHash(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			encoding, hash, err := testCase.node.EncodeAndHash(testCase.isRoot)
+			encoding, hash, err := testCase.node.EncodeAndHash(testCase.isRoot, NoMaxInlineValue)
 
 			assert.ErrorIs(t, err, testCase.errWrapped)
 			if testCase.errWrapped != nil {
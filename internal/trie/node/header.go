@@ -4,44 +4,101 @@
 package node
 
 import (
+	"fmt"
 	"io"
 )
 
-const (
-	leafHeader            byte = 1 // 01
-	branchHeader          byte = 2 // 10
-	branchWithValueHeader byte = 3 // 11
-)
+// variant is a node variant, identified by the most significant
+// bits of the node header selected by mask.
+// The remaining least significant bits of the header are used
+// to encode the partial key length.
+type variant struct {
+	bits byte
+	mask byte
+}
 
-const (
-	keyLenOffset    = 0x3f
-	nodeHeaderShift = 6
+var (
+	leafVariant = variant{ // 01
+		bits: 0b0100_0000,
+		mask: 0b1100_0000,
+	}
+	branchVariant = variant{ // 10
+		bits: 0b1000_0000,
+		mask: 0b1100_0000,
+	}
+	branchWithValueVariant = variant{ // 11
+		bits: 0b1100_0000,
+		mask: 0b1100_0000,
+	}
+	leafWithHashedValueVariant = variant{ // 001
+		bits: 0b0010_0000,
+		mask: 0b1110_0000,
+	}
+	branchWithHashedValueVariant = variant{ // 0001
+		bits: 0b0001_0000,
+		mask: 0b1111_0000,
+	}
 )
 
+// variants is the list of node variants, ordered by
+// increasing number of bits identifying the variant.
+var variants = []variant{
+	leafVariant,
+	branchVariant,
+	branchWithValueVariant,
+	leafWithHashedValueVariant,
+	branchWithHashedValueVariant,
+}
+
+// keyLengthMask returns the mask selecting the bits
+// of the header encoding the partial key length.
+func (v variant) keyLengthMask() byte {
+	return ^v.mask
+}
+
+// decodeVariant returns the node variant of the header byte given.
+func decodeVariant(header byte) (v variant, err error) {
+	for _, v = range variants {
+		if header&v.mask == v.bits {
+			return v, nil
+		}
+	}
+	return v, fmt.Errorf("%w: %d", ErrUnknownNodeType, header)
+}
+
 // encodeHeader writes the encoded header for the node.
-func encodeHeader(node *Node, writer io.Writer) (err error) {
-	var header byte
-	if node.Type() == Leaf {
-		header = leafHeader
-	} else if node.Value == nil {
-		header = branchHeader
-	} else {
-		header = branchWithValueHeader
-	}
-	header <<= nodeHeaderShift
-
-	if len(node.Key) < keyLenOffset {
-		header |= byte(len(node.Key))
+// Values larger than maxInlineValue bytes are hashed in the node
+// encoding, which is reflected by the node variant in the header.
+func encodeHeader(node *Node, maxInlineValue int, writer io.Writer) (err error) {
+	hashedValue := node.hashesValue(maxInlineValue)
+
+	var v variant
+	switch {
+	case node.Type() == Leaf && hashedValue:
+		v = leafWithHashedValueVariant
+	case node.Type() == Leaf:
+		v = leafVariant
+	case node.Value == nil:
+		v = branchVariant
+	case hashedValue:
+		v = branchWithHashedValueVariant
+	default:
+		v = branchWithValueVariant
+	}
+
+	keyLengthMask := v.keyLengthMask()
+	if len(node.Key) < int(keyLengthMask) {
+		header := v.bits | byte(len(node.Key))
 		_, err = writer.Write([]byte{header})
 		return err
 	}
 
-	header = header | keyLenOffset
+	header := v.bits | keyLengthMask
 	_, err = writer.Write([]byte{header})
 	if err != nil {
 		return err
 	}
 
-	err = encodeKeyLength(len(node.Key), writer)
+	err = encodeKeyLength(len(node.Key), keyLengthMask, writer)
 	return err
 }
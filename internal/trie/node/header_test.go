@@ -207,7 +207,7 @@ func Test_encodeHeader(t *testing.T) {
 				previousCall = call
 			}
 
-			err := encodeHeader(testCase.node, writer)
+			err := encodeHeader(testCase.node, NoMaxInlineValue, writer)
 
 			assert.ErrorIs(t, err, testCase.errWrapped)
 			if testCase.errWrapped != nil {
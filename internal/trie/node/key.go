@@ -21,9 +21,10 @@
 	ErrReadKeyData      = errors.New("cannot read key data")
 )
 
-// encodeKeyLength encodes the key length.
-func encodeKeyLength(keyLength int, writer io.Writer) (err error) {
-	keyLength -= 63
+// encodeKeyLength encodes the key length remaining after
+// the key length mask already written in the node header.
+func encodeKeyLength(keyLength int, keyLengthMask byte, writer io.Writer) (err error) {
+	keyLength -= int(keyLengthMask)
 
 	if keyLength >= int(maxPartialKeySize) {
 		return fmt.Errorf("%w: %d",
@@ -49,12 +50,13 @@ func encodeKeyLength(keyLength int, writer io.Writer) (err error) {
 	return nil
 }
 
-// decodeKey decodes a key from a reader.
-func decodeKey(reader io.Reader, keyLengthByte byte) (b []byte, err error) {
+// decodeKey decodes a key from a reader, where keyLengthByte is the key
+// length read from the node header using the key length mask given.
+func decodeKey(reader io.Reader, keyLengthByte, keyLengthMask byte) (b []byte, err error) {
 	keyLength := int(keyLengthByte)
 
-	if keyLengthByte == keyLenOffset {
-		// partial key longer than 63, read next bytes for rest of pk len
+	if keyLengthByte == keyLengthMask {
+		// partial key longer than the mask, read next bytes for rest of pk len
 		buffer := pools.SingleByteBuffers.Get().(*bytes.Buffer)
 		defer pools.SingleByteBuffers.Put(buffer)
 		oneByteBuf := buffer.Bytes()
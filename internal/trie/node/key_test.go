@@ -110,7 +110,7 @@ func Test_encodeKeyLength(t *testing.T) {
 				previousCall = call
 			}
 
-			err := encodeKeyLength(testCase.keyLength, writer)
+			err := encodeKeyLength(testCase.keyLength, leafVariant.keyLengthMask(), writer)
 
 			assert.ErrorIs(t, err, testCase.errWrapped)
 			if testCase.errWrapped != nil {
@@ -137,7 +137,7 @@ func Test_encodeKeyLength(t *testing.T) {
 		buffer := bytes.NewBuffer(nil)
 		buffer.Grow(expectedEncodingLength)
 
-		err := encodeKeyLength(keyLength, buffer)
+		err := encodeKeyLength(keyLength, leafVariant.keyLengthMask(), buffer)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedBytes, buffer.Bytes())
@@ -197,45 +197,51 @@ func Test_decodeKey(t *testing.T) {
 	t.Parallel()
 
 	testCases := map[string]struct {
-		reads      []readCall
-		keyLength  byte
-		b          []byte
-		errWrapped error
-		errMessage string
+		reads         []readCall
+		keyLength     byte
+		keyLengthMask byte
+		b             []byte
+		errWrapped    error
+		errMessage    string
 	}{
 		"zero key length": {
-			b: []byte{},
+			keyLengthMask: 0x3f,
+			b:             []byte{},
 		},
 		"short key length": {
 			reads: []readCall{
 				{buffArgCap: 3, read: []byte{1, 2, 3}, n: 3},
 			},
-			keyLength: 5,
-			b:         []byte{0x1, 0x0, 0x2, 0x0, 0x3},
+			keyLength:     5,
+			keyLengthMask: 0x3f,
+			b:             []byte{0x1, 0x0, 0x2, 0x0, 0x3},
 		},
 		"key read error": {
 			reads: []readCall{
 				{buffArgCap: 3, err: errTest},
 			},
-			keyLength:  5,
-			errWrapped: ErrReadKeyData,
-			errMessage: "cannot read key data: test error",
+			keyLength:     5,
+			keyLengthMask: 0x3f,
+			errWrapped:    ErrReadKeyData,
+			errMessage:    "cannot read key data: test error",
 		},
 
 		"key read bytes count mismatch": {
 			reads: []readCall{
 				{buffArgCap: 3, n: 2},
 			},
-			keyLength:  5,
-			errWrapped: ErrReadKeyData,
-			errMessage: "cannot read key data: read 2 bytes instead of 3",
+			keyLength:     5,
+			keyLengthMask: 0x3f,
+			errWrapped:    ErrReadKeyData,
+			errMessage:    "cannot read key data: read 2 bytes instead of 3",
 		},
 		"long key length": {
 			reads: []readCall{
 				{buffArgCap: 1, read: []byte{6}, n: 1},            // key length
 				{buffArgCap: 35, read: repeatBytes(35, 7), n: 35}, // key data
 			},
-			keyLength: 0x3f,
+			keyLength:     0x3f,
+			keyLengthMask: 0x3f,
 			b: []byte{
 				0x0, 0x7, 0x0, 0x7, 0x0, 0x7, 0x0, 0x7, 0x0, 0x7,
 				0x0, 0x7, 0x0, 0x7, 0x0, 0x7, 0x0, 0x7, 0x0, 0x7,
@@ -249,15 +255,29 @@ func Test_decodeKey(t *testing.T) {
 			reads: []readCall{
 				{buffArgCap: 1, err: errTest},
 			},
-			keyLength:  0x3f,
-			errWrapped: ErrReadKeyLength,
-			errMessage: "cannot read key length: test error",
+			keyLength:     0x3f,
+			keyLengthMask: 0x3f,
+			errWrapped:    ErrReadKeyLength,
+			errMessage:    "cannot read key length: test error",
 		},
 		"key length too big": {
-			reads:      repeatReadCalls(readCall{buffArgCap: 1, read: []byte{0xff}, n: 1}, 257),
-			keyLength:  0x3f,
-			errWrapped: ErrPartialKeyTooBig,
-			errMessage: "partial key length cannot be larger than or equal to 2^16: 65598",
+			reads:         repeatReadCalls(readCall{buffArgCap: 1, read: []byte{0xff}, n: 1}, 257),
+			keyLength:     0x3f,
+			keyLengthMask: 0x3f,
+			errWrapped:    ErrPartialKeyTooBig,
+			errMessage:    "partial key length cannot be larger than or equal to 2^16: 65598",
+		},
+		"long key length with hashed value branch variant": {
+			reads: []readCall{
+				{buffArgCap: 1, read: []byte{7}, n: 1},            // key length
+				{buffArgCap: 11, read: repeatBytes(11, 7), n: 11}, // key data
+			},
+			keyLength:     0x0f,
+			keyLengthMask: 0x0f,
+			b: []byte{
+				0x0, 0x7, 0x0, 0x7, 0x0, 0x7, 0x0, 0x7, 0x0, 0x7,
+				0x0, 0x7, 0x0, 0x7, 0x0, 0x7, 0x0, 0x7, 0x0, 0x7,
+				0x0, 0x7},
 		},
 	}
 
@@ -282,7 +302,7 @@ func Test_decodeKey(t *testing.T) {
 				previousCall = call
 			}
 
-			b, err := decodeKey(reader, testCase.keyLength)
+			b, err := decodeKey(reader, testCase.keyLength, testCase.keyLengthMask)
 
 			assert.ErrorIs(t, err, testCase.errWrapped)
 			if err != nil {
@@ -16,6 +16,11 @@ type Node struct {
 	// Key is the partial key bytes in nibbles (0 to f in hexadecimal)
 	Key   []byte
 	Value []byte
+	// HashedValue is true when Value is the blake2b hash of the
+	// node value, which is stored separately from the node.
+	// It is set for nodes decoded from an encoding storing
+	// the hash of their value, until the value is loaded.
+	HashedValue bool
 	// Generation is incremented on every trie Snapshot() call.
 	// Each node also contain a certain Generation number,
 	// which is updated to match the trie Generation once they are
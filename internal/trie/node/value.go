@@ -0,0 +1,50 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package node
+
+import (
+	"math"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// NoMaxInlineValue can be given as maximum inline value size
+// to always encode the values in the nodes, whatever their size.
+const NoMaxInlineValue = math.MaxInt
+
+// hashesValue returns true if the encoding of the node stores
+// the hash of its value instead of its value, for the given
+// maximum inline value size.
+func (n *Node) hashesValue(maxInlineValue int) bool {
+	return n.HashedValue || len(n.Value) > maxInlineValue
+}
+
+// ValueHash returns the hash of the node value if the cached
+// encoding of the node stores the hash of its value instead of
+// its value. It returns nil otherwise.
+func (n *Node) ValueHash() (hash []byte, err error) {
+	if len(n.Encoding) == 0 {
+		return nil, nil
+	}
+
+	variant, err := decodeVariant(n.Encoding[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if variant != leafWithHashedValueVariant &&
+		variant != branchWithHashedValueVariant {
+		return nil, nil
+	}
+
+	if n.HashedValue {
+		return n.Value, nil
+	}
+
+	digest, err := common.Blake2bHash(n.Value)
+	if err != nil {
+		return nil, err
+	}
+	return digest[:], nil
+}
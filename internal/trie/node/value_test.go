@@ -0,0 +1,83 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package node
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Node_ValueHash(t *testing.T) {
+	t.Parallel()
+
+	value := repeatBytes(33, 1)
+	valueHash := common.MustBlake2bHash(value)
+
+	testCases := map[string]struct {
+		node       *Node
+		hash       []byte
+		errWrapped error
+		errMessage string
+	}{
+		"no encoding": {
+			node: &Node{
+				Value: value,
+			},
+		},
+		"invalid encoding": {
+			node: &Node{
+				Value:    value,
+				Encoding: []byte{0},
+			},
+			errWrapped: ErrUnknownNodeType,
+			errMessage: "unknown node type: 0",
+		},
+		"leaf encoded with value": {
+			node: &Node{
+				Value:    value,
+				Encoding: []byte{0x40},
+			},
+		},
+		"leaf encoded with hashed value": {
+			node: &Node{
+				Value:    value,
+				Encoding: []byte{0x20},
+			},
+			hash: valueHash[:],
+		},
+		"branch encoded with hashed value": {
+			node: &Node{
+				Value:    value,
+				Children: make([]*Node, ChildrenCapacity),
+				Encoding: []byte{0x10},
+			},
+			hash: valueHash[:],
+		},
+		"decoded leaf with hashed value": {
+			node: &Node{
+				Value:       []byte{1, 2},
+				HashedValue: true,
+				Encoding:    []byte{0x20},
+			},
+			hash: []byte{1, 2},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hash, err := testCase.node.ValueHash()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.hash, hash)
+		})
+	}
+}
@@ -98,6 +98,7 @@ type ServiceIFace interface {
 	IsPaused() bool
 	Resume() error
 	SlotDuration() uint64
+	EpochAuthorship(keypairs []*sr25519.Keypair) (map[string]*EpochAuthorship, error)
 }
 
 // Builder struct to hold babe builder functions
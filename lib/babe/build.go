@@ -205,7 +205,7 @@ func (b *BlockBuilder) buildBlockExtrinsics(slot Slot, rt runtime.Instance) []*t
 			continue
 		}
 
-		err = determineErr(ret)
+		err = DetermineErr(ret)
 		if err != nil {
 			logger.Warnf("failed to apply extrinsic %s: %s", extrinsic, err)
 
@@ -283,7 +283,7 @@ func buildBlockInherents(slot Slot, rt runtime.Instance) ([][]byte, error) {
 		}
 
 		if !bytes.Equal(ret, []byte{0, 0}) {
-			errTxt := determineErr(ret)
+			errTxt := DetermineErr(ret)
 			return nil, fmt.Errorf("error applying inherent: %s", errTxt)
 		}
 	}
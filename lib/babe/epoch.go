@@ -4,6 +4,7 @@
 package babe
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
@@ -90,63 +91,71 @@ func (b *Service) getEpochDataAndStartSlot(epoch uint64) (*epochData, uint64, er
 		return epochData, startSlot, nil
 	}
 
+	ed, err := b.getEpochData(epoch)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ed.authorityIndex, err = b.getAuthorityIndex(ed.authorities)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot get authority index: %w", err)
+	}
+
+	startSlot, err := b.epochState.GetStartSlotForEpoch(epoch)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot get start slot for epoch %d: %w", epoch, err)
+	}
+
+	return ed, startSlot, nil
+}
+
+// getEpochData returns the epoch data for the given epoch, which must be greater than 0,
+// without setting the authority index.
+func (b *Service) getEpochData(epoch uint64) (*epochData, error) {
 	has, err := b.epochState.HasEpochData(epoch)
 	if err != nil {
-		return nil, 0, fmt.Errorf("cannot check epoch state: %w", err)
+		return nil, fmt.Errorf("cannot check epoch state: %w", err)
 	}
 
 	if !has {
 		logger.Criticalf("%s number=%d", errNoEpochData, epoch)
-		return nil, 0, fmt.Errorf("%w: for epoch %d", errNoEpochData, epoch)
+		return nil, fmt.Errorf("%w: for epoch %d", errNoEpochData, epoch)
 	}
 
 	data, err := b.epochState.GetEpochData(epoch, nil)
 	if err != nil {
-		return nil, 0, fmt.Errorf("cannot get epoch data for epoch %d: %w", epoch, err)
-	}
-
-	idx, err := b.getAuthorityIndex(data.Authorities)
-	if err != nil {
-		return nil, 0, fmt.Errorf("cannot get authority index: %w", err)
+		return nil, fmt.Errorf("cannot get epoch data for epoch %d: %w", epoch, err)
 	}
 
 	has, err = b.epochState.HasConfigData(epoch)
 	if err != nil {
-		return nil, 0, fmt.Errorf("cannot check for config data for epoch %d: %w", epoch, err)
+		return nil, fmt.Errorf("cannot check for config data for epoch %d: %w", epoch, err)
 	}
 
 	var cfgData *types.ConfigData
 	if has {
 		cfgData, err = b.epochState.GetConfigData(epoch, nil)
 		if err != nil {
-			return nil, 0, fmt.Errorf("cannot get config data for epoch %d: %w", epoch, err)
+			return nil, fmt.Errorf("cannot get config data for epoch %d: %w", epoch, err)
 		}
 	} else {
 		cfgData, err = b.epochState.GetLatestConfigData()
 		if err != nil {
-			return nil, 0, fmt.Errorf("cannot get latest config data from epoch state: %w", err)
+			return nil, fmt.Errorf("cannot get latest config data from epoch state: %w", err)
 		}
 	}
 
 	threshold, err := CalculateThreshold(cfgData.C1, cfgData.C2, len(data.Authorities))
 	if err != nil {
-		return nil, 0, fmt.Errorf("cannot calculate threshold: %w", err)
-	}
-
-	ed := &epochData{
-		randomness:     data.Randomness,
-		authorities:    data.Authorities,
-		authorityIndex: idx,
-		threshold:      threshold,
-		allowedSlots:   types.AllowedSlots(cfgData.SecondarySlots),
-	}
-
-	startSlot, err := b.epochState.GetStartSlotForEpoch(epoch)
-	if err != nil {
-		return nil, 0, fmt.Errorf("cannot get start slot for epoch %d: %w", epoch, err)
+		return nil, fmt.Errorf("cannot calculate threshold: %w", err)
 	}
 
-	return ed, startSlot, nil
+	return &epochData{
+		randomness:   data.Randomness,
+		authorities:  data.Authorities,
+		threshold:    threshold,
+		allowedSlots: types.AllowedSlots(cfgData.SecondarySlots),
+	}, nil
 }
 
 func (b *Service) getLatestEpochData() (resEpochData *epochData, error error) {
@@ -201,6 +210,100 @@ func (b *Service) getFirstAuthoringSlot(epoch uint64, epochData *epochData) (uin
 	return startSlot, nil
 }
 
+// EpochAuthorship returns, for each of the given keypairs that is a BABE authority
+// of the current epoch, the slots of the current epoch the keypair can claim.
+// The returned map is keyed by the SS58 address of the keypairs public keys.
+func (b *Service) EpochAuthorship(keypairs []*sr25519.Keypair) (map[string]*EpochAuthorship, error) {
+	epoch, err := b.epochState.GetCurrentEpoch()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get current epoch: %w", err)
+	}
+
+	var data *epochData
+	if epoch == 0 {
+		data, err = b.getLatestEpochData()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get latest epoch data: %w", err)
+		}
+	} else {
+		data, err = b.getEpochData(epoch)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get epoch data: %w", err)
+		}
+	}
+
+	startSlot, err := b.epochState.GetStartSlotForEpoch(epoch)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get start slot for epoch %d: %w", epoch, err)
+	}
+
+	authorships := make(map[string]*EpochAuthorship, len(keypairs))
+	for _, kp := range keypairs {
+		authorityIndex, isAuthority := getKeypairAuthorityIndex(kp, data.authorities)
+		if !isAuthority {
+			continue
+		}
+
+		keypairData := *data
+		keypairData.authorityIndex = authorityIndex
+
+		authorship, err := b.getEpochAuthorship(epoch, startSlot, &keypairData, kp)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get epoch authorship for key %s: %w", kp.Public().Hex(), err)
+		}
+
+		address := string(kp.Public().(*sr25519.PublicKey).Address())
+		authorships[address] = authorship
+	}
+
+	return authorships, nil
+}
+
+func (b *Service) getEpochAuthorship(epoch, startSlot uint64, data *epochData, kp *sr25519.Keypair) (
+	*EpochAuthorship, error) {
+	authorship := &EpochAuthorship{
+		Primary:      []uint64{},
+		Secondary:    []uint64{},
+		SecondaryVRF: []uint64{},
+	}
+
+	for slot := startSlot; slot < startSlot+b.constants.epochLength; slot++ {
+		preRuntimeDigest, err := claimSlot(epoch, slot, data, kp)
+		if errors.Is(err, errNotOurTurnToPropose) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot claim slot %d: %w", slot, err)
+		}
+
+		digest, err := types.DecodeBabePreDigest(preRuntimeDigest.Data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode babe pre-digest of slot %d: %w", slot, err)
+		}
+
+		switch digest.(type) {
+		case types.BabePrimaryPreDigest:
+			authorship.Primary = append(authorship.Primary, slot)
+		case types.BabeSecondaryPlainPreDigest:
+			authorship.Secondary = append(authorship.Secondary, slot)
+		case types.BabeSecondaryVRFPreDigest:
+			authorship.SecondaryVRF = append(authorship.SecondaryVRF, slot)
+		}
+	}
+
+	return authorship, nil
+}
+
+func getKeypairAuthorityIndex(kp *sr25519.Keypair, authorities []types.Authority) (index uint32, ok bool) {
+	pub := kp.Public().Encode()
+	for i, auth := range authorities {
+		if bytes.Equal(pub, auth.Key.Encode()) {
+			return uint32(i), true
+		}
+	}
+
+	return 0, false
+}
+
 // incrementEpoch increments the current epoch stored in the db and returns the new epoch number
 func (b *Service) incrementEpoch() (uint64, error) {
 	epoch, err := b.epochState.GetCurrentEpoch()
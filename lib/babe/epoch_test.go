@@ -198,3 +198,77 @@ func TestBabeService_getEpochDataAndStartSlot(t *testing.T) {
 		require.Equal(t, tc.expectedStartSlot, startSlot)
 	}
 }
+
+func TestBabeService_EpochAuthorship(t *testing.T) {
+	t.Parallel()
+
+	alice := keyring.Alice().(*sr25519.Keypair)
+	bob := keyring.Bob().(*sr25519.Keypair)
+	authority := types.NewAuthority(alice.Public(), uint64(1))
+	aliceAddress := string(alice.Public().(*sr25519.PublicKey).Address())
+
+	testEpochData := &types.EpochData{
+		Randomness:  [32]byte{1},
+		Authorities: []types.Authority{*authority},
+	}
+
+	testCases := map[string]struct {
+		configData  *types.ConfigData
+		authorships map[string]*EpochAuthorship
+	}{
+		"primary slots": {
+			configData: &types.ConfigData{
+				C1: 1,
+				C2: 1,
+			},
+			authorships: map[string]*EpochAuthorship{
+				aliceAddress: {
+					Primary:      []uint64{201, 202, 203, 204},
+					Secondary:    []uint64{},
+					SecondaryVRF: []uint64{},
+				},
+			},
+		},
+		"secondary plain slots": {
+			configData: &types.ConfigData{
+				C1:             1,
+				C2:             1 << 40,
+				SecondarySlots: byte(types.PrimaryAndSecondaryPlainSlots),
+			},
+			authorships: map[string]*EpochAuthorship{
+				aliceAddress: {
+					Primary:      []uint64{},
+					Secondary:    []uint64{201, 202, 203, 204},
+					SecondaryVRF: []uint64{},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			epochState := NewMockEpochState(ctrl)
+			epochState.EXPECT().GetCurrentEpoch().Return(uint64(1), nil)
+			epochState.EXPECT().HasEpochData(uint64(1)).Return(true, nil)
+			epochState.EXPECT().GetEpochData(uint64(1), nil).Return(testEpochData, nil)
+			epochState.EXPECT().HasConfigData(uint64(1)).Return(true, nil)
+			epochState.EXPECT().GetConfigData(uint64(1), nil).Return(testCase.configData, nil)
+			epochState.EXPECT().GetStartSlotForEpoch(uint64(1)).Return(uint64(201), nil)
+
+			service := &Service{
+				epochState: epochState,
+				constants: constants{
+					epochLength: 4,
+				},
+			}
+
+			authorships, err := service.EpochAuthorship([]*sr25519.Keypair{alice, bob})
+			require.NoError(t, err)
+			require.Equal(t, testCase.authorships, authorships)
+		})
+	}
+}
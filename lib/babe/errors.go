@@ -288,7 +288,10 @@ func determineErrType(vdt scale.VaryingDataType) error {
 	return errInvalidResult
 }
 
-func determineErr(res []byte) error {
+// DetermineErr decodes the SCALE encoded ApplyExtrinsicResult returned by the runtime.
+// It returns nil if the extrinsic was applied successfully, or a *DispatchOutcomeError or
+// *TransactionValidityError describing why it was not.
+func DetermineErr(res []byte) error {
 	dispatchError := scale.MustNewVaryingDataType(other, CannotLookup{}, BadOrigin{}, Module{})
 	invalid := scale.MustNewVaryingDataType(Call{}, Payment{}, Future{}, Stale{}, BadProof{}, AncientBirthBlock{},
 		ExhaustsResources{}, invalidCustom, BadMandatory{}, MandatoryDispatch{})
@@ -72,6 +72,14 @@ func (ed *epochData) String() string {
 	)
 }
 
+// EpochAuthorship contains the slots of an epoch that a BABE key is able to claim,
+// for each kind of slot.
+type EpochAuthorship struct {
+	Primary      []uint64
+	Secondary    []uint64
+	SecondaryVRF []uint64
+}
+
 type constants struct {
 	slotDuration time.Duration
 	epochLength  uint64
@@ -12,6 +12,7 @@
 	keystore "github.com/ChainSafe/gossamer/lib/keystore"
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
 	transaction "github.com/ChainSafe/gossamer/lib/transaction"
+	trie "github.com/ChainSafe/gossamer/lib/trie"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -156,15 +157,18 @@ func (mr *MockInstanceMockRecorder) FinalizeBlock() *gomock.Call {
 }
 
 // GenerateSessionKeys mocks base method.
-func (m *MockInstance) GenerateSessionKeys() {
+func (m *MockInstance) GenerateSessionKeys(arg0 *[]byte) ([]byte, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "GenerateSessionKeys")
+	ret := m.ctrl.Call(m, "GenerateSessionKeys", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GenerateSessionKeys indicates an expected call of GenerateSessionKeys.
-func (mr *MockInstanceMockRecorder) GenerateSessionKeys() *gomock.Call {
+func (mr *MockInstanceMockRecorder) GenerateSessionKeys(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSessionKeys", reflect.TypeOf((*MockInstance)(nil).GenerateSessionKeys))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSessionKeys", reflect.TypeOf((*MockInstance)(nil).GenerateSessionKeys), arg0)
 }
 
 // GetCodeHash mocks base method.
@@ -282,16 +286,32 @@ func (mr *MockInstanceMockRecorder) NodeStorage() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeStorage", reflect.TypeOf((*MockInstance)(nil).NodeStorage))
 }
 
+// OffchainHTTPDisabled mocks base method.
+func (m *MockInstance) OffchainHTTPDisabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OffchainHTTPDisabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// OffchainHTTPDisabled indicates an expected call of OffchainHTTPDisabled.
+func (mr *MockInstanceMockRecorder) OffchainHTTPDisabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffchainHTTPDisabled", reflect.TypeOf((*MockInstance)(nil).OffchainHTTPDisabled))
+}
+
 // OffchainWorker mocks base method.
-func (m *MockInstance) OffchainWorker() {
+func (m *MockInstance) OffchainWorker(arg0 *types.Header) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OffchainWorker")
+	ret := m.ctrl.Call(m, "OffchainWorker", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // OffchainWorker indicates an expected call of OffchainWorker.
-func (mr *MockInstanceMockRecorder) OffchainWorker() *gomock.Call {
+func (mr *MockInstanceMockRecorder) OffchainWorker(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffchainWorker", reflect.TypeOf((*MockInstance)(nil).OffchainWorker))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffchainWorker", reflect.TypeOf((*MockInstance)(nil).OffchainWorker), arg0)
 }
 
 // PaymentQueryInfo mocks base method.
@@ -333,6 +353,21 @@ func (mr *MockInstanceMockRecorder) SetContextStorage(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContextStorage", reflect.TypeOf((*MockInstance)(nil).SetContextStorage), arg0)
 }
 
+// StateVersion mocks base method.
+func (m *MockInstance) StateVersion() (trie.Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateVersion")
+	ret0, _ := ret[0].(trie.Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateVersion indicates an expected call of StateVersion.
+func (mr *MockInstanceMockRecorder) StateVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateVersion", reflect.TypeOf((*MockInstance)(nil).StateVersion))
+}
+
 // Stop mocks base method.
 func (m *MockInstance) Stop() {
 	m.ctrl.T.Helper()
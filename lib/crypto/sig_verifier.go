@@ -5,8 +5,9 @@
 
 import (
 	"errors"
+	"runtime"
 	"sync"
-	"time"
+	"sync/atomic"
 
 	"github.com/ChainSafe/gossamer/internal/log"
 )
@@ -24,16 +25,19 @@ type SignatureInfo struct {
 	VerifyFunc SigVerifyFunc
 }
 
-// SignatureVerifier ...
+// SignatureVerifier verifies signatures in the background, using a pool of workers, between
+// the start and the finish of a batch.
 type SignatureVerifier struct {
-	batch   []*SignatureInfo
-	init    bool // Indicates whether the batch processing is started.
-	invalid bool // Set to true if any signature verification fails.
 	logger  log.LeveledLogger
-	closeCh chan struct{}
-	sync.RWMutex
-	sync.Once
-	sync.WaitGroup
+	workers int
+
+	invalid int32 // set to 1 if the verification of a signature fails, accessed atomically
+
+	mu         sync.Mutex
+	started    bool
+	pending    []*SignatureInfo // signatures added before the batch is started
+	signatures chan *SignatureInfo
+	wg         sync.WaitGroup
 }
 
 // NewSignatureVerifier initialises SignatureVerifier which does background verification of signatures.
@@ -42,115 +46,130 @@ type SignatureVerifier struct {
 // Signatures can be added to the batch using Add().
 func NewSignatureVerifier(logger log.LeveledLogger) *SignatureVerifier {
 	return &SignatureVerifier{
-		batch:   make([]*SignatureInfo, 0),
-		init:    false,
-		invalid: false,
 		logger:  logger,
-		RWMutex: sync.RWMutex{},
-		closeCh: make(chan struct{}),
+		workers: runtime.GOMAXPROCS(0),
 	}
 }
 
-// Start signature verification in batch.
+// SetWorkers sets the number of workers verifying the signatures of the next batches. With no
+// workers, the batch verification is disabled and the signatures are verified one after the other,
+// as they are added.
+func (sv *SignatureVerifier) SetWorkers(workers int) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.workers = workers
+}
+
+// Start starts the workers verifying the signatures of the batch.
 func (sv *SignatureVerifier) Start() {
-	// Update the init state.
-	sv.Lock()
-	sv.init = true
-	sv.Unlock()
-
-	sv.WaitGroup.Add(1)
-
-	go func() {
-		defer sv.Done()
-		for {
-			select {
-			case <-sv.closeCh:
-				return
-			default:
-				signature := sv.Remove()
-				if signature == nil {
-					continue
-				}
-				err := signature.VerifyFunc(signature.PubKey, signature.Sign, signature.Msg)
-				if err != nil {
-					sv.logger.Errorf("[ext_crypto_start_batch_verify_version_1]: %s", err)
-					sv.Invalid()
-					return
-				}
-			}
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	if sv.started {
+		return
+	}
+
+	sv.started = true
+	if sv.workers == 0 {
+		for _, signature := range sv.pending {
+			sv.verifySignature(signature)
 		}
-	}()
+		sv.pending = nil
+		return
+	}
+
+	sv.signatures = make(chan *SignatureInfo, sv.workers*4)
+	sv.wg.Add(sv.workers)
+	for i := 0; i < sv.workers; i++ {
+		go sv.verify(sv.signatures)
+	}
+
+	pending := sv.pending
+	sv.pending = nil
+	for _, signature := range pending {
+		sv.signatures <- signature
+	}
+}
+
+func (sv *SignatureVerifier) verify(signatures <-chan *SignatureInfo) {
+	defer sv.wg.Done()
+
+	for signature := range signatures {
+		sv.verifySignature(signature)
+	}
 }
 
-// IsStarted ...
+func (sv *SignatureVerifier) verifySignature(signature *SignatureInfo) {
+	if sv.IsInvalid() {
+		// the batch already failed
+		return
+	}
+
+	err := signature.VerifyFunc(signature.PubKey, signature.Sign, signature.Msg)
+	if err != nil {
+		sv.logger.Errorf("[ext_crypto_start_batch_verify_version_1]: %s", err)
+		sv.Invalid()
+	}
+}
+
+// IsStarted returns true if a batch is started
 func (sv *SignatureVerifier) IsStarted() bool {
-	sv.RLock()
-	defer sv.RUnlock()
-	return sv.init
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.started
 }
 
-// IsInvalid ...
+// IsInvalid returns true if the verification of a signature of the batch failed
 func (sv *SignatureVerifier) IsInvalid() bool {
-	sv.RLock()
-	defer sv.RUnlock()
-	return sv.invalid
+	return atomic.LoadInt32(&sv.invalid) == 1
 }
 
-// Invalid ...
+// Invalid marks the batch as invalid
 func (sv *SignatureVerifier) Invalid() {
-	sv.RLock()
-	defer sv.RUnlock()
-	sv.invalid = true
+	atomic.StoreInt32(&sv.invalid, 1)
 }
 
-// Add ...
+// Add adds a signature to the batch. The signature is verified once the batch is started.
 func (sv *SignatureVerifier) Add(s *SignatureInfo) {
 	if sv.IsInvalid() {
 		return
 	}
 
-	sv.Lock()
-	defer sv.Unlock()
-	sv.batch = append(sv.batch, s)
-}
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
 
-// Remove returns the first signature from the batch. Returns nil if batch is empty.
-func (sv *SignatureVerifier) Remove() *SignatureInfo {
-	sv.Lock()
-	defer sv.Unlock()
-	if len(sv.batch) == 0 {
-		return nil
+	if !sv.started {
+		sv.pending = append(sv.pending, s)
+		return
+	}
+
+	if sv.signatures == nil {
+		sv.verifySignature(s)
+		return
 	}
-	sign := sv.batch[0]
-	sv.batch = sv.batch[1:len(sv.batch)]
-	return sign
+
+	sv.signatures <- s
 }
 
-// Reset reset the signature verifier for reuse.
+// Reset discards the batch, so that the signature verifier can be reused.
 func (sv *SignatureVerifier) Reset() {
-	sv.Lock()
-	defer sv.Unlock()
-	sv.init = false
-	sv.batch = make([]*SignatureInfo, 0)
-	sv.invalid = false
-	sv.closeCh = make(chan struct{})
+	_ = sv.Finish()
 }
 
 // Finish waits till batch is finished. Returns true if all the signatures are valid, Otherwise returns false.
 func (sv *SignatureVerifier) Finish() bool {
-	for {
-		time.Sleep(100 * time.Millisecond)
-		sv.Lock()
-		if sv.invalid || len(sv.batch) == 0 {
-			close(sv.closeCh)
-			sv.Unlock()
-			break
-		}
-		sv.RUnlock()
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	if sv.signatures != nil {
+		close(sv.signatures)
+		sv.wg.Wait()
 	}
-	// Wait till start function to finish and then reset it.
-	sv.Wait()
-	isInvalid := sv.IsInvalid()
-	sv.Reset()
-	return !isInvalid
+
+	valid := !sv.IsInvalid()
+	sv.started = false
+	sv.pending = nil
+	sv.signatures = nil
+	atomic.StoreInt32(&sv.invalid, 0)
+	return valid
 }
@@ -110,17 +110,63 @@ func TestVerifySignature(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			signVerify := crypto.NewSignatureVerifier(log.New(log.SetWriter(io.Discard)))
+			// no workers disables the batch verification
+			for _, workers := range []int{0, 4} {
+				signVerify := crypto.NewSignatureVerifier(log.New(log.SetWriter(io.Discard)))
+				signVerify.SetWorkers(workers)
 
-			for _, sig := range testCase.signaturesToVerify {
-				signVerify.Add(sig)
-			}
+				for _, sig := range testCase.signaturesToVerify {
+					signVerify.Add(sig)
+				}
 
-			signVerify.Start()
+				signVerify.Start()
 
-			ok := signVerify.Finish()
-			require.Equal(t, testCase.expect, ok)
+				ok := signVerify.Finish()
+				require.Equal(t, testCase.expect, ok)
+			}
 		})
 	}
 
 }
+
+// Benchmark_SignatureVerifier compares the verification of the signatures of a
+// signature heavy block, one after the other and in a batch.
+func Benchmark_SignatureVerifier(b *testing.B) {
+	const signaturesCount = 1000
+
+	message := []byte("a225e8c75da7da319af6335e7642d473")
+	signatures := make([]*crypto.SignatureInfo, signaturesCount)
+	for i := range signatures {
+		keypair, err := sr25519.GenerateKeypair()
+		require.NoError(b, err)
+		signature, err := keypair.Sign(message)
+		require.NoError(b, err)
+
+		signatures[i] = &crypto.SignatureInfo{
+			PubKey:     keypair.Public().Encode(),
+			Sign:       signature,
+			Msg:        message,
+			VerifyFunc: sr25519.VerifySignature,
+		}
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, signature := range signatures {
+				err := signature.VerifyFunc(signature.PubKey, signature.Sign, signature.Msg)
+				require.NoError(b, err)
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		signVerify := crypto.NewSignatureVerifier(log.New(log.SetWriter(io.Discard)))
+		for i := 0; i < b.N; i++ {
+			signVerify.Start()
+			for _, signature := range signatures {
+				signVerify.Add(signature)
+			}
+			require.True(b, signVerify.Finish())
+		}
+	})
+}
@@ -68,10 +68,23 @@
 	BlockBuilderFinalizeBlock = "BlockBuilder_finalize_block"
 	// DecodeSessionKeys is the runtime API call SessionKeys_decode_session_keys
 	DecodeSessionKeys = "SessionKeys_decode_session_keys"
+	// GenerateSessionKeys is the runtime API call SessionKeys_generate_session_keys
+	GenerateSessionKeys = "SessionKeys_generate_session_keys"
 	// TransactionPaymentAPIQueryInfo returns information of a given extrinsic
 	TransactionPaymentAPIQueryInfo = "TransactionPaymentApi_query_info"
+	// TransactionPaymentAPIQueryFeeDetails returns the fee details of a given extrinsic
+	TransactionPaymentAPIQueryFeeDetails = "TransactionPaymentApi_query_fee_details"
+	// OffchainWorkerAPI is the runtime API call OffchainWorkerApi_offchain_worker
+	OffchainWorkerAPI = "OffchainWorkerApi_offchain_worker"
 )
 
+// OffchainWorkerAPIID is the identifier of the OffchainWorkerApi in the API items of the
+// runtime version, which is the blake2b-64 hash of the API name
+var OffchainWorkerAPIID = [8]byte{0xf7, 0x8b, 0x27, 0x8b, 0xe5, 0x3f, 0x45, 0x4c}
+
+// CoreAPIID is the identifier of the Core API in the API items of the runtime version
+var CoreAPIID = [8]byte{0xdf, 0x6a, 0xcb, 0x68, 0x99, 0x07, 0x60, 0x9b}
+
 // GrandpaAuthoritiesKey is the location of GRANDPA authority data
 // in the storage trie for LEGACY_NODE_RUNTIME and NODE_RUNTIME
 var GrandpaAuthoritiesKey, _ = common.HexToBytes("0x3a6772616e6470615f617574686f726974696573")
@@ -91,6 +104,18 @@ func BABERandomnessKey() []byte {
 	return append(BABEPrefix, key...)
 }
 
+// DefaultTransactionStoragePeriod is the default number of blocks during which the data indexed by
+// the transactions of a block is kept, used when the runtime does not define the storage period
+const DefaultTransactionStoragePeriod = 100800
+
+// TransactionStoragePeriodKey is the location of the storage period of the transaction storage pallet
+// in the storage trie
+func TransactionStoragePeriodKey() []byte {
+	prefix, _ := common.Twox128Hash([]byte("TransactionStorage"))
+	key, _ := common.Twox128Hash([]byte("StoragePeriod"))
+	return append(prefix, key...)
+}
+
 // SystemAccountPrefix is the prefix for all System Account related storage values
 func SystemAccountPrefix() []byte {
 	// build prefix
@@ -22,3 +22,10 @@
 
 // ErrNilStorage is returned when the runtime context storage isn't set
 var ErrNilStorage = errors.New("runtime context storage is nil")
+
+// ErrExportFunctionNotFound is returned when the runtime does not export the called function
+var ErrExportFunctionNotFound = errors.New("could not find exported function")
+
+// ErrSandboxNotSupported is returned when the runtime instantiates sandboxed modules, and the
+// interpreter cannot dispatch the host functions of the sandboxed modules to the runtime
+var ErrSandboxNotSupported = errors.New("runtimes instantiating sandboxed modules are not supported")
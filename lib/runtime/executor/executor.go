@@ -0,0 +1,42 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package executor
+
+import (
+	"sort"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/life"
+	"github.com/ChainSafe/gossamer/lib/runtime/wazero"
+)
+
+// executors maps the wasm interpreter names to the functions instantiating the runtimes
+// with the corresponding backend. The backends written in pure Go are always available,
+// and wasmer is only registered when building with cgo.
+var executors = map[string]runtime.NewInstanceFunc{
+	life.Name:   life.NewRuntimeInstance,
+	wazero.Name: wazero.NewRuntimeInstance,
+}
+
+// Lookup returns the function instantiating the runtimes with the wasm interpreter of the given name
+func Lookup(name string) (newInstance runtime.NewInstanceFunc, ok bool) {
+	newInstance, ok = executors[name]
+	return newInstance, ok
+}
+
+// Names returns the sorted names of the available wasm interpreters
+func Names() (names []string) {
+	names = make([]string, 0, len(executors))
+	for name := range executors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewInstance instantiates the runtime code with the default wasm interpreter,
+// and implements runtime.NewInstanceFunc
+func NewInstance(code []byte, cfg runtime.InstanceConfig) (runtime.Instance, error) {
+	return executors[Default](code, cfg)
+}
@@ -0,0 +1,15 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+//go:build cgo
+
+package executor
+
+import "github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+
+// Default is the name of the wasm interpreter used by default
+const Default = wasmer.Name
+
+func init() {
+	executors[wasmer.Name] = wasmer.NewRuntimeInstance
+}
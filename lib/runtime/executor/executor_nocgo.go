@@ -0,0 +1,12 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+//go:build !cgo
+
+package executor
+
+import "github.com/ChainSafe/gossamer/lib/runtime/wazero"
+
+// Default is the name of the wasm interpreter used by default, since wasmer
+// cannot be linked without cgo
+const Default = wazero.Name
@@ -0,0 +1,126 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// DefaultTestLogLvl is the log level used for test runtime instances
+var DefaultTestLogLvl = log.Info
+
+// testInstance is a runtime instance giving the tests access to its context
+type testInstance interface {
+	runtime.Instance
+	GetContext() *runtime.Context
+}
+
+// forEachExecutor runs the test as a subtest for each available wasm interpreter
+func forEachExecutor(t *testing.T, test func(t *testing.T, executor string)) {
+	t.Helper()
+
+	for _, executor := range Names() {
+		executor := executor
+		t.Run(executor, func(t *testing.T) {
+			test(t, executor)
+		})
+	}
+}
+
+// newTestInstance will create a new runtime instance using the given target runtime
+func newTestInstance(t *testing.T, executor, targetRuntime string) testInstance {
+	t.Helper()
+	return newTestInstanceWithTrie(t, executor, targetRuntime, nil)
+}
+
+// newTestInstanceWithTrie will create a new runtime instance using the given target runtime,
+// with the supplied trie as the storage
+func newTestInstanceWithTrie(t *testing.T, executor, targetRuntime string, tt *trie.Trie) testInstance {
+	t.Helper()
+
+	runtimeFilepath, err := runtime.GetRuntime(context.Background(), targetRuntime)
+	require.NoError(t, err)
+
+	code, err := os.ReadFile(runtimeFilepath)
+	require.NoError(t, err)
+
+	instance, err := newInstance(t, executor, code, setupConfig(t, tt, DefaultTestLogLvl, 0))
+	require.NoError(t, err, "Got error when trying to create new VM", "targetRuntime", targetRuntime)
+	require.NotNil(t, instance, "Could not create new VM instance", "targetRuntime", targetRuntime)
+	return instance
+}
+
+// newInstanceFromGenesis instantiates the code of the storage of the configuration
+func newInstanceFromGenesis(t testing.TB, executor string, cfg runtime.InstanceConfig) (testInstance, error) {
+	t.Helper()
+	return newInstance(t, executor, cfg.Storage.LoadCode(), cfg)
+}
+
+// newInstanceFromTrie instantiates the code of the given trie
+func newInstanceFromTrie(t testing.TB, executor string, tt *trie.Trie, cfg runtime.InstanceConfig) (
+	testInstance, error) {
+	t.Helper()
+	return newInstance(t, executor, tt.Get(common.CodeKey), cfg)
+}
+
+// newInstance instantiates the code with the given wasm interpreter. The test is skipped
+// if the interpreter cannot execute the runtimes instantiating sandboxed modules.
+func newInstance(t testing.TB, executor string, code []byte, cfg runtime.InstanceConfig) (testInstance, error) {
+	t.Helper()
+
+	instantiate, ok := Lookup(executor)
+	require.Truef(t, ok, "unknown wasm interpreter %s", executor)
+
+	instance, err := instantiate(code, cfg)
+	if errors.Is(err, runtime.ErrSandboxNotSupported) {
+		t.Skipf("%s: %s", executor, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return instance.(testInstance), nil
+}
+
+func setupConfig(t *testing.T, tt *trie.Trie, lvl log.Level, role byte) runtime.InstanceConfig {
+	t.Helper()
+
+	s, err := storage.NewTrieState(tt)
+	require.NoError(t, err)
+
+	ns := runtime.NodeStorage{
+		LocalStorage:      runtime.NewInMemoryDB(t),
+		PersistentStorage: runtime.NewInMemoryDB(t), // we're using a local storage here since this is a test runtime
+		BaseDB:            runtime.NewInMemoryDB(t), // we're using a local storage here since this is a test runtime
+	}
+	return runtime.InstanceConfig{
+		Storage:     s,
+		Keystore:    keystore.NewGlobalKeystore(),
+		LogLvl:      lvl,
+		NodeStorage: ns,
+		Network:     new(runtime.TestRuntimeNetwork),
+		Transaction: newTransactionStateMock(),
+		Role:        role,
+	}
+}
+
+// newTransactionStateMock create and return an runtime Transaction State interface mock
+func newTransactionStateMock() *mocks.TransactionState {
+	m := new(mocks.TransactionState)
+	m.On("AddToPool", mock.AnythingOfType("*transaction.ValidTransaction")).Return(common.BytesToHash([]byte("test")))
+	return m
+}
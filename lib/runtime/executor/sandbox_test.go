@@ -0,0 +1,112 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/runtime/life"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/require"
+)
+
+// supervisorModule is the hand written binary of the following module, whose
+// dispatch thunk returns `Ok(Value(I32(state + index + args[0])))`:
+//
+//	(module
+//	  (import "env" "memory" (memory 1))
+//	  (import "env" "ext_allocator_malloc_version_1" (func $malloc (param i32) (result i32)))
+//	  (table 2 funcref)
+//	  (elem (i32.const 1) $thunk)
+//	  (func $thunk (param $args i32) (param $len i32) (param $state i32) (param $index i32) (result i64)
+//	    (local $ptr i32)
+//	    (local.set $ptr (call $malloc (i32.const 7)))
+//	    (i32.store (local.get $ptr) (i32.const 256))
+//	    (i32.store offset=3 align=1 (local.get $ptr)
+//	      (i32.add (i32.add (local.get $state) (local.get $index)) (i32.load offset=2 (local.get $args))))
+//	    (i64.or (i64.extend_i32_u (local.get $ptr)) (i64.const 30064771072))))
+var supervisorModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, // magic and version
+	// type section
+	0x01, 0x0e, 0x02,
+	0x60, 0x01, 0x7f, 0x01, 0x7f, // (i32) -> i32
+	0x60, 0x04, 0x7f, 0x7f, 0x7f, 0x7f, 0x01, 0x7e, // (i32, i32, i32, i32) -> i64
+	// import section
+	0x02, 0x34, 0x02,
+	0x03, 'e', 'n', 'v', 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00, 0x01,
+	0x03, 'e', 'n', 'v', 0x1e, 'e', 'x', 't', '_', 'a', 'l', 'l', 'o', 'c', 'a', 't', 'o', 'r', '_',
+	'm', 'a', 'l', 'l', 'o', 'c', '_', 'v', 'e', 'r', 's', 'i', 'o', 'n', '_', '1', 0x00, 0x00,
+	// function section
+	0x03, 0x02, 0x01, 0x01,
+	// table section
+	0x04, 0x04, 0x01, 0x70, 0x00, 0x02,
+	// element section
+	0x09, 0x07, 0x01, 0x00, 0x41, 0x01, 0x0b, 0x01, 0x01,
+	// code section
+	0x0a, 0x2f, 0x01, 0x2d,
+	0x01, 0x01, 0x7f, // local $ptr
+	0x41, 0x07, 0x10, 0x00, 0x21, 0x04, // malloc
+	0x20, 0x04, 0x41, 0x80, 0x02, 0x36, 0x02, 0x00, // Ok(Value(I32(...
+	0x20, 0x04, 0x20, 0x02, 0x20, 0x03, 0x6a, 0x20, 0x00, 0x28, 0x02, 0x02, 0x6a, 0x36, 0x00, 0x03, // value
+	0x20, 0x04, 0xad, 0x42, 0x80, 0x80, 0x80, 0x80, 0xf0, 0x00, 0x84, // pointer and length
+	0x0b,
+}
+
+// guestModule is the hand written binary of the following module:
+//
+//	(module
+//	  (import "env" "add" (func $add (param i32 i32) (result i32)))
+//	  (func (export "call_add") (param i32 i32) (result i32)
+//	    local.get 0 local.get 1 call $add))
+var guestModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, // magic and version
+	0x01, 0x07, 0x01, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7f, // type section
+	0x02, 0x0b, 0x01, 0x03, 'e', 'n', 'v', 0x03, 'a', 'd', 'd', 0x00, 0x00, // import section
+	0x03, 0x02, 0x01, 0x00, // function section
+	0x07, 0x0c, 0x01, 0x08, 'c', 'a', 'l', 'l', '_', 'a', 'd', 'd', 0x00, 0x01, // export section
+	0x0a, 0x0a, 0x01, 0x08, 0x00, 0x20, 0x00, 0x20, 0x01, 0x10, 0x00, 0x0b, // code section
+}
+
+const supervisorThunk = 1
+
+func TestSandboxDispatcher(t *testing.T) {
+	t.Parallel()
+	forEachExecutor(t, func(t *testing.T, executor string) {
+		t.Parallel()
+		if executor == life.Name {
+			t.Skip("life cannot dispatch the host functions of the sandboxed modules")
+		}
+
+		cfg := setupConfig(t, nil, DefaultTestLogLvl, 0)
+		instance, err := newInstance(t, executor, supervisorModule, cfg)
+		require.NoError(t, err)
+		t.Cleanup(instance.Stop)
+
+		// the host functions of the supervisor are called by the sandboxed instances
+		// through the dispatch thunks of the supervisor
+		store := instance.GetContext().Sandbox
+		env, err := scale.Marshal([]struct {
+			ModuleName []byte
+			FieldName  []byte
+			Kind       byte
+			Index      uint32
+		}{{
+			ModuleName: []byte("env"),
+			FieldName:  []byte("add"),
+			Index:      20,
+		}})
+		require.NoError(t, err)
+
+		index, err := store.Instantiate(guestModule, env, supervisorThunk, 10)
+		require.NoError(t, err)
+
+		guest, err := store.Instance(index)
+		require.NoError(t, err)
+
+		value, err := guest.Invoke("call_add", []sandbox.Value{sandbox.I32(5), sandbox.I32(6)}, 100)
+		require.NoError(t, err)
+		require.Equal(t, sandbox.I32(125), value)
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package hostapi
+
+import (
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+)
+
+var logger = log.NewFromGlobal(
+	log.AddContext("pkg", "runtime"),
+	log.AddContext("module", "hostapi"),
+)
+
+// SetLogLevel sets the log level of the host functions
+func SetLogLevel(level log.Level) {
+	logger.Patch(log.SetLevel(level), log.SetCallerFunc(true))
+}
+
+// Environment is the runtime instance a host function is called from. The host functions
+// only access the instance through it, so that they can be shared by the wasm executors.
+type Environment interface {
+	// Memory returns the memory of the runtime instance. The memory data must be
+	// retrieved again after an allocation, since the memory may have grown.
+	Memory() runtime.Memory
+	// Context returns the context of the runtime instance
+	Context() *runtime.Context
+	// Version instantiates the given runtime code with the same executor as the
+	// runtime instance, and returns its version.
+	Version(code []byte) (runtime.Version, error)
+}
@@ -0,0 +1,2274 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package hostapi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// ExtLoggingLogVersion1 implements ext_logging_log_version_1
+func ExtLoggingLogVersion1(env Environment, level int32, targetData, msgData int64) {
+	logger.Trace("executing...")
+	target := string(asMemorySlice(env, targetData))
+	msg := string(asMemorySlice(env, msgData))
+
+	switch int(level) {
+	case 0:
+		logger.Critical("target=" + target + " message=" + msg)
+	case 1:
+		logger.Warn("target=" + target + " message=" + msg)
+	case 2:
+		logger.Info("target=" + target + " message=" + msg)
+	case 3:
+		logger.Debug("target=" + target + " message=" + msg)
+	case 4:
+		logger.Trace("target=" + target + " message=" + msg)
+	default:
+		logger.Errorf("level=%d target=%s message=%s", int(level), target, msg)
+	}
+}
+
+// ExtLoggingMaxLevelVersion1 implements ext_logging_max_level_version_1
+func ExtLoggingMaxLevelVersion1(env Environment) int32 {
+	logger.Trace("executing...")
+	return 4
+}
+
+// ExtTransactionIndexIndexVersion1 implements ext_transaction_index_index_version_1
+func ExtTransactionIndexIndexVersion1(env Environment, extrinsic, size, hashPtr int32) {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	hash, err := readMemory(env, uint32(hashPtr), common.HashLength)
+	if err != nil {
+		logger.Errorf("failed to read hash of indexed transaction: %s", err)
+		return
+	}
+
+	runtimeCtx.Storage.IndexTransaction(uint32(extrinsic), uint32(size), common.BytesToHash(hash))
+}
+
+// ExtTransactionIndexRenewVersion1 implements ext_transaction_index_renew_version_1
+func ExtTransactionIndexRenewVersion1(env Environment, extrinsic, hashPtr int32) {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	hash, err := readMemory(env, uint32(hashPtr), common.HashLength)
+	if err != nil {
+		logger.Errorf("failed to read hash of renewed indexed transaction: %s", err)
+		return
+	}
+
+	runtimeCtx.Storage.RenewTransactionIndex(uint32(extrinsic), common.BytesToHash(hash))
+}
+
+// ExtSandboxInstanceTeardownVersion1 implements ext_sandbox_instance_teardown_version_1
+func ExtSandboxInstanceTeardownVersion1(env Environment, instanceIndex int32) {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	err := runtimeCtx.Sandbox.TeardownInstance(uint32(instanceIndex))
+	if err != nil {
+		logger.Errorf("failed to teardown sandboxed instance: %s", err)
+	}
+}
+
+// ExtSandboxInstantiateVersion1 implements ext_sandbox_instantiate_version_1
+func ExtSandboxInstantiateVersion1(env Environment, dispatchThunk int32,
+	wasmCodeSpan, envDefSpan int64, state int32) int32 {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	code := asMemorySlice(env, wasmCodeSpan)
+	envDef := asMemorySlice(env, envDefSpan)
+
+	index, err := runtimeCtx.Sandbox.Instantiate(code, envDef, uint32(dispatchThunk), uint32(state))
+	if errors.Is(err, sandbox.ErrExecution) {
+		logger.Debugf("failed to start sandboxed instance: %s", err)
+		return sandboxReturnCode(sandbox.ReturnExecution)
+	} else if err != nil {
+		logger.Debugf("failed to instantiate sandboxed module: %s", err)
+		return sandboxReturnCode(sandbox.ReturnModule)
+	}
+
+	return int32(index)
+}
+
+// ExtSandboxInvokeVersion1 implements ext_sandbox_invoke_version_1
+func ExtSandboxInvokeVersion1(env Environment, instanceIndex int32, exportNameSpan, argsSpan int64,
+	returnValuePtr, returnValueLen, state int32) int32 {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	instance, err := runtimeCtx.Sandbox.Instance(uint32(instanceIndex))
+	if err != nil {
+		logger.Errorf("failed to invoke sandboxed function: %s", err)
+		return sandboxReturnCode(sandbox.ReturnExecution)
+	}
+
+	name := string(asMemorySlice(env, exportNameSpan))
+	args, err := sandbox.DecodeValues(asMemorySlice(env, argsSpan))
+	if err != nil {
+		logger.Errorf("failed to decode arguments of sandboxed function %s: %s", name, err)
+		return sandboxReturnCode(sandbox.ReturnExecution)
+	}
+
+	result, err := instance.Invoke(name, args, uint32(state))
+	if err != nil {
+		logger.Debugf("failed to invoke sandboxed function %s: %s", name, err)
+		return sandboxReturnCode(sandbox.ReturnExecution)
+	}
+
+	if result == nil {
+		return sandboxReturnCode(sandbox.ReturnOK)
+	}
+
+	enc, err := sandbox.EncodeReturnValue(result)
+	if err != nil {
+		logger.Errorf("failed to encode value returned by sandboxed function %s: %s", name, err)
+		return sandboxReturnCode(sandbox.ReturnExecution)
+	}
+
+	// the memory of the runtime may have been grown by the host functions
+	memory := env.Memory().Data()
+	ptr := uint32(returnValuePtr)
+	if len(enc) > int(uint32(returnValueLen)) || uint64(ptr)+uint64(len(enc)) > uint64(len(memory)) {
+		logger.Errorf("buffer of value returned by sandboxed function %s is out of bounds", name)
+		return sandboxReturnCode(sandbox.ReturnOutOfBounds)
+	}
+
+	copy(memory[ptr:], enc)
+	return sandboxReturnCode(sandbox.ReturnOK)
+}
+
+// ExtSandboxMemoryGetVersion1 implements ext_sandbox_memory_get_version_1
+func ExtSandboxMemoryGetVersion1(env Environment, memoryIndex, offset, bufPtr, bufLen int32) int32 {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	sandboxMemory, err := runtimeCtx.Sandbox.Memory(uint32(memoryIndex))
+	if err != nil {
+		logger.Errorf("failed to get sandbox memory: %s", err)
+		return sandboxReturnCode(sandbox.ReturnOutOfBounds)
+	}
+
+	buf, ok := sandboxBuffer(env, bufPtr, bufLen)
+	if !ok {
+		return sandboxReturnCode(sandbox.ReturnOutOfBounds)
+	}
+
+	err = sandboxMemory.Get(uint32(offset), buf)
+	if err != nil {
+		return sandboxReturnCode(sandbox.ReturnOutOfBounds)
+	}
+
+	return sandboxReturnCode(sandbox.ReturnOK)
+}
+
+// ExtSandboxMemoryNewVersion1 implements ext_sandbox_memory_new_version_1
+func ExtSandboxMemoryNewVersion1(env Environment, initial, maximum int32) int32 {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	index, err := runtimeCtx.Sandbox.NewMemory(uint32(initial), uint32(maximum))
+	if err != nil {
+		logger.Errorf("failed to create sandbox memory: %s", err)
+		return sandboxReturnCode(sandbox.ReturnModule)
+	}
+
+	return int32(index)
+}
+
+// ExtSandboxMemorySetVersion1 implements ext_sandbox_memory_set_version_1
+func ExtSandboxMemorySetVersion1(env Environment, memoryIndex, offset, valuePtr, valueLen int32) int32 {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	sandboxMemory, err := runtimeCtx.Sandbox.Memory(uint32(memoryIndex))
+	if err != nil {
+		logger.Errorf("failed to set sandbox memory: %s", err)
+		return sandboxReturnCode(sandbox.ReturnOutOfBounds)
+	}
+
+	value, ok := sandboxBuffer(env, valuePtr, valueLen)
+	if !ok {
+		return sandboxReturnCode(sandbox.ReturnOutOfBounds)
+	}
+
+	err = sandboxMemory.Set(uint32(offset), value)
+	if err != nil {
+		return sandboxReturnCode(sandbox.ReturnOutOfBounds)
+	}
+
+	return sandboxReturnCode(sandbox.ReturnOK)
+}
+
+// ExtSandboxMemoryTeardownVersion1 implements ext_sandbox_memory_teardown_version_1
+func ExtSandboxMemoryTeardownVersion1(env Environment, memoryIndex int32) {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	err := runtimeCtx.Sandbox.TeardownMemory(uint32(memoryIndex))
+	if err != nil {
+		logger.Errorf("failed to teardown sandbox memory: %s", err)
+	}
+}
+
+// sandboxBuffer returns the slice of the memory of the runtime at the given pointer
+// and length, and false if it is out of bounds.
+func sandboxBuffer(env Environment, ptr, length int32) ([]byte, bool) {
+	memory := env.Memory().Data()
+	start := uint64(uint32(ptr))
+	end := start + uint64(uint32(length))
+	if end > uint64(len(memory)) {
+		return nil, false
+	}
+
+	return memory[start:end], true
+}
+
+// sandboxReturnCode converts the return code of a sandbox host function to its wasm representation.
+func sandboxReturnCode(code uint32) int32 {
+	return int32(code)
+}
+
+// ExtCryptoEd25519GenerateVersion1 implements ext_crypto_ed25519_generate_version_1
+func ExtCryptoEd25519GenerateVersion1(env Environment, keyTypeID int32, seedSpan int64) int32 {
+	logger.Trace("executing...")
+
+	runtimeCtx := env.Context()
+	memory := env.Memory().Data()
+
+	id := memory[keyTypeID : keyTypeID+4]
+	seedBytes := asMemorySlice(env, seedSpan)
+
+	var seed *[]byte
+	err := scale.Unmarshal(seedBytes, &seed)
+	if err != nil {
+		logger.Warnf("cannot generate key: %s", err)
+		return 0
+	}
+
+	var kp crypto.Keypair
+
+	if seed != nil {
+		kp, err = ed25519.NewKeypairFromMnenomic(string(*seed), "")
+	} else {
+		kp, err = ed25519.GenerateKeypair()
+	}
+
+	if err != nil {
+		logger.Warnf("cannot generate key: %s", err)
+		return 0
+	}
+
+	ks, err := runtimeCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id 0x%x: %s", id, err)
+		return 0
+	}
+
+	err = ks.Insert(kp)
+	if err != nil {
+		logger.Warnf("failed to insert key: %s", err)
+		return 0
+	}
+
+	ret, err := toWasmMemorySized(env, kp.Public().Encode(), 32)
+	if err != nil {
+		logger.Warnf("failed to allocate memory: %s", err)
+		return 0
+	}
+
+	logger.Debug("generated ed25519 keypair with public key: " + kp.Public().Hex())
+	return int32(ret)
+}
+
+// ExtCryptoEd25519PublicKeysVersion1 implements ext_crypto_ed25519_public_keys_version_1
+func ExtCryptoEd25519PublicKeysVersion1(env Environment, keyTypeID int32) int64 {
+	logger.Debug("executing...")
+
+	runtimeCtx := env.Context()
+	memory := env.Memory().Data()
+
+	id := memory[keyTypeID : keyTypeID+4]
+
+	ks, err := runtimeCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id 0x%x: %s", id, err)
+		ret, _ := toWasmMemory(env, []byte{0})
+		return int64(ret)
+	}
+
+	if ks.Type() != crypto.Ed25519Type && ks.Type() != crypto.UnknownType {
+		logger.Warnf(
+			"error for id 0x%x: keystore type is %s and not the expected ed25519",
+			id, ks.Type())
+		ret, _ := toWasmMemory(env, []byte{0})
+		return int64(ret)
+	}
+
+	keys := ks.PublicKeys()
+
+	var encodedKeys []byte
+	for _, key := range keys {
+		encodedKeys = append(encodedKeys, key.Encode()...)
+	}
+
+	prefix, err := scale.Marshal(big.NewInt(int64(len(keys))))
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		ret, _ := toWasmMemory(env, []byte{0})
+		return int64(ret)
+	}
+
+	ret, err := toWasmMemory(env, append(prefix, encodedKeys...))
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		ret, _ = toWasmMemory(env, []byte{0})
+		return int64(ret)
+	}
+
+	return int64(ret)
+}
+
+// ExtCryptoEd25519SignVersion1 implements ext_crypto_ed25519_sign_version_1
+func ExtCryptoEd25519SignVersion1(env Environment, keyTypeID, key int32, msg int64) int64 {
+	logger.Debug("executing...")
+
+	runtimeCtx := env.Context()
+	memory := env.Memory().Data()
+
+	id := memory[keyTypeID : keyTypeID+4]
+
+	pubKeyData := memory[key : key+32]
+	pubKey, err := ed25519.NewPublicKey(pubKeyData)
+	if err != nil {
+		logger.Errorf("failed to get public keys: %s", err)
+		return 0
+	}
+
+	ks, err := runtimeCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id 0x%x: %s", id, err)
+		ret, _ := toWasmMemoryOptional(env, nil)
+		return int64(ret)
+	}
+
+	var ret int64
+	signingKey := ks.GetKeypair(pubKey)
+	if signingKey == nil {
+		logger.Error("could not find public key " + pubKey.Hex() + " in keystore")
+		ret, err = toWasmMemoryOptional(env, nil)
+		if err != nil {
+			logger.Errorf("failed to allocate memory: %s", err)
+			return 0
+		}
+		return int64(ret)
+	}
+
+	sig, err := signingKey.Sign(asMemorySlice(env, msg))
+	if err != nil {
+		logger.Error("could not sign message")
+	}
+
+	ret, err = toWasmMemoryFixedSizeOptional(env, sig)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		return 0
+	}
+
+	return int64(ret)
+}
+
+// ExtCryptoEd25519VerifyVersion1 implements ext_crypto_ed25519_verify_version_1
+func ExtCryptoEd25519VerifyVersion1(env Environment, sig int32, msg int64, key int32) int32 {
+	logger.Debug("executing...")
+
+	memory := env.Memory().Data()
+	sigVerifier := env.Context().SigVerifier
+
+	signature := memory[sig : sig+64]
+	message := asMemorySlice(env, msg)
+	pubKeyData := memory[key : key+32]
+
+	pubKey, err := ed25519.NewPublicKey(pubKeyData)
+	if err != nil {
+		logger.Error("failed to create public key")
+		return 0
+	}
+
+	if sigVerifier.IsStarted() {
+		// the signature and message are copied, as the memory can be
+		// overwritten before the signature is verified
+		signature := crypto.SignatureInfo{
+			PubKey:     pubKey.Encode(),
+			Sign:       append([]byte{}, signature...),
+			Msg:        append([]byte{}, message...),
+			VerifyFunc: ed25519.VerifySignature,
+		}
+		sigVerifier.Add(&signature)
+		return 1
+	}
+
+	if ok, err := pubKey.Verify(message, signature); err != nil || !ok {
+		logger.Error("failed to verify")
+		return 0
+	}
+
+	logger.Debug("verified ed25519 signature")
+	return 1
+}
+
+// ExtCryptoSecp256k1EcdsaRecoverVersion1 implements ext_crypto_secp256k1_ecdsa_recover_version_1
+func ExtCryptoSecp256k1EcdsaRecoverVersion1(env Environment, sig, msg int32) int64 {
+	logger.Trace("executing...")
+	memory := env.Memory().Data()
+
+	// msg must be the 32-byte hash of the message to be signed.
+	// sig must be a 65-byte compact ECDSA signature containing the
+	// recovery id as the last element
+	message := memory[msg : msg+32]
+	signature := memory[sig : sig+65]
+
+	pub, err := secp256k1.RecoverPublicKey(message, signature)
+	if err != nil {
+		logger.Errorf("failed to recover public key: %s", err)
+		var ret int64
+		ret, err = toWasmMemoryResult(env, nil)
+		if err != nil {
+			logger.Errorf("failed to allocate memory: %s", err)
+			return 0
+		}
+		return int64(ret)
+	}
+
+	logger.Debugf(
+		"recovered public key of length %d: 0x%x",
+		len(pub), pub)
+
+	ret, err := toWasmMemoryResult(env, pub[1:])
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		return 0
+	}
+
+	return int64(ret)
+}
+
+// ExtCryptoSecp256k1EcdsaRecoverVersion2 implements ext_crypto_secp256k1_ecdsa_recover_version_2
+func ExtCryptoSecp256k1EcdsaRecoverVersion2(env Environment, sig, msg int32) int64 {
+	logger.Trace("executing...")
+	return ExtCryptoSecp256k1EcdsaRecoverVersion1(env, sig, msg)
+}
+
+// ExtCryptoEcdsaVerifyVersion2 implements ext_crypto_ecdsa_verify_version_2
+func ExtCryptoEcdsaVerifyVersion2(env Environment, sig int32, msg int64, key int32) int32 {
+	logger.Trace("executing...")
+
+	memory := env.Memory().Data()
+	sigVerifier := env.Context().SigVerifier
+
+	message := asMemorySlice(env, msg)
+	signature := memory[sig : sig+64]
+	pubKey := memory[key : key+33]
+
+	pub := new(secp256k1.PublicKey)
+	err := pub.Decode(pubKey)
+	if err != nil {
+		logger.Errorf("failed to decode public key: %s", err)
+		return 0
+	}
+
+	logger.Debugf("pub=%s, message=0x%x, signature=0x%x",
+		pub.Hex(), fmt.Sprintf("0x%x", message), fmt.Sprintf("0x%x", signature))
+
+	hash, err := common.Blake2bHash(message)
+	if err != nil {
+		logger.Errorf("failed to hash message: %s", err)
+		return 0
+	}
+
+	if sigVerifier.IsStarted() {
+		signature := crypto.SignatureInfo{
+			PubKey:     pub.Encode(),
+			Sign:       append([]byte{}, signature...),
+			Msg:        hash[:],
+			VerifyFunc: secp256k1.VerifySignature,
+		}
+		sigVerifier.Add(&signature)
+		return int32(1)
+	}
+
+	if ok, err := pub.Verify(hash[:], signature); err != nil || !ok {
+		logger.Errorf("failed to validate signature: %s", err)
+		return 0
+	}
+
+	logger.Debug("validated signature")
+	return int32(1)
+}
+
+// ExtCryptoSecp256k1EcdsaRecoverCompressedVersion1 implements ext_crypto_secp256k1_ecdsa_recover_compressed_version_1
+func ExtCryptoSecp256k1EcdsaRecoverCompressedVersion1(env Environment, sig, msg int32) int64 {
+	logger.Trace("executing...")
+	memory := env.Memory().Data()
+
+	// msg must be the 32-byte hash of the message to be signed.
+	// sig must be a 65-byte compact ECDSA signature containing the
+	// recovery id as the last element
+	message := memory[msg : msg+32]
+	signature := memory[sig : sig+65]
+
+	cpub, err := secp256k1.RecoverPublicKeyCompressed(message, signature)
+	if err != nil {
+		logger.Errorf("failed to recover public key: %s", err)
+		ret, _ := toWasmMemoryResult(env, nil)
+		return int64(ret)
+	}
+
+	logger.Debugf(
+		"recovered public key of length %d: 0x%x",
+		len(cpub), cpub)
+
+	ret, err := toWasmMemoryResult(env, cpub)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		return 0
+	}
+
+	return int64(ret)
+}
+
+// ExtCryptoSecp256k1EcdsaRecoverCompressedVersion2 implements ext_crypto_secp256k1_ecdsa_recover_compressed_version_2
+func ExtCryptoSecp256k1EcdsaRecoverCompressedVersion2(env Environment, sig, msg int32) int64 {
+	logger.Trace("executing...")
+	return ExtCryptoSecp256k1EcdsaRecoverCompressedVersion1(env, sig, msg)
+}
+
+// ExtCryptoSr25519GenerateVersion1 implements ext_crypto_sr25519_generate_version_1
+func ExtCryptoSr25519GenerateVersion1(env Environment, keyTypeID int32, seedSpan int64) int32 {
+	logger.Trace("executing...")
+
+	runtimeCtx := env.Context()
+	memory := env.Memory().Data()
+
+	id := memory[keyTypeID : keyTypeID+4]
+	seedBytes := asMemorySlice(env, seedSpan)
+
+	var seed *[]byte
+	err := scale.Unmarshal(seedBytes, &seed)
+	if err != nil {
+		logger.Warnf("cannot generate key: %s", err)
+		return 0
+	}
+
+	var kp crypto.Keypair
+	if seed != nil {
+		kp, err = sr25519.NewKeypairFromMnenomic(string(*seed), "")
+	} else {
+		kp, err = sr25519.GenerateKeypair()
+	}
+
+	if err != nil {
+		logger.Tracef("cannot generate key: %s", err)
+		panic(err)
+	}
+
+	ks, err := runtimeCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id "+common.BytesToHex(id)+": %s", err)
+		return 0
+	}
+
+	err = ks.Insert(kp)
+	if err != nil {
+		logger.Warnf("failed to insert key: %s", err)
+		return 0
+	}
+
+	ret, err := toWasmMemorySized(env, kp.Public().Encode(), 32)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		return 0
+	}
+
+	logger.Debug("generated sr25519 keypair with public key: " + kp.Public().Hex())
+	return int32(ret)
+}
+
+// ExtCryptoSr25519PublicKeysVersion1 implements ext_crypto_sr25519_public_keys_version_1
+func ExtCryptoSr25519PublicKeysVersion1(env Environment, keyTypeID int32) int64 {
+	logger.Debug("executing...")
+
+	runtimeCtx := env.Context()
+	memory := env.Memory().Data()
+
+	id := memory[keyTypeID : keyTypeID+4]
+
+	ks, err := runtimeCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id "+common.BytesToHex(id)+": %s", err)
+		ret, _ := toWasmMemory(env, []byte{0})
+		return int64(ret)
+	}
+
+	if ks.Type() != crypto.Sr25519Type && ks.Type() != crypto.UnknownType {
+		logger.Warnf(
+			"keystore type for id 0x%x is %s and not expected sr25519",
+			id, ks.Type())
+		ret, _ := toWasmMemory(env, []byte{0})
+		return int64(ret)
+	}
+
+	keys := ks.PublicKeys()
+
+	var encodedKeys []byte
+	for _, key := range keys {
+		encodedKeys = append(encodedKeys, key.Encode()...)
+	}
+
+	prefix, err := scale.Marshal(big.NewInt(int64(len(keys))))
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		ret, _ := toWasmMemory(env, []byte{0})
+		return int64(ret)
+	}
+
+	ret, err := toWasmMemory(env, append(prefix, encodedKeys...))
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		ret, _ = toWasmMemory(env, []byte{0})
+		return int64(ret)
+	}
+
+	return int64(ret)
+}
+
+// ExtCryptoSr25519SignVersion1 implements ext_crypto_sr25519_sign_version_1
+func ExtCryptoSr25519SignVersion1(env Environment, keyTypeID, key int32, msg int64) int64 {
+	logger.Debug("executing...")
+	runtimeCtx := env.Context()
+	memory := env.Memory().Data()
+
+	emptyRet, _ := toWasmMemoryOptional(env, nil)
+
+	id := memory[keyTypeID : keyTypeID+4]
+
+	ks, err := runtimeCtx.Keystore.GetKeystore(id)
+	if err != nil {
+		logger.Warnf("error for id 0x%x: %s", id, err)
+		return int64(emptyRet)
+	}
+
+	var ret int64
+	pubKey, err := sr25519.NewPublicKey(memory[key : key+32])
+	if err != nil {
+		logger.Errorf("failed to get public key: %s", err)
+		return int64(emptyRet)
+	}
+
+	signingKey := ks.GetKeypair(pubKey)
+	if signingKey == nil {
+		logger.Error("could not find public key " + pubKey.Hex() + " in keystore")
+		return int64(emptyRet)
+	}
+
+	msgData := asMemorySlice(env, msg)
+	sig, err := signingKey.Sign(msgData)
+	if err != nil {
+		logger.Errorf("could not sign message: %s", err)
+		return int64(emptyRet)
+	}
+
+	ret, err = toWasmMemoryFixedSizeOptional(env, sig)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		return int64(emptyRet)
+	}
+
+	return int64(ret)
+}
+
+// ExtCryptoSr25519VerifyVersion1 implements ext_crypto_sr25519_verify_version_1
+func ExtCryptoSr25519VerifyVersion1(env Environment, sig int32, msg int64, key int32) int32 {
+	logger.Debug("executing...")
+
+	memory := env.Memory().Data()
+
+	message := asMemorySlice(env, msg)
+	signature := memory[sig : sig+64]
+
+	pub, err := sr25519.NewPublicKey(memory[key : key+32])
+	if err != nil {
+		logger.Error("invalid sr25519 public key")
+		return 0
+	}
+
+	logger.Debugf(
+		"pub=%s message=0x%x signature=0x%x",
+		pub.Hex(), message, signature)
+
+	// the deprecated verification is never batched
+	if ok, err := pub.VerifyDeprecated(message, signature); err != nil || !ok {
+		logger.Debugf("failed to validate signature: %s", err)
+		// this fails at block 3876, which seems to be expected, based on discussions
+		return 1
+	}
+
+	logger.Debug("verified sr25519 signature")
+	return 1
+}
+
+// ExtCryptoSr25519VerifyVersion2 implements ext_crypto_sr25519_verify_version_2
+func ExtCryptoSr25519VerifyVersion2(env Environment, sig int32, msg int64, key int32) int32 {
+	logger.Trace("executing...")
+
+	memory := env.Memory().Data()
+	sigVerifier := env.Context().SigVerifier
+
+	message := asMemorySlice(env, msg)
+	signature := memory[sig : sig+64]
+
+	pub, err := sr25519.NewPublicKey(memory[key : key+32])
+	if err != nil {
+		logger.Error("invalid sr25519 public key")
+		return 0
+	}
+
+	logger.Debugf(
+		"pub=%s; message=0x%x; signature=0x%x",
+		pub.Hex(), message, signature)
+
+	if sigVerifier.IsStarted() {
+		signature := crypto.SignatureInfo{
+			PubKey:     pub.Encode(),
+			Sign:       append([]byte{}, signature...),
+			Msg:        append([]byte{}, message...),
+			VerifyFunc: sr25519.VerifySignature,
+		}
+		sigVerifier.Add(&signature)
+		return 1
+	}
+
+	if ok, err := pub.Verify(message, signature); err != nil || !ok {
+		logger.Errorf("failed to validate signature: %s", err)
+		return 0
+	}
+
+	logger.Debug("validated signature")
+	return int32(1)
+}
+
+// ExtCryptoStartBatchVerifyVersion1 implements ext_crypto_start_batch_verify_version_1
+func ExtCryptoStartBatchVerifyVersion1(env Environment) {
+	logger.Debug("executing...")
+
+	sigVerifier := env.Context().SigVerifier
+
+	if sigVerifier.IsStarted() {
+		logger.Error("batch verification is already started")
+		return
+	}
+
+	sigVerifier.Start()
+}
+
+// ExtCryptoFinishBatchVerifyVersion1 implements ext_crypto_finish_batch_verify_version_1
+func ExtCryptoFinishBatchVerifyVersion1(env Environment) int32 {
+	logger.Debug("executing...")
+
+	sigVerifier := env.Context().SigVerifier
+
+	if !sigVerifier.IsStarted() {
+		logger.Error("batch verification is not started")
+		return 0
+	}
+
+	if !sigVerifier.Finish() {
+		logger.Error("failed to verify the signatures of the batch")
+		return 0
+	}
+
+	return 1
+}
+
+// ExtTrieBlake2256RootVersion1 implements ext_trie_blake2_256_root_version_1
+func ExtTrieBlake2256RootVersion1(env Environment, dataSpan int64) int32 {
+	logger.Debug("executing...")
+
+	memory := env.Memory().Data()
+	runtimeCtx := env.Context()
+	data := asMemorySlice(env, dataSpan)
+
+	t := trie.NewEmptyTrie()
+
+	type kv struct {
+		Key, Value []byte
+	}
+
+	// this function is expecting an array of (key, value) tuples
+	var kvs []kv
+	if err := scale.Unmarshal(data, &kvs); err != nil {
+		logger.Errorf("[ext_trie_blake2_256_root_version_1]: %s", err)
+		return 0
+	}
+
+	for _, kv := range kvs {
+		t.Put(kv.Key, kv.Value)
+	}
+
+	// allocate memory for value and copy value to memory
+	ptr, err := runtimeCtx.Allocator.Allocate(32)
+	if err != nil {
+		logger.Errorf("[ext_trie_blake2_256_root_version_1]: %s", err)
+		return 0
+	}
+
+	hash, err := t.Hash()
+	if err != nil {
+		logger.Errorf("[ext_trie_blake2_256_root_version_1]: %s", err)
+		return 0
+	}
+
+	logger.Debugf("[ext_trie_blake2_256_root_version_1]: root hash is %s", hash)
+	copy(memory[ptr:ptr+32], hash[:])
+	return int32(ptr)
+}
+
+// ExtTrieBlake2256OrderedRootVersion1 implements ext_trie_blake2_256_ordered_root_version_1
+func ExtTrieBlake2256OrderedRootVersion1(env Environment, dataSpan int64) int32 {
+	logger.Debug("executing...")
+	return trieBlake2256OrderedRoot(env, dataSpan, trie.V0)
+}
+
+// ExtTrieBlake2256OrderedRootVersion2 implements ext_trie_blake2_256_ordered_root_version_2
+func ExtTrieBlake2256OrderedRootVersion2(env Environment, dataSpan int64, version int32) int32 {
+	logger.Debug("executing...")
+
+	stateVersion, err := trie.ParseVersion(uint32(version))
+	if err != nil {
+		logger.Errorf("[ext_trie_blake2_256_ordered_root_version_2]: %s", err)
+		return 0
+	}
+
+	return trieBlake2256OrderedRoot(env, dataSpan, stateVersion)
+}
+
+// trieBlake2256OrderedRoot returns the pointer to the root of the trie built with the
+// values given, keyed by their SCALE encoded index, using the state trie version given.
+func trieBlake2256OrderedRoot(env Environment, dataSpan int64, version trie.Version) int32 {
+	memory := env.Memory().Data()
+	runtimeCtx := env.Context()
+	data := asMemorySlice(env, dataSpan)
+
+	t := trie.NewEmptyTrie()
+	var values [][]byte
+	err := scale.Unmarshal(data, &values)
+	if err != nil {
+		logger.Errorf("[ext_trie_blake2_256_ordered_root]: %s", err)
+		return 0
+	}
+
+	for i, val := range values {
+		key, err := scale.Marshal(big.NewInt(int64(i)))
+		if err != nil {
+			logger.Errorf("[ext_trie_blake2_256_ordered_root]: %s", err)
+			return 0
+		}
+		logger.Tracef(
+			"put key=0x%x and value=0x%x",
+			key, val)
+
+		t.Put(key, val)
+	}
+
+	// allocate memory for value and copy value to memory
+	ptr, err := runtimeCtx.Allocator.Allocate(32)
+	if err != nil {
+		logger.Errorf("[ext_trie_blake2_256_ordered_root]: %s", err)
+		return 0
+	}
+
+	hash, err := t.HashWithVersion(version)
+	if err != nil {
+		logger.Errorf("[ext_trie_blake2_256_ordered_root]: %s", err)
+		return 0
+	}
+
+	logger.Debugf("[ext_trie_blake2_256_ordered_root]: root hash is %s for state version %s", hash, version)
+	copy(memory[ptr:ptr+32], hash[:])
+	return int32(ptr)
+}
+
+// ExtTrieBlake2256VerifyProofVersion1 implements ext_trie_blake2_256_verify_proof_version_1
+func ExtTrieBlake2256VerifyProofVersion1(env Environment, rootSpan int32, proofSpan, keySpan, valueSpan int64) int32 {
+	logger.Debug("executing...")
+
+	toDecProofs := asMemorySlice(env, proofSpan)
+	var decProofs [][]byte
+	err := scale.Unmarshal(toDecProofs, &decProofs)
+	if err != nil {
+		logger.Errorf("[ext_trie_blake2_256_verify_proof_version_1]: %s", err)
+		return 0
+	}
+
+	key := asMemorySlice(env, keySpan)
+	value := asMemorySlice(env, valueSpan)
+
+	mem := env.Memory().Data()
+	trieRoot := mem[rootSpan : rootSpan+32]
+
+	exists, err := trie.VerifyProof(decProofs, trieRoot, []trie.Pair{{Key: key, Value: value}})
+	if err != nil {
+		logger.Errorf("[ext_trie_blake2_256_verify_proof_version_1]: %s", err)
+		return 0
+	}
+
+	var result int32 = 0
+	if exists {
+		result = 1
+	}
+
+	return result
+}
+
+// ExtMiscPrintHexVersion1 implements ext_misc_print_hex_version_1
+func ExtMiscPrintHexVersion1(env Environment, dataSpan int64) {
+	logger.Trace("executing...")
+
+	data := asMemorySlice(env, dataSpan)
+	logger.Debugf("data: 0x%x", data)
+}
+
+// ExtMiscPrintNumVersion1 implements ext_misc_print_num_version_1
+func ExtMiscPrintNumVersion1(env Environment, data int64) {
+	logger.Trace("executing...")
+
+	logger.Debugf("num: %d", int64(data))
+}
+
+// ExtMiscPrintUtf8Version1 implements ext_misc_print_utf8_version_1
+func ExtMiscPrintUtf8Version1(env Environment, dataSpan int64) {
+	logger.Trace("executing...")
+
+	data := asMemorySlice(env, dataSpan)
+	logger.Debug("utf8: " + string(data))
+}
+
+// ExtMiscRuntimeVersionVersion1 implements ext_misc_runtime_version_version_1
+func ExtMiscRuntimeVersionVersion1(env Environment, dataSpan int64) int64 {
+	logger.Trace("executing...")
+
+	data := asMemorySlice(env, dataSpan)
+
+	version, err := env.Version(data)
+	if err != nil {
+		logger.Errorf("failed to get runtime version: %s", err)
+		out, _ := toWasmMemoryOptional(env, nil)
+		return out
+	}
+
+	encodedData, err := version.Encode()
+	if err != nil {
+		logger.Errorf("failed to encode result: %s", err)
+		return 0
+	}
+
+	out, err := toWasmMemoryOptional(env, encodedData)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return out
+}
+
+// ExtDefaultChildStorageReadVersion1 implements ext_default_child_storage_read_version_1
+func ExtDefaultChildStorageReadVersion1(env Environment, childStorageKey, key, valueOut int64, offset int32) int64 {
+	logger.Debug("executing...")
+
+	storage := env.Context().Storage
+	memory := env.Memory().Data()
+
+	value, err := storage.GetChildStorage(asMemorySlice(env, childStorageKey), asMemorySlice(env, key))
+	if err != nil {
+		logger.Errorf("failed to get child storage: %s", err)
+		return 0
+	}
+
+	valueBuf, valueLen := runtime.Int64ToPointerAndSize(int64(valueOut))
+	copy(memory[valueBuf:valueBuf+valueLen], value[offset:])
+
+	size := uint32(len(value[offset:]))
+	sizeBuf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(sizeBuf, size)
+
+	sizeSpan, err := toWasmMemoryOptional(env, sizeBuf)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int64(sizeSpan)
+}
+
+// ExtDefaultChildStorageClearVersion1 implements ext_default_child_storage_clear_version_1
+func ExtDefaultChildStorageClearVersion1(env Environment, childStorageKey, keySpan int64) {
+	logger.Debug("executing...")
+
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	keyToChild := asMemorySlice(env, childStorageKey)
+	key := asMemorySlice(env, keySpan)
+
+	err := storage.ClearChildStorage(keyToChild, key)
+	if err != nil {
+		logger.Errorf("failed to clear child storage: %s", err)
+	}
+}
+
+// ExtDefaultChildStorageClearPrefixVersion1 implements ext_default_child_storage_clear_prefix_version_1
+func ExtDefaultChildStorageClearPrefixVersion1(env Environment, childStorageKey, prefixSpan int64) {
+	logger.Debug("executing...")
+
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	keyToChild := asMemorySlice(env, childStorageKey)
+	prefix := asMemorySlice(env, prefixSpan)
+
+	err := storage.ClearPrefixInChild(keyToChild, prefix)
+	if err != nil {
+		logger.Errorf("failed to clear prefix in child: %s", err)
+	}
+}
+
+// ExtDefaultChildStorageExistsVersion1 implements ext_default_child_storage_exists_version_1
+func ExtDefaultChildStorageExistsVersion1(env Environment, childStorageKey, key int64) int32 {
+	logger.Debug("executing...")
+
+	storage := env.Context().Storage
+
+	child, err := storage.GetChildStorage(asMemorySlice(env, childStorageKey), asMemorySlice(env, key))
+	if err != nil {
+		logger.Errorf("failed to get child from child storage: %s", err)
+		return 0
+	}
+	if child != nil {
+		return 1
+	}
+	return 0
+}
+
+// ExtDefaultChildStorageGetVersion1 implements ext_default_child_storage_get_version_1
+func ExtDefaultChildStorageGetVersion1(env Environment, childStorageKey, key int64) int64 {
+	logger.Debug("executing...")
+
+	storage := env.Context().Storage
+
+	child, err := storage.GetChildStorage(asMemorySlice(env, childStorageKey), asMemorySlice(env, key))
+	if err != nil {
+		logger.Errorf("failed to get child from child storage: %s", err)
+		return 0
+	}
+
+	value, err := toWasmMemoryOptional(env, child)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int64(value)
+}
+
+// ExtDefaultChildStorageNextKeyVersion1 implements ext_default_child_storage_next_key_version_1
+func ExtDefaultChildStorageNextKeyVersion1(env Environment, childStorageKey, key int64) int64 {
+	logger.Debug("executing...")
+
+	storage := env.Context().Storage
+
+	child, err := storage.GetChildNextKey(asMemorySlice(env, childStorageKey), asMemorySlice(env, key))
+	if err != nil {
+		logger.Errorf("failed to get child's next key: %s", err)
+		return 0
+	}
+
+	value, err := toWasmMemoryOptional(env, child)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int64(value)
+}
+
+// ExtDefaultChildStorageRootVersion1 implements ext_default_child_storage_root_version_1
+func ExtDefaultChildStorageRootVersion1(env Environment, childStorageKey int64) int64 {
+	logger.Debug("executing...")
+
+	storage := env.Context().Storage
+
+	child, err := storage.GetChild(asMemorySlice(env, childStorageKey))
+	if err != nil {
+		logger.Errorf("failed to retrieve child: %s", err)
+		return 0
+	}
+
+	childRoot, err := child.Hash()
+	if err != nil {
+		logger.Errorf("failed to encode child root: %s", err)
+		return 0
+	}
+
+	root, err := toWasmMemoryOptional(env, childRoot[:])
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int64(root)
+}
+
+// ExtDefaultChildStorageSetVersion1 implements ext_default_child_storage_set_version_1
+func ExtDefaultChildStorageSetVersion1(env Environment, childStorageKeySpan, keySpan, valueSpan int64) {
+	logger.Debug("executing...")
+
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	childStorageKey := asMemorySlice(env, childStorageKeySpan)
+	key := asMemorySlice(env, keySpan)
+	value := asMemorySlice(env, valueSpan)
+
+	cp := make([]byte, len(value))
+	copy(cp, value)
+
+	err := storage.SetChildStorage(childStorageKey, key, cp)
+	if err != nil {
+		logger.Errorf("failed to set value in child storage: %s", err)
+		return
+	}
+}
+
+// ExtDefaultChildStorageStorageKillVersion1 implements ext_default_child_storage_storage_kill_version_1
+func ExtDefaultChildStorageStorageKillVersion1(env Environment, childStorageKeySpan int64) {
+	logger.Debug("executing...")
+
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	childStorageKey := asMemorySlice(env, childStorageKeySpan)
+	storage.DeleteChild(childStorageKey)
+}
+
+// ExtDefaultChildStorageStorageKillVersion2 implements ext_default_child_storage_storage_kill_version_2
+func ExtDefaultChildStorageStorageKillVersion2(env Environment, childStorageKeySpan, lim int64) int32 {
+	logger.Debug("executing...")
+
+	ctx := env.Context()
+	storage := ctx.Storage
+	childStorageKey := asMemorySlice(env, childStorageKeySpan)
+
+	limitBytes := asMemorySlice(env, lim)
+
+	var limit *[]byte
+	err := scale.Unmarshal(limitBytes, &limit)
+	if err != nil {
+		logger.Warnf("cannot generate limit: %s", err)
+		return 0
+	}
+
+	_, all, err := storage.DeleteChildLimit(childStorageKey, limit)
+	if err != nil {
+		logger.Warnf("cannot get child storage: %s", err)
+	}
+
+	if all {
+		return 1
+	}
+
+	return 0
+}
+
+type noneRemain uint32
+type someRemain uint32
+
+func (noneRemain) Index() uint {
+	return 0
+}
+func (someRemain) Index() uint {
+	return 1
+}
+
+// ExtDefaultChildStorageStorageKillVersion3 implements ext_default_child_storage_storage_kill_version_3
+func ExtDefaultChildStorageStorageKillVersion3(env Environment, childStorageKeySpan, lim int64) int64 {
+	logger.Debug("executing...")
+	ctx := env.Context()
+	storage := ctx.Storage
+	childStorageKey := asMemorySlice(env, childStorageKeySpan)
+
+	limitBytes := asMemorySlice(env, lim)
+
+	var limit *[]byte
+	err := scale.Unmarshal(limitBytes, &limit)
+	if err != nil {
+		logger.Warnf("cannot generate limit: %s", err)
+	}
+
+	deleted, all, err := storage.DeleteChildLimit(childStorageKey, limit)
+	if err != nil {
+		logger.Warnf("cannot get child storage: %s", err)
+		return 0
+	}
+
+	vdt, err := scale.NewVaryingDataType(noneRemain(0), someRemain(0))
+	if err != nil {
+		logger.Warnf("cannot create new varying data type: %s", err)
+	}
+
+	if all {
+		err = vdt.Set(noneRemain(deleted))
+	} else {
+		err = vdt.Set(someRemain(deleted))
+	}
+	if err != nil {
+		logger.Warnf("cannot set varying data type: %s", err)
+		return 0
+	}
+
+	encoded, err := scale.Marshal(vdt)
+	if err != nil {
+		logger.Warnf("problem marshaling varying data type: %s", err)
+		return 0
+	}
+
+	out, err := toWasmMemoryOptional(env, encoded)
+	if err != nil {
+		logger.Warnf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return out
+}
+
+// ExtAllocatorFreeVersion1 implements ext_allocator_free_version_1
+func ExtAllocatorFreeVersion1(env Environment, addr int32) {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	// Deallocate memory
+	err := runtimeCtx.Allocator.Deallocate(uint32(addr))
+	if err != nil {
+		logger.Errorf("failed to free memory: %s", err)
+	}
+}
+
+// ExtAllocatorMallocVersion1 implements ext_allocator_malloc_version_1
+func ExtAllocatorMallocVersion1(env Environment, size int32) int32 {
+	logger.Tracef("executing with size %d...", int64(size))
+
+	ctx := env.Context()
+
+	// Allocate memory
+	res, err := ctx.Allocator.Allocate(uint32(size))
+	if err != nil {
+		logger.Criticalf("failed to allocate memory: %s", err)
+		panic(err)
+	}
+
+	return int32(res)
+}
+
+// ExtHashingBlake2128Version1 implements ext_hashing_blake2_128_version_1
+func ExtHashingBlake2128Version1(env Environment, dataSpan int64) int32 {
+	logger.Trace("executing...")
+	data := asMemorySlice(env, dataSpan)
+
+	hash, err := common.Blake2b128(data)
+	if err != nil {
+		logger.Errorf("[ext_hashing_blake2_128_version_1]: %s", err)
+		return 0
+	}
+
+	logger.Debugf(
+		"data 0x%x has hash 0x%x",
+		data, hash)
+
+	out, err := toWasmMemorySized(env, hash, 16)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int32(out)
+}
+
+// ExtHashingBlake2256Version1 implements ext_hashing_blake2_256_version_1
+func ExtHashingBlake2256Version1(env Environment, dataSpan int64) int32 {
+	logger.Trace("executing...")
+	data := asMemorySlice(env, dataSpan)
+
+	hash, err := common.Blake2bHash(data)
+	if err != nil {
+		logger.Errorf("[ext_hashing_blake2_256_version_1]: %s", err)
+		return 0
+	}
+
+	logger.Debugf("data 0x%x has hash %s", data, hash)
+
+	out, err := toWasmMemorySized(env, hash[:], 32)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int32(out)
+}
+
+// ExtHashingKeccak256Version1 implements ext_hashing_keccak_256_version_1
+func ExtHashingKeccak256Version1(env Environment, dataSpan int64) int32 {
+	logger.Trace("executing...")
+	data := asMemorySlice(env, dataSpan)
+
+	hash, err := common.Keccak256(data)
+	if err != nil {
+		logger.Errorf("[ext_hashing_keccak_256_version_1]: %s", err)
+		return 0
+	}
+
+	logger.Debugf("data 0x%x has hash %s", data, hash)
+
+	out, err := toWasmMemorySized(env, hash[:], 32)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int32(out)
+}
+
+// ExtHashingSha2256Version1 implements ext_hashing_sha2_256_version_1
+func ExtHashingSha2256Version1(env Environment, dataSpan int64) int32 {
+	logger.Trace("executing...")
+	data := asMemorySlice(env, dataSpan)
+	hash := common.Sha256(data)
+
+	logger.Debugf("data 0x%x has hash %s", data, hash)
+
+	out, err := toWasmMemorySized(env, hash[:], 32)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int32(out)
+}
+
+// ExtHashingTwox256Version1 implements ext_hashing_twox_256_version_1
+func ExtHashingTwox256Version1(env Environment, dataSpan int64) int32 {
+	logger.Trace("executing...")
+	data := asMemorySlice(env, dataSpan)
+
+	hash, err := common.Twox256(data)
+	if err != nil {
+		logger.Errorf("[ext_hashing_twox_256_version_1]: %s", err)
+		return 0
+	}
+
+	logger.Debugf("data 0x%x has hash %s", data, hash)
+
+	out, err := toWasmMemorySized(env, hash[:], 32)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int32(out)
+}
+
+// ExtHashingTwox128Version1 implements ext_hashing_twox_128_version_1
+func ExtHashingTwox128Version1(env Environment, dataSpan int64) int32 {
+	logger.Trace("executing...")
+	data := asMemorySlice(env, dataSpan)
+
+	hash, err := common.Twox128Hash(data)
+	if err != nil {
+		logger.Errorf("[ext_hashing_twox_128_version_1]: %s", err)
+		return 0
+	}
+
+	logger.Debugf(
+		"data 0x%x hash hash 0x%x",
+		data, hash)
+
+	out, err := toWasmMemorySized(env, hash, 16)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int32(out)
+}
+
+// ExtHashingTwox64Version1 implements ext_hashing_twox_64_version_1
+func ExtHashingTwox64Version1(env Environment, dataSpan int64) int32 {
+	logger.Trace("executing...")
+	data := asMemorySlice(env, dataSpan)
+
+	hash, err := common.Twox64(data)
+	if err != nil {
+		logger.Errorf("[ext_hashing_twox_64_version_1]: %s", err)
+		return 0
+	}
+
+	logger.Debugf(
+		"data 0x%x has hash 0x%x",
+		data, hash)
+
+	out, err := toWasmMemorySized(env, hash, 8)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int32(out)
+}
+
+// ExtOffchainIndexSetVersion1 implements ext_offchain_index_set_version_1
+func ExtOffchainIndexSetVersion1(env Environment, keySpan, valueSpan int64) {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	storageKey := asMemorySlice(env, keySpan)
+	newValue := asMemorySlice(env, valueSpan)
+	cp := make([]byte, len(newValue))
+	copy(cp, newValue)
+
+	cpKey := make([]byte, len(storageKey))
+	copy(cpKey, storageKey)
+
+	runtimeCtx.Storage.SetOffchainIndex(cpKey, cp)
+}
+
+// ExtOffchainIndexClearVersion1 implements ext_offchain_index_clear_version_1
+func ExtOffchainIndexClearVersion1(env Environment, keySpan int64) {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	storageKey := asMemorySlice(env, keySpan)
+	cp := make([]byte, len(storageKey))
+	copy(cp, storageKey)
+
+	runtimeCtx.Storage.ClearOffchainIndex(cp)
+}
+
+// ExtOffchainLocalStorageClearVersion1 implements ext_offchain_local_storage_clear_version_1
+func ExtOffchainLocalStorageClearVersion1(env Environment, kind int32, key int64) {
+	logger.Trace("executing...")
+	runtimeCtx := env.Context()
+
+	storageKey := asMemorySlice(env, key)
+
+	memory := env.Memory().Data()
+	kindInt := binary.LittleEndian.Uint32(memory[kind : kind+4])
+
+	var err error
+
+	switch runtime.NodeStorageType(kindInt) {
+	case runtime.NodeStorageTypePersistent:
+		err = runtimeCtx.NodeStorage.PersistentStorage.Del(storageKey)
+	case runtime.NodeStorageTypeLocal:
+		err = runtimeCtx.NodeStorage.LocalStorage.Del(storageKey)
+	}
+
+	if err != nil {
+		logger.Errorf("failed to clear value from storage: %s", err)
+	}
+}
+
+// ExtOffchainIsValidatorVersion1 implements ext_offchain_is_validator_version_1
+func ExtOffchainIsValidatorVersion1(env Environment) int32 {
+	logger.Debug("executing...")
+	runtimeCtx := env.Context()
+	if runtimeCtx.Validator {
+		return 1
+	}
+	return 0
+}
+
+// ExtOffchainLocalStorageCompareAndSetVersion1 implements ext_offchain_local_storage_compare_and_set_version_1
+func ExtOffchainLocalStorageCompareAndSetVersion1(env Environment, kind int32, key, oldValue, newValue int64) int32 {
+	logger.Debug("executing...")
+
+	runtimeCtx := env.Context()
+
+	storageKey := asMemorySlice(env, key)
+
+	var storedValue []byte
+	var err error
+
+	switch runtime.NodeStorageType(kind) {
+	case runtime.NodeStorageTypePersistent:
+		storedValue, err = runtimeCtx.NodeStorage.PersistentStorage.Get(storageKey)
+	case runtime.NodeStorageTypeLocal:
+		storedValue, err = runtimeCtx.NodeStorage.LocalStorage.Get(storageKey)
+	}
+
+	if err != nil {
+		logger.Errorf("failed to get value from storage: %s", err)
+		return 0
+	}
+
+	oldVal := asMemorySlice(env, oldValue)
+	newVal := asMemorySlice(env, newValue)
+	if reflect.DeepEqual(storedValue, oldVal) {
+		cp := make([]byte, len(newVal))
+		copy(cp, newVal)
+		err = runtimeCtx.NodeStorage.LocalStorage.Put(storageKey, cp)
+		if err != nil {
+			logger.Errorf("failed to set value in storage: %s", err)
+			return 0
+		}
+	}
+
+	return 1
+}
+
+// ExtOffchainLocalStorageGetVersion1 implements ext_offchain_local_storage_get_version_1
+func ExtOffchainLocalStorageGetVersion1(env Environment, kind int32, key int64) int64 {
+	logger.Debug("executing...")
+
+	runtimeCtx := env.Context()
+	storageKey := asMemorySlice(env, key)
+
+	var res []byte
+	var err error
+
+	switch runtime.NodeStorageType(kind) {
+	case runtime.NodeStorageTypePersistent:
+		res, err = runtimeCtx.NodeStorage.PersistentStorage.Get(storageKey)
+	case runtime.NodeStorageTypeLocal:
+		res, err = runtimeCtx.NodeStorage.LocalStorage.Get(storageKey)
+	}
+
+	if err != nil {
+		logger.Errorf("failed to get value from storage: %s", err)
+	}
+	// allocate memory for value and copy value to memory
+	ptr, err := toWasmMemoryOptional(env, res)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		return 0
+	}
+	return int64(ptr)
+}
+
+// ExtOffchainLocalStorageSetVersion1 implements ext_offchain_local_storage_set_version_1
+func ExtOffchainLocalStorageSetVersion1(env Environment, kind int32, key, value int64) {
+	logger.Debug("executing...")
+
+	runtimeCtx := env.Context()
+	storageKey := asMemorySlice(env, key)
+	newValue := asMemorySlice(env, value)
+	cp := make([]byte, len(newValue))
+	copy(cp, newValue)
+
+	var err error
+	switch runtime.NodeStorageType(kind) {
+	case runtime.NodeStorageTypePersistent:
+		err = runtimeCtx.NodeStorage.PersistentStorage.Put(storageKey, cp)
+	case runtime.NodeStorageTypeLocal:
+		err = runtimeCtx.NodeStorage.LocalStorage.Put(storageKey, cp)
+	}
+
+	if err != nil {
+		logger.Errorf("failed to set value in storage: %s", err)
+	}
+}
+
+// ExtOffchainNetworkStateVersion1 implements ext_offchain_network_state_version_1
+func ExtOffchainNetworkStateVersion1(env Environment) int64 {
+	logger.Debug("executing...")
+	runtimeCtx := env.Context()
+	if runtimeCtx.Network == nil {
+		return 0
+	}
+
+	nsEnc, err := scale.Marshal(runtimeCtx.Network.NetworkState())
+	if err != nil {
+		logger.Errorf("failed at encoding network state: %s", err)
+		return 0
+	}
+
+	// copy network state length to memory writtenOut location
+	nsEncLen := uint32(len(nsEnc))
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, nsEncLen)
+
+	// allocate memory for value and copy value to memory
+	ptr, err := toWasmMemorySized(env, nsEnc, nsEncLen)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		return 0
+	}
+
+	return int64(ptr)
+}
+
+// ExtOffchainRandomSeedVersion1 implements ext_offchain_random_seed_version_1
+func ExtOffchainRandomSeedVersion1(env Environment) int32 {
+	logger.Debug("executing...")
+	seed := make([]byte, 32)
+	_, err := rand.Read(seed)
+	if err != nil {
+		logger.Errorf("failed to generate random seed: %s", err)
+	}
+	ptr, err := toWasmMemorySized(env, seed, 32)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+	}
+	return int32(ptr)
+}
+
+// ExtOffchainSubmitTransactionVersion1 implements ext_offchain_submit_transaction_version_1
+func ExtOffchainSubmitTransactionVersion1(env Environment, data int64) int64 {
+	logger.Debug("executing...")
+
+	extBytes := asMemorySlice(env, data)
+
+	var extrinsic []byte
+	err := scale.Unmarshal(extBytes, &extrinsic)
+	if err != nil {
+		logger.Errorf("failed to decode extrinsic data: %s", err)
+	}
+
+	// validate the transaction
+	txv := transaction.NewValidity(0, [][]byte{{}}, [][]byte{{}}, 0, false)
+	vtx := transaction.NewValidTransaction(extrinsic, txv)
+
+	runtimeCtx := env.Context()
+	runtimeCtx.Transaction.AddToPool(vtx)
+
+	ptr, err := toWasmMemoryOptional(env, nil)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+	}
+	return int64(ptr)
+}
+
+// ExtOffchainTimestampVersion1 implements ext_offchain_timestamp_version_1
+func ExtOffchainTimestampVersion1(env Environment) int64 {
+	logger.Trace("executing...")
+
+	now := time.Now().UnixMilli()
+	return int64(now)
+}
+
+// ExtOffchainSleepUntilVersion1 implements ext_offchain_sleep_until_version_1
+func ExtOffchainSleepUntilVersion1(env Environment, deadline int64) {
+	logger.Trace("executing...")
+
+	dur := time.Until(time.UnixMilli(int64(deadline)))
+	if dur <= 0 {
+		return
+	}
+
+	// the sleep ends early when the offchain workers are cancelled
+	timer := time.NewTimer(dur)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-env.Context().OffchainHTTPSet.Done():
+	}
+}
+
+// ExtOffchainHttpRequestStartVersion1 implements ext_offchain_http_request_start_version_1
+func ExtOffchainHttpRequestStartVersion1( // skipcq: RVV-B0012
+	env Environment, methodSpan, uriSpan, metaSpan int64) int64 {
+	logger.Debug("executing...")
+
+	runtimeCtx := env.Context()
+
+	httpMethod := asMemorySlice(env, methodSpan)
+	uri := asMemorySlice(env, uriSpan)
+
+	result := scale.NewResult(int16(0), nil)
+
+	reqID, err := runtimeCtx.OffchainHTTPSet.StartRequest(string(httpMethod), string(uri))
+	if err != nil {
+		// StartRequest error already was logged
+		logger.Errorf("failed to start request: %s", err)
+		err = result.Set(scale.Err, nil)
+	} else {
+		err = result.Set(scale.OK, reqID)
+	}
+
+	// note: just check if an error occurs while setting the result data
+	if err != nil {
+		logger.Errorf("failed to set the result data: %s", err)
+		return 0
+	}
+
+	enc, err := scale.Marshal(result)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the result: %s", err)
+		return 0
+	}
+
+	ptr, err := toWasmMemory(env, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return 0
+	}
+
+	return int64(ptr)
+}
+
+// ExtOffchainHttpRequestAddHeaderVersion1 implements ext_offchain_http_request_add_header_version_1
+func ExtOffchainHttpRequestAddHeaderVersion1(env Environment, reqID int32, nameSpan, valueSpan int64) int64 {
+	logger.Debug("executing...")
+	name := asMemorySlice(env, nameSpan)
+	value := asMemorySlice(env, valueSpan)
+
+	runtimeCtx := env.Context()
+	offchainReq := runtimeCtx.OffchainHTTPSet.Get(int16(reqID))
+
+	result := scale.NewResult(nil, nil)
+	resultMode := scale.OK
+
+	err := offchainReq.AddHeader(string(name), string(value))
+	if err != nil {
+		logger.Errorf("failed to add request header: %s", err)
+		resultMode = scale.Err
+	}
+
+	err = result.Set(resultMode, nil)
+	if err != nil {
+		logger.Errorf("failed to set the result data: %s", err)
+		return 0
+	}
+
+	enc, err := scale.Marshal(result)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the result: %s", err)
+		return 0
+	}
+
+	ptr, err := toWasmMemory(env, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return 0
+	}
+
+	return int64(ptr)
+}
+
+// ExtOffchainHttpRequestWriteBodyVersion1 implements ext_offchain_http_request_write_body_version_1
+func ExtOffchainHttpRequestWriteBodyVersion1(env Environment, reqID int32, chunkSpan, deadlineSpan int64) int64 { //nolint:lll
+	logger.Debug("executing...")
+	runtimeCtx := env.Context()
+
+	chunk := asMemorySlice(env, chunkSpan)
+
+	deadline, err := offchainDeadline(env, deadlineSpan)
+	if err == nil {
+		err = runtimeCtx.OffchainHTTPSet.WriteBody(int16(reqID), chunk, deadline)
+	}
+
+	if err != nil {
+		logger.Errorf("failed to write request body: %s", err)
+	}
+
+	return offchainHTTPResult(env, nil, nil, err)
+}
+
+// ExtOffchainHttpResponseWaitVersion1 implements ext_offchain_http_response_wait_version_1
+func ExtOffchainHttpResponseWaitVersion1(env Environment, idsSpan, deadlineSpan int64) int64 {
+	logger.Debug("executing...")
+	runtimeCtx := env.Context()
+
+	var reqIDs []uint16
+	err := scale.Unmarshal(asMemorySlice(env, idsSpan), &reqIDs)
+	if err != nil {
+		logger.Errorf("failed to decode request ids: %s", err)
+		return 0
+	}
+
+	deadline, err := offchainDeadline(env, deadlineSpan)
+	if err != nil {
+		logger.Errorf("failed to decode deadline: %s", err)
+		return 0
+	}
+
+	ids := make([]int16, len(reqIDs))
+	for i, id := range reqIDs {
+		ids[i] = int16(id)
+	}
+
+	statuses, err := runtimeCtx.OffchainHTTPSet.Wait(ids, deadline)
+	if err != nil {
+		logger.Errorf("failed to wait for the responses: %s", err)
+		return 0
+	}
+
+	enc, err := scale.Marshal(statuses)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the request statuses: %s", err)
+		return 0
+	}
+
+	ptr, err := toWasmMemory(env, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return 0
+	}
+
+	return int64(ptr)
+}
+
+// ExtOffchainHttpResponseHeadersVersion1 implements ext_offchain_http_response_headers_version_1
+func ExtOffchainHttpResponseHeadersVersion1(env Environment, reqID int32) int64 {
+	logger.Debug("executing...")
+	runtimeCtx := env.Context()
+
+	headers := runtimeCtx.OffchainHTTPSet.Headers(int16(reqID))
+	if headers == nil {
+		headers = []offchain.Header{}
+	}
+
+	enc, err := scale.Marshal(headers)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the response headers: %s", err)
+		return 0
+	}
+
+	ptr, err := toWasmMemory(env, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return 0
+	}
+
+	return int64(ptr)
+}
+
+// ExtOffchainHttpResponseReadBodyVersion1 implements ext_offchain_http_response_read_body_version_1
+func ExtOffchainHttpResponseReadBodyVersion1(env Environment, reqID int32, bufferSpan, deadlineSpan int64) int64 { //nolint:lll
+	logger.Debug("executing...")
+	runtimeCtx := env.Context()
+
+	buffer := asMemorySlice(env, bufferSpan)
+
+	var read int
+	deadline, err := offchainDeadline(env, deadlineSpan)
+	if err == nil {
+		read, err = runtimeCtx.OffchainHTTPSet.ReadBody(int16(reqID), buffer, deadline)
+	}
+
+	if err != nil {
+		logger.Errorf("failed to read response body: %s", err)
+	}
+
+	return offchainHTTPResult(env, uint32(0), uint32(read), err)
+}
+
+// offchainDeadline decodes the `Option<u64>` deadline of the offchain HTTP functions, which is
+// a timestamp in milliseconds, and returns the zero time if there is no deadline.
+func offchainDeadline(env Environment, deadlineSpan int64) (time.Time, error) {
+	var deadline *uint64
+	err := scale.Unmarshal(asMemorySlice(env, deadlineSpan), &deadline)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot decode deadline: %w", err)
+	}
+
+	if deadline == nil {
+		return time.Time{}, nil
+	}
+
+	return time.UnixMilli(int64(*deadline)), nil
+}
+
+// offchainHTTPResult writes the `Result<T, HttpError>` of the offchain HTTP functions in memory,
+// where okType is the zero value of T and the error is converted to a HttpError.
+func offchainHTTPResult(env Environment, okType, ok interface{}, err error) int64 {
+	enc, err := encodeOffchainHTTPResult(okType, ok, err)
+	if err != nil {
+		logger.Errorf("failed to encode the result: %s", err)
+		return 0
+	}
+
+	ptr, err := toWasmMemory(env, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return 0
+	}
+
+	return int64(ptr)
+}
+
+// encodeOffchainHTTPResult returns the SCALE encoding of the `Result<T, HttpError>` of the
+// offchain HTTP functions. Errors which are not a HttpError are encoded as HttpError::Invalid.
+func encodeOffchainHTTPResult(okType, ok interface{}, httpErr error) ([]byte, error) {
+	result := scale.NewResult(okType, offchain.HTTPError(0))
+
+	var err error
+	if httpErr == nil {
+		err = result.Set(scale.OK, ok)
+	} else {
+		resultErr := offchain.ErrInvalid
+		errors.As(httpErr, &resultErr)
+		err = result.Set(scale.Err, resultErr)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot set the result data: %w", err)
+	}
+
+	return scale.Marshal(result)
+}
+
+func storageAppend(storage runtime.Storage, key, valueToAppend []byte) error {
+	nextLength := big.NewInt(1)
+	var valueRes []byte
+
+	// this function assumes the item in storage is a SCALE encoded array of items
+	// the valueToAppend is a new item, so it appends the item and increases the length prefix by 1
+	valueCurr := storage.Get(key)
+
+	if len(valueCurr) == 0 {
+		valueRes = valueToAppend
+	} else {
+		var currLength *big.Int
+		err := scale.Unmarshal(valueCurr, &currLength)
+		if err != nil {
+			logger.Tracef(
+				"item in storage is not SCALE encoded, overwriting at key 0x%x", key)
+			storage.Set(key, append([]byte{4}, valueToAppend...))
+			return nil
+		}
+
+		lengthBytes, err := scale.Marshal(currLength)
+		if err != nil {
+			return err
+		}
+		// append new item, pop off number of bytes required for length encoding,
+		// since we're not using old scale.Decoder
+		valueRes = append(valueCurr[len(lengthBytes):], valueToAppend...)
+
+		// increase length by 1
+		nextLength = big.NewInt(0).Add(currLength, big.NewInt(1))
+	}
+
+	lengthEnc, err := scale.Marshal(nextLength)
+	if err != nil {
+		logger.Tracef("failed to encode new length: %s", err)
+		return err
+	}
+
+	// append new length prefix to start of items array
+	lengthEnc = append(lengthEnc, valueRes...)
+	logger.Debugf("resulting value: 0x%x", lengthEnc)
+	storage.Set(key, lengthEnc)
+	return nil
+}
+
+// ExtStorageAppendVersion1 implements ext_storage_append_version_1
+func ExtStorageAppendVersion1(env Environment, keySpan, valueSpan int64) {
+	logger.Trace("executing...")
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	key := asMemorySlice(env, keySpan)
+	valueAppend := asMemorySlice(env, valueSpan)
+	logger.Debugf(
+		"will append value 0x%x to values at key 0x%x",
+		valueAppend, key)
+
+	cp := make([]byte, len(valueAppend))
+	copy(cp, valueAppend)
+
+	err := storageAppend(storage, key, cp)
+	if err != nil {
+		logger.Errorf("[ext_storage_append_version_1]: %s", err)
+	}
+}
+
+// ExtStorageChangesRootVersion1 implements ext_storage_changes_root_version_1
+func ExtStorageChangesRootVersion1(env Environment, parentHashSpan int64) int64 {
+	logger.Trace("executing...")
+	logger.Debug("returning None")
+
+	rootSpan, err := toWasmMemoryOptional(env, nil)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int64(rootSpan)
+}
+
+// ExtStorageClearVersion1 implements ext_storage_clear_version_1
+func ExtStorageClearVersion1(env Environment, keySpan int64) {
+	logger.Trace("executing...")
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	key := asMemorySlice(env, keySpan)
+
+	logger.Debugf("key: 0x%x", key)
+	storage.Delete(key)
+}
+
+// ExtStorageClearPrefixVersion1 implements ext_storage_clear_prefix_version_1
+func ExtStorageClearPrefixVersion1(env Environment, prefixSpan int64) {
+	logger.Trace("executing...")
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	prefix := asMemorySlice(env, prefixSpan)
+	logger.Debugf("prefix: 0x%x", prefix)
+
+	err := storage.ClearPrefix(prefix)
+	if err != nil {
+		logger.Errorf("[ext_storage_clear_prefix_version_1]: %s", err)
+	}
+}
+
+// ExtStorageClearPrefixVersion2 implements ext_storage_clear_prefix_version_2
+func ExtStorageClearPrefixVersion2(env Environment, prefixSpan, lim int64) int64 {
+	logger.Trace("executing...")
+
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	prefix := asMemorySlice(env, prefixSpan)
+	logger.Debugf("prefix: 0x%x", prefix)
+
+	limitBytes := asMemorySlice(env, lim)
+
+	var limit []byte
+	err := scale.Unmarshal(limitBytes, &limit)
+	if err != nil {
+		logger.Warnf("[ext_storage_clear_prefix_version_2]: cannot generate limit: %s", err)
+		ret, _ := toWasmMemory(env, nil)
+		return int64(ret)
+	}
+
+	if len(limit) == 0 {
+		// limit is None, set limit to max
+		limit = []byte{0xff, 0xff, 0xff, 0xff}
+	}
+
+	limitUint := binary.LittleEndian.Uint32(limit)
+	numRemoved, all := storage.ClearPrefixLimit(prefix, limitUint)
+	encBytes, err := toKillStorageResultEnum(all, numRemoved)
+	if err != nil {
+		logger.Errorf("failed to allocate memory: %s", err)
+		ret, _ := toWasmMemory(env, nil)
+		return int64(ret)
+	}
+
+	valueSpan, err := toWasmMemory(env, encBytes)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		ptr, _ := toWasmMemory(env, nil)
+		return int64(ptr)
+	}
+
+	return int64(valueSpan)
+}
+
+// ExtStorageExistsVersion1 implements ext_storage_exists_version_1
+func ExtStorageExistsVersion1(env Environment, keySpan int64) int32 {
+	logger.Trace("executing...")
+	storage := env.Context().Storage
+
+	key := asMemorySlice(env, keySpan)
+	logger.Debugf("key: 0x%x", key)
+
+	val := storage.Get(key)
+	if len(val) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// ExtStorageGetVersion1 implements ext_storage_get_version_1
+func ExtStorageGetVersion1(env Environment, keySpan int64) int64 {
+	logger.Trace("executing...")
+
+	storage := env.Context().Storage
+
+	key := asMemorySlice(env, keySpan)
+	logger.Debugf("key: 0x%x", key)
+
+	value := storage.Get(key)
+	logger.Debugf("value: 0x%x", value)
+
+	valueSpan, err := toWasmMemoryOptional(env, value)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		ptr, _ := toWasmMemoryOptional(env, nil)
+		return int64(ptr)
+	}
+
+	return int64(valueSpan)
+}
+
+// ExtStorageNextKeyVersion1 implements ext_storage_next_key_version_1
+func ExtStorageNextKeyVersion1(env Environment, keySpan int64) int64 {
+	logger.Trace("executing...")
+
+	storage := env.Context().Storage
+
+	key := asMemorySlice(env, keySpan)
+
+	next := storage.NextKey(key)
+	logger.Debugf(
+		"key: 0x%x; next key 0x%x",
+		key, next)
+
+	nextSpan, err := toWasmMemoryOptional(env, next)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int64(nextSpan)
+}
+
+// ExtStorageReadVersion1 implements ext_storage_read_version_1
+func ExtStorageReadVersion1(env Environment, keySpan, valueOut int64, offset int32) int64 {
+	logger.Trace("executing...")
+
+	storage := env.Context().Storage
+	memory := env.Memory().Data()
+
+	key := asMemorySlice(env, keySpan)
+	value := storage.Get(key)
+	logger.Debugf(
+		"key 0x%x has value 0x%x",
+		key, value)
+
+	if value == nil {
+		ret, _ := toWasmMemoryOptional(env, nil)
+		return int64(ret)
+	}
+
+	var size uint32
+
+	if int(offset) > len(value) {
+		size = uint32(0)
+	} else {
+		size = uint32(len(value[offset:]))
+		valueBuf, valueLen := runtime.Int64ToPointerAndSize(int64(valueOut))
+		copy(memory[valueBuf:valueBuf+valueLen], value[offset:])
+	}
+
+	sizeSpan, err := toWasmMemoryOptionalUint32(env, &size)
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int64(sizeSpan)
+}
+
+// ExtStorageRootVersion1 implements ext_storage_root_version_1
+func ExtStorageRootVersion1(env Environment) int64 {
+	logger.Trace("executing...")
+	return storageRoot(env, trie.V0)
+}
+
+// ExtStorageRootVersion2 implements ext_storage_root_version_2
+func ExtStorageRootVersion2(env Environment, version int32) int64 {
+	logger.Trace("executing...")
+
+	stateVersion, err := trie.ParseVersion(uint32(version))
+	if err != nil {
+		logger.Errorf("failed to parse state version: %s", err)
+		return 0
+	}
+
+	return storageRoot(env, stateVersion)
+}
+
+// storageRoot returns the span of the storage root computed with the state trie version given.
+func storageRoot(env Environment, version trie.Version) int64 {
+	storage := env.Context().Storage
+
+	root, err := storage.RootWithVersion(version)
+	if err != nil {
+		logger.Errorf("failed to get storage root: %s", err)
+		return 0
+	}
+
+	logger.Debugf("root hash is %s for state version %s", root, version)
+
+	rootSpan, err := toWasmMemory(env, root[:])
+	if err != nil {
+		logger.Errorf("failed to allocate: %s", err)
+		return 0
+	}
+
+	return int64(rootSpan)
+}
+
+// ExtStorageSetVersion1 implements ext_storage_set_version_1
+func ExtStorageSetVersion1(env Environment, keySpan, valueSpan int64) {
+	logger.Trace("executing...")
+
+	ctx := env.Context()
+	storage := ctx.Storage
+
+	key := asMemorySlice(env, keySpan)
+	value := asMemorySlice(env, valueSpan)
+
+	cp := make([]byte, len(value))
+	copy(cp, value)
+
+	logger.Debugf(
+		"key 0x%x has value 0x%x",
+		key, value)
+	storage.Set(key, cp)
+}
+
+// ExtStorageStartTransactionVersion1 implements ext_storage_start_transaction_version_1
+func ExtStorageStartTransactionVersion1(env Environment) {
+	logger.Debug("executing...")
+	env.Context().Storage.BeginStorageTransaction()
+}
+
+// ExtStorageRollbackTransactionVersion1 implements ext_storage_rollback_transaction_version_1
+func ExtStorageRollbackTransactionVersion1(env Environment) {
+	logger.Debug("executing...")
+	env.Context().Storage.RollbackStorageTransaction()
+}
+
+// ExtStorageCommitTransactionVersion1 implements ext_storage_commit_transaction_version_1
+func ExtStorageCommitTransactionVersion1(env Environment) {
+	logger.Debug("[ext_storage_commit_transaction_version_1] executing...")
+	env.Context().Storage.CommitStorageTransaction()
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package hostapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_encodeOffchainHTTPResult(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		okType  interface{}
+		ok      interface{}
+		err     error
+		encoded []byte
+	}{
+		"ok unit": {
+			encoded: []byte{0},
+		},
+		"ok u32": {
+			okType:  uint32(0),
+			ok:      uint32(5),
+			encoded: []byte{0, 5, 0, 0, 0},
+		},
+		"deadline reached": {
+			okType:  uint32(0),
+			err:     offchain.ErrDeadlineReached,
+			encoded: []byte{1, 1},
+		},
+		"io error": {
+			err:     offchain.ErrIO,
+			encoded: []byte{1, 2},
+		},
+		"invalid": {
+			err:     offchain.ErrInvalid,
+			encoded: []byte{1, 3},
+		},
+		"wrapped http error": {
+			err:     fmt.Errorf("wrapped: %w", offchain.ErrDeadlineReached),
+			encoded: []byte{1, 1},
+		},
+		"other error": {
+			err:     errors.New("test error"),
+			encoded: []byte{1, 3},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := encodeOffchainHTTPResult(testCase.okType, testCase.ok, testCase.err)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.encoded, encoded)
+		})
+	}
+}
+
+type testMemory []byte
+
+func (m testMemory) Data() []byte            { return m }
+func (m testMemory) Length() uint32          { return uint32(len(m)) }
+func (m testMemory) Grow(pages uint32) error { return errors.New("cannot grow test memory") }
+
+type testEnvironment struct {
+	memory testMemory
+	ctx    *runtime.Context
+}
+
+func (e *testEnvironment) Memory() runtime.Memory    { return e.memory }
+func (e *testEnvironment) Context() *runtime.Context { return e.ctx }
+func (e *testEnvironment) Version(code []byte) (runtime.Version, error) {
+	return nil, errors.New("not implemented")
+}
+
+func Test_ExtTransactionIndex(t *testing.T) {
+	t.Parallel()
+
+	state, err := rtstorage.NewTrieState(trie.NewEmptyTrie())
+	require.NoError(t, err)
+
+	memory := make(testMemory, 40)
+	memory[8] = 1
+	env := &testEnvironment{
+		memory: memory,
+		ctx:    &runtime.Context{Storage: state},
+	}
+
+	ExtTransactionIndexIndexVersion1(env, 0, 2, 8)
+	ExtTransactionIndexRenewVersion1(env, 1, 8)
+
+	// the hashes out of the bounds of the memory are ignored
+	ExtTransactionIndexIndexVersion1(env, 2, 2, 9)
+	ExtTransactionIndexRenewVersion1(env, 3, -1)
+
+	expected := []rtstorage.TransactionIndexOperation{
+		{Extrinsic: 0, Size: 2, Hash: common.Hash{1}},
+		{Extrinsic: 1, Hash: common.Hash{1}, Renew: true},
+	}
+	assert.Equal(t, expected, state.TransactionIndexOperations())
+}
+
+func Test_readMemory(t *testing.T) {
+	t.Parallel()
+
+	env := &testEnvironment{memory: testMemory{1, 2, 3}}
+
+	data, err := readMemory(env, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2, 3}, data)
+
+	_, err = readMemory(env, 2, 2)
+	assert.ErrorIs(t, err, errMemoryOutOfBounds)
+	assert.EqualError(t, err, "memory out of bounds: reading 2 bytes at 2 from 3 bytes")
+
+	_, err = readMemory(env, ^uint32(0), 2)
+	assert.ErrorIs(t, err, errMemoryOutOfBounds)
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package hostapi_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/hostapi"
+	"github.com/ChainSafe/gossamer/lib/runtime/life"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/runtime/wazero"
+	"github.com/go-interpreter/wagon/wasm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRuntimes = []string{
+	runtime.NODE_RUNTIME,
+	runtime.NODE_RUNTIME_v098,
+	runtime.POLKADOT_RUNTIME_v0910,
+	runtime.POLKADOT_RUNTIME_v0917,
+	runtime.POLKADOT_RUNTIME,
+	runtime.HOST_API_TEST_RUNTIME,
+	runtime.DEV_RUNTIME,
+}
+
+// importedFunctions returns the names of the host functions imported by the runtime code
+func importedFunctions(t *testing.T, code []byte) []string {
+	t.Helper()
+
+	module, err := wasm.DecodeModule(bytes.NewReader(code))
+	require.NoError(t, err)
+	require.NotNil(t, module.Import)
+
+	var names []string
+	for _, entry := range module.Import.Entries {
+		if entry.Type.Kind() == wasm.ExternalFunction {
+			require.Equal(t, "env", entry.ModuleName)
+			names = append(names, entry.FieldName)
+		}
+	}
+
+	return names
+}
+
+func newTestStorage(t *testing.T) *rtstorage.TrieState {
+	t.Helper()
+
+	storage, err := rtstorage.NewTrieState(nil)
+	require.NoError(t, err)
+	return storage
+}
+
+func TestImportsResolved(t *testing.T) {
+	for _, testRuntime := range testRuntimes {
+		testRuntime := testRuntime
+		t.Run(testRuntime, func(t *testing.T) {
+			fp, err := runtime.GetRuntime(context.Background(), testRuntime)
+			require.NoError(t, err)
+
+			code, err := os.ReadFile(fp)
+			require.NoError(t, err)
+
+			code, err = runtime.DecompressWasm(code)
+			require.NoError(t, err)
+
+			names := importedFunctions(t, code)
+
+			// life cannot dispatch the host functions of sandboxed modules
+			layout, err := runtime.ParseMemoryLayout(code)
+			require.NoError(t, err)
+			importsSandbox := layout.ImportsSandbox
+
+			t.Run("hostapi", func(t *testing.T) {
+				for _, name := range names {
+					_, ok := hostapi.Lookup(name)
+					assert.Truef(t, ok, "host function %s is not implemented", name)
+				}
+			})
+
+			t.Run(wasmer.Name, func(t *testing.T) {
+				cfg := &wasmer.Config{
+					Imports: wasmer.ImportsNodeRuntime,
+				}
+				cfg.LogLvl = log.Critical
+				cfg.Storage = newTestStorage(t)
+
+				instance, err := wasmer.NewInstance(code, cfg)
+				require.NoError(t, err)
+				instance.Stop()
+			})
+
+			t.Run(wazero.Name, func(t *testing.T) {
+				cfg := &wazero.Config{}
+				cfg.LogLvl = log.Critical
+				cfg.Storage = newTestStorage(t)
+
+				instance, err := wazero.NewInstance(code, cfg)
+				require.NoError(t, err)
+				instance.Stop()
+			})
+
+			t.Run(life.Name, func(t *testing.T) {
+				// life resolves the host functions when they are first called
+				resolver := new(life.Resolver)
+				for _, name := range names {
+					assert.NotPanicsf(t, func() {
+						resolver.ResolveFunc("env", name)
+					}, "host function %s is not resolved", name)
+				}
+
+				cfg := &life.Config{}
+				cfg.LogLvl = log.Critical
+				cfg.Storage = newTestStorage(t)
+
+				_, err := life.NewInstance(code, cfg)
+				if importsSandbox {
+					require.ErrorIs(t, err, runtime.ErrSandboxNotSupported)
+					return
+				}
+				require.NoError(t, err)
+			})
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package hostapi
+
+import (
+	"errors"
+	"fmt"
+
+	rtype "github.com/ChainSafe/gossamer/lib/common/types"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+var errMemoryOutOfBounds = errors.New("memory out of bounds")
+
+// readMemory returns the slice of the memory of the runtime at the given pointer and length,
+// or an error if it is out of bounds.
+func readMemory(env Environment, ptr, length uint32) ([]byte, error) {
+	memory := env.Memory().Data()
+	end := uint64(ptr) + uint64(length)
+	if end > uint64(len(memory)) {
+		return nil, fmt.Errorf("%w: reading %d bytes at %d from %d bytes", errMemoryOutOfBounds, length, ptr, len(memory))
+	}
+
+	return memory[ptr:end], nil
+}
+
+// Convert 64bit wasm span descriptor to Go memory slice
+func asMemorySlice(env Environment, span int64) []byte {
+	memory := env.Memory().Data()
+	ptr, size := runtime.Int64ToPointerAndSize(int64(span))
+	return memory[ptr : ptr+size]
+}
+
+// Copy a byte slice to wasm memory and return the resulting 64bit span descriptor
+func toWasmMemory(env Environment, data []byte) (int64, error) {
+	allocator := env.Context().Allocator
+	size := uint32(len(data))
+
+	out, err := allocator.Allocate(size)
+	if err != nil {
+		return 0, err
+	}
+
+	memory := env.Memory().Data()
+
+	if uint32(len(memory)) < out+size {
+		panic(fmt.Sprintf("length of memory is less than expected, want %d have %d", out+size, len(memory)))
+	}
+
+	copy(memory[out:out+size], data)
+	return runtime.PointerAndSizeToInt64(int32(out), int32(size)), nil
+}
+
+// Copy a byte slice of a fixed size to wasm memory and return resulting pointer
+func toWasmMemorySized(env Environment, data []byte, size uint32) (uint32, error) {
+	if int(size) != len(data) {
+		return 0, errors.New("internal byte array size missmatch")
+	}
+
+	allocator := env.Context().Allocator
+
+	out, err := allocator.Allocate(size)
+	if err != nil {
+		return 0, err
+	}
+
+	memory := env.Memory().Data()
+	copy(memory[out:out+size], data)
+
+	return out, nil
+}
+
+// Wraps slice in optional.Bytes and copies result to wasm memory. Returns resulting 64bit span descriptor
+func toWasmMemoryOptional(env Environment, data []byte) (int64, error) {
+	var opt *[]byte
+	if data != nil {
+		temp := data
+		opt = &temp
+	}
+
+	enc, err := scale.Marshal(opt)
+	if err != nil {
+		return 0, err
+	}
+
+	return toWasmMemory(env, enc)
+}
+
+// Wraps slice in Result type and copies result to wasm memory. Returns resulting 64bit span descriptor
+func toWasmMemoryResult(env Environment, data []byte) (int64, error) {
+	var res *rtype.Result
+	if len(data) == 0 {
+		res = rtype.NewResult(byte(1), nil)
+	} else {
+		res = rtype.NewResult(byte(0), data)
+	}
+
+	enc, err := res.Encode()
+	if err != nil {
+		return 0, err
+	}
+
+	return toWasmMemory(env, enc)
+}
+
+// Wraps slice in optional and copies result to wasm memory. Returns resulting 64bit span descriptor
+func toWasmMemoryOptionalUint32(env Environment, data *uint32) (int64, error) {
+	var opt *uint32
+	if data != nil {
+		temp := *data
+		opt = &temp
+	}
+
+	enc, err := scale.Marshal(opt)
+	if err != nil {
+		return int64(0), err
+	}
+	return toWasmMemory(env, enc)
+}
+
+// toKillStorageResult returns enum encoded value
+func toKillStorageResultEnum(allRemoved bool, numRemoved uint32) ([]byte, error) {
+	var b, sbytes []byte
+	sbytes, err := scale.Marshal(numRemoved)
+	if err != nil {
+		return nil, err
+	}
+
+	if allRemoved {
+		// No key remains in the child trie.
+		b = append(b, byte(0))
+	} else {
+		// At least one key still resides in the child trie due to the supplied limit.
+		b = append(b, byte(1))
+	}
+
+	b = append(b, sbytes...)
+
+	return b, err
+}
+
+// Wraps slice in optional.FixedSizeBytes and copies result to wasm memory. Returns resulting 64bit span descriptor
+func toWasmMemoryFixedSizeOptional(env Environment, data []byte) (int64, error) {
+	var opt [64]byte
+	copy(opt[:], data)
+	enc, err := scale.Marshal(&opt)
+	if err != nil {
+		return 0, err
+	}
+	return toWasmMemory(env, enc)
+}
@@ -22,11 +22,13 @@ type Instance interface {
 	NetworkService() BasicNetwork
 	Keystore() *keystore.GlobalKeystore
 	Validator() bool
+	OffchainHTTPDisabled() bool
 	Exec(function string, data []byte) ([]byte, error)
 	SetContextStorage(s Storage) // used to set the TrieState before a runtime call
 
 	GetCodeHash() common.Hash
 	Version() (Version, error)
+	StateVersion() (trie.Version, error)
 	Metadata() ([]byte, error)
 	BabeConfiguration() (*types.BabeConfiguration, error)
 	GrandpaAuthorities() ([]types.Authority, error)
@@ -37,14 +39,25 @@ type Instance interface {
 	FinalizeBlock() (*types.Header, error)
 	ExecuteBlock(block *types.Block) ([]byte, error)
 	DecodeSessionKeys(enc []byte) ([]byte, error)
+	GenerateSessionKeys(seed *[]byte) ([]byte, error)
 	PaymentQueryInfo(ext []byte) (*types.TransactionPaymentQueryInfo, error)
+	OffchainWorker(header *types.Header) error
 
 	CheckInherents() // TODO: use this in block verification process (#1873)
 
 	// parameters and return values for these are undefined in the spec
 	RandomSeed()
-	OffchainWorker()
-	GenerateSessionKeys()
+}
+
+// InstancePool is a pool of identical runtime instances, allowing runtime calls to be
+// executed concurrently
+type InstancePool interface {
+	// Get takes an instance out of the pool, waiting until one is available
+	Get() (Instance, error)
+	// Put puts the instance back in the pool once the runtime calls are done
+	Put(instance Instance)
+	// Close stops the instances of the pool
+	Close()
 }
 
 // Storage interface
@@ -52,6 +65,7 @@ type Storage interface {
 	Set(key []byte, value []byte)
 	Get(key []byte) []byte
 	Root() (common.Hash, error)
+	RootWithVersion(version trie.Version) (common.Hash, error)
 	SetChild(keyToChild []byte, child *trie.Trie) error
 	SetChildStorage(keyToChild, key, value []byte) error
 	GetChildStorage(keyToChild, key []byte) ([]byte, error)
@@ -69,6 +83,10 @@ type Storage interface {
 	CommitStorageTransaction()
 	RollbackStorageTransaction()
 	LoadCode() []byte
+	SetOffchainIndex(key, value []byte)
+	ClearOffchainIndex(key []byte)
+	IndexTransaction(extrinsic, size uint32, hash common.Hash)
+	RenewTransactionIndex(extrinsic uint32, hash common.Hash)
 }
 
 // BasicNetwork interface for functions used by runtime network state function
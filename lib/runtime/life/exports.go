@@ -11,6 +11,7 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/ChainSafe/gossamer/lib/trie"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
@@ -54,6 +55,12 @@ func (in *Instance) Version() (runtime.Version, error) {
 	return version, nil
 }
 
+// StateVersion returns the state trie version of the runtime code,
+// read from the runtime version once per runtime code
+func (in *Instance) StateVersion() (trie.Version, error) {
+	return in.stateVersion.Get(in.Version)
+}
+
 // Metadata calls runtime function Metadata_metadata
 func (in *Instance) Metadata() ([]byte, error) {
 	return in.Exec(runtime.Metadata, []byte{})
@@ -164,13 +171,60 @@ func (in *Instance) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	return in.Exec(runtime.DecodeSessionKeys, enc)
 }
 
+// GenerateSessionKeys generates a new set of session keys with the given optional seed,
+// storing the private keys in the keystore, and returns the encoded public session keys.
+func (in *Instance) GenerateSessionKeys(seed *[]byte) ([]byte, error) {
+	encSeed, err := scale.Marshal(seed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode seed: %w", err)
+	}
+
+	ret, err := in.Exec(runtime.GenerateSessionKeys, encSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []byte
+	err = scale.Unmarshal(ret, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode session keys: %w", err)
+	}
+
+	return keys, nil
+}
+
 // PaymentQueryInfo returns information of a given extrinsic
 func (*Instance) PaymentQueryInfo([]byte) (*types.TransactionPaymentQueryInfo, error) {
 	// TODO: implement the payment query info (see issue #1892)
 	return nil, errors.New("not implemented yet")
 }
 
-func (in *Instance) CheckInherents()      {} //nolint:revive
-func (in *Instance) RandomSeed()          {} //nolint:revive
-func (in *Instance) OffchainWorker()      {} //nolint:revive
-func (in *Instance) GenerateSessionKeys() {} //nolint:revive
+// OffchainWorker calls runtime API function OffchainWorkerApi_offchain_worker for the given block,
+// whose header is passed since the version 2 of the API and whose number is passed otherwise
+func (in *Instance) OffchainWorker(header *types.Header) error {
+	version, err := in.Version()
+	if err != nil {
+		return fmt.Errorf("cannot get runtime version: %w", err)
+	}
+
+	apiVersion, ok := runtime.APIVersion(version, runtime.OffchainWorkerAPIID)
+	if !ok {
+		return fmt.Errorf("%w: %s", runtime.ErrExportFunctionNotFound, runtime.OffchainWorkerAPI)
+	}
+
+	var args []byte
+	if apiVersion < 2 {
+		args, err = scale.Marshal(uint32(header.Number))
+	} else {
+		args, err = scale.Marshal(*header)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot encode offchain worker arguments: %w", err)
+	}
+
+	_, err = in.Exec(runtime.OffchainWorkerAPI, args)
+	return err
+}
+
+func (in *Instance) CheckInherents() {} //nolint:revive
+func (in *Instance) RandomSeed()     {} //nolint:revive
@@ -15,6 +15,9 @@
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/hostapi"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
 
 	"github.com/perlin-network/life/exec"
 	wasm_validation "github.com/perlin-network/life/wasm-validation"
@@ -26,23 +29,27 @@
 // Check that runtime interfaces are satisfied
 var (
 	_      runtime.Instance = (*Instance)(nil)
+	_      runtime.Memory   = (*Memory)(nil)
 	logger                  = log.NewFromGlobal(
 		log.AddContext("pkg", "runtime"),
 		log.AddContext("component", "perlin/life"),
 	)
-	ctx *runtime.Context
 )
 
 // Config represents a life configuration
 type Config struct {
 	runtime.InstanceConfig
-	Resolver exec.ImportResolver
 }
 
-// Instance is a runtime life instance
+// Instance is a runtime life instance. A life vm cannot be called again from the host functions
+// it is executing, so the host functions of sandboxed modules cannot be dispatched to the runtime,
+// and the runtimes instantiating sandboxed modules, such as the runtimes including the contracts
+// pallet, are rejected.
 type Instance struct {
-	vm *exec.VirtualMachine
-	mu sync.Mutex
+	vm           *exec.VirtualMachine
+	ctx          *runtime.Context
+	stateVersion runtime.StateVersionCache
+	mu           sync.Mutex
 }
 
 // GetCodeHash returns code hash of the runtime
@@ -50,6 +57,11 @@ func (*Instance) GetCodeHash() common.Hash {
 	return common.Hash{}
 }
 
+// GetContext returns the context of the instance
+func (in *Instance) GetContext() *runtime.Context {
+	return in.ctx
+}
+
 // NewRuntimeFromGenesis creates a runtime instance from the genesis data
 func NewRuntimeFromGenesis(cfg *Config) (runtime.Instance, error) {
 	if cfg.Storage == nil {
@@ -61,7 +73,6 @@ func NewRuntimeFromGenesis(cfg *Config) (runtime.Instance, error) {
 		return nil, fmt.Errorf("cannot find :code in state")
 	}
 
-	cfg.Resolver = new(Resolver)
 	return NewInstance(code, cfg)
 }
 
@@ -80,6 +91,15 @@ func NewInstanceFromFile(fp string, cfg *Config) (*Instance, error) {
 	return NewInstance(bytes, cfg)
 }
 
+// NewRuntimeInstance instantiates a runtime from raw wasm bytecode, and implements runtime.NewInstanceFunc
+func NewRuntimeInstance(code []byte, cfg runtime.InstanceConfig) (runtime.Instance, error) {
+	instance, err := NewInstance(code, &Config{InstanceConfig: cfg})
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
 // NewInstance ...
 func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 	if len(code) == 0 {
@@ -87,63 +107,77 @@ func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 	}
 
 	logger.Patch(log.SetLevel(cfg.LogLvl))
+	hostapi.SetLogLevel(cfg.LogLvl)
 
-	vmCfg := exec.VMConfig{
-		DefaultMemoryPages: 23,
+	code, err := runtime.DecompressWasm(code)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress WASM code: %w", err)
 	}
 
-	instance, err := exec.NewVirtualMachine(code, vmCfg, cfg.Resolver, nil)
+	layout, err := runtime.ParseMemoryLayout(code)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("cannot parse memory layout: %w", err)
 	}
 
-	memory := &Memory{
-		memory: instance.Memory,
+	if layout.ImportsSandbox {
+		return nil, fmt.Errorf("%w by life", runtime.ErrSandboxNotSupported)
 	}
 
-	// TODO: use __heap_base (#1874)
-	allocator := runtime.NewAllocator(memory, 0)
+	httpSet := offchain.NewHTTPSet(cfg.OffchainCtx)
+	if cfg.OffchainHTTPDisabled {
+		httpSet.Disable()
+	}
 
 	runtimeCtx := &runtime.Context{
-		Storage:     cfg.Storage,
-		Allocator:   allocator,
-		Keystore:    cfg.Keystore,
-		Validator:   cfg.Role == byte(4),
-		NodeStorage: cfg.NodeStorage,
-		Network:     cfg.Network,
-		Transaction: cfg.Transaction,
-		SigVerifier: crypto.NewSignatureVerifier(logger),
+		Storage:         cfg.Storage,
+		Keystore:        cfg.Keystore,
+		Validator:       cfg.Role == byte(4),
+		NodeStorage:     cfg.NodeStorage,
+		Network:         cfg.Network,
+		Transaction:     cfg.Transaction,
+		SigVerifier:     crypto.NewSignatureVerifier(logger),
+		OffchainHTTPSet: httpSet,
+		Sandbox:         sandbox.NewStore(nil),
 	}
 
 	logger.Debugf("creating new runtime instance with context: %v", runtimeCtx)
 
 	inst := &Instance{
-		vm: instance,
+		ctx: runtimeCtx,
 	}
 
-	ctx = runtimeCtx
+	vmCfg := exec.VMConfig{
+		DefaultMemoryPages: int(layout.MinPages),
+	}
+
+	inst.vm, err = exec.NewVirtualMachine(code, vmCfg, &Resolver{instance: inst}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeCtx.Allocator = layout.NewAllocator(&Memory{vm: inst.vm}, runtime.HeapPages(cfg.Storage))
 	return inst, nil
 }
 
 // Memory is a thin wrapper around life's memory to support
 // Gossamer runtime.Memory interface
 type Memory struct {
-	memory []byte
+	vm *exec.VirtualMachine
 }
 
 // Data returns the memory's data
 func (m *Memory) Data() []byte {
-	return m.memory
+	return m.vm.Memory
 }
 
 // Length returns the memory's length
 func (m *Memory) Length() uint32 {
-	return uint32(len(m.memory))
+	return uint32(len(m.vm.Memory))
 }
 
 // Grow ...
 func (m *Memory) Grow(numPages uint32) error {
-	m.memory = append(m.memory, make([]byte, runtime.PageSize*numPages)...)
+	m.vm.Memory = append(m.vm.Memory, make([]byte, runtime.PageSize*numPages)...)
 	return nil
 }
 
@@ -158,8 +192,10 @@ func (*Instance) CheckRuntimeVersion(_ []byte) (runtime.Version, error) {
 }
 
 // SetContextStorage sets the runtime's storage. It should be set before calls to the below functions.
-func (*Instance) SetContextStorage(s runtime.Storage) {
-	ctx.Storage = s
+func (in *Instance) SetContextStorage(s runtime.Storage) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.ctx.Storage = s
 }
 
 // Exec calls the given function with the given data
@@ -167,17 +203,19 @@ func (in *Instance) Exec(function string, data []byte) ([]byte, error) {
 	in.mu.Lock()
 	defer in.mu.Unlock()
 
-	ptr, err := ctx.Allocator.Allocate(uint32(len(data)))
+	ptr, err := in.ctx.Allocator.Allocate(uint32(len(data)))
 	if err != nil {
 		return nil, err
 	}
-	defer ctx.Allocator.Clear()
+	defer in.ctx.Allocator.Clear()
+	// the sandboxed memories only live for the duration of the call
+	defer in.ctx.Sandbox.Reset()
 
 	copy(in.vm.Memory[ptr:ptr+uint32(len(data))], data)
 
 	fnc, ok := in.vm.GetFunctionExport(function)
 	if !ok {
-		return nil, fmt.Errorf("could not find exported function %s", function)
+		return nil, fmt.Errorf("%w: %s", runtime.ErrExportFunctionNotFound, function)
 	}
 
 	ret, err := in.vm.Run(fnc, int64(ptr), int64(len(data)))
@@ -194,21 +232,26 @@ func (in *Instance) Exec(function string, data []byte) ([]byte, error) {
 func (*Instance) Stop() {}
 
 // NodeStorage to get reference to runtime node service
-func (*Instance) NodeStorage() runtime.NodeStorage {
-	return ctx.NodeStorage
+func (in *Instance) NodeStorage() runtime.NodeStorage {
+	return in.ctx.NodeStorage
 }
 
 // NetworkService to get referernce to runtime network service
-func (*Instance) NetworkService() runtime.BasicNetwork {
-	return ctx.Network
+func (in *Instance) NetworkService() runtime.BasicNetwork {
+	return in.ctx.Network
 }
 
 // Validator returns the context's Validator
-func (*Instance) Validator() bool {
-	return ctx.Validator
+func (in *Instance) Validator() bool {
+	return in.ctx.Validator
+}
+
+// OffchainHTTPDisabled returns true if the outbound HTTP requests of the offchain workers are disabled
+func (in *Instance) OffchainHTTPDisabled() bool {
+	return in.ctx.OffchainHTTPSet.Disabled()
 }
 
 // Keystore to get reference to runtime keystore
-func (*Instance) Keystore() *keystore.GlobalKeystore {
-	return ctx.Keystore
+func (in *Instance) Keystore() *keystore.GlobalKeystore {
+	return in.ctx.Keystore
 }
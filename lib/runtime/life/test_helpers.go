@@ -49,6 +49,5 @@ func setupConfig(t *testing.T, tt *trie.Trie, lvl log.Level, role byte) *Config
 	cfg.NodeStorage = ns
 	cfg.Network = new(runtime.TestRuntimeNetwork)
 	cfg.Role = role
-	cfg.Resolver = new(Resolver)
 	return cfg
 }
@@ -0,0 +1,415 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// DefaultHeapPages is the number of pages the memory of the runtime can grow by, beyond its
+// initial size, when the :heappages storage value is not set
+const DefaultHeapPages = uint64(2048)
+
+// maxWasmPages is the maximum number of pages of a wasm memory
+const maxWasmPages = uint64(65536)
+
+const heapBaseExport = "__heap_base"
+
+var (
+	errNotWasmModule   = errors.New("code is not a wasm module")
+	errModuleMalformed = errors.New("wasm module is malformed")
+	errInvalidHeapBase = errors.New("invalid __heap_base global")
+	errMemoryTooLarge  = errors.New("memory cannot grow beyond its maximum size")
+)
+
+// MemoryLayout is the layout of the memory declared by a runtime wasm module
+type MemoryLayout struct {
+	// HeapBase is the value of the __heap_base global exported by the runtime, or
+	// DefaultHeapBase if the runtime does not export it
+	HeapBase uint32
+	// MinPages is the initial number of pages of the memory
+	MinPages uint32
+	// MaxPages is the maximum number of pages of the memory, 0 if the memory has no maximum
+	MaxPages uint32
+	// ImportedMemory is true if the runtime imports its memory rather than exporting it
+	ImportedMemory bool
+	// ImportsSandbox is true if the runtime imports the host function instantiating sandboxed
+	// modules, whose host functions are dispatched to the runtime while it is executing
+	ImportsSandbox bool
+}
+
+// sandboxInstantiateImport is the host function instantiating sandboxed modules
+const sandboxInstantiateImport = "ext_sandbox_instantiate_version_1"
+
+// ParseMemoryLayout parses the import, memory, global and export sections of the wasm
+// code to find the memory limits and the heap base of the runtime, and whether it uses
+// the sandbox.
+func ParseMemoryLayout(code []byte) (*MemoryLayout, error) {
+	if len(code) < 8 || !bytes.Equal(code[:4], []byte("\x00asm")) {
+		return nil, errNotWasmModule
+	}
+
+	layout := &MemoryLayout{
+		HeapBase: DefaultHeapBase,
+	}
+
+	var (
+		importedGlobals uint32
+		globals         []byte
+		heapBaseGlobal  *uint32
+	)
+
+	r := bytes.NewReader(code[8:])
+	for r.Len() > 0 {
+		id, _ := r.ReadByte()
+		size, err := readULEB128(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if uint64(size) > uint64(r.Len()) {
+			return nil, fmt.Errorf("%w: section %d is truncated", errModuleMalformed, id)
+		}
+
+		offset := len(code) - r.Len()
+		payload := bytes.NewReader(code[offset : offset+int(size)])
+		_, _ = r.Seek(int64(size), io.SeekCurrent)
+
+		switch id {
+		case importSectionID:
+			importedGlobals, err = layout.parseImports(payload)
+		case memorySectionID:
+			err = layout.parseMemories(payload)
+		case globalSectionID:
+			globals = code[offset : offset+int(size)]
+		case exportSectionID:
+			heapBaseGlobal, err = findHeapBaseExport(payload)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if heapBaseGlobal == nil {
+		return layout, nil
+	}
+
+	if *heapBaseGlobal < importedGlobals {
+		return nil, fmt.Errorf("%w: global is imported", errInvalidHeapBase)
+	}
+
+	heapBase, err := readGlobalI32(bytes.NewReader(globals), *heapBaseGlobal-importedGlobals)
+	if err != nil {
+		return nil, err
+	}
+
+	layout.HeapBase = heapBase
+	return layout, nil
+}
+
+// parseImports sets the limits of the imported memory, if any, and whether the sandbox is imported.
+// It returns the number of imported globals.
+func (l *MemoryLayout) parseImports(r *bytes.Reader) (globals uint32, err error) {
+	count, err := readULEB128(r)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := uint32(0); i < count; i++ {
+		// skip the module name
+		if _, err = readName(r); err != nil {
+			return 0, err
+		}
+
+		var field []byte
+		field, err = readName(r)
+		if err != nil {
+			return 0, err
+		}
+
+		var kind byte
+		kind, err = r.ReadByte()
+		if err != nil {
+			return 0, errModuleMalformed
+		}
+
+		switch kind {
+		case 0: // function
+			_, err = readULEB128(r)
+			if string(field) == sandboxInstantiateImport {
+				l.ImportsSandbox = true
+			}
+		case 1: // table
+			if _, err = r.ReadByte(); err == nil {
+				_, _, err = readLimits(r)
+			}
+		case 2: // memory
+			l.MinPages, l.MaxPages, err = readLimits(r)
+			l.ImportedMemory = true
+		case 3: // global
+			globals++
+			_, err = r.Seek(2, io.SeekCurrent)
+		default:
+			return 0, fmt.Errorf("%w: invalid import kind %d", errModuleMalformed, kind)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return globals, nil
+}
+
+// parseMemories sets the limits of the memory defined by the module
+func (l *MemoryLayout) parseMemories(r *bytes.Reader) error {
+	count, err := readULEB128(r)
+	if err != nil || count == 0 {
+		return err
+	}
+
+	l.MinPages, l.MaxPages, err = readLimits(r)
+	return err
+}
+
+// findHeapBaseExport returns the index of the __heap_base global, or nil if it is not exported.
+func findHeapBaseExport(r *bytes.Reader) (*uint32, error) {
+	count, err := readULEB128(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := uint32(0); i < count; i++ {
+		var name []byte
+		name, err = readName(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var kind byte
+		kind, err = r.ReadByte()
+		if err != nil {
+			return nil, errModuleMalformed
+		}
+
+		var index uint32
+		index, err = readULEB128(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if string(name) != heapBaseExport {
+			continue
+		}
+
+		if kind != 3 {
+			return nil, fmt.Errorf("%w: export is not a global", errInvalidHeapBase)
+		}
+
+		return &index, nil
+	}
+
+	return nil, nil
+}
+
+// readGlobalI32 returns the initial value of the i32 global of the global section with the given index.
+func readGlobalI32(r *bytes.Reader, index uint32) (uint32, error) {
+	count, err := readULEB128(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if index >= count {
+		return 0, fmt.Errorf("%w: global %d does not exist", errInvalidHeapBase, index)
+	}
+
+	for i := uint32(0); ; i++ {
+		var valueType byte
+		valueType, err = r.ReadByte()
+		if err != nil {
+			return 0, errModuleMalformed
+		}
+
+		// skip the mutability
+		if _, err = r.ReadByte(); err != nil {
+			return 0, errModuleMalformed
+		}
+
+		var opcode byte
+		opcode, err = r.ReadByte()
+		if err != nil {
+			return 0, errModuleMalformed
+		}
+
+		if i == index {
+			if valueType != 0x7f || opcode != 0x41 {
+				return 0, fmt.Errorf("%w: global is not an i32 constant", errInvalidHeapBase)
+			}
+
+			var value int32
+			value, err = readSLEB128(r)
+			return uint32(value), err
+		}
+
+		if err = skipConstantExpression(r, opcode); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// skipConstantExpression skips the immediate of the opcode, and the end of the expression
+func skipConstantExpression(r *bytes.Reader, opcode byte) (err error) {
+	switch opcode {
+	case 0x41: // i32.const
+		_, err = readSLEB128(r)
+	case 0x42: // i64.const
+		for b := byte(0x80); b&0x80 != 0 && err == nil; {
+			b, err = r.ReadByte()
+		}
+	case 0x43: // f32.const
+		_, err = r.Seek(4, io.SeekCurrent)
+	case 0x44: // f64.const
+		_, err = r.Seek(8, io.SeekCurrent)
+	case 0x23: // global.get
+		_, err = readULEB128(r)
+	default:
+		return fmt.Errorf("%w: invalid constant expression opcode %d", errModuleMalformed, opcode)
+	}
+
+	if err != nil {
+		return errModuleMalformed
+	}
+
+	end, err := r.ReadByte()
+	if err != nil || end != 0x0b {
+		return errModuleMalformed
+	}
+
+	return nil
+}
+
+func readLimits(r *bytes.Reader) (min, max uint32, err error) {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, errModuleMalformed
+	}
+
+	min, err = readULEB128(r)
+	if err != nil || flags&1 == 0 {
+		return min, 0, err
+	}
+
+	max, err = readULEB128(r)
+	return min, max, err
+}
+
+func readName(r *bytes.Reader) ([]byte, error) {
+	length, err := readULEB128(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(length) > uint64(r.Len()) {
+		return nil, fmt.Errorf("%w: name is truncated", errModuleMalformed)
+	}
+
+	name := make([]byte, length)
+	_, _ = r.Read(name)
+	return name, nil
+}
+
+func readULEB128(r *bytes.Reader) (uint32, error) {
+	var value uint32
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, errModuleMalformed
+		}
+
+		value |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: integer is too large", errModuleMalformed)
+}
+
+func readSLEB128(r *bytes.Reader) (int32, error) {
+	var value int32
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, errModuleMalformed
+		}
+
+		value |= int32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if shift+7 < 32 && b&0x40 != 0 {
+				value |= -1 << (shift + 7)
+			}
+			return value, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: integer is too large", errModuleMalformed)
+}
+
+// HeapPages returns the number of heap pages set by the :heappages storage value,
+// or DefaultHeapPages if it is not set.
+func HeapPages(s Storage) uint64 {
+	if s == nil {
+		return DefaultHeapPages
+	}
+
+	value := s.Get(common.HeapPagesKey)
+	if len(value) != 8 {
+		return DefaultHeapPages
+	}
+
+	return binary.LittleEndian.Uint64(value)
+}
+
+// MaxMemoryPages returns the number of pages the memory can grow to, which is its initial
+// size and the heap pages, bounded by the maximum size of the memory.
+func (l *MemoryLayout) MaxMemoryPages(heapPages uint64) uint32 {
+	pages := uint64(l.MinPages) + heapPages
+	if pages > maxWasmPages {
+		pages = maxWasmPages
+	}
+
+	if l.MaxPages != 0 && pages > uint64(l.MaxPages) {
+		pages = uint64(l.MaxPages)
+	}
+
+	return uint32(pages)
+}
+
+// NewAllocator creates an allocator whose heap starts at the heap base of the runtime, and which
+// cannot grow the memory beyond the given number of heap pages.
+func (l *MemoryLayout) NewAllocator(mem Memory, heapPages uint64) *FreeingBumpHeapAllocator {
+	return NewAllocator(&limitedMemory{Memory: mem, maxPages: l.MaxMemoryPages(heapPages)}, l.HeapBase)
+}
+
+// limitedMemory is a memory which cannot grow beyond a number of pages
+type limitedMemory struct {
+	Memory
+	maxPages uint32
+}
+
+// Grow grows the memory by the number of pages, unless it would exceed its maximum size
+func (m *limitedMemory) Grow(pages uint32) error {
+	if uint64(m.Length()/PageSize)+uint64(pages) > uint64(m.maxPages) {
+		return fmt.Errorf("%w: %d pages", errMemoryTooLarge, m.maxPages)
+	}
+
+	return m.Memory.Grow(pages)
+}
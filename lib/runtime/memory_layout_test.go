@@ -0,0 +1,168 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wasmModule(sections ...[]byte) []byte {
+	code := []byte("\x00asm\x01\x00\x00\x00")
+	for _, section := range sections {
+		code = append(code, section...)
+	}
+	return code
+}
+
+func wasmSection(id byte, entries ...[]byte) []byte {
+	payload := []byte{byte(len(entries))}
+	for _, entry := range entries {
+		payload = append(payload, entry...)
+	}
+	return append([]byte{id, byte(len(payload))}, payload...)
+}
+
+func wasmName(name string) []byte {
+	return append([]byte{byte(len(name))}, name...)
+}
+
+func Test_ParseMemoryLayout(t *testing.T) {
+	t.Parallel()
+
+	// i32.const 131072
+	heapBaseGlobal := []byte{0x7f, 0x00, 0x41, 0x80, 0x80, 0x08, 0x0b}
+	// i64.const -1
+	i64Global := []byte{0x7e, 0x00, 0x42, 0x7f, 0x0b}
+	importedMemory := append(append(wasmName("env"), wasmName("memory")...), 0x02, 0x01, 30, 100)
+	importedGlobal := append(append(wasmName("env"), wasmName("global")...), 0x03, 0x7f, 0x00)
+	importedFunction := append(append(wasmName("env"), wasmName("ext_logging_log_version_1")...), 0x00, 0x01)
+	importedSandbox := append(append(wasmName("env"), wasmName("ext_sandbox_instantiate_version_1")...), 0x00, 0x02)
+
+	tests := map[string]struct {
+		code   []byte
+		layout *MemoryLayout
+		err    error
+	}{
+		"imported memory and heap base": {
+			code: wasmModule(
+				wasmSection(importSectionID, importedFunction, importedMemory),
+				wasmSection(globalSectionID, i64Global, heapBaseGlobal),
+				wasmSection(exportSectionID, append(wasmName(heapBaseExport), 0x03, 0x01)),
+			),
+			layout: &MemoryLayout{HeapBase: 131072, MinPages: 30, MaxPages: 100, ImportedMemory: true},
+		},
+		"exported memory without heap base": {
+			code: wasmModule(
+				wasmSection(memorySectionID, []byte{0x00, 17}),
+				wasmSection(exportSectionID, append(wasmName("memory"), 0x02, 0x00)),
+			),
+			layout: &MemoryLayout{HeapBase: DefaultHeapBase, MinPages: 17},
+		},
+		"heap base after imported global": {
+			code: wasmModule(
+				wasmSection(importSectionID, importedGlobal),
+				wasmSection(memorySectionID, []byte{0x00, 17}),
+				wasmSection(globalSectionID, heapBaseGlobal),
+				wasmSection(exportSectionID, append(wasmName(heapBaseExport), 0x03, 0x01)),
+			),
+			layout: &MemoryLayout{HeapBase: 131072, MinPages: 17},
+		},
+		"imported sandbox": {
+			code: wasmModule(
+				wasmSection(importSectionID, importedSandbox, importedMemory),
+			),
+			layout: &MemoryLayout{
+				HeapBase:       DefaultHeapBase,
+				MinPages:       30,
+				MaxPages:       100,
+				ImportedMemory: true,
+				ImportsSandbox: true,
+			},
+		},
+		"imported heap base": {
+			code: wasmModule(
+				wasmSection(importSectionID, importedGlobal),
+				wasmSection(exportSectionID, append(wasmName(heapBaseExport), 0x03, 0x00)),
+			),
+			err: errInvalidHeapBase,
+		},
+		"heap base is not a global": {
+			code: wasmModule(
+				wasmSection(exportSectionID, append(wasmName(heapBaseExport), 0x00, 0x00)),
+			),
+			err: errInvalidHeapBase,
+		},
+		"heap base is not an i32": {
+			code: wasmModule(
+				wasmSection(globalSectionID, i64Global),
+				wasmSection(exportSectionID, append(wasmName(heapBaseExport), 0x03, 0x00)),
+			),
+			err: errInvalidHeapBase,
+		},
+		"truncated section": {
+			code: wasmModule([]byte{memorySectionID, 0x03, 0x01, 0x00}),
+			err:  errModuleMalformed,
+		},
+		"not a wasm module": {
+			code: []byte{1, 2, 3},
+			err:  errNotWasmModule,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			layout, err := ParseMemoryLayout(tt.code)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.layout, layout)
+		})
+	}
+}
+
+func Test_HeapPages(t *testing.T) {
+	t.Parallel()
+
+	ts, err := storage.NewTrieState(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultHeapPages, HeapPages(nil))
+	assert.Equal(t, DefaultHeapPages, HeapPages(ts))
+
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, 64)
+	ts.Set(common.HeapPagesKey, value)
+	assert.Equal(t, uint64(64), HeapPages(ts))
+}
+
+func Test_MemoryLayout_NewAllocator(t *testing.T) {
+	t.Parallel()
+
+	layout := &MemoryLayout{HeapBase: 2 * PageSize, MinPages: 3}
+	assert.Equal(t, uint32(5), layout.MaxMemoryPages(2))
+	assert.Equal(t, uint32(65536), layout.MaxMemoryPages(1<<20))
+
+	layout.MaxPages = 4
+	assert.Equal(t, uint32(4), layout.MaxMemoryPages(2))
+
+	memory := newMemoryMock(3 * PageSize)
+	allocator := layout.NewAllocator(memory, 2)
+
+	ptr, err := allocator.Allocate(1)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, ptr, layout.HeapBase)
+
+	// the memory can grow by one page only
+	_, err = allocator.Allocate(PageSize)
+	require.NoError(t, err)
+	_, err = allocator.Allocate(PageSize)
+	assert.ErrorIs(t, err, errMemoryTooLarge)
+}
@@ -12,6 +12,8 @@
 
 	transaction "github.com/ChainSafe/gossamer/lib/transaction"
 
+	trie "github.com/ChainSafe/gossamer/lib/trie"
+
 	types "github.com/ChainSafe/gossamer/dot/types"
 )
 
@@ -186,9 +188,27 @@ func (_m *Instance) FinalizeBlock() (*types.Header, error) {
 	return r0, r1
 }
 
-// GenerateSessionKeys provides a mock function with given fields:
-func (_m *Instance) GenerateSessionKeys() {
-	_m.Called()
+// GenerateSessionKeys provides a mock function with given fields: seed
+func (_m *Instance) GenerateSessionKeys(seed *[]byte) ([]byte, error) {
+	ret := _m.Called(seed)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(*[]byte) []byte); ok {
+		r0 = rf(seed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*[]byte) error); ok {
+		r1 = rf(seed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetCodeHash provides a mock function with given fields:
@@ -336,9 +356,32 @@ func (_m *Instance) NodeStorage() runtime.NodeStorage {
 	return r0
 }
 
-// OffchainWorker provides a mock function with given fields:
-func (_m *Instance) OffchainWorker() {
-	_m.Called()
+// OffchainHTTPDisabled provides a mock function with given fields:
+func (_m *Instance) OffchainHTTPDisabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// OffchainWorker provides a mock function with given fields: header
+func (_m *Instance) OffchainWorker(header *types.Header) error {
+	ret := _m.Called(header)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Header) error); ok {
+		r0 = rf(header)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // PaymentQueryInfo provides a mock function with given fields: ext
@@ -374,6 +417,27 @@ func (_m *Instance) SetContextStorage(s runtime.Storage) {
 	_m.Called(s)
 }
 
+// StateVersion provides a mock function with given fields:
+func (_m *Instance) StateVersion() (trie.Version, error) {
+	ret := _m.Called()
+
+	var r0 trie.Version
+	if rf, ok := ret.Get(0).(func() trie.Version); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(trie.Version)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Stop provides a mock function with given fields:
 func (_m *Instance) Stop() {
 	_m.Called()
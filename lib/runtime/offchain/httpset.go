@@ -4,12 +4,17 @@
 package offchain
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
 type contextKey string
@@ -21,14 +26,79 @@
 
 const maxConcurrentRequests = 1000
 
+// bodyChunkSize is the size of the chunks read from the response bodies
+const bodyChunkSize = 32 * 1024
+
 var (
 	errIntBufferEmpty        = errors.New("int buffer exhausted")
 	errIntBufferFull         = errors.New("int buffer is full")
 	errRequestIDNotAvailable = errors.New("request id not available")
 	errRequestInvalid        = errors.New("request is invalid")
 	errInvalidHeaderKey      = errors.New("invalid header key")
+	errHTTPDisabled          = errors.New("outbound http requests are disabled")
+)
+
+// HTTPError is the error returned by the offchain HTTP functions of the runtime. Its values
+// are the SCALE encoded discriminants of the HttpError enum of substrate, which start at 1.
+type HTTPError byte
+
+const (
+	// ErrDeadlineReached is returned when the deadline is reached before the operation completes
+	ErrDeadlineReached HTTPError = iota + 1
+	// ErrIO is returned when an error occurred while sending the request or receiving the response
+	ErrIO
+	// ErrInvalid is returned when the request id is unknown or the request cannot be used anymore
+	ErrInvalid
 )
 
+func (e HTTPError) Error() string {
+	switch e {
+	case ErrDeadlineReached:
+		return "deadline reached"
+	case ErrIO:
+		return "i/o error"
+	case ErrInvalid:
+		return "invalid request"
+	default:
+		return fmt.Sprintf("unknown http error %d", byte(e))
+	}
+}
+
+// StatusDeadlineReached is the status of a request whose response was not received before the deadline
+type StatusDeadlineReached struct{}
+
+// Index returns the VDT index
+func (StatusDeadlineReached) Index() uint { return 0 }
+
+// StatusIOError is the status of a request which failed to be sent or to receive its response
+type StatusIOError struct{}
+
+// Index returns the VDT index
+func (StatusIOError) Index() uint { return 1 }
+
+// StatusInvalid is the status of a request whose id is unknown
+type StatusInvalid struct{}
+
+// Index returns the VDT index
+func (StatusInvalid) Index() uint { return 2 }
+
+// StatusFinished is the status of a request whose response was received, holding the status code
+type StatusFinished uint16
+
+// Index returns the VDT index
+func (StatusFinished) Index() uint { return 3 }
+
+// NewHTTPRequestStatus returns a VaryingDataType representing the status of a request
+func NewHTTPRequestStatus() scale.VaryingDataType {
+	return scale.MustNewVaryingDataType(StatusDeadlineReached{}, StatusIOError{}, StatusInvalid{}, StatusFinished(0))
+}
+
+// Header is a header of a HTTP response
+type Header struct {
+	Name  []byte
+	Value []byte
+}
+
 // requestIDBuffer created to control the amount of available non-duplicated ids
 type requestIDBuffer chan int16
 
@@ -64,12 +134,26 @@ func (b requestIDBuffer) put(i int16) error {
 // the request starts or is waiting to be read
 type Request struct {
 	Request *http.Request
+
+	body       []byte
+	dispatched bool
+	cancel     context.CancelFunc
+
+	// done is closed once the response is received or the request failed
+	done     chan struct{}
+	response *http.Response
+	err      error
+
+	// chunks receives the chunks of the response body read in the background
+	chunks   chan []byte
+	readErr  error
+	leftover []byte
 }
 
 // AddHeader adds a new HTTP header into request property, only if request is valid
 func (r *Request) AddHeader(name, value string) error {
 	invalid, ok := r.Request.Context().Value(invalidKey).(bool)
-	if ok && invalid {
+	if (ok && invalid) || r.dispatched {
 		return errRequestInvalid
 	}
 
@@ -82,29 +166,178 @@ func (r *Request) AddHeader(name, value string) error {
 	return nil
 }
 
+// dispatch sends the request with the body written so far in the background
+func (r *Request) dispatch(client *http.Client) {
+	r.dispatched = true
+	r.done = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(r.Request.Context())
+	r.cancel = cancel
+
+	req := r.Request.WithContext(ctx)
+	req.ContentLength = int64(len(r.body))
+	if len(r.body) > 0 {
+		req.Body = io.NopCloser(bytes.NewReader(r.body))
+	}
+
+	go func() {
+		defer close(r.done)
+		r.response, r.err = client.Do(req) //nolint:bodyclose
+	}()
+}
+
+// wait waits for the response until the deadline, dispatching the request if needed
+func (r *Request) wait(client *http.Client, deadline <-chan struct{}) error {
+	if !r.dispatched {
+		r.dispatch(client)
+	}
+
+	select {
+	case <-r.done:
+	case <-deadline:
+		return ErrDeadlineReached
+	}
+
+	if r.err != nil {
+		return fmt.Errorf("%w: %s", ErrIO, r.err)
+	}
+	return nil
+}
+
+// readBody reads the response body into buf until the deadline, and returns
+// the number of bytes read, which is 0 once the whole body is read.
+func (r *Request) readBody(buf []byte, deadline <-chan struct{}) (int, error) {
+	if r.chunks == nil {
+		r.chunks = make(chan []byte)
+		go r.readChunks()
+	}
+
+	if len(r.leftover) == 0 {
+		select {
+		case chunk, ok := <-r.chunks:
+			if !ok {
+				if r.readErr != nil {
+					return 0, fmt.Errorf("%w: %s", ErrIO, r.readErr)
+				}
+				return 0, nil
+			}
+			r.leftover = chunk
+		case <-deadline:
+			return 0, ErrDeadlineReached
+		}
+	}
+
+	n := copy(buf, r.leftover)
+	r.leftover = r.leftover[n:]
+	return n, nil
+}
+
+// readChunks reads the response body in chunks, until the end of the body
+// or until the request is removed.
+func (r *Request) readChunks() {
+	defer close(r.chunks)
+
+	for {
+		chunk := make([]byte, bodyChunkSize)
+		n, err := r.response.Body.Read(chunk)
+		if n > 0 {
+			r.chunks <- chunk[:n]
+		}
+
+		if errors.Is(err, io.EOF) {
+			return
+		} else if err != nil {
+			r.readErr = err
+			return
+		}
+	}
+}
+
+// close cancels the request and releases the response
+func (r *Request) close() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	if r.done == nil {
+		return
+	}
+
+	go func() {
+		<-r.done
+		if r.response == nil {
+			return
+		}
+
+		_ = r.response.Body.Close()
+		if r.chunks != nil {
+			// unblock the reader of the response body
+			for range r.chunks { //nolint:revive
+			}
+		}
+	}()
+}
+
 // HTTPSet holds a pool of concurrent http request calls
 type HTTPSet struct {
 	*sync.Mutex
-	reqs   map[int16]*Request
-	idBuff requestIDBuffer
+	ctx      context.Context
+	reqs     map[int16]*Request
+	idBuff   requestIDBuffer
+	client   *http.Client
+	disabled bool
 }
 
 // NewHTTPSet creates a offchain http set that can be used
-// by runtime as HTTP clients, the max concurrent requests is 1000
-func NewHTTPSet() *HTTPSet {
+// by runtime as HTTP clients, the max concurrent requests is 1000.
+// The requests are cancelled and the waits end once the given context is done,
+// so that the offchain workers complete as soon as possible. A nil context is
+// never done.
+func NewHTTPSet(ctx context.Context) *HTTPSet {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	return &HTTPSet{
-		new(sync.Mutex),
-		make(map[int16]*Request),
-		newIntBuffer(maxConcurrentRequests),
+		Mutex:  new(sync.Mutex),
+		ctx:    ctx,
+		reqs:   make(map[int16]*Request),
+		idBuff: newIntBuffer(maxConcurrentRequests),
+		client: new(http.Client),
 	}
 }
 
+// Done returns a channel closed once the context of the set is done
+func (p *HTTPSet) Done() <-chan struct{} {
+	return p.ctx.Done()
+}
+
+// Disable makes the set refuse to start any new request
+func (p *HTTPSet) Disable() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.disabled = true
+}
+
+// Disabled returns true if the set refuses to start any new request
+func (p *HTTPSet) Disabled() bool {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.disabled
+}
+
 // StartRequest create a new request using the method and the uri, adds the request into the list
 // and then return the position of the request inside the list
 func (p *HTTPSet) StartRequest(method, uri string) (int16, error) {
 	p.Lock()
 	defer p.Unlock()
 
+	if p.disabled {
+		return 0, errHTTPDisabled
+	}
+
 	id, err := p.idBuff.get()
 	if err != nil {
 		return 0, err
@@ -114,7 +347,11 @@ func (p *HTTPSet) StartRequest(method, uri string) (int16, error) {
 		return 0, errRequestIDNotAvailable
 	}
 
-	req, err := http.NewRequest(method, uri, nil)
+	req, err := http.NewRequestWithContext(p.ctx, method, uri, nil)
+	if err != nil {
+		return 0, err
+	}
+
 	req.Header = make(http.Header)
 
 	ctx := context.WithValue(req.Context(), waitingKey, false)
@@ -122,10 +359,6 @@ func (p *HTTPSet) StartRequest(method, uri string) (int16, error) {
 
 	req = req.WithContext(ctx)
 
-	if err != nil {
-		return 0, err
-	}
-
 	p.reqs[id] = &Request{
 		Request: req,
 	}
@@ -138,6 +371,14 @@ func (p *HTTPSet) Remove(id int16) error {
 	p.Lock()
 	defer p.Unlock()
 
+	return p.remove(id)
+}
+
+func (p *HTTPSet) remove(id int16) error {
+	if req, ok := p.reqs[id]; ok {
+		req.close()
+	}
+
 	delete(p.reqs, id)
 
 	return p.idBuff.put(id)
@@ -150,3 +391,145 @@ func (p *HTTPSet) Get(id int16) *Request {
 
 	return p.reqs[id]
 }
+
+// WriteBody writes a chunk of the body of the request. Writing an empty chunk
+// finalises the body and sends the request, after which the body cannot be written anymore.
+// A zero deadline means no deadline.
+func (p *HTTPSet) WriteBody(id int16, chunk []byte, deadline time.Time) error {
+	p.Lock()
+	defer p.Unlock()
+
+	req, ok := p.reqs[id]
+	if !ok || req.dispatched {
+		return ErrInvalid
+	}
+
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return ErrDeadlineReached
+	}
+
+	if len(chunk) == 0 {
+		req.dispatch(p.client)
+		return nil
+	}
+
+	req.body = append(req.body, chunk...)
+	return nil
+}
+
+// Wait waits until the responses of the requests are received or the deadline is reached,
+// sending the requests whose body is not finalised yet, and returns the status of each request.
+// A zero deadline means no deadline.
+func (p *HTTPSet) Wait(ids []int16, deadline time.Time) ([]scale.VaryingDataType, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	ctx, cancel := p.deadlineContext(deadline)
+	defer cancel()
+
+	statuses := make([]scale.VaryingDataType, len(ids))
+	for i, id := range ids {
+		var status scale.VaryingDataTypeValue
+
+		req, ok := p.reqs[id]
+		if !ok {
+			status = StatusInvalid{}
+		} else {
+			err := req.wait(p.client, ctx.Done())
+			switch {
+			case errors.Is(err, ErrDeadlineReached):
+				status = StatusDeadlineReached{}
+			case err != nil:
+				status = StatusIOError{}
+			default:
+				status = StatusFinished(req.response.StatusCode)
+			}
+		}
+
+		statuses[i] = NewHTTPRequestStatus()
+		err := statuses[i].Set(status)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return statuses, nil
+}
+
+// Headers returns the headers of the response of the request, which are empty
+// if the response is not received yet.
+func (p *HTTPSet) Headers(id int16) []Header {
+	p.Lock()
+	defer p.Unlock()
+
+	req, ok := p.reqs[id]
+	if !ok || !req.dispatched {
+		return nil
+	}
+
+	select {
+	case <-req.done:
+	default:
+		return nil
+	}
+
+	if req.response == nil {
+		return nil
+	}
+
+	var headers []Header
+	for name, values := range req.response.Header {
+		for _, value := range values {
+			headers = append(headers, Header{Name: []byte(name), Value: []byte(value)})
+		}
+	}
+
+	return headers
+}
+
+// ReadBody reads the response body of the request into buf, waiting for the response
+// if needed, and returns the number of bytes read. Reading 0 bytes means the whole body
+// was read, and the request is then removed, as well as when an i/o error occurs.
+// A zero deadline means no deadline.
+func (p *HTTPSet) ReadBody(id int16, buf []byte, deadline time.Time) (int, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	req, ok := p.reqs[id]
+	if !ok {
+		return 0, ErrInvalid
+	}
+
+	ctx, cancel := p.deadlineContext(deadline)
+	defer cancel()
+
+	err := req.wait(p.client, ctx.Done())
+	if err == nil {
+		var n int
+		n, err = req.readBody(buf, ctx.Done())
+		if err == nil && n > 0 {
+			return n, nil
+		}
+	}
+
+	if errors.Is(err, ErrDeadlineReached) {
+		return 0, err
+	}
+
+	removeErr := p.remove(id)
+	if removeErr != nil {
+		return 0, removeErr
+	}
+
+	return 0, err
+}
+
+// deadlineContext returns a context which is done once the deadline is reached or the
+// context of the set is done, and which is not done before for a zero deadline.
+func (p *HTTPSet) deadlineContext(deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithCancel(p.ctx)
+	}
+
+	return context.WithDeadline(p.ctx, deadline)
+}
@@ -6,9 +6,14 @@
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,7 +22,7 @@
 func TestHTTPSetLimit(t *testing.T) {
 	t.Parallel()
 
-	set := NewHTTPSet()
+	set := NewHTTPSet(context.Background())
 	var err error
 	for i := 0; i < maxConcurrentRequests+1; i++ {
 		_, err = set.StartRequest(http.MethodGet, defaultTestURI)
@@ -29,7 +34,7 @@ func TestHTTPSetLimit(t *testing.T) {
 func TestHTTPSet_StartRequest_NotAvailableID(t *testing.T) {
 	t.Parallel()
 
-	set := NewHTTPSet()
+	set := NewHTTPSet(context.Background())
 	set.reqs[1] = &Request{}
 
 	_, err := set.StartRequest(http.MethodGet, defaultTestURI)
@@ -39,7 +44,7 @@ func TestHTTPSet_StartRequest_NotAvailableID(t *testing.T) {
 func TestHTTPSetGet(t *testing.T) {
 	t.Parallel()
 
-	set := NewHTTPSet()
+	set := NewHTTPSet(context.Background())
 
 	id, err := set.StartRequest(http.MethodGet, defaultTestURI)
 	require.NoError(t, err)
@@ -64,7 +69,7 @@ func TestOffchainRequest_AddHeader(t *testing.T) {
 		headerK, headerV string
 	}{
 		"should return invalid request": {
-			offReq: Request{invalidReq},
+			offReq: Request{Request: invalidReq},
 			err:    errRequestInvalid,
 		},
 		"should add header": {
@@ -101,3 +106,197 @@ func TestOffchainRequest_AddHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPSet_Disable(t *testing.T) {
+	t.Parallel()
+
+	set := NewHTTPSet(context.Background())
+	set.Disable()
+
+	_, err := set.StartRequest(http.MethodGet, defaultTestURI)
+	require.ErrorIs(t, err, errHTTPDisabled)
+}
+
+func requireStatuses(t *testing.T, expected []scale.VaryingDataTypeValue, statuses []scale.VaryingDataType) {
+	t.Helper()
+
+	values := make([]scale.VaryingDataTypeValue, len(statuses))
+	for i, status := range statuses {
+		values[i] = status.Value()
+	}
+	require.Equal(t, expected, values)
+}
+
+func TestHTTPSet_Response(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Test", r.Header.Get("X-Test"))
+		w.WriteHeader(http.StatusCreated)
+		_, err = w.Write(body)
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	set := NewHTTPSet(context.Background())
+	id, err := set.StartRequest(http.MethodPost, server.URL)
+	require.NoError(t, err)
+
+	err = set.Get(id).AddHeader("X-Test", "value")
+	require.NoError(t, err)
+
+	// the headers are empty until the response is received
+	require.Empty(t, set.Headers(id))
+
+	err = set.WriteBody(id, []byte("hello "), time.Time{})
+	require.NoError(t, err)
+	err = set.WriteBody(id, []byte("world"), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	err = set.WriteBody(id, nil, time.Time{})
+	require.NoError(t, err)
+
+	// the body cannot be written once finalised
+	err = set.WriteBody(id, []byte("!"), time.Time{})
+	require.ErrorIs(t, err, ErrInvalid)
+	err = set.Get(id).AddHeader("X-Other", "value")
+	require.ErrorIs(t, err, errRequestInvalid)
+
+	statuses, err := set.Wait([]int16{id, id + 1}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	requireStatuses(t, []scale.VaryingDataTypeValue{StatusFinished(http.StatusCreated), StatusInvalid{}}, statuses)
+
+	headers := set.Headers(id)
+	require.Contains(t, headers, Header{Name: []byte("X-Method"), Value: []byte(http.MethodPost)})
+	require.Contains(t, headers, Header{Name: []byte("X-Test"), Value: []byte("value")})
+
+	var body []byte
+	buf := make([]byte, 4)
+	for {
+		n, err := set.ReadBody(id, buf, time.Time{})
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+		body = append(body, buf[:n]...)
+	}
+	require.Equal(t, []byte("hello world"), body)
+
+	// the request is removed once the body is read
+	require.Nil(t, set.Get(id))
+	_, err = set.ReadBody(id, buf, time.Time{})
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestHTTPSet_Deadline(t *testing.T) {
+	t.Parallel()
+
+	respond := make(chan struct{})
+	flushed := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-respond
+		_, err := w.Write([]byte("partial"))
+		require.NoError(t, err)
+		w.(http.Flusher).Flush()
+		close(flushed)
+		<-respond
+	}))
+	t.Cleanup(func() {
+		close(respond)
+		server.Close()
+	})
+
+	set := NewHTTPSet(context.Background())
+	id, err := set.StartRequest(http.MethodGet, server.URL)
+	require.NoError(t, err)
+
+	err = set.WriteBody(id, []byte("body"), time.Now().Add(-time.Second))
+	require.ErrorIs(t, err, ErrDeadlineReached)
+
+	// waiting sends the request even if the body is not finalised
+	statuses, err := set.Wait([]int16{id}, time.Now().Add(50*time.Millisecond))
+	require.NoError(t, err)
+	requireStatuses(t, []scale.VaryingDataTypeValue{StatusDeadlineReached{}}, statuses)
+
+	buf := make([]byte, 16)
+	_, err = set.ReadBody(id, buf, time.Now().Add(50*time.Millisecond))
+	require.ErrorIs(t, err, ErrDeadlineReached)
+
+	respond <- struct{}{}
+	<-flushed
+
+	n, err := set.ReadBody(id, buf, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []byte("partial"), buf[:n])
+
+	// the request is kept when the deadline is reached while reading the body
+	_, err = set.ReadBody(id, buf, time.Now().Add(50*time.Millisecond))
+	require.ErrorIs(t, err, ErrDeadlineReached)
+	require.NotNil(t, set.Get(id))
+
+	err = set.Remove(id)
+	require.NoError(t, err)
+}
+
+func TestHTTPSet_IOError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	uri := server.URL
+	server.Close()
+
+	set := NewHTTPSet(context.Background())
+	id, err := set.StartRequest(http.MethodGet, uri)
+	require.NoError(t, err)
+
+	statuses, err := set.Wait([]int16{id}, time.Time{})
+	require.NoError(t, err)
+	requireStatuses(t, []scale.VaryingDataTypeValue{StatusIOError{}}, statuses)
+	require.Empty(t, set.Headers(id))
+
+	_, err = set.ReadBody(id, make([]byte, 16), time.Time{})
+	require.ErrorIs(t, err, ErrIO)
+	require.Nil(t, set.Get(id))
+}
+
+func TestHTTPSet_Cancel(t *testing.T) {
+	t.Parallel()
+
+	respond := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-respond
+	}))
+	t.Cleanup(func() {
+		close(respond)
+		server.Close()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	set := NewHTTPSet(ctx)
+	id, err := set.StartRequest(http.MethodGet, server.URL)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := set.Wait([]int16{id}, time.Time{})
+		assert.NoError(t, err)
+	}()
+
+	// the wait without deadline ends once the set is cancelled
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("wait did not end after cancellation")
+	}
+
+	select {
+	case <-set.Done():
+	default:
+		t.Fatal("set is not done")
+	}
+}
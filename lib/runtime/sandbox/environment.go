@@ -0,0 +1,79 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+const (
+	externFunction byte = iota
+	externMemory
+)
+
+// externEntity is an entity provided to the sandboxed instance, which is either the
+// index of a host function of the supervisor or the index of a sandbox memory.
+type externEntity struct {
+	Kind  byte
+	Index uint32
+}
+
+// environmentEntry is an entry of the environment definition, providing an entity
+// for the import of the given module and field names.
+type environmentEntry struct {
+	ModuleName []byte
+	FieldName  []byte
+	Entity     externEntity
+}
+
+// importName is the module and field names of an import
+type importName struct {
+	module string
+	field  string
+}
+
+// environment is the decoded environment definition of a sandboxed instance
+type environment struct {
+	functions map[importName]uint32
+	memories  map[importName]*Memory
+}
+
+// decodeEnvironment decodes the SCALE encoded environment definition, resolving
+// the memories using the given store.
+func decodeEnvironment(in []byte, store *Store) (*environment, error) {
+	var entries []environmentEntry
+	err := scale.Unmarshal(in, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode environment definition: %w", err)
+	}
+
+	env := &environment{
+		functions: make(map[importName]uint32),
+		memories:  make(map[importName]*Memory),
+	}
+
+	for _, entry := range entries {
+		name := importName{
+			module: string(entry.ModuleName),
+			field:  string(entry.FieldName),
+		}
+
+		switch entry.Entity.Kind {
+		case externFunction:
+			env.functions[name] = entry.Entity.Index
+		case externMemory:
+			env.memories[name], err = store.Memory(entry.Entity.Index)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid extern entity kind %d for %s.%s",
+				entry.Entity.Kind, name.module, name.field)
+		}
+	}
+
+	return env, nil
+}
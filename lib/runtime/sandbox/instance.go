@@ -0,0 +1,380 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/go-interpreter/wagon/wasm"
+	"github.com/go-interpreter/wagon/wasm/leb128"
+	ops "github.com/go-interpreter/wagon/wasm/operators"
+	"github.com/perlin-network/life/compiler"
+	"github.com/perlin-network/life/exec"
+)
+
+var (
+	// ErrInvalidModule is returned when the code of a sandboxed instance cannot be
+	// decoded, or its imports cannot be resolved using the environment definition.
+	ErrInvalidModule = errors.New("invalid sandboxed module")
+	// ErrExecution is returned when the execution of a sandboxed function fails.
+	ErrExecution = errors.New("sandboxed execution failed")
+	// ErrExportNotFound is returned when invoking a function which is not exported.
+	ErrExportNotFound = errors.New("exported function not found")
+
+	errReentrantInvoke = errors.New("instance is already executing")
+)
+
+// Dispatcher calls the host functions of the supervisor on behalf of the sandboxed instances.
+type Dispatcher interface {
+	// Dispatch calls the dispatch thunk of the supervisor with the SCALE encoded arguments,
+	// the state and the index of the host function, and returns the SCALE encoded
+	// `Result<ReturnValue, HostError>` returned by the host function.
+	Dispatch(dispatchThunk, state, index uint32, args []byte) ([]byte, error)
+}
+
+// hostFunction is a host function of the supervisor imported by a sandboxed instance
+type hostFunction struct {
+	index     uint32
+	signature *wasm.FunctionSig
+}
+
+// Instance is a sandboxed instance executed by an interpreter, whose imported
+// functions are dispatched to the host functions of the supervisor.
+type Instance struct {
+	vm            *exec.VirtualMachine
+	memory        *Memory
+	hostFunctions map[importName]hostFunction
+	dispatcher    Dispatcher
+	dispatchThunk uint32
+	state         uint32
+	running       bool
+}
+
+// newInstance instantiates the given code with the imports provided by the environment,
+// and runs its start function if any.
+func newInstance(code []byte, env *environment, dispatcher Dispatcher,
+	dispatchThunk, state uint32) (instance *Instance, err error) {
+	defer func() {
+		// the decoder and the interpreter panic on some malformed modules
+		if r := recover(); r != nil {
+			instance, err = nil, fmt.Errorf("%w: %v", ErrInvalidModule, r)
+		}
+	}()
+
+	module, err := compiler.LoadModule(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidModule, err)
+	}
+
+	instance = &Instance{
+		hostFunctions: make(map[importName]hostFunction),
+		dispatcher:    dispatcher,
+		dispatchThunk: dispatchThunk,
+	}
+
+	err = instance.resolveImports(module.Base, env)
+	if err != nil {
+		return nil, err
+	}
+
+	config := exec.VMConfig{
+		MaxMemoryPages: maxPages,
+	}
+
+	if instance.memory != nil {
+		config.DefaultMemoryPages = int(instance.memory.Pages())
+		if instance.memory.maximum != 0 {
+			config.MaxMemoryPages = int(instance.memory.maximum)
+		}
+	}
+
+	instance.vm, err = exec.NewVirtualMachine(code, config, &resolver{instance: instance}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidModule, err)
+	}
+
+	if instance.memory != nil {
+		err = instance.initMemory()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidModule, err)
+		}
+	}
+
+	start := instance.vm.Module.Base.Start
+	if start != nil {
+		_, err = instance.run(int(start.Index), nil, state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+// resolveImports checks all the imports of the module are provided by the environment.
+func (in *Instance) resolveImports(module *wasm.Module, env *environment) error {
+	if module.Import == nil {
+		return nil
+	}
+
+	for _, entry := range module.Import.Entries {
+		name := importName{module: entry.ModuleName, field: entry.FieldName}
+
+		switch imp := entry.Type.(type) {
+		case wasm.FuncImport:
+			index, ok := env.functions[name]
+			if !ok {
+				return fmt.Errorf("%w: function %s.%s is not provided", ErrInvalidModule, name.module, name.field)
+			}
+
+			if module.Types == nil || int(imp.Type) >= len(module.Types.Entries) {
+				return fmt.Errorf("%w: function %s.%s has invalid type", ErrInvalidModule, name.module, name.field)
+			}
+
+			in.hostFunctions[name] = hostFunction{
+				index:     index,
+				signature: &module.Types.Entries[imp.Type],
+			}
+		case wasm.MemoryImport:
+			memory, ok := env.memories[name]
+			if !ok {
+				return fmt.Errorf("%w: memory %s.%s is not provided", ErrInvalidModule, name.module, name.field)
+			}
+
+			limits := imp.Type.Limits
+			if memory.Pages() < limits.Initial ||
+				(limits.Flags&1 == 1 && (memory.maximum == 0 || memory.maximum > limits.Maximum)) {
+				return fmt.Errorf("%w: memory %s.%s has incompatible limits", ErrInvalidModule, name.module, name.field)
+			}
+
+			in.memory = memory
+		default:
+			return fmt.Errorf("%w: import %s.%s is not supported", ErrInvalidModule, name.module, name.field)
+		}
+	}
+
+	return nil
+}
+
+// initMemory copies the data segments of the module into the imported memory,
+// and makes the interpreter use the imported memory.
+func (in *Instance) initMemory() error {
+	data := in.vm.Module.Base.Data
+	if data != nil {
+		for _, segment := range data.Entries {
+			offset, err := evalInitExpr(segment.Offset, in.vm.Globals)
+			if err != nil {
+				return err
+			}
+
+			if offset < 0 || offset > int64(^uint32(0)) {
+				return ErrOutOfBounds
+			}
+
+			err = in.memory.Set(uint32(offset), segment.Data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	in.vm.Memory = in.memory.data
+	return nil
+}
+
+// evalInitExpr evaluates the constant expression used as offset of a data segment.
+func evalInitExpr(expr []byte, globals []int64) (int64, error) {
+	r := bytes.NewReader(expr)
+	opcode, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	switch opcode {
+	case ops.I32Const:
+		var value int32
+		value, err = leb128.ReadVarint32(r)
+		return int64(value), err
+	case ops.GetGlobal:
+		var index uint32
+		index, err = leb128.ReadVarUint32(r)
+		if err != nil {
+			return 0, err
+		}
+		if int(index) >= len(globals) {
+			return 0, fmt.Errorf("global %d does not exist", index)
+		}
+		return int64(int32(globals[index])), nil
+	default:
+		return 0, fmt.Errorf("unsupported opcode 0x%x in offset expression", opcode)
+	}
+}
+
+// Invoke calls the exported function with the given arguments. The state is passed
+// to the dispatch thunk when the function calls the host functions of the supervisor.
+// The value returned by the function is nil if the function does not return any value.
+func (in *Instance) Invoke(name string, args []Value, state uint32) (Value, error) {
+	functionID, ok := in.vm.GetFunctionExport(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrExportNotFound, name)
+	}
+
+	signature := in.signature(functionID)
+	if signature == nil || len(signature.ParamTypes) != len(args) {
+		return nil, fmt.Errorf("%w: invalid number of arguments for %s", ErrExecution, name)
+	}
+
+	for i, arg := range args {
+		if !hasType(arg, signature.ParamTypes[i]) {
+			return nil, fmt.Errorf("%w: invalid type of argument %d for %s", ErrExecution, i, name)
+		}
+	}
+
+	return in.run(functionID, args, state)
+}
+
+// signature returns the signature of the function having the given index.
+func (in *Instance) signature(functionID int) *wasm.FunctionSig {
+	module := in.vm.Module.Base
+
+	imported := 0
+	if module.Import != nil {
+		for _, entry := range module.Import.Entries {
+			imp, ok := entry.Type.(wasm.FuncImport)
+			if !ok {
+				continue
+			}
+			if imported == functionID {
+				return &module.Types.Entries[imp.Type]
+			}
+			imported++
+		}
+	}
+
+	index := functionID - imported
+	if index < 0 || index >= len(module.FunctionIndexSpace) {
+		return nil
+	}
+
+	return module.FunctionIndexSpace[index].Sig
+}
+
+func (in *Instance) run(functionID int, args []Value, state uint32) (result Value, err error) {
+	if in.running {
+		return nil, errReentrantInvoke
+	}
+
+	in.running = true
+	in.state = state
+
+	defer func() {
+		in.running = false
+		if in.memory != nil {
+			// the memory may have been grown during the execution
+			in.memory.data = in.vm.Memory
+		}
+
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%w: %v", ErrExecution, r)
+		}
+	}()
+
+	// reset the interpreter, which cannot be used anymore after a trap otherwise
+	in.vm.ExitError = nil
+	in.vm.CurrentFrame = -1
+	in.vm.NumValueSlots = 0
+
+	params := make([]int64, len(args))
+	for i, arg := range args {
+		params[i] = arg.raw()
+	}
+
+	ret, err := in.vm.Run(functionID, params...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrExecution, err)
+	}
+
+	signature := in.signature(functionID)
+	if signature == nil || len(signature.ReturnTypes) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	return valueOf(signature.ReturnTypes[0], ret), nil
+}
+
+// callHost calls the host function of the supervisor using the dispatch thunk.
+func (in *Instance) callHost(function hostFunction, args []Value) (Value, error) {
+	enc, err := EncodeValues(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.memory != nil {
+		// let the supervisor access the memory as grown by the sandboxed instance,
+		// and use the memory as possibly modified by the supervisor afterwards.
+		in.memory.data = in.vm.Memory
+		defer func() {
+			in.vm.Memory = in.memory.data
+		}()
+	}
+
+	res, err := in.dispatcher.Dispatch(in.dispatchThunk, in.state, function.index, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decodeHostResult(res)
+	if err != nil {
+		return nil, err
+	}
+
+	returnTypes := function.signature.ReturnTypes
+	switch {
+	case len(returnTypes) == 0 && value == nil:
+		return nil, nil //nolint:nilnil
+	case len(returnTypes) == 1 && value != nil && hasType(value, returnTypes[0]):
+		return value, nil
+	default:
+		return nil, fmt.Errorf("host function %d returned a value of unexpected type", function.index)
+	}
+}
+
+// resolver resolves the imported functions of a sandboxed instance to host functions of the supervisor.
+type resolver struct {
+	instance *Instance
+}
+
+// ResolveFunc returns the function calling the host function imported by the instance.
+func (r *resolver) ResolveFunc(module, field string) exec.FunctionImport {
+	function, ok := r.instance.hostFunctions[importName{module: module, field: field}]
+	if !ok {
+		panic(fmt.Sprintf("function %s.%s is not provided", module, field))
+	}
+
+	return func(vm *exec.VirtualMachine) int64 {
+		frame := vm.GetCurrentFrame()
+
+		args := make([]Value, len(function.signature.ParamTypes))
+		for i, paramType := range function.signature.ParamTypes {
+			args[i] = valueOf(paramType, frame.Locals[i])
+		}
+
+		value, err := r.instance.callHost(function, args)
+		if err != nil {
+			panic(err)
+		}
+
+		if value == nil {
+			return 0
+		}
+		return value.raw()
+	}
+}
+
+// ResolveGlobal panics since the sandboxed instances cannot import globals.
+func (*resolver) ResolveGlobal(module, field string) int64 {
+	panic(fmt.Sprintf("global %s.%s is not provided", module, field))
+}
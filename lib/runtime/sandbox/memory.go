@@ -0,0 +1,76 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// PageSize is the size of a page of a sandbox memory
+	PageSize = 65536
+	// maxPages is the maximum number of pages of a 32 bits linear memory
+	maxPages = 65536
+)
+
+var (
+	// ErrOutOfBounds is returned when accessing a sandbox memory out of its bounds.
+	ErrOutOfBounds = errors.New("out of bounds memory access")
+
+	errMemoryLimitsInvalid = errors.New("memory limits are invalid")
+)
+
+// Memory is a linear memory created by the supervisor, which can be imported by the
+// sandboxed instances, and read and written by the supervisor.
+type Memory struct {
+	data []byte
+	// maximum is the maximum number of pages, or 0 if the memory is unbounded.
+	maximum uint32
+}
+
+// newMemory creates a memory of the given initial number of pages. The maximum
+// is math.MaxUint32 for memories without maximum number of pages.
+func newMemory(initial, maximum uint32) (*Memory, error) {
+	if maximum == math.MaxUint32 {
+		maximum = 0
+	}
+
+	if initial > maxPages || maximum > maxPages || (maximum != 0 && initial > maximum) {
+		return nil, fmt.Errorf("%w: initial %d and maximum %d", errMemoryLimitsInvalid, initial, maximum)
+	}
+
+	return &Memory{
+		data:    make([]byte, int(initial)*PageSize),
+		maximum: maximum,
+	}, nil
+}
+
+// Pages returns the current number of pages of the memory.
+func (m *Memory) Pages() uint32 {
+	return uint32(len(m.data) / PageSize)
+}
+
+// Get copies the memory at the given offset into buf.
+func (m *Memory) Get(offset uint32, buf []byte) error {
+	end := uint64(offset) + uint64(len(buf))
+	if end > uint64(len(m.data)) {
+		return ErrOutOfBounds
+	}
+
+	copy(buf, m.data[offset:end])
+	return nil
+}
+
+// Set copies data into the memory at the given offset.
+func (m *Memory) Set(offset uint32, data []byte) error {
+	end := uint64(offset) + uint64(len(data))
+	if end > uint64(len(m.data)) {
+		return ErrOutOfBounds
+	}
+
+	copy(m.data[offset:end], data)
+	return nil
+}
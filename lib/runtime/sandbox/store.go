@@ -0,0 +1,125 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Return codes of the sandbox host functions
+const (
+	// ReturnOK is returned on success
+	ReturnOK uint32 = 0
+	// ReturnExecution is returned when the sandboxed execution fails
+	ReturnExecution uint32 = math.MaxUint32
+	// ReturnOutOfBounds is returned when a memory access is out of bounds
+	ReturnOutOfBounds uint32 = math.MaxUint32 - 1
+	// ReturnModule is returned when a sandboxed module cannot be instantiated
+	ReturnModule uint32 = math.MaxUint32 - 2
+)
+
+var (
+	// ErrMemoryNotFound is returned when a sandbox memory does not exist or was torn down.
+	ErrMemoryNotFound = errors.New("sandbox memory not found")
+	// ErrInstanceNotFound is returned when a sandboxed instance does not exist or was torn down.
+	ErrInstanceNotFound = errors.New("sandboxed instance not found")
+)
+
+// Store holds the memories and the instances created by the supervisor runtime using
+// the sandbox host functions, which refer to them by index. It is not safe for
+// concurrent use, like the runtime instance owning it.
+type Store struct {
+	memories   []*Memory
+	instances  []*Instance
+	dispatcher Dispatcher
+}
+
+// NewStore returns an empty store, whose instances call the host functions
+// of the supervisor using the given dispatcher.
+func NewStore(dispatcher Dispatcher) *Store {
+	return &Store{
+		dispatcher: dispatcher,
+	}
+}
+
+// Reset releases all the memories and the instances of the store. The indexes
+// of the store only refer to its memories and instances during a runtime call.
+func (s *Store) Reset() {
+	s.memories = nil
+	s.instances = nil
+}
+
+// NewMemory creates a memory of the given initial number of pages, and returns its index.
+// The maximum is math.MaxUint32 for memories without maximum number of pages.
+func (s *Store) NewMemory(initial, maximum uint32) (uint32, error) {
+	memory, err := newMemory(initial, maximum)
+	if err != nil {
+		return 0, err
+	}
+
+	s.memories = append(s.memories, memory)
+	return uint32(len(s.memories) - 1), nil
+}
+
+// Memory returns the memory having the given index.
+func (s *Store) Memory(index uint32) (*Memory, error) {
+	if uint64(index) >= uint64(len(s.memories)) || s.memories[index] == nil {
+		return nil, fmt.Errorf("%w: %d", ErrMemoryNotFound, index)
+	}
+
+	return s.memories[index], nil
+}
+
+// TeardownMemory releases the memory having the given index.
+func (s *Store) TeardownMemory(index uint32) error {
+	_, err := s.Memory(index)
+	if err != nil {
+		return err
+	}
+
+	s.memories[index] = nil
+	return nil
+}
+
+// Instantiate instantiates the code of a sandboxed module, whose imports are resolved using
+// the SCALE encoded environment definition, and returns the index of the instance.
+// The dispatch thunk is the function of the supervisor called with the state to execute its
+// host functions. ErrInvalidModule is returned if the module cannot be instantiated, and
+// ErrExecution if its start function fails.
+func (s *Store) Instantiate(code, environment []byte, dispatchThunk, state uint32) (uint32, error) {
+	env, err := decodeEnvironment(environment, s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidModule, err)
+	}
+
+	instance, err := newInstance(code, env, s.dispatcher, dispatchThunk, state)
+	if err != nil {
+		return 0, err
+	}
+
+	s.instances = append(s.instances, instance)
+	return uint32(len(s.instances) - 1), nil
+}
+
+// Instance returns the instance having the given index.
+func (s *Store) Instance(index uint32) (*Instance, error) {
+	if uint64(index) >= uint64(len(s.instances)) || s.instances[index] == nil {
+		return nil, fmt.Errorf("%w: %d", ErrInstanceNotFound, index)
+	}
+
+	return s.instances[index], nil
+}
+
+// TeardownInstance releases the instance having the given index.
+func (s *Store) TeardownInstance(index uint32) error {
+	_, err := s.Instance(index)
+	if err != nil {
+		return err
+	}
+
+	s.instances[index] = nil
+	return nil
+}
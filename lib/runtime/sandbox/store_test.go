@@ -0,0 +1,372 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/require"
+)
+
+// guestModule is the hand written binary of the following module:
+//
+//	(module
+//	  (import "env" "memory" (memory 1))
+//	  (import "env" "add" (func $add (param i32 i32) (result i32)))
+//	  (func (export "call_add") (param i32 i32) (result i32)
+//	    local.get 0 local.get 1 call $add)
+//	  (func (export "store") (param i32 i32)
+//	    local.get 0 local.get 1 i32.store)
+//	  (func (export "trap") unreachable)
+//	  (func (export "grow") (param i32) (result i32)
+//	    local.get 0 memory.grow)
+//	  (data (i32.const 16) "hi"))
+var guestModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, // magic and version
+	// type section
+	0x01, 0x14, 0x04,
+	0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7f, // (i32, i32) -> i32
+	0x60, 0x02, 0x7f, 0x7f, 0x00, // (i32, i32) -> ()
+	0x60, 0x00, 0x00, // () -> ()
+	0x60, 0x01, 0x7f, 0x01, 0x7f, // (i32) -> i32
+	// import section
+	0x02, 0x19, 0x02,
+	0x03, 'e', 'n', 'v', 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00, 0x01,
+	0x03, 'e', 'n', 'v', 0x03, 'a', 'd', 'd', 0x00, 0x00,
+	// function section
+	0x03, 0x05, 0x04, 0x00, 0x01, 0x02, 0x03,
+	// export section
+	0x07, 0x22, 0x04,
+	0x08, 'c', 'a', 'l', 'l', '_', 'a', 'd', 'd', 0x00, 0x01,
+	0x05, 's', 't', 'o', 'r', 'e', 0x00, 0x02,
+	0x04, 't', 'r', 'a', 'p', 0x00, 0x03,
+	0x04, 'g', 'r', 'o', 'w', 0x00, 0x04,
+	// code section
+	0x0a, 0x1f, 0x04,
+	0x08, 0x00, 0x20, 0x00, 0x20, 0x01, 0x10, 0x00, 0x0b,
+	0x09, 0x00, 0x20, 0x00, 0x20, 0x01, 0x36, 0x02, 0x00, 0x0b,
+	0x03, 0x00, 0x00, 0x0b,
+	0x06, 0x00, 0x20, 0x00, 0x40, 0x00, 0x0b,
+	// data section
+	0x0b, 0x08, 0x01, 0x00, 0x41, 0x10, 0x0b, 0x02, 'h', 'i',
+}
+
+const (
+	testDispatchThunk = 3
+	testAddIndex      = 7
+)
+
+type dispatcherFunc func(dispatchThunk, state, index uint32, args []byte) ([]byte, error)
+
+func (f dispatcherFunc) Dispatch(dispatchThunk, state, index uint32, args []byte) ([]byte, error) {
+	return f(dispatchThunk, state, index, args)
+}
+
+// addDispatcher implements the `add` host function, and fails if the state is 0.
+func addDispatcher(t *testing.T) Dispatcher {
+	return dispatcherFunc(func(dispatchThunk, state, index uint32, args []byte) ([]byte, error) {
+		require.Equal(t, uint32(testDispatchThunk), dispatchThunk)
+		require.Equal(t, uint32(testAddIndex), index)
+
+		if state == 0 {
+			return []byte{1}, nil
+		}
+
+		values, err := DecodeValues(args)
+		require.NoError(t, err)
+		require.Len(t, values, 2)
+
+		sum := values[0].(I32) + values[1].(I32)
+		enc, err := EncodeReturnValue(sum)
+		require.NoError(t, err)
+		return append([]byte{0}, enc...), nil
+	})
+}
+
+func encodeTestEnvironment(t *testing.T, memoryIndex uint32) []byte {
+	t.Helper()
+
+	entries := []environmentEntry{
+		{
+			ModuleName: []byte("env"),
+			FieldName:  []byte("memory"),
+			Entity:     externEntity{Kind: externMemory, Index: memoryIndex},
+		},
+		{
+			ModuleName: []byte("env"),
+			FieldName:  []byte("add"),
+			Entity:     externEntity{Kind: externFunction, Index: testAddIndex},
+		},
+	}
+
+	enc, err := scale.Marshal(entries)
+	require.NoError(t, err)
+	return enc
+}
+
+func TestStore_Memory(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(nil)
+
+	_, err := store.NewMemory(2, 1)
+	require.ErrorIs(t, err, errMemoryLimitsInvalid)
+
+	index, err := store.NewMemory(1, math.MaxUint32)
+	require.NoError(t, err)
+
+	memory, err := store.Memory(index)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), memory.Pages())
+
+	err = memory.Set(PageSize-2, []byte{1, 2})
+	require.NoError(t, err)
+
+	buf := make([]byte, 2)
+	err = memory.Get(PageSize-2, buf)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, buf)
+
+	err = memory.Set(PageSize-1, []byte{1, 2})
+	require.ErrorIs(t, err, ErrOutOfBounds)
+
+	err = memory.Get(math.MaxUint32, buf)
+	require.ErrorIs(t, err, ErrOutOfBounds)
+
+	err = store.TeardownMemory(index)
+	require.NoError(t, err)
+
+	_, err = store.Memory(index)
+	require.ErrorIs(t, err, ErrMemoryNotFound)
+
+	err = store.TeardownMemory(index)
+	require.ErrorIs(t, err, ErrMemoryNotFound)
+}
+
+func TestStore_Reset(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(addDispatcher(t))
+
+	memoryIndex, err := store.NewMemory(1, math.MaxUint32)
+	require.NoError(t, err)
+
+	index, err := store.Instantiate(guestModule, encodeTestEnvironment(t, memoryIndex), testDispatchThunk, 1)
+	require.NoError(t, err)
+
+	store.Reset()
+
+	_, err = store.Memory(memoryIndex)
+	require.ErrorIs(t, err, ErrMemoryNotFound)
+
+	_, err = store.Instance(index)
+	require.ErrorIs(t, err, ErrInstanceNotFound)
+
+	// the indexes start again from 0
+	memoryIndex, err = store.NewMemory(1, math.MaxUint32)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), memoryIndex)
+}
+
+func TestStore_Instance(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(addDispatcher(t))
+
+	memoryIndex, err := store.NewMemory(1, math.MaxUint32)
+	require.NoError(t, err)
+
+	index, err := store.Instantiate(guestModule, encodeTestEnvironment(t, memoryIndex), testDispatchThunk, 1)
+	require.NoError(t, err)
+
+	instance, err := store.Instance(index)
+	require.NoError(t, err)
+
+	memory, err := store.Memory(memoryIndex)
+	require.NoError(t, err)
+
+	// the data segment is copied into the imported memory
+	buf := make([]byte, 2)
+	err = memory.Get(16, buf)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hi"), buf)
+
+	// the imported function is dispatched to the supervisor
+	result, err := instance.Invoke("call_add", []Value{I32(2), I32(3)}, 1)
+	require.NoError(t, err)
+	require.Equal(t, I32(5), result)
+
+	// the writes of the instance are visible by the supervisor
+	result, err = instance.Invoke("store", []Value{I32(32), I32(0x01020304)}, 1)
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	buf = make([]byte, 4)
+	err = memory.Get(32, buf)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 3, 2, 1}, buf)
+
+	result, err = instance.Invoke("grow", []Value{I32(1)}, 1)
+	require.NoError(t, err)
+	require.Equal(t, I32(1), result)
+	require.Equal(t, uint32(2), memory.Pages())
+
+	_, err = instance.Invoke("trap", nil, 1)
+	require.ErrorIs(t, err, ErrExecution)
+
+	// the host function returns an error if the state is 0
+	_, err = instance.Invoke("call_add", []Value{I32(2), I32(3)}, 0)
+	require.ErrorIs(t, err, ErrExecution)
+
+	// the instance can still be used after a trap
+	result, err = instance.Invoke("call_add", []Value{I32(-1), I32(3)}, 1)
+	require.NoError(t, err)
+	require.Equal(t, I32(2), result)
+
+	_, err = instance.Invoke("call_add", []Value{I64(2), I32(3)}, 1)
+	require.ErrorIs(t, err, ErrExecution)
+
+	_, err = instance.Invoke("call_add", []Value{I32(2)}, 1)
+	require.ErrorIs(t, err, ErrExecution)
+
+	_, err = instance.Invoke("unknown", nil, 1)
+	require.ErrorIs(t, err, ErrExportNotFound)
+
+	err = store.TeardownInstance(index)
+	require.NoError(t, err)
+
+	_, err = store.Instance(index)
+	require.ErrorIs(t, err, ErrInstanceNotFound)
+}
+
+func TestStore_Instantiate(t *testing.T) {
+	t.Parallel()
+
+	missingFunction, err := scale.Marshal([]environmentEntry{{
+		ModuleName: []byte("env"),
+		FieldName:  []byte("memory"),
+		Entity:     externEntity{Kind: externMemory, Index: 0},
+	}})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		code        []byte
+		environment func(t *testing.T) []byte
+		errIs       error
+	}{
+		"valid": {
+			code:        guestModule,
+			environment: func(t *testing.T) []byte { return encodeTestEnvironment(t, 0) },
+		},
+		"invalid code": {
+			code:        []byte{1, 2, 3},
+			environment: func(t *testing.T) []byte { return encodeTestEnvironment(t, 0) },
+			errIs:       ErrInvalidModule,
+		},
+		"function without code": {
+			// (type (func)) (func (type 0)) without the code section of the function
+			code:        []byte("\x00asm\x01\x00\x00\x00\x01\x04\x01\x60\x00\x00\x03\x02\x01\x00"),
+			environment: func(t *testing.T) []byte { return encodeTestEnvironment(t, 0) },
+			errIs:       ErrInvalidModule,
+		},
+		"data segment without offset": {
+			// (memory 1) with a data segment whose offset expression is empty
+			code:        []byte("\x00asm\x01\x00\x00\x00\x05\x03\x01\x00\x01\x0b\x04\x01\x00\x0b\x00"),
+			environment: func(t *testing.T) []byte { return encodeTestEnvironment(t, 0) },
+			errIs:       ErrInvalidModule,
+		},
+		"invalid environment": {
+			code:        guestModule,
+			environment: func(t *testing.T) []byte { return []byte{4} },
+			errIs:       ErrInvalidModule,
+		},
+		"missing memory": {
+			code:        guestModule,
+			environment: func(t *testing.T) []byte { return encodeTestEnvironment(t, 1) },
+			errIs:       ErrInvalidModule,
+		},
+		"missing function": {
+			code:        guestModule,
+			environment: func(t *testing.T) []byte { return missingFunction },
+			errIs:       ErrInvalidModule,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewStore(addDispatcher(t))
+			_, err := store.NewMemory(1, math.MaxUint32)
+			require.NoError(t, err)
+
+			_, err = store.Instantiate(testCase.code, testCase.environment(t), testDispatchThunk, 1)
+			if testCase.errIs != nil {
+				require.ErrorIs(t, err, testCase.errIs)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestInstance_DispatchError(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	store := NewStore(dispatcherFunc(func(dispatchThunk, state, index uint32, args []byte) ([]byte, error) {
+		return nil, errTest
+	}))
+
+	memoryIndex, err := store.NewMemory(1, math.MaxUint32)
+	require.NoError(t, err)
+
+	index, err := store.Instantiate(guestModule, encodeTestEnvironment(t, memoryIndex), testDispatchThunk, 1)
+	require.NoError(t, err)
+
+	instance, err := store.Instance(index)
+	require.NoError(t, err)
+
+	_, err = instance.Invoke("call_add", []Value{I32(2), I32(3)}, 1)
+	require.ErrorIs(t, err, ErrExecution)
+	require.ErrorContains(t, err, errTest.Error())
+}
+
+func TestValues(t *testing.T) {
+	t.Parallel()
+
+	values := []Value{I32(-1), I64(2), F32(3), F64(4)}
+	enc, err := EncodeValues(values)
+	require.NoError(t, err)
+	require.Equal(t, []byte{
+		16,
+		0, 0xff, 0xff, 0xff, 0xff,
+		1, 2, 0, 0, 0, 0, 0, 0, 0,
+		2, 3, 0, 0, 0,
+		3, 4, 0, 0, 0, 0, 0, 0, 0,
+	}, enc)
+
+	decoded, err := DecodeValues(enc)
+	require.NoError(t, err)
+	require.Equal(t, values, decoded)
+
+	enc, err = EncodeReturnValue(nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0}, enc)
+
+	enc, err = EncodeReturnValue(I32(1))
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 0, 1, 0, 0, 0}, enc)
+
+	value, err := decodeHostResult(append([]byte{0}, enc...))
+	require.NoError(t, err)
+	require.Equal(t, I32(1), value)
+
+	_, err = decodeHostResult([]byte{1, 0})
+	require.ErrorIs(t, err, errHostFunction)
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/go-interpreter/wagon/wasm"
+)
+
+var (
+	errHostFunction         = errors.New("host function returned an error")
+	errReturnValueMalformed = errors.New("return value is malformed")
+)
+
+// Value is a value passed to or returned by a sandboxed function,
+// which is one of I32, I64, F32 and F64.
+type Value interface {
+	scale.VaryingDataTypeValue
+	raw() int64
+}
+
+// I32 is a 32 bits integer value
+type I32 int32
+
+// Index returns the VDT index
+func (I32) Index() uint { return 0 }
+
+func (v I32) raw() int64 { return int64(uint32(v)) }
+
+// I64 is a 64 bits integer value
+type I64 int64
+
+// Index returns the VDT index
+func (I64) Index() uint { return 1 }
+
+func (v I64) raw() int64 { return int64(v) }
+
+// F32 is a 32 bits float value, represented by its bits
+type F32 uint32
+
+// Index returns the VDT index
+func (F32) Index() uint { return 2 }
+
+func (v F32) raw() int64 { return int64(v) }
+
+// F64 is a 64 bits float value, represented by its bits
+type F64 uint64
+
+// Index returns the VDT index
+func (F64) Index() uint { return 3 }
+
+func (v F64) raw() int64 { return int64(v) }
+
+func newValueVDT() scale.VaryingDataType {
+	return scale.MustNewVaryingDataType(I32(0), I64(0), F32(0), F64(0))
+}
+
+// valueOf returns the value of the given type stored in the raw representation of the interpreter.
+func valueOf(valueType wasm.ValueType, raw int64) Value {
+	switch valueType {
+	case wasm.ValueTypeI64:
+		return I64(raw)
+	case wasm.ValueTypeF32:
+		return F32(uint32(raw))
+	case wasm.ValueTypeF64:
+		return F64(uint64(raw))
+	default:
+		return I32(int32(raw))
+	}
+}
+
+// hasType returns true if the value is of the given type.
+func hasType(value Value, valueType wasm.ValueType) bool {
+	switch value.(type) {
+	case I32:
+		return valueType == wasm.ValueTypeI32
+	case I64:
+		return valueType == wasm.ValueTypeI64
+	case F32:
+		return valueType == wasm.ValueTypeF32
+	case F64:
+		return valueType == wasm.ValueTypeF64
+	default:
+		return false
+	}
+}
+
+// DecodeValues decodes the SCALE encoded values, such as the arguments of a sandboxed function.
+func DecodeValues(in []byte) ([]Value, error) {
+	vdts := scale.NewVaryingDataTypeSlice(newValueVDT())
+	err := scale.Unmarshal(in, &vdts)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]Value, len(vdts.Types))
+	for i, vdt := range vdts.Types {
+		values[i] = vdt.Value().(Value)
+	}
+
+	return values, nil
+}
+
+// EncodeValues SCALE encodes the given values.
+func EncodeValues(values []Value) ([]byte, error) {
+	vdts := scale.NewVaryingDataTypeSlice(newValueVDT())
+	for _, value := range values {
+		err := vdts.Add(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scale.Marshal(vdts)
+}
+
+// EncodeReturnValue SCALE encodes the value returned by a sandboxed function,
+// which is nil if the function does not return any value.
+func EncodeReturnValue(value Value) ([]byte, error) {
+	if value == nil {
+		return []byte{0}, nil
+	}
+
+	vdt := newValueVDT()
+	err := vdt.Set(value)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := scale.Marshal(vdt)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{1}, enc...), nil
+}
+
+// decodeHostResult decodes the SCALE encoded result of a host function
+// called by the dispatch thunk, which is a `Result<ReturnValue, HostError>`.
+func decodeHostResult(in []byte) (Value, error) {
+	if len(in) < 2 {
+		return nil, errReturnValueMalformed
+	}
+
+	switch in[0] {
+	case 0:
+	case 1:
+		return nil, errHostFunction
+	default:
+		return nil, fmt.Errorf("%w: invalid result variant %d", errReturnValueMalformed, in[0])
+	}
+
+	switch in[1] {
+	case 0:
+		return nil, nil //nolint:nilnil
+	case 1:
+		vdt := newValueVDT()
+		err := scale.Unmarshal(in[2:], &vdt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errReturnValueMalformed, err)
+		}
+		return vdt.Value().(Value), nil
+	default:
+		return nil, fmt.Errorf("%w: invalid return value variant %d", errReturnValueMalformed, in[1])
+	}
+}
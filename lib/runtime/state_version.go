@@ -0,0 +1,63 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/lib/trie"
+)
+
+// StateVersionCache caches the state trie version of the code of a runtime instance,
+// so the runtime version is only read once per runtime code.
+// Its zero value is ready to use.
+type StateVersionCache struct {
+	mu         sync.Mutex
+	version    trie.Version
+	set        bool
+	generation uint64
+}
+
+// Get returns the cached state version, or reads it from the runtime version
+// returned by the given function if it is not cached yet.
+func (c *StateVersionCache) Get(runtimeVersion func() (Version, error)) (trie.Version, error) {
+	c.mu.Lock()
+	if c.set {
+		c.mu.Unlock()
+		return c.version, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	// the runtime version is read without holding the lock,
+	// since the runtime call may take a while.
+	version, err := runtimeVersion()
+	if err != nil {
+		return 0, fmt.Errorf("cannot get runtime version: %w", err)
+	}
+
+	stateVersion, err := trie.ParseVersion(uint32(version.StateVersion()))
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse state version: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// do not cache the state version of a runtime code replaced in the meantime
+	if c.generation == generation {
+		c.version = stateVersion
+		c.set = true
+	}
+
+	return stateVersion, nil
+}
+
+// Reset clears the cached state version, and should be called when the runtime code changes.
+func (c *StateVersionCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set = false
+	c.generation++
+}
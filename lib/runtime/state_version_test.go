@@ -0,0 +1,70 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StateVersionCache(t *testing.T) {
+	t.Parallel()
+
+	var cache StateVersionCache
+	calls := 0
+	runtimeVersion := func() (Version, error) {
+		calls++
+		return &VersionData{stateVersion: 1}, nil
+	}
+
+	version, err := cache.Get(runtimeVersion)
+	require.NoError(t, err)
+	assert.Equal(t, trie.V1, version)
+
+	// the cached state version is returned without calling the runtime
+	version, err = cache.Get(runtimeVersion)
+	require.NoError(t, err)
+	assert.Equal(t, trie.V1, version)
+	assert.Equal(t, 1, calls)
+
+	// the state version is read again once reset
+	cache.Reset()
+	version, err = cache.Get(func() (Version, error) {
+		return &LegacyVersionData{}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, trie.V0, version)
+
+	errTest := errors.New("test error")
+	cache.Reset()
+	_, err = cache.Get(func() (Version, error) {
+		return nil, errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+
+	// the state version of a runtime code replaced while reading it is not cached
+	cache.Reset()
+	version, err = cache.Get(func() (Version, error) {
+		cache.Reset()
+		return &VersionData{stateVersion: 1}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, trie.V1, version)
+
+	version, err = cache.Get(func() (Version, error) {
+		return &VersionData{stateVersion: 0}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, trie.V0, version)
+
+	cache.Reset()
+	_, err = cache.Get(func() (Version, error) {
+		return &VersionData{stateVersion: 2}, nil
+	})
+	assert.Error(t, err)
+}
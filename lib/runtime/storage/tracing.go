@@ -0,0 +1,220 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+)
+
+// Storage methods recorded by a TracingTrieState
+const (
+	TraceMethodGet                = "Get"
+	TraceMethodPut                = "Put"
+	TraceMethodDelete             = "Delete"
+	TraceMethodNextKey            = "NextKey"
+	TraceMethodClearPrefix        = "ClearPrefix"
+	TraceMethodGetChild           = "ChildGet"
+	TraceMethodPutChild           = "ChildPut"
+	TraceMethodDeleteFromChild    = "ChildDelete"
+	TraceMethodNextKeyInChild     = "ChildNextKey"
+	TraceMethodClearPrefixInChild = "ChildClearPrefix"
+	TraceMethodKillChild          = "ChildKill"
+)
+
+// TraceEvent is a storage access recorded by a TracingTrieState
+type TraceEvent struct {
+	// ExtrinsicIndex is the value of :extrinsic_index when the access happened,
+	// it is nil if the key was not set, for example outside of block execution.
+	ExtrinsicIndex *uint32
+	Method         string
+	// ChildKey is the key to the child trie accessed, it is nil for accesses to the main trie.
+	ChildKey []byte
+	Key      []byte
+	// Value is the value read or written, or the key returned for NextKey accesses.
+	Value []byte
+}
+
+// TracingTrieState is a TrieState recording every storage access made to it.
+type TracingTrieState struct {
+	*TrieState
+
+	lock           sync.Mutex
+	extrinsicIndex *uint32
+	events         []TraceEvent
+}
+
+// NewTracingTrieState returns a new TracingTrieState recording the storage accesses made to the given TrieState
+func NewTracingTrieState(ts *TrieState) *TracingTrieState {
+	return &TracingTrieState{
+		TrieState: ts,
+	}
+}
+
+// Events returns the storage accesses recorded so far, in the order they happened
+func (s *TracingTrieState) Events() []TraceEvent {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	events := make([]TraceEvent, len(s.events))
+	copy(events, s.events)
+	return events
+}
+
+func (s *TracingTrieState) record(method string, childKey, key, value []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	event := TraceEvent{
+		Method:   method,
+		ChildKey: copyBytes(childKey),
+		Key:      copyBytes(key),
+		Value:    copyBytes(value),
+	}
+
+	if s.extrinsicIndex != nil {
+		index := *s.extrinsicIndex
+		event.ExtrinsicIndex = &index
+	}
+
+	s.events = append(s.events, event)
+}
+
+func (s *TracingTrieState) setExtrinsicIndex(value []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(value) < 4 {
+		s.extrinsicIndex = nil
+		return
+	}
+
+	index := binary.LittleEndian.Uint32(value)
+	s.extrinsicIndex = &index
+}
+
+// Set sets a key-value pair in the trie
+func (s *TracingTrieState) Set(key, value []byte) {
+	if bytes.Equal(key, common.ExtrinsicIndexKey) {
+		s.setExtrinsicIndex(value)
+	}
+
+	s.record(TraceMethodPut, nil, key, value)
+	s.TrieState.Set(key, value)
+}
+
+// Get gets a value from the trie
+func (s *TracingTrieState) Get(key []byte) []byte {
+	value := s.TrieState.Get(key)
+	s.record(TraceMethodGet, nil, key, value)
+	return value
+}
+
+// Delete deletes a key from the trie
+func (s *TracingTrieState) Delete(key []byte) {
+	if bytes.Equal(key, common.ExtrinsicIndexKey) {
+		s.setExtrinsicIndex(nil)
+	}
+
+	s.record(TraceMethodDelete, nil, key, nil)
+	s.TrieState.Delete(key)
+}
+
+// NextKey returns the next key in the trie in lexicographical order. If it does not exist, it returns nil.
+func (s *TracingTrieState) NextKey(key []byte) []byte {
+	next := s.TrieState.NextKey(key)
+	s.record(TraceMethodNextKey, nil, key, next)
+	return next
+}
+
+// ClearPrefix deletes all key-value pairs from the trie where the key starts with the given prefix
+func (s *TracingTrieState) ClearPrefix(prefix []byte) error {
+	s.record(TraceMethodClearPrefix, nil, prefix, nil)
+	return s.TrieState.ClearPrefix(prefix)
+}
+
+// ClearPrefixLimit deletes key-value pairs from the trie where the key starts with the given prefix till limit reached
+func (s *TracingTrieState) ClearPrefixLimit(prefix []byte, limit uint32) (uint32, bool) {
+	s.record(TraceMethodClearPrefix, nil, prefix, nil)
+	return s.TrieState.ClearPrefixLimit(prefix, limit)
+}
+
+// SetChild sets the child trie at the given key
+func (s *TracingTrieState) SetChild(keyToChild []byte, child *trie.Trie) error {
+	s.record(TraceMethodPutChild, keyToChild, nil, nil)
+	return s.TrieState.SetChild(keyToChild, child)
+}
+
+// SetChildStorage sets a key-value pair in a child trie
+func (s *TracingTrieState) SetChildStorage(keyToChild, key, value []byte) error {
+	s.record(TraceMethodPutChild, keyToChild, key, value)
+	return s.TrieState.SetChildStorage(keyToChild, key, value)
+}
+
+// GetChild returns the child trie at the given key
+func (s *TracingTrieState) GetChild(keyToChild []byte) (*trie.Trie, error) {
+	s.record(TraceMethodGetChild, keyToChild, nil, nil)
+	return s.TrieState.GetChild(keyToChild)
+}
+
+// GetChildStorage returns a value from a child trie
+func (s *TracingTrieState) GetChildStorage(keyToChild, key []byte) ([]byte, error) {
+	value, err := s.TrieState.GetChildStorage(keyToChild, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(TraceMethodGetChild, keyToChild, key, value)
+	return value, nil
+}
+
+// DeleteChild deletes a child trie from the main trie
+func (s *TracingTrieState) DeleteChild(key []byte) {
+	s.record(TraceMethodKillChild, key, nil, nil)
+	s.TrieState.DeleteChild(key)
+}
+
+// DeleteChildLimit deletes up to limit of database entries by lexicographic order, return number
+// deleted, true if all delete otherwise false
+func (s *TracingTrieState) DeleteChildLimit(key []byte, limit *[]byte) (uint32, bool, error) {
+	s.record(TraceMethodKillChild, key, nil, nil)
+	return s.TrieState.DeleteChildLimit(key, limit)
+}
+
+// ClearChildStorage removes the child storage entry from the trie
+func (s *TracingTrieState) ClearChildStorage(keyToChild, key []byte) error {
+	s.record(TraceMethodDeleteFromChild, keyToChild, key, nil)
+	return s.TrieState.ClearChildStorage(keyToChild, key)
+}
+
+// ClearPrefixInChild clears all the keys from the child trie that have the given prefix
+func (s *TracingTrieState) ClearPrefixInChild(keyToChild, prefix []byte) error {
+	s.record(TraceMethodClearPrefixInChild, keyToChild, prefix, nil)
+	return s.TrieState.ClearPrefixInChild(keyToChild, prefix)
+}
+
+// GetChildNextKey returns the next lexicographical larger key from child storage. If it does not exist, it returns nil.
+func (s *TracingTrieState) GetChildNextKey(keyToChild, key []byte) ([]byte, error) {
+	next, err := s.TrieState.GetChildNextKey(keyToChild, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(TraceMethodNextKeyInChild, keyToChild, key, next)
+	return next, nil
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
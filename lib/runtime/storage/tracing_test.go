@@ -0,0 +1,51 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingTrieState(t *testing.T) {
+	ts := NewTracingTrieState(newTestTrieState(t))
+
+	ts.Set([]byte("a"), []byte("1"))
+	ts.Set(common.ExtrinsicIndexKey, []byte{1, 0, 0, 0})
+	value := ts.Get([]byte("a"))
+	assert.Equal(t, []byte("1"), value)
+
+	err := ts.SetChild([]byte("child"), trie.NewEmptyTrie())
+	require.NoError(t, err)
+	err = ts.SetChildStorage([]byte("child"), []byte("b"), []byte("2"))
+	require.NoError(t, err)
+	value, err = ts.GetChildStorage([]byte("child"), []byte("b"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("2"), value)
+
+	ts.Delete(common.ExtrinsicIndexKey)
+	next := ts.NextKey([]byte("Z"))
+	assert.Equal(t, []byte("a"), next)
+	err = ts.ClearPrefix([]byte("a"))
+	require.NoError(t, err)
+
+	one := uint32(1)
+	expected := []TraceEvent{
+		{Method: TraceMethodPut, Key: []byte("a"), Value: []byte("1")},
+		{ExtrinsicIndex: &one, Method: TraceMethodPut, Key: common.ExtrinsicIndexKey, Value: []byte{1, 0, 0, 0}},
+		{ExtrinsicIndex: &one, Method: TraceMethodGet, Key: []byte("a"), Value: []byte("1")},
+		{ExtrinsicIndex: &one, Method: TraceMethodPutChild, ChildKey: []byte("child")},
+		{ExtrinsicIndex: &one, Method: TraceMethodPutChild, ChildKey: []byte("child"), Key: []byte("b"), Value: []byte("2")},
+		{ExtrinsicIndex: &one, Method: TraceMethodGetChild, ChildKey: []byte("child"), Key: []byte("b"), Value: []byte("2")},
+		{Method: TraceMethodDelete, Key: common.ExtrinsicIndexKey},
+		{Method: TraceMethodNextKey, Key: []byte("Z"), Value: []byte("a")},
+		{Method: TraceMethodClearPrefix, Key: []byte("a")},
+	}
+	assert.Equal(t, expected, ts.Events())
+	assert.Nil(t, ts.Get([]byte("a")))
+}
@@ -18,6 +18,29 @@ type TrieState struct {
 	t       *trie.Trie
 	oldTrie *trie.Trie // this is the trie before BeginStorageTransaction is called. set to nil if it isn't called
 	lock    sync.RWMutex
+
+	offchainIndex    []OffchainIndexChange
+	oldOffchainIndex int // this is the number of offchain index changes before BeginStorageTransaction is called
+
+	transactionIndex    []TransactionIndexOperation
+	oldTransactionIndex int // this is the number of transaction index operations before BeginStorageTransaction is called
+}
+
+// OffchainIndexChange is a change of the offchain index made during the execution of a block.
+// The key is removed from the offchain storage if the value is nil.
+type OffchainIndexChange struct {
+	Key   []byte
+	Value *[]byte
+}
+
+// TransactionIndexOperation is an operation of the transaction index made during the execution of a block.
+// The operation either indexes the last Size bytes of the extrinsic, whose hash is Hash, or renews the data
+// with the given hash which was indexed by a previous block.
+type TransactionIndexOperation struct {
+	Extrinsic uint32
+	Hash      common.Hash
+	Size      uint32
+	Renew     bool
 }
 
 // NewTrieState returns a new TrieState with the given trie
@@ -52,6 +75,8 @@ func (s *TrieState) BeginStorageTransaction() {
 	defer s.lock.Unlock()
 	s.oldTrie = s.t
 	s.t = s.t.Snapshot()
+	s.oldOffchainIndex = len(s.offchainIndex)
+	s.oldTransactionIndex = len(s.transactionIndex)
 }
 
 // CommitStorageTransaction commits all storage changes made since BeginStorageTransaction was called.
@@ -67,6 +92,8 @@ func (s *TrieState) RollbackStorageTransaction() {
 	defer s.lock.Unlock()
 	s.t = s.oldTrie
 	s.oldTrie = nil
+	s.offchainIndex = s.offchainIndex[:s.oldOffchainIndex]
+	s.transactionIndex = s.transactionIndex[:s.oldTransactionIndex]
 }
 
 // Set sets a key-value pair in the trie
@@ -83,6 +110,62 @@ func (s *TrieState) Get(key []byte) []byte {
 	return s.t.Get(key)
 }
 
+// SetOffchainIndex records that the key is set to the value in the offchain index
+func (s *TrieState) SetOffchainIndex(key, value []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.offchainIndex = append(s.offchainIndex, OffchainIndexChange{Key: key, Value: &value})
+}
+
+// ClearOffchainIndex records that the key is removed from the offchain index
+func (s *TrieState) ClearOffchainIndex(key []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.offchainIndex = append(s.offchainIndex, OffchainIndexChange{Key: key})
+}
+
+// OffchainIndexChanges returns the changes of the offchain index, in the order they were made
+func (s *TrieState) OffchainIndexChanges() []OffchainIndexChange {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	changes := make([]OffchainIndexChange, len(s.offchainIndex))
+	copy(changes, s.offchainIndex)
+	return changes
+}
+
+// IndexTransaction records that the last size bytes of the extrinsic, whose hash is the given hash, are indexed
+func (s *TrieState) IndexTransaction(extrinsic, size uint32, hash common.Hash) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.transactionIndex = append(s.transactionIndex, TransactionIndexOperation{
+		Extrinsic: extrinsic,
+		Hash:      hash,
+		Size:      size,
+	})
+}
+
+// RenewTransactionIndex records that the extrinsic renews the indexed data with the given hash
+func (s *TrieState) RenewTransactionIndex(extrinsic uint32, hash common.Hash) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.transactionIndex = append(s.transactionIndex, TransactionIndexOperation{
+		Extrinsic: extrinsic,
+		Hash:      hash,
+		Renew:     true,
+	})
+}
+
+// TransactionIndexOperations returns the operations of the transaction index, in the order they were made
+func (s *TrieState) TransactionIndexOperations() []TransactionIndexOperation {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	operations := make([]TransactionIndexOperation, len(s.transactionIndex))
+	copy(operations, s.transactionIndex)
+	return operations
+}
+
 // MustRoot returns the trie's root hash. It panics if it fails to compute the root.
 func (s *TrieState) MustRoot() common.Hash {
 	return s.t.MustHash()
@@ -93,6 +176,12 @@ func (s *TrieState) Root() (common.Hash, error) {
 	return s.t.Hash()
 }
 
+// RootWithVersion returns the trie's root hash, using the
+// state trie version given to encode the modified nodes.
+func (s *TrieState) RootWithVersion(version trie.Version) (common.Hash, error) {
+	return s.t.HashWithVersion(version)
+}
+
 // Has returns whether or not a key exists
 func (s *TrieState) Has(key []byte) bool {
 	return s.Get(key) != nil
@@ -274,12 +363,13 @@ func (s *TrieState) LoadCodeHash() (common.Hash, error) {
 	return common.Blake2bHash(code)
 }
 
-// GetInsertedNodeHashes returns a set of hashes of all nodes
-// that were inserted into state trie since the last block produced.
-func (s *TrieState) GetInsertedNodeHashes() (hashesSet map[common.Hash]struct{}, err error) {
+// GetInsertedNodeHashes returns a set of hashes of all nodes and values
+// that were inserted into state trie since the last block produced,
+// using the state trie version given to encode the inserted nodes.
+func (s *TrieState) GetInsertedNodeHashes(version trie.Version) (hashesSet map[common.Hash]struct{}, err error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
-	return s.t.GetInsertedNodeHashes()
+	return s.t.GetInsertedNodeHashes(version)
 }
 
 // GetDeletedNodeHashes returns the hash of nodes that were deleted
@@ -187,6 +187,47 @@ func TestTrieState_RollbackStorageTransaction(t *testing.T) {
 	require.Equal(t, []byte(testCases[0]), val)
 }
 
+func TestTrieState_OffchainIndex(t *testing.T) {
+	ts := newTestTrieState(t)
+
+	value := []byte("noot")
+	ts.SetOffchainIndex([]byte("a"), value)
+
+	ts.BeginStorageTransaction()
+	ts.ClearOffchainIndex([]byte("a"))
+	ts.CommitStorageTransaction()
+
+	ts.BeginStorageTransaction()
+	ts.SetOffchainIndex([]byte("b"), value)
+	ts.RollbackStorageTransaction()
+
+	expected := []OffchainIndexChange{
+		{Key: []byte("a"), Value: &value},
+		{Key: []byte("a")},
+	}
+	require.Equal(t, expected, ts.OffchainIndexChanges())
+}
+
+func TestTrieState_TransactionIndex(t *testing.T) {
+	ts := newTestTrieState(t)
+
+	ts.IndexTransaction(1, 4, common.Hash{1})
+
+	ts.BeginStorageTransaction()
+	ts.RenewTransactionIndex(2, common.Hash{2})
+	ts.CommitStorageTransaction()
+
+	ts.BeginStorageTransaction()
+	ts.IndexTransaction(3, 8, common.Hash{3})
+	ts.RollbackStorageTransaction()
+
+	expected := []TransactionIndexOperation{
+		{Extrinsic: 1, Hash: common.Hash{1}, Size: 4},
+		{Extrinsic: 2, Hash: common.Hash{2}, Renew: true},
+	}
+	require.Equal(t, expected, ts.TransactionIndexOperations())
+}
+
 func TestTrieState_DeleteChildLimit(t *testing.T) {
 	ts := newTestTrieState(t)
 	child := trie.NewEmptyTrie()
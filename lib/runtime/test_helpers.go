@@ -196,7 +196,7 @@ func generateEd25519Signatures(t *testing.T, n int) []*crypto.SignatureInfo {
 }
 
 // NewTestExtrinsic builds a new extrinsic using centrifuge pkg
-func NewTestExtrinsic(t *testing.T, rt Instance, genHash, blockHash common.Hash,
+func NewTestExtrinsic(t testing.TB, rt Instance, genHash, blockHash common.Hash,
 	nonce uint64, call string, args ...interface{}) string {
 	t.Helper()
 
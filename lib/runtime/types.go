@@ -4,11 +4,14 @@
 package runtime
 
 import (
+	"context"
+
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
 )
 
 // NodeStorageType type to identify offchain storage type
@@ -57,8 +60,17 @@ type InstanceConfig struct {
 	Network     BasicNetwork
 	Transaction TransactionState
 	CodeHash    common.Hash
+	// OffchainHTTPDisabled disables the outbound HTTP requests of the offchain workers
+	OffchainHTTPDisabled bool
+	// OffchainCtx cancels the HTTP requests and the sleeps of the offchain workers once done,
+	// so that they complete as soon as possible. It is never done if nil.
+	OffchainCtx context.Context
 }
 
+// NewInstanceFunc instantiates the runtime code with the given configuration,
+// using one of the wasm executors.
+type NewInstanceFunc func(code []byte, cfg InstanceConfig) (Instance, error)
+
 // Context is the context for the wasm interpreter's imported functions
 type Context struct {
 	Storage         Storage
@@ -70,6 +82,7 @@ type Context struct {
 	Transaction     TransactionState
 	SigVerifier     *crypto.SignatureVerifier
 	OffchainHTTPSet *offchain.HTTPSet
+	Sandbox         *sandbox.Store
 }
 
 // NewValidateTransactionError returns an error based on a return value from TaggedTransactionQueueValidateTransaction
@@ -4,6 +4,8 @@
 package runtime
 
 import (
+	"bytes"
+
 	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
@@ -18,6 +20,7 @@ type Version interface {
 	ImplVersion() uint32
 	APIItems() []APIItem
 	TransactionVersion() uint32
+	StateVersion() uint8
 	Encode() ([]byte, error)
 }
 
@@ -27,6 +30,18 @@ type APIItem struct {
 	Ver  uint32
 }
 
+// APIVersion returns the version of the runtime API having the given identifier,
+// and false if the runtime does not implement the API.
+func APIVersion(version Version, id [8]byte) (uint32, bool) {
+	for _, item := range version.APIItems() {
+		if item.Name == id {
+			return item.Ver, true
+		}
+	}
+
+	return 0, false
+}
+
 // LegacyVersionData is the runtime version info returned by legacy runtimes
 type LegacyVersionData struct {
 	specName         []byte
@@ -86,6 +101,11 @@ func (lvd *LegacyVersionData) TransactionVersion() uint32 {
 	return 0
 }
 
+// StateVersion returns the state trie version
+func (lvd *LegacyVersionData) StateVersion() uint8 {
+	return 0
+}
+
 type legacyVersionData struct {
 	SpecName         []byte
 	ImplName         []byte
@@ -140,6 +160,7 @@ type VersionData struct {
 	implVersion        uint32
 	apiItems           []APIItem
 	transactionVersion uint32
+	stateVersion       uint8
 }
 
 // NewVersionData returns a new VersionData
@@ -192,6 +213,24 @@ func (vd *VersionData) TransactionVersion() uint32 {
 	return vd.transactionVersion
 }
 
+// StateVersion returns the state trie version, which is only
+// given by runtimes implementing the Core API version 4 or above.
+func (vd *VersionData) StateVersion() uint8 {
+	return vd.stateVersion
+}
+
+// hasStateVersion returns true if the state version is part
+// of the encoding of the version, which is the case from the
+// Core API version 4.
+func hasStateVersion(apiItems []APIItem) bool {
+	for _, item := range apiItems {
+		if item.Name == CoreAPIID {
+			return item.Ver >= 4
+		}
+	}
+	return false
+}
+
 type versionData struct {
 	SpecName           []byte
 	ImplName           []byte
@@ -218,17 +257,30 @@ func (vd *VersionData) Encode() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if hasStateVersion(vd.apiItems) {
+		enc = append(enc, vd.stateVersion)
+	}
 	return enc, nil
 }
 
 // Decode to scale decode []byte to VersionAPI struct
 func (vd *VersionData) Decode(in []byte) error {
 	var info versionData
-	err := scale.Unmarshal(in, &info)
+	decoder := scale.NewDecoder(bytes.NewReader(in))
+	err := decoder.Decode(&info)
 	if err != nil {
 		return err
 	}
 
+	var stateVersion uint8
+	if hasStateVersion(info.APIItems) {
+		err = decoder.Decode(&stateVersion)
+		if err != nil {
+			return err
+		}
+	}
+
 	vd.specName = info.SpecName
 	vd.implName = info.ImplName
 	vd.authoringVersion = info.AuthoringVersion
@@ -236,6 +288,7 @@ func (vd *VersionData) Decode(in []byte) error {
 	vd.implVersion = info.ImplVersion
 	vd.apiItems = info.APIItems
 	vd.transactionVersion = info.TransactionVersion
+	vd.stateVersion = stateVersion
 
 	return nil
 }
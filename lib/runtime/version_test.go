@@ -34,6 +34,45 @@ func TestVersionData(t *testing.T) {
 	require.Equal(t, version, dec)
 }
 
+func TestVersionData_StateVersion(t *testing.T) {
+	coreAPIItem := APIItem{
+		Name: CoreAPIID,
+		Ver:  4,
+	}
+
+	version := NewVersionData(
+		[]byte("polkadot"),
+		[]byte("parity-polkadot"),
+		0,
+		9190,
+		0,
+		[]APIItem{coreAPIItem},
+		12,
+	)
+	version.stateVersion = 1
+
+	b, err := version.Encode()
+	require.NoError(t, err)
+	require.Equal(t, byte(1), b[len(b)-1])
+
+	dec := new(VersionData)
+	err = dec.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, version, dec)
+	require.Equal(t, uint8(1), dec.StateVersion())
+
+	coreAPIItem.Ver = 3
+	version.apiItems = []APIItem{coreAPIItem}
+
+	b, err = version.Encode()
+	require.NoError(t, err)
+
+	dec = new(VersionData)
+	err = dec.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, uint8(0), dec.StateVersion())
+}
+
 func TestLegacyVersionData(t *testing.T) {
 	testAPIItem := APIItem{
 		Name: [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
@@ -0,0 +1,387 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+const (
+	customSectionID   byte = 0
+	typeSectionID     byte = 1
+	importSectionID   byte = 2
+	functionSectionID byte = 3
+	tableSectionID    byte = 4
+	memorySectionID   byte = 5
+	globalSectionID   byte = 6
+	exportSectionID   byte = 7
+	codeSectionID     byte = 10
+)
+
+// SandboxDispatchExport is the name of the function calling the dispatch thunks of
+// the sandbox. The dispatch thunks are functions of the table of the runtime, which
+// wasmer 0.3.x and wazero cannot call, so this function is injected in the runtime code
+// and calls the dispatch thunk given as first argument using `call_indirect`.
+const SandboxDispatchExport = "__gossamer_sandbox_dispatch"
+
+// sectionsOrder is the order of the known sections in a wasm module
+var sectionsOrder = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 12, 10, 11}
+
+// moduleSection is a section of a wasm module
+type moduleSection struct {
+	id      byte
+	payload []byte
+}
+
+// InjectSandboxDispatch adds the exported function calling the dispatch thunks
+// to the given wasm module, if the module has a table.
+func InjectSandboxDispatch(code []byte) ([]byte, error) {
+	sections, err := parseSections(code)
+	if err != nil {
+		return nil, err
+	}
+
+	var functionImports uint32
+	hasTable := false
+	for _, section := range sections {
+		switch section.id {
+		case importSectionID:
+			var tableImported bool
+			functionImports, tableImported, err = countImports(section.payload)
+			if err != nil {
+				return nil, err
+			}
+			hasTable = hasTable || tableImported
+		case tableSectionID:
+			hasTable = true
+		}
+	}
+
+	if !hasTable {
+		// the runtime cannot call any dispatch thunk
+		return code, nil
+	}
+
+	typesCount, err := sectionVectorLength(sections, typeSectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	functionsCount, err := sectionVectorLength(sections, functionSectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// (i32, i32, i32, i32) -> i64 is the type of the dispatch thunks, taking the pointer and length
+	// of the arguments, the state and the index of the host function.
+	thunkType := typesCount
+	// (i32, i32, i32, i32, i32) -> i64 is the type of the injected function, also taking the thunk.
+	dispatchType := typesCount + 1
+	dispatchIndex := functionImports + functionsCount
+
+	body := []byte{0x00} // no locals
+	for _, local := range []byte{1, 2, 3, 4, 0} {
+		body = append(body, 0x20, local) // local.get
+	}
+	body = append(body, 0x11) // call_indirect
+	body = appendULEB128(body, thunkType)
+	body = append(body, 0x00, 0x0b) // table 0, end
+
+	export := appendULEB128(nil, uint32(len(SandboxDispatchExport)))
+	export = append(export, SandboxDispatchExport...)
+	export = append(export, 0x00) // function export
+	export = appendULEB128(export, dispatchIndex)
+
+	sections, err = appendToSection(sections, typeSectionID,
+		[]byte{0x60, 0x04, 0x7f, 0x7f, 0x7f, 0x7f, 0x01, 0x7e},
+		[]byte{0x60, 0x05, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x01, 0x7e})
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err = appendToSection(sections, functionSectionID, appendULEB128(nil, dispatchType))
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err = appendToSection(sections, exportSectionID, export)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err = appendToSection(sections, codeSectionID,
+		append(appendULEB128(nil, uint32(len(body))), body...))
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeSections(code, sections), nil
+}
+
+// DefineImportedMemory replaces the memory imported by the given wasm module, if any, by a
+// memory defined by the module with the same limits, for the executors which cannot provide
+// the memory imported by the runtime.
+func DefineImportedMemory(code []byte) ([]byte, error) {
+	sections, err := parseSections(code)
+	if err != nil {
+		return nil, err
+	}
+
+	var limits []byte
+	for i, section := range sections {
+		if section.id != importSectionID {
+			continue
+		}
+
+		sections[i].payload, limits, err = removeMemoryImport(section.payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if limits == nil {
+		return code, nil
+	}
+
+	memories, err := sectionVectorLength(sections, memorySectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if memories != 0 {
+		return nil, fmt.Errorf("%w: memory is both imported and defined", errModuleMalformed)
+	}
+
+	sections, err = appendToSection(sections, memorySectionID, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeSections(code, sections), nil
+}
+
+// removeMemoryImport removes the memory import from the payload of the import section,
+// and returns the limits of the memory, which are nil if the memory is not imported.
+func removeMemoryImport(payload []byte) (imports, limits []byte, err error) {
+	count, n, err := readSliceULEB128(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := bytes.NewReader(payload[n:])
+
+	for i := uint32(0); i < count; i++ {
+		start := len(payload) - r.Len()
+
+		// skip the module and field names
+		for j := 0; j < 2; j++ {
+			if _, err = readName(r); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		var kind byte
+		kind, err = r.ReadByte()
+		if err != nil {
+			return nil, nil, errModuleMalformed
+		}
+
+		switch kind {
+		case 0: // function
+			_, err = readULEB128(r)
+		case 1: // table
+			if _, err = r.ReadByte(); err == nil {
+				_, _, err = readLimits(r)
+			}
+		case 2: // memory
+			limitsStart := len(payload) - r.Len()
+			if _, _, err = readLimits(r); err != nil {
+				return nil, nil, err
+			}
+
+			end := len(payload) - r.Len()
+			limits = append([]byte{}, payload[limitsStart:end]...)
+			imports = appendULEB128(nil, count-1)
+			imports = append(imports, payload[n:start]...)
+			imports = append(imports, payload[end:]...)
+			return imports, limits, nil
+		case 3: // global
+			_, err = r.Seek(2, io.SeekCurrent)
+		default:
+			return nil, nil, fmt.Errorf("%w: invalid import kind %d", errModuleMalformed, kind)
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return payload, nil, nil
+}
+
+func parseSections(code []byte) ([]moduleSection, error) {
+	if len(code) < 8 || !bytes.Equal(code[:4], []byte("\x00asm")) {
+		return nil, errNotWasmModule
+	}
+
+	var sections []moduleSection
+	rest := code[8:]
+	for len(rest) > 0 {
+		size, n, err := readSliceULEB128(rest[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		start := 1 + n
+		if uint64(start)+uint64(size) > uint64(len(rest)) {
+			return nil, fmt.Errorf("%w: section %d is truncated", errModuleMalformed, rest[0])
+		}
+
+		end := start + int(size)
+		sections = append(sections, moduleSection{id: rest[0], payload: rest[start:end]})
+		rest = rest[end:]
+	}
+
+	return sections, nil
+}
+
+// encodeSections encodes the sections after the magic and version of the given code
+func encodeSections(code []byte, sections []moduleSection) []byte {
+	out := append([]byte{}, code[:8]...)
+	for _, section := range sections {
+		out = append(out, section.id)
+		out = appendULEB128(out, uint32(len(section.payload)))
+		out = append(out, section.payload...)
+	}
+
+	return out
+}
+
+// countImports returns the number of imported functions, and whether a table is imported.
+func countImports(payload []byte) (functions uint32, table bool, err error) {
+	count, n, err := readSliceULEB128(payload)
+	if err != nil {
+		return 0, false, err
+	}
+	r := bytes.NewReader(payload[n:])
+
+	for i := uint32(0); i < count; i++ {
+		// skip the module and field names
+		for j := 0; j < 2; j++ {
+			var length uint32
+			length, err = readULEB128(r)
+			if err != nil {
+				return 0, false, err
+			}
+			_, _ = r.Seek(int64(length), io.SeekCurrent)
+		}
+
+		var kind byte
+		kind, err = r.ReadByte()
+		if err != nil {
+			return 0, false, errModuleMalformed
+		}
+
+		switch kind {
+		case 0: // function
+			functions++
+			_, err = readULEB128(r)
+		case 1: // table
+			table = true
+			if _, err = r.ReadByte(); err == nil {
+				_, _, err = readLimits(r)
+			}
+		case 2: // memory
+			_, _, err = readLimits(r)
+		case 3: // global
+			_, err = r.Seek(2, io.SeekCurrent)
+		default:
+			return 0, false, fmt.Errorf("%w: invalid import kind %d", errModuleMalformed, kind)
+		}
+
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	return functions, table, nil
+}
+
+// sectionVectorLength returns the number of entries of the section, which is 0 if the section is absent.
+func sectionVectorLength(sections []moduleSection, id byte) (uint32, error) {
+	for _, section := range sections {
+		if section.id == id {
+			length, _, err := readSliceULEB128(section.payload)
+			return length, err
+		}
+	}
+
+	return 0, nil
+}
+
+// appendToSection appends the entries to the vector of the section, which is created if absent.
+func appendToSection(sections []moduleSection, id byte, entries ...[]byte) ([]moduleSection, error) {
+	for i, section := range sections {
+		if section.id != id {
+			continue
+		}
+
+		length, n, err := readSliceULEB128(section.payload)
+		if err != nil {
+			return nil, err
+		}
+
+		payload := appendULEB128(nil, length+uint32(len(entries)))
+		payload = append(payload, section.payload[n:]...)
+		for _, entry := range entries {
+			payload = append(payload, entry...)
+		}
+
+		sections[i].payload = payload
+		return sections, nil
+	}
+
+	payload := appendULEB128(nil, uint32(len(entries)))
+	for _, entry := range entries {
+		payload = append(payload, entry...)
+	}
+
+	position := len(sections)
+	for i, section := range sections {
+		if section.id != customSectionID && sectionOrder(section.id) > sectionOrder(id) {
+			position = i
+			break
+		}
+	}
+
+	sections = append(sections[:position], append([]moduleSection{{id: id, payload: payload}}, sections[position:]...)...)
+	return sections, nil
+}
+
+func sectionOrder(id byte) int {
+	for i, sectionID := range sectionsOrder {
+		if sectionID == id {
+			return i
+		}
+	}
+	return len(sectionsOrder)
+}
+
+func readSliceULEB128(b []byte) (value uint32, n int, err error) {
+	r := bytes.NewReader(b)
+	value, err = readULEB128(r)
+	return value, len(b) - r.Len(), err
+}
+
+func appendULEB128(b []byte, value uint32) []byte {
+	for {
+		c := byte(value & 0x7f)
+		value >>= 7
+		if value == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InjectSandboxDispatch(t *testing.T) {
+	t.Parallel()
+
+	importedMemory := append(append(wasmName("env"), wasmName("memory")...), 0x02, 0x00, 0x01)
+	withoutTable := wasmModule(
+		wasmSection(typeSectionID, []byte{0x60, 0x01, 0x7f, 0x01, 0x7f}),
+		wasmSection(importSectionID, importedMemory),
+		wasmSection(functionSectionID, []byte{0x00}),
+		wasmSection(codeSectionID, []byte{0x04, 0x00, 0x20, 0x00, 0x0b}),
+	)
+
+	// the module has no table, so it cannot have any dispatch thunk
+	code, err := InjectSandboxDispatch(withoutTable)
+	require.NoError(t, err)
+	assert.Equal(t, withoutTable, code)
+
+	withTable := wasmModule(
+		wasmSection(typeSectionID, []byte{0x60, 0x01, 0x7f, 0x01, 0x7f}),
+		wasmSection(importSectionID, importedMemory),
+		wasmSection(functionSectionID, []byte{0x00}),
+		wasmSection(tableSectionID, []byte{0x70, 0x00, 0x02}),
+		wasmSection(9, []byte{0x00, 0x41, 0x01, 0x0b, 0x01, 0x00}),
+		wasmSection(codeSectionID, []byte{0x04, 0x00, 0x20, 0x00, 0x0b}),
+	)
+
+	code, err = InjectSandboxDispatch(withTable)
+	require.NoError(t, err)
+
+	sections, err := parseSections(code)
+	require.NoError(t, err)
+
+	ids := make([]byte, len(sections))
+	for i, section := range sections {
+		ids[i] = section.id
+	}
+	assert.Equal(t, []byte{
+		typeSectionID, importSectionID, functionSectionID, tableSectionID,
+		exportSectionID, 9, codeSectionID,
+	}, ids)
+
+	// the thunk and dispatch types, and the dispatch function are appended
+	types, err := sectionVectorLength(sections, typeSectionID)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), types)
+	functions, err := sectionVectorLength(sections, functionSectionID)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), functions)
+
+	_, err = InjectSandboxDispatch([]byte{1, 2, 3})
+	assert.ErrorIs(t, err, errNotWasmModule)
+}
+
+func Test_DefineImportedMemory(t *testing.T) {
+	t.Parallel()
+
+	importedMemory := append(append(wasmName("env"), wasmName("memory")...), 0x02, 0x01, 30, 100)
+	importedFunction := append(append(wasmName("env"), wasmName("ext_logging_log_version_1")...), 0x00, 0x01)
+	importedGlobal := append(append(wasmName("env"), wasmName("global")...), 0x03, 0x7f, 0x00)
+
+	tests := map[string]struct {
+		code     []byte
+		expected []byte
+		err      error
+	}{
+		"imported memory": {
+			code: wasmModule(
+				wasmSection(importSectionID, importedFunction, importedMemory, importedGlobal),
+				wasmSection(exportSectionID),
+			),
+			expected: wasmModule(
+				wasmSection(importSectionID, importedFunction, importedGlobal),
+				wasmSection(memorySectionID, []byte{0x01, 30, 100}),
+				wasmSection(exportSectionID),
+			),
+		},
+		"defined memory": {
+			code: wasmModule(
+				wasmSection(importSectionID, importedFunction),
+				wasmSection(memorySectionID, []byte{0x00, 17}),
+			),
+			expected: wasmModule(
+				wasmSection(importSectionID, importedFunction),
+				wasmSection(memorySectionID, []byte{0x00, 17}),
+			),
+		},
+		"imported and defined memories": {
+			code: wasmModule(
+				wasmSection(importSectionID, importedMemory),
+				wasmSection(memorySectionID, []byte{0x00, 17}),
+			),
+			err: errModuleMalformed,
+		},
+		"not a wasm module": {
+			code: []byte{1, 2, 3},
+			err:  errNotWasmModule,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			code, err := DefineImportedMemory(tt.code)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+}
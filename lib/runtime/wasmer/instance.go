@@ -4,7 +4,6 @@
 package wasmer
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"sync"
@@ -13,14 +12,14 @@
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/hostapi"
 	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
 	"github.com/ChainSafe/gossamer/lib/trie"
 
 	"github.com/ChainSafe/gossamer/lib/crypto"
 
 	wasm "github.com/wasmerio/go-ext-wasm/wasmer"
-
-	"github.com/klauspost/compress/zstd"
 )
 
 // Name represents the name of the interpreter
@@ -50,6 +49,13 @@ type Instance struct {
 	imports  func() (*wasm.Imports, error)
 	isClosed bool
 	codeHash common.Hash
+	code     []byte // the instantiated code, used to create pools of identical instances
+	trapped  bool   // set if a runtime call failed, in which case the instance state is undefined
+	// layout is the memory layout of the instantiated code, and heapPages the number of pages
+	// its memory can grow by
+	layout       *runtime.MemoryLayout
+	heapPages    uint64
+	stateVersion runtime.StateVersionCache
 	sync.Mutex
 }
 
@@ -90,6 +96,19 @@ func NewInstanceFromFile(fp string, cfg *Config) (*Instance, error) {
 	return NewInstance(bytes, cfg)
 }
 
+// NewRuntimeInstance instantiates a runtime importing the node runtime host functions,
+// and implements runtime.NewInstanceFunc
+func NewRuntimeInstance(code []byte, cfg runtime.InstanceConfig) (runtime.Instance, error) {
+	instance, err := NewInstance(code, &Config{
+		InstanceConfig: cfg,
+		Imports:        ImportsNodeRuntime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
 // NewInstance instantiates a runtime from raw wasm bytecode
 func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 	if len(code) == 0 {
@@ -97,22 +116,50 @@ func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 	}
 
 	var err error
-	code, err = decompressWasm(code)
+	code, err = runtime.DecompressWasm(code)
 	if err != nil {
 		return nil, fmt.Errorf("cannot decompress WASM code: %w", err)
 	}
 
+	code, err = runtime.InjectSandboxDispatch(code)
+	if err != nil {
+		return nil, fmt.Errorf("cannot inject sandbox dispatch function: %w", err)
+	}
+
+	layout, err := runtime.ParseMemoryLayout(code)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse memory layout: %w", err)
+	}
+
 	logger.Patch(log.SetLevel(cfg.LogLvl), log.SetCallerFunc(true))
+	hostapi.SetLogLevel(cfg.LogLvl)
+
+	// Compiles the WebAssembly module.
+	module, err := wasm.Compile(code)
+	if err != nil {
+		return nil, err
+	}
+	defer module.Close()
+
+	inst, err := newInstanceFromModule(module, layout, runtime.HeapPages(cfg.Storage), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	inst.code = code
+	return inst, nil
+}
 
+// newInstanceFromModule instantiates a runtime from the compiled module, whose memory has the given layout
+func newInstanceFromModule(module wasm.Module, layout *runtime.MemoryLayout, heapPages uint64,
+	cfg *Config) (*Instance, error) {
 	imports, err := cfg.Imports()
 	if err != nil {
 		return nil, err
 	}
 
 	// Provide importable memory for newer runtimes
-	// TODO: determine memory descriptor size that the runtime wants from the wasm. (#1268)
-	// should be doable w/ wasmer 1.0.0.
-	memory, err := wasm.NewMemory(23, 0)
+	memory, err := wasm.NewMemory(layout.MinPages, layout.MaxPages)
 	if err != nil {
 		return nil, err
 	}
@@ -123,21 +170,22 @@ func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 	}
 
 	// Instantiates the WebAssembly module.
-	instance, err := wasm.NewInstanceWithImports(code, imports)
+	instance, err := module.InstantiateWithImports(imports)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: get __heap_base exported value from runtime.
-	// wasmer 0.3.x does not support this, but wasmer 1.0.0 does (#1268)
-	heapBase := runtime.DefaultHeapBase
-
 	// Assume imported memory is used if runtime does not export any
 	if !instance.HasMemory() {
 		instance.Memory = memory
 	}
 
-	allocator := runtime.NewAllocator(instance.Memory, heapBase)
+	allocator := layout.NewAllocator(instance.Memory, heapPages)
+
+	httpSet := offchain.NewHTTPSet(cfg.OffchainCtx)
+	if cfg.OffchainHTTPDisabled {
+		httpSet.Disable()
+	}
 
 	runtimeCtx := &runtime.Context{
 		Storage:         cfg.Storage,
@@ -148,38 +196,25 @@ func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 		Network:         cfg.Network,
 		Transaction:     cfg.Transaction,
 		SigVerifier:     crypto.NewSignatureVerifier(logger),
-		OffchainHTTPSet: offchain.NewHTTPSet(),
+		OffchainHTTPSet: httpSet,
 	}
 
 	logger.Debugf("NewInstance called with runtimeCtx: %v", runtimeCtx)
 	instance.SetContextData(runtimeCtx)
 
 	inst := &Instance{
-		vm:       instance,
-		ctx:      runtimeCtx,
-		imports:  cfg.Imports,
-		codeHash: cfg.CodeHash,
+		vm:        instance,
+		ctx:       runtimeCtx,
+		imports:   cfg.Imports,
+		codeHash:  cfg.CodeHash,
+		layout:    layout,
+		heapPages: heapPages,
 	}
+	runtimeCtx.Sandbox = sandbox.NewStore(&sandboxDispatcher{instance: inst})
 
 	return inst, nil
 }
 
-// decompressWasm decompresses a Wasm blob that may or may not be compressed with zstd
-// ref: https://github.com/paritytech/substrate/blob/master/primitives/maybe-compressed-blob/src/lib.rs
-func decompressWasm(code []byte) ([]byte, error) {
-	compressionFlag := []byte{82, 188, 83, 118, 70, 219, 142, 5}
-	if !bytes.HasPrefix(code, compressionFlag) {
-		return code, nil
-	}
-
-	decoder, err := zstd.NewReader(nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
-	}
-
-	return decoder.DecodeAll(code[len(compressionFlag):], nil)
-}
-
 // GetCodeHash returns the code of the instance
 func (in *Instance) GetCodeHash() common.Hash {
 	return in.codeHash
@@ -193,6 +228,7 @@ func (in *Instance) GetContext() *runtime.Context {
 // UpdateRuntimeCode updates the runtime instance to run the given code
 func (in *Instance) UpdateRuntimeCode(code []byte) error {
 	in.Stop()
+	in.stateVersion.Reset()
 
 	err := in.setupInstanceVM(code)
 	if err != nil {
@@ -226,9 +262,22 @@ func (in *Instance) setupInstanceVM(code []byte) error {
 		return err
 	}
 
-	// TODO: determine memory descriptor size that the runtime wants from the wasm.
-	// should be doable w/ wasmer 1.0.0. (#1268)
-	memory, err := wasm.NewMemory(23, 0)
+	code, err = runtime.DecompressWasm(code)
+	if err != nil {
+		return fmt.Errorf("cannot decompress WASM code: %w", err)
+	}
+
+	code, err = runtime.InjectSandboxDispatch(code)
+	if err != nil {
+		return fmt.Errorf("cannot inject sandbox dispatch function: %w", err)
+	}
+
+	layout, err := runtime.ParseMemoryLayout(code)
+	if err != nil {
+		return fmt.Errorf("cannot parse memory layout: %w", err)
+	}
+
+	memory, err := wasm.NewMemory(layout.MinPages, layout.MaxPages)
 	if err != nil {
 		return err
 	}
@@ -243,17 +292,16 @@ func (in *Instance) setupInstanceVM(code []byte) error {
 	if err != nil {
 		return err
 	}
+	in.code = code
+	in.layout = layout
+	in.heapPages = runtime.HeapPages(in.ctx.Storage)
 
 	// Assume imported memory is used if runtime does not export any
 	if !in.vm.HasMemory() {
 		in.vm.Memory = memory
 	}
 
-	// TODO: get __heap_base exported value from runtime.
-	// wasmer 0.3.x does not support this, but wasmer 1.0.0 does (#1268)
-	heapBase := runtime.DefaultHeapBase
-
-	in.ctx.Allocator = runtime.NewAllocator(in.vm.Memory, heapBase)
+	in.ctx.Allocator = layout.NewAllocator(in.vm.Memory, in.heapPages)
 	in.vm.SetContextData(in.ctx)
 	return nil
 }
@@ -311,6 +359,8 @@ func (in *Instance) exec(function string, data []byte) ([]byte, error) {
 	}
 
 	defer in.clear()
+	// the sandboxed memories and instances only live for the duration of the call
+	defer in.ctx.Sandbox.Reset()
 
 	// Store the data into memory
 	in.store(data, int32(ptr))
@@ -318,11 +368,16 @@ func (in *Instance) exec(function string, data []byte) ([]byte, error) {
 
 	runtimeFunc, ok := in.vm.Exports[function]
 	if !ok {
-		return nil, fmt.Errorf("could not find exported function %s", function)
+		return nil, fmt.Errorf("%w: %s", runtime.ErrExportFunctionNotFound, function)
 	}
 
 	res, err := runtimeFunc(int32(ptr), datalen)
 	if err != nil {
+		in.trapped = true
+		if in.ctx.SigVerifier.IsStarted() {
+			// discard the batch verification which the runtime could not finish
+			in.ctx.SigVerifier.Reset()
+		}
 		return nil, err
 	}
 
@@ -357,3 +412,8 @@ func (in *Instance) Keystore() *keystore.GlobalKeystore {
 func (in *Instance) Validator() bool {
 	return in.ctx.Validator
 }
+
+// OffchainHTTPDisabled returns true if the outbound HTTP requests of the offchain workers are disabled
+func (in *Instance) OffchainHTTPDisabled() bool {
+	return in.ctx.OffchainHTTPSet.Disabled()
+}
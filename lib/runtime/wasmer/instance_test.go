@@ -5,13 +5,13 @@
 
 import (
 	"context"
+	"encoding/binary"
 	"os"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/stretchr/testify/require"
-
-	"github.com/klauspost/compress/zstd"
 )
 
 // test used for ensuring runtime exec calls can me made concurrently
@@ -65,40 +65,56 @@ func TestInstance_CheckRuntimeVersion(t *testing.T) {
 	require.Equal(t, expected.TransactionVersion(), version.TransactionVersion())
 }
 
-func TestDecompressWasm(t *testing.T) {
-	encoder, err := zstd.NewWriter(nil)
+func TestNewInstance_MemoryLayout(t *testing.T) {
+	module := func(sections ...[]byte) []byte {
+		code := []byte("\x00asm\x01\x00\x00\x00")
+		for _, section := range sections {
+			code = append(code, section...)
+		}
+		return code
+	}
+	section := func(id byte, payload ...byte) []byte {
+		return append([]byte{id, byte(len(payload))}, payload...)
+	}
+
+	// imports a memory of at least 30 pages, and exports a __heap_base of 131072
+	importedMemoryCode := module(
+		section(2, append([]byte{1, 3}, "env\x06memory\x02\x00\x1e"...)...),
+		section(6, 1, 0x7f, 0x00, 0x41, 0x80, 0x80, 0x08, 0x0b),
+		section(7, append([]byte{1, 11}, "__heap_base\x03\x00"...)...),
+	)
+
+	instance, err := NewInstance(importedMemoryCode, setupConfig(t, nil, DefaultTestLogLvl, 0))
 	require.NoError(t, err)
+	defer instance.Stop()
 
-	cases := []struct {
-		in       []byte
-		expected []byte
-		msg      string
-	}{
-		{
-			[]byte{82, 188, 83, 118, 70, 219, 142},
-			[]byte{82, 188, 83, 118, 70, 219, 142},
-			"partial compression flag",
-		},
-		{
-			[]byte{82, 188, 83, 118, 70, 219, 142, 6},
-			[]byte{82, 188, 83, 118, 70, 219, 142, 6},
-			"wrong compression flag",
-		},
-		{
-			[]byte{82, 188, 83, 118, 70, 219, 142, 6, 221},
-			[]byte{82, 188, 83, 118, 70, 219, 142, 6, 221},
-			"wrong compression flag with data",
-		},
-		{
-			append([]byte{82, 188, 83, 118, 70, 219, 142, 5}, encoder.EncodeAll([]byte("compressed"), nil)...),
-			[]byte("compressed"),
-			"compressed data",
-		},
-	}
+	require.Equal(t, uint32(30*runtime.PageSize), instance.vm.Memory.Length())
+	ptr, err := instance.ctx.Allocator.Allocate(1)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, ptr, uint32(131072))
 
-	for _, test := range cases {
-		actual, err := decompressWasm(test.in)
-		require.NoError(t, err)
-		require.Equal(t, test.expected, actual)
-	}
+	// exports a memory of 2 pages, and a __heap_base of 65536
+	exportedMemoryCode := module(
+		section(5, 1, 0x00, 0x02),
+		section(6, 1, 0x7f, 0x00, 0x41, 0x80, 0x80, 0x04, 0x0b),
+		section(7, append([]byte{2, 6}, "memory\x02\x00\x0b__heap_base\x03\x00"...)...),
+	)
+
+	// the memory can only grow by the number of pages set by :heappages
+	cfg := setupConfig(t, nil, DefaultTestLogLvl, 0)
+	heapPages := make([]byte, 8)
+	binary.LittleEndian.PutUint64(heapPages, 1)
+	cfg.Storage.Set(common.HeapPagesKey, heapPages)
+
+	instance, err = NewInstance(exportedMemoryCode, cfg)
+	require.NoError(t, err)
+	defer instance.Stop()
+
+	require.Equal(t, uint32(2*runtime.PageSize), instance.vm.Memory.Length())
+	ptr, err = instance.ctx.Allocator.Allocate(runtime.PageSize)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, ptr, uint32(65536))
+
+	_, err = instance.ctx.Allocator.Allocate(runtime.PageSize)
+	require.Error(t, err)
 }
@@ -0,0 +1,197 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wasmer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	wasm "github.com/wasmerio/go-ext-wasm/wasmer"
+)
+
+var errPoolClosed = errors.New("instance pool is closed")
+
+var _ runtime.InstancePool = (*InstancePool)(nil)
+
+var poolWaitDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+	Namespace: "gossamer_runtime",
+	Name:      "instance_pool_wait_duration_seconds",
+	Help:      "duration spent waiting for an instance of a runtime instance pool to be available",
+	Buckets:   []float64{0.0001, 0.001, 0.01, 0.05, 0.1, 0.5, 1, 5},
+})
+
+// InstancePool is a pool of identical runtime instances, instantiated from the same compiled module,
+// allowing runtime calls to be executed concurrently. The memory of an instance is reset to its
+// initial state when the instance is put back in the pool.
+type InstancePool struct {
+	module    wasm.Module
+	code      []byte
+	layout    *runtime.MemoryLayout
+	heapPages uint64
+	cfg       *Config
+	snapshot  []byte // memory of an instance right after its instantiation
+	instances chan *Instance
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewInstancePool creates a pool of size instances identical to the given instance.
+// The instances of the pool have no storage set.
+func NewInstancePool(in *Instance, size int) (*InstancePool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("invalid instance pool size: %d", size)
+	}
+
+	module, err := wasm.Compile(in.code)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile runtime code: %w", err)
+	}
+
+	cfg := &Config{
+		Imports: in.imports,
+	}
+	cfg.Keystore = in.ctx.Keystore
+	cfg.NodeStorage = in.ctx.NodeStorage
+	cfg.Network = in.ctx.Network
+	cfg.Transaction = in.ctx.Transaction
+	cfg.CodeHash = in.codeHash
+	cfg.OffchainHTTPDisabled = in.OffchainHTTPDisabled()
+
+	if in.ctx.Validator {
+		cfg.Role = 4
+	}
+
+	pool := &InstancePool{
+		module:    module,
+		code:      in.code,
+		layout:    in.layout,
+		heapPages: in.heapPages,
+		cfg:       cfg,
+		instances: make(chan *Instance, size),
+	}
+
+	for i := 0; i < size; i++ {
+		var instance *Instance
+		instance, err = pool.newInstance()
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("cannot create runtime instance: %w", err)
+		}
+
+		if pool.snapshot == nil {
+			pool.snapshot = append([]byte{}, instance.vm.Memory.Data()...)
+		}
+
+		pool.instances <- instance
+	}
+
+	return pool, nil
+}
+
+func (p *InstancePool) newInstance() (*Instance, error) {
+	instance, err := newInstanceFromModule(p.module, p.layout, p.heapPages, p.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	instance.code = p.code
+	return instance, nil
+}
+
+// Get takes an instance out of the pool, waiting until one is available.
+// The instance must be put back in the pool once the runtime calls are done.
+func (p *InstancePool) Get() (runtime.Instance, error) {
+	start := time.Now()
+	instance, ok := <-p.instances
+	if !ok {
+		return nil, errPoolClosed
+	}
+
+	poolWaitDuration.Observe(time.Since(start).Seconds())
+	return instance, nil
+}
+
+// Put resets the state of the instance and puts it back in the pool. The instance is replaced
+// by a new one if it is stopped, or if a runtime call failed.
+func (p *InstancePool) Put(pooled runtime.Instance) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	instance, ok := pooled.(*Instance)
+	if p.closed || !ok {
+		pooled.Stop()
+		return
+	}
+
+	instance.Lock()
+	replace := instance.isClosed || instance.trapped
+	instance.Unlock()
+
+	if replace {
+		instance.Stop()
+
+		var err error
+		instance, err = p.newInstance()
+		if err != nil {
+			// the pool is left with one instance less
+			logger.Errorf("cannot replace runtime instance of pool: %s", err)
+			return
+		}
+	} else {
+		instance.reset(p.snapshot)
+	}
+
+	p.instances <- instance
+}
+
+// NewPool creates a pool of size instances identical to the instance, and implements
+// the pooling of the runtime instances of the core service.
+func (in *Instance) NewPool(size int) (runtime.InstancePool, error) {
+	pool, err := NewInstancePool(in, size)
+	if err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Close stops the instances of the pool. The instances taken out of the pool are
+// stopped once put back.
+func (p *InstancePool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	p.closed = true
+	close(p.instances)
+	for instance := range p.instances {
+		instance.Stop()
+	}
+
+	p.module.Close()
+}
+
+// reset restores the memory of the instance to the given snapshot, and clears its context
+func (in *Instance) reset(snapshot []byte) {
+	in.Lock()
+	defer in.Unlock()
+
+	memory := in.vm.Memory.Data()
+	n := copy(memory, snapshot)
+	for i := n; i < len(memory); i++ {
+		memory[i] = 0
+	}
+
+	in.ctx.Storage = nil
+	in.ctx.Allocator = in.layout.NewAllocator(in.vm.Memory, in.heapPages)
+}
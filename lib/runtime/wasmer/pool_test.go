@@ -0,0 +1,78 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wasmer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancePool(t *testing.T) {
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+	expected, err := instance.Version()
+	require.NoError(t, err)
+
+	pool, err := NewInstancePool(instance, 2)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			pooled, err := pool.Get()
+			require.NoError(t, err)
+			defer pool.Put(pooled)
+
+			pooled.SetContextStorage(instance.ctx.Storage)
+			version, err := pooled.Version()
+			require.NoError(t, err)
+			require.Equal(t, expected, version)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestInstancePool_Put(t *testing.T) {
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+
+	pool, err := NewInstancePool(instance, 1)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	got, err := pool.Get()
+	require.NoError(t, err)
+	pooled := got.(*Instance)
+
+	// the memory of the instance is reset when it is put back in the pool
+	memory := pooled.vm.Memory.Data()
+	memory[len(memory)-1] = 1
+	pooled.SetContextStorage(instance.ctx.Storage)
+	pool.Put(pooled)
+
+	got, err = pool.Get()
+	require.NoError(t, err)
+	pooled = got.(*Instance)
+	require.Equal(t, pool.snapshot, pooled.vm.Memory.Data()[:len(pool.snapshot)])
+	require.Zero(t, pooled.vm.Memory.Data()[len(memory)-1])
+	require.Nil(t, pooled.ctx.Storage)
+
+	// an instance is replaced when it is stopped
+	pooled.Stop()
+	pool.Put(pooled)
+
+	replaced, err := pool.Get()
+	require.NoError(t, err)
+	require.NotSame(t, pooled, replaced)
+	pool.Put(replaced)
+
+	pool.Close()
+	_, err = pool.Get()
+	require.ErrorIs(t, err, errPoolClosed)
+}
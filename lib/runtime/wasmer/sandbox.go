@@ -0,0 +1,59 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wasmer
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+)
+
+// sandboxDispatcher calls the dispatch thunks of the runtime on behalf of the sandboxed instances.
+type sandboxDispatcher struct {
+	instance *Instance
+}
+
+// Dispatch implements sandbox.Dispatcher
+func (d *sandboxDispatcher) Dispatch(dispatchThunk, state, index uint32, args []byte) ([]byte, error) {
+	dispatch, ok := d.instance.vm.Exports[runtime.SandboxDispatchExport]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", runtime.ErrExportFunctionNotFound, runtime.SandboxDispatchExport)
+	}
+
+	allocator := d.instance.ctx.Allocator
+	argsPtr, err := allocator.Allocate(uint32(len(args)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot allocate arguments: %w", err)
+	}
+
+	defer func() {
+		deallocateErr := allocator.Deallocate(argsPtr)
+		if deallocateErr != nil {
+			logger.Errorf("failed to free sandbox dispatch arguments: %s", deallocateErr)
+		}
+	}()
+
+	copy(d.instance.vm.Memory.Data()[argsPtr:], args)
+
+	res, err := dispatch(int32(dispatchThunk), int32(argsPtr), int32(len(args)), int32(state), int32(index))
+	if err != nil {
+		return nil, err
+	}
+
+	ptr, length := runtime.Int64ToPointerAndSize(res.ToI64())
+	memory := d.instance.vm.Memory.Data()
+	if uint64(uint32(ptr))+uint64(uint32(length)) > uint64(len(memory)) {
+		return nil, fmt.Errorf("dispatch thunk result is out of bounds")
+	}
+
+	result := make([]byte, uint32(length))
+	copy(result, memory[uint32(ptr):])
+
+	err = allocator.Deallocate(uint32(ptr))
+	if err != nil {
+		return nil, fmt.Errorf("cannot free dispatch thunk result: %w", err)
+	}
+
+	return result, nil
+}
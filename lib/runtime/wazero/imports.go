@@ -0,0 +1,137 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/hostapi"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// importsModule is the name of the module the runtimes import the host functions from
+const importsModule = "env"
+
+var (
+	environmentType = reflect.TypeOf((*hostapi.Environment)(nil)).Elem()
+	int32Type       = reflect.TypeOf(int32(0))
+	int64Type       = reflect.TypeOf(int64(0))
+)
+
+// environment gives the host functions access to the wazero instance calling them
+type environment struct {
+	instance *Instance
+}
+
+// Memory implements hostapi.Environment
+func (e *environment) Memory() runtime.Memory {
+	return &memory{module: e.instance.module}
+}
+
+// Context implements hostapi.Environment
+func (e *environment) Context() *runtime.Context {
+	return e.instance.ctx
+}
+
+// Version implements hostapi.Environment
+func (*environment) Version(code []byte) (runtime.Version, error) {
+	cfg := &Config{}
+	cfg.LogLvl = log.DoNotChange
+	cfg.Storage, _ = rtstorage.NewTrieState(nil)
+
+	instance, err := NewInstance(code, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer instance.Stop()
+
+	return instance.Version()
+}
+
+// exportHostFunctions adds the host functions, called with the given environment, to
+// the env module imported by the runtimes.
+func exportHostFunctions(builder wazero.HostModuleBuilder, env hostapi.Environment) error {
+	for _, field := range hostapi.Names() {
+		fn, _ := hostapi.Lookup(field)
+		err := exportHostFunction(builder, env, field, fn)
+		if err != nil {
+			return fmt.Errorf("cannot import host function %s: %w", field, err)
+		}
+	}
+
+	return nil
+}
+
+// exportHostFunction wraps the host function implementation, whose first parameter is the
+// environment, in a wazero host function reading its parameters from the stack.
+func exportHostFunction(builder wazero.HostModuleBuilder, env hostapi.Environment, name string,
+	fn interface{}) error {
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+	if fnType.Kind() != reflect.Func || fnType.NumIn() == 0 || fnType.In(0) != environmentType {
+		return fmt.Errorf("invalid host function type %s", fnType)
+	}
+
+	params := make([]api.ValueType, fnType.NumIn()-1)
+	for i := range params {
+		valueType, ok := valueType(fnType.In(i + 1))
+		if !ok {
+			return fmt.Errorf("invalid parameter type %s", fnType.In(i+1))
+		}
+		params[i] = valueType
+	}
+
+	results := make([]api.ValueType, fnType.NumOut())
+	for i := range results {
+		valueType, ok := valueType(fnType.Out(i))
+		if !ok {
+			return fmt.Errorf("invalid result type %s", fnType.Out(i))
+		}
+		results[i] = valueType
+	}
+
+	envValue := reflect.ValueOf(&env).Elem()
+	host := func(_ context.Context, _ api.Module, stack []uint64) {
+		args := make([]reflect.Value, len(params)+1)
+		args[0] = envValue
+		for i, param := range params {
+			if param == api.ValueTypeI32 {
+				args[i+1] = reflect.ValueOf(api.DecodeI32(stack[i]))
+			} else {
+				args[i+1] = reflect.ValueOf(int64(stack[i]))
+			}
+		}
+
+		for i, result := range fnValue.Call(args) {
+			stack[i] = uint64(result.Int())
+			if results[i] == api.ValueTypeI32 {
+				stack[i] = api.EncodeI32(int32(result.Int()))
+			}
+		}
+	}
+
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(host), params, results).
+		Export(name)
+	return nil
+}
+
+// valueType returns the wasm value type of the host function parameter or result type
+func valueType(t reflect.Type) (api.ValueType, bool) {
+	switch t {
+	case int32Type:
+		return api.ValueTypeI32, true
+	case int64Type:
+		return api.ValueTypeI64, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/hostapi"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Name represents the name of the interpreter
+const Name = "wazero"
+
+// Check that runtime interfaces are satisfied
+var (
+	_ runtime.Instance = (*Instance)(nil)
+	_ runtime.Memory   = (*memory)(nil)
+
+	logger = log.NewFromGlobal(
+		log.AddContext("pkg", "runtime"),
+		log.AddContext("module", "wazero"),
+	)
+)
+
+var (
+	errInstanceStopped = errors.New("instance is stopped")
+	errMemoryGrow      = errors.New("cannot grow memory")
+)
+
+// Config represents a wazero configuration
+type Config struct {
+	runtime.InstanceConfig
+}
+
+// Instance is a runtime instance executed by the wazero interpreter, which is written in pure Go
+// and can be built without cgo.
+type Instance struct {
+	engine       wazero.Runtime
+	compiled     wazero.CompiledModule
+	module       api.Module
+	layout       *runtime.MemoryLayout
+	heapPages    uint64
+	ctx          *runtime.Context
+	isClosed     bool
+	codeHash     common.Hash
+	stateVersion runtime.StateVersionCache
+	sync.Mutex
+}
+
+// NewRuntimeFromGenesis creates a runtime instance from the genesis data
+func NewRuntimeFromGenesis(cfg *Config) (runtime.Instance, error) {
+	if cfg.Storage == nil {
+		return nil, errors.New("storage is nil")
+	}
+
+	code := cfg.Storage.LoadCode()
+	if len(code) == 0 {
+		return nil, fmt.Errorf("cannot find :code in state")
+	}
+
+	return NewInstance(code, cfg)
+}
+
+// NewInstanceFromTrie returns a new runtime instance with the code provided in the given trie
+func NewInstanceFromTrie(t *trie.Trie, cfg *Config) (*Instance, error) {
+	code := t.Get(common.CodeKey)
+	if len(code) == 0 {
+		return nil, fmt.Errorf("cannot find :code in trie")
+	}
+
+	return NewInstance(code, cfg)
+}
+
+// NewInstanceFromFile instantiates a runtime from a .wasm file
+func NewInstanceFromFile(fp string, cfg *Config) (*Instance, error) {
+	code, err := os.ReadFile(filepath.Clean(fp))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInstance(code, cfg)
+}
+
+// NewRuntimeInstance instantiates a runtime from raw wasm bytecode, and implements runtime.NewInstanceFunc
+func NewRuntimeInstance(code []byte, cfg runtime.InstanceConfig) (runtime.Instance, error) {
+	instance, err := NewInstance(code, &Config{InstanceConfig: cfg})
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// NewInstance instantiates a runtime from raw wasm bytecode
+func NewInstance(code []byte, cfg *Config) (*Instance, error) {
+	if len(code) == 0 {
+		return nil, errors.New("code is empty")
+	}
+
+	logger.Patch(log.SetLevel(cfg.LogLvl), log.SetCallerFunc(true))
+	hostapi.SetLogLevel(cfg.LogLvl)
+
+	httpSet := offchain.NewHTTPSet(cfg.OffchainCtx)
+	if cfg.OffchainHTTPDisabled {
+		httpSet.Disable()
+	}
+
+	inst := &Instance{
+		ctx: &runtime.Context{
+			Storage:         cfg.Storage,
+			Keystore:        cfg.Keystore,
+			Validator:       cfg.Role == byte(4),
+			NodeStorage:     cfg.NodeStorage,
+			Network:         cfg.Network,
+			Transaction:     cfg.Transaction,
+			SigVerifier:     crypto.NewSignatureVerifier(logger),
+			OffchainHTTPSet: httpSet,
+		},
+		codeHash: cfg.CodeHash,
+	}
+	inst.ctx.Sandbox = sandbox.NewStore(&sandboxDispatcher{instance: inst})
+
+	err := inst.setupInstanceVM(code)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("NewInstance called with runtimeCtx: %v", inst.ctx)
+	return inst, nil
+}
+
+// setupInstanceVM compiles the code in a new wazero runtime exporting the host functions,
+// and instantiates it. The memory imported by the runtime is defined by the module itself,
+// since the wazero host modules cannot export a memory.
+func (in *Instance) setupInstanceVM(code []byte) (err error) {
+	code, err = runtime.DecompressWasm(code)
+	if err != nil {
+		return fmt.Errorf("cannot decompress WASM code: %w", err)
+	}
+
+	layout, err := runtime.ParseMemoryLayout(code)
+	if err != nil {
+		return fmt.Errorf("cannot parse memory layout: %w", err)
+	}
+
+	code, err = runtime.InjectSandboxDispatch(code)
+	if err != nil {
+		return fmt.Errorf("cannot inject sandbox dispatch function: %w", err)
+	}
+
+	code, err = runtime.DefineImportedMemory(code)
+	if err != nil {
+		return fmt.Errorf("cannot define imported memory: %w", err)
+	}
+
+	ctx := context.Background()
+	engine := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfigInterpreter())
+	defer func() {
+		if err != nil {
+			_ = engine.Close(ctx)
+		}
+	}()
+
+	builder := engine.NewHostModuleBuilder(importsModule)
+	err = exportHostFunctions(builder, &environment{instance: in})
+	if err != nil {
+		return err
+	}
+
+	_, err = builder.Instantiate(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot instantiate host module: %w", err)
+	}
+
+	compiled, err := engine.CompileModule(ctx, code)
+	if err != nil {
+		return fmt.Errorf("cannot compile wasm module: %w", err)
+	}
+
+	in.engine = engine
+	in.compiled = compiled
+	in.layout = layout
+	in.heapPages = runtime.HeapPages(in.ctx.Storage)
+	in.isClosed = false
+	return in.instantiate()
+}
+
+// instantiate creates a new module instance from the compiled code, with a fresh memory and
+// globals, and sets the allocator of the context.
+func (in *Instance) instantiate() error {
+	ctx := context.Background()
+	if in.module != nil {
+		_ = in.module.Close(ctx)
+		in.module = nil
+	}
+
+	module, err := in.engine.InstantiateModule(ctx, in.compiled, wazero.NewModuleConfig())
+	if err != nil {
+		return fmt.Errorf("cannot instantiate wasm module: %w", err)
+	}
+
+	in.module = module
+	in.ctx.Allocator = in.layout.NewAllocator(&memory{module: module}, in.heapPages)
+	return nil
+}
+
+// GetCodeHash returns the code of the instance
+func (in *Instance) GetCodeHash() common.Hash {
+	return in.codeHash
+}
+
+// GetContext returns the context of the instance
+func (in *Instance) GetContext() *runtime.Context {
+	return in.ctx
+}
+
+// UpdateRuntimeCode updates the runtime instance to run the given code
+func (in *Instance) UpdateRuntimeCode(code []byte) error {
+	in.Stop()
+	in.stateVersion.Reset()
+
+	in.Lock()
+	defer in.Unlock()
+
+	return in.setupInstanceVM(code)
+}
+
+// CheckRuntimeVersion calculates runtime Version for runtime blob passed in
+func (in *Instance) CheckRuntimeVersion(code []byte) (runtime.Version, error) {
+	in.Lock()
+	ctx := *in.ctx
+	in.Unlock()
+
+	tmp := &Instance{
+		ctx: &ctx,
+	}
+	ctx.Sandbox = sandbox.NewStore(&sandboxDispatcher{instance: tmp})
+
+	err := tmp.setupInstanceVM(code)
+	if err != nil {
+		return nil, err
+	}
+	defer tmp.Stop()
+
+	return tmp.Version()
+}
+
+// SetContextStorage sets the runtime's storage. It should be set before calls to the below functions.
+func (in *Instance) SetContextStorage(s runtime.Storage) {
+	in.Lock()
+	defer in.Unlock()
+	in.ctx.Storage = s
+}
+
+// Stop func
+func (in *Instance) Stop() {
+	in.Lock()
+	defer in.Unlock()
+	if !in.isClosed {
+		_ = in.engine.Close(context.Background())
+		in.module = nil
+		in.isClosed = true
+	}
+}
+
+// Exec calls the given function with the given data
+func (in *Instance) Exec(function string, data []byte) ([]byte, error) {
+	return in.exec(function, data)
+}
+
+func (in *Instance) exec(function string, data []byte) ([]byte, error) {
+	if in.ctx.Storage == nil {
+		return nil, runtime.ErrNilStorage
+	}
+
+	in.Lock()
+	defer in.Unlock()
+
+	if in.isClosed {
+		return nil, errInstanceStopped
+	}
+
+	export := in.module.ExportedFunction(function)
+	if export == nil {
+		return nil, fmt.Errorf("%w: %s", runtime.ErrExportFunctionNotFound, function)
+	}
+
+	ptr, err := in.ctx.Allocator.Allocate(uint32(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	defer in.ctx.Allocator.Clear()
+	// the sandboxed memories only live for the duration of the call
+	defer in.ctx.Sandbox.Reset()
+
+	memory := in.module.Memory()
+	if !memory.Write(ptr, data) {
+		return nil, fmt.Errorf("input of %s is out of bounds", function)
+	}
+
+	res, err := export.Call(context.Background(), uint64(ptr), uint64(len(data)))
+	if err != nil {
+		if in.ctx.SigVerifier.IsStarted() {
+			// discard the batch verification which the runtime could not finish
+			in.ctx.SigVerifier.Reset()
+		}
+
+		// the globals and the memory of the runtime are left as they were at the trap
+		instantiateErr := in.instantiate()
+		if instantiateErr != nil {
+			in.isClosed = true
+			_ = in.engine.Close(context.Background())
+			return nil, fmt.Errorf("%w, and cannot reset instance: %s", err, instantiateErr)
+		}
+		return nil, err
+	}
+
+	if len(res) != 1 {
+		return nil, fmt.Errorf("unexpected %d results returned by %s", len(res), function)
+	}
+
+	offset, length := runtime.Int64ToPointerAndSize(int64(res[0]))
+	result, ok := in.module.Memory().Read(uint32(offset), uint32(length))
+	if !ok {
+		return nil, fmt.Errorf("result of %s is out of bounds", function)
+	}
+
+	return append([]byte(nil), result...), nil
+}
+
+// NodeStorage to get reference to runtime node service
+func (in *Instance) NodeStorage() runtime.NodeStorage {
+	return in.ctx.NodeStorage
+}
+
+// NetworkService to get referernce to runtime network service
+func (in *Instance) NetworkService() runtime.BasicNetwork {
+	return in.ctx.Network
+}
+
+// Keystore to get reference to runtime keystore
+func (in *Instance) Keystore() *keystore.GlobalKeystore {
+	return in.ctx.Keystore
+}
+
+// Validator returns the context's Validator
+func (in *Instance) Validator() bool {
+	return in.ctx.Validator
+}
+
+// OffchainHTTPDisabled returns true if the outbound HTTP requests of the offchain workers are disabled
+func (in *Instance) OffchainHTTPDisabled() bool {
+	return in.ctx.OffchainHTTPSet.Disabled()
+}
+
+// memory is the memory of a wazero module instance
+type memory struct {
+	module api.Module
+}
+
+// Data returns the memory's data
+func (m *memory) Data() []byte {
+	mem := m.module.Memory()
+	data, _ := mem.Read(0, mem.Size())
+	return data
+}
+
+// Length returns the memory's length
+func (m *memory) Length() uint32 {
+	return m.module.Memory().Size()
+}
+
+// Grow grows the memory by the given number of pages
+func (m *memory) Grow(pages uint32) error {
+	_, ok := m.module.Memory().Grow(pages)
+	if !ok {
+		return fmt.Errorf("%w by %d pages", errMemoryGrow, pages)
+	}
+	return nil
+}
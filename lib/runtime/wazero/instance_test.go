@@ -0,0 +1,123 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+// test used for ensuring runtime exec calls can me made concurrently
+func TestConcurrentRuntimeCalls(t *testing.T) {
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+
+	// execute 2 concurrent calls to the runtime
+	go func() {
+		_, _ = instance.exec(runtime.CoreVersion, []byte{})
+	}()
+	go func() {
+		_, _ = instance.exec(runtime.CoreVersion, []byte{})
+	}()
+}
+
+func TestInstance_CheckRuntimeVersion(t *testing.T) {
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+	polkadotRuntimeFilepath, err := runtime.GetRuntime(
+		context.Background(), runtime.POLKADOT_RUNTIME)
+	require.NoError(t, err)
+	code, err := os.ReadFile(polkadotRuntimeFilepath)
+	require.NoError(t, err)
+	version, err := instance.CheckRuntimeVersion(code)
+	require.NoError(t, err)
+
+	expected := runtime.NewVersionData(
+		[]byte("polkadot"),
+		[]byte("parity-polkadot"),
+		0,
+		25,
+		0,
+		nil,
+		5,
+	)
+
+	require.Equal(t, 12, len(version.APIItems()))
+	require.Equal(t, expected.SpecName(), version.SpecName())
+	require.Equal(t, expected.ImplName(), version.ImplName())
+	require.Equal(t, expected.AuthoringVersion(), version.AuthoringVersion())
+	require.Equal(t, expected.SpecVersion(), version.SpecVersion())
+	require.Equal(t, expected.ImplVersion(), version.ImplVersion())
+	require.Equal(t, expected.TransactionVersion(), version.TransactionVersion())
+}
+
+func TestNewInstance_MemoryLayout(t *testing.T) {
+	module := func(sections ...[]byte) []byte {
+		code := []byte("\x00asm\x01\x00\x00\x00")
+		for _, section := range sections {
+			code = append(code, section...)
+		}
+		return code
+	}
+	section := func(id byte, payload ...byte) []byte {
+		return append([]byte{id, byte(len(payload))}, payload...)
+	}
+
+	// imports a memory of at least 30 pages, and exports a __heap_base of 131072
+	importedMemoryCode := module(
+		section(2, append([]byte{1, 3}, "env\x06memory\x02\x00\x1e"...)...),
+		section(6, 1, 0x7f, 0x00, 0x41, 0x80, 0x80, 0x08, 0x0b),
+		section(7, append([]byte{1, 11}, "__heap_base\x03\x00"...)...),
+	)
+
+	instance, err := NewInstance(importedMemoryCode, setupConfig(t, nil, DefaultTestLogLvl, 0))
+	require.NoError(t, err)
+	defer instance.Stop()
+
+	// the imported memory is defined by the module with the imported limits
+	require.Equal(t, uint32(30*runtime.PageSize), instance.module.Memory().Size())
+	ptr, err := instance.ctx.Allocator.Allocate(1)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, ptr, uint32(131072))
+
+	// exports a memory of 2 pages, and a __heap_base of 65536
+	exportedMemoryCode := module(
+		section(5, 1, 0x00, 0x02),
+		section(6, 1, 0x7f, 0x00, 0x41, 0x80, 0x80, 0x04, 0x0b),
+		section(7, append([]byte{2, 6}, "memory\x02\x00\x0b__heap_base\x03\x00"...)...),
+	)
+
+	// the memory can only grow by the number of pages set by :heappages
+	cfg := setupConfig(t, nil, DefaultTestLogLvl, 0)
+	heapPages := make([]byte, 8)
+	binary.LittleEndian.PutUint64(heapPages, 1)
+	cfg.Storage.Set(common.HeapPagesKey, heapPages)
+
+	instance, err = NewInstance(exportedMemoryCode, cfg)
+	require.NoError(t, err)
+	defer instance.Stop()
+
+	ptr, err = instance.ctx.Allocator.Allocate(runtime.PageSize)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, ptr, uint32(65536))
+
+	_, err = instance.ctx.Allocator.Allocate(runtime.PageSize)
+	require.Error(t, err)
+}
+
+func TestInstance_ExecTrap(t *testing.T) {
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+
+	// the runtime panics decoding the empty input, which traps the module
+	_, err := instance.exec(runtime.TransactionPaymentAPIQueryInfo, []byte{})
+	require.Error(t, err)
+
+	// the module is instantiated again after the trap
+	_, err = instance.Version()
+	require.NoError(t, err)
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+)
+
+// sandboxDispatcher calls the dispatch thunks of the runtime on behalf of the sandboxed instances.
+type sandboxDispatcher struct {
+	instance *Instance
+}
+
+// Dispatch implements sandbox.Dispatcher
+func (d *sandboxDispatcher) Dispatch(dispatchThunk, state, index uint32, args []byte) ([]byte, error) {
+	// every exported function has its own call stack, so it can be called by the host functions
+	// the runtime is executing
+	dispatch := d.instance.module.ExportedFunction(runtime.SandboxDispatchExport)
+	if dispatch == nil {
+		return nil, fmt.Errorf("%w: %s", runtime.ErrExportFunctionNotFound, runtime.SandboxDispatchExport)
+	}
+
+	allocator := d.instance.ctx.Allocator
+	argsPtr, err := allocator.Allocate(uint32(len(args)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot allocate arguments: %w", err)
+	}
+
+	defer func() {
+		deallocateErr := allocator.Deallocate(argsPtr)
+		if deallocateErr != nil {
+			logger.Errorf("failed to free sandbox dispatch arguments: %s", deallocateErr)
+		}
+	}()
+
+	memory := d.instance.module.Memory()
+	memory.Write(argsPtr, args)
+
+	res, err := dispatch.Call(context.Background(), uint64(dispatchThunk), uint64(argsPtr),
+		uint64(len(args)), uint64(state), uint64(index))
+	if err != nil {
+		return nil, err
+	}
+
+	ptr, length := runtime.Int64ToPointerAndSize(int64(res[0]))
+	data, ok := memory.Read(uint32(ptr), uint32(length))
+	if !ok {
+		return nil, fmt.Errorf("dispatch thunk result is out of bounds")
+	}
+
+	result := append([]byte(nil), data...)
+	err = allocator.Deallocate(uint32(ptr))
+	if err != nil {
+		return nil, fmt.Errorf("cannot free dispatch thunk result: %w", err)
+	}
+
+	return result, nil
+}
@@ -109,6 +109,19 @@ func (spq *PriorityQueue) RemoveExtrinsic(ext types.Extrinsic) {
 	delete(spq.txs, hash)
 }
 
+// Get returns the transaction with the given extrinsic hash, or nil if it is not in the queue
+func (spq *PriorityQueue) Get(extHash common.Hash) *ValidTransaction {
+	spq.Lock()
+	defer spq.Unlock()
+
+	item, ok := spq.txs[extHash]
+	if !ok {
+		return nil
+	}
+
+	return item.data
+}
+
 // Exists returns true if a hash is in the txs map, false otherwise
 func (spq *PriorityQueue) Exists(extHash common.Hash) bool {
 	_, ok := spq.txs[extHash]
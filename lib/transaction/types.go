@@ -5,6 +5,7 @@
 
 import (
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
 )
 
 // Validity struct see
@@ -42,14 +43,6 @@ func NewValidTransaction(extrinsic types.Extrinsic, validity *Validity) *ValidTr
 	}
 }
 
-// // StatusNotification represents information about a transaction status update.
-// type StatusNotification struct {
-// 	Ext                types.Extrinsic
-// 	Status             string
-// 	PeersBroadcastedTo []string
-// 	BlockHash          *common.Hash
-// }
-
 // Status represents possible transaction statuses.
 //
 // The status events can be grouped based on their kinds as:
@@ -126,3 +119,14 @@ func (s Status) String() string {
 	}
 	return "unknown"
 }
+
+// StatusNotification represents information about a transaction status update.
+type StatusNotification struct {
+	Status Status
+	// PeersBroadcastedTo is set for the Broadcast status.
+	PeersBroadcastedTo []string
+	// Hash is the block hash for the InBlock, Retracted, FinalityTimeout and
+	// Finalized statuses, and the hash of the replacing transaction for the
+	// Usurped status. It is nil for all other statuses.
+	Hash *common.Hash
+}
@@ -16,23 +16,27 @@
 )
 
 var (
-	ErrEmptyProof = errors.New("proof slice empty")
-	ErrDecodeNode = errors.New("cannot decode node")
+	ErrEmptyProof         = errors.New("proof slice empty")
+	ErrDecodeNode         = errors.New("cannot decode node")
+	ErrRootNodeNotInProof = errors.New("root node not found in proof")
 )
 
 // Store stores each trie node in the database,
 // where the key is the hash of the encoded node
 // and the value is the encoded node.
+// The modified nodes are encoded using the state trie version given,
+// and the values hashed in the node encodings are stored with their
+// hash as key.
 // Generally, this will only be used for the genesis trie.
-func (t *Trie) Store(db chaindb.Database) error {
+func (t *Trie) Store(db chaindb.Database, version Version) error {
 	for _, v := range t.childTries {
-		if err := v.Store(db); err != nil {
+		if err := v.Store(db, version); err != nil {
 			return fmt.Errorf("failed to store child trie with root hash=0x%x in the db: %w", v.root.HashDigest, err)
 		}
 	}
 
 	batch := db.NewBatch()
-	err := t.store(batch, t.root)
+	err := t.store(batch, t.root, version.MaxInlineValue())
 	if err != nil {
 		batch.Reset()
 		return err
@@ -41,12 +45,12 @@ func (t *Trie) Store(db chaindb.Database) error {
 	return batch.Flush()
 }
 
-func (t *Trie) store(db chaindb.Batch, n *Node) error {
+func (t *Trie) store(db chaindb.Batch, n *Node, maxInlineValue int) error {
 	if n == nil {
 		return nil
 	}
 
-	encoding, hash, err := n.EncodeAndHash(n == t.root)
+	encoding, hash, err := n.EncodeAndHash(n == t.root, maxInlineValue)
 	if err != nil {
 		return err
 	}
@@ -56,13 +60,18 @@ func (t *Trie) store(db chaindb.Batch, n *Node) error {
 		return err
 	}
 
+	err = storeValue(db, hash, n)
+	if err != nil {
+		return err
+	}
+
 	if n.Type() == node.Branch {
 		for _, child := range n.Children {
 			if child == nil {
 				continue
 			}
 
-			err = t.store(db, child)
+			err = t.store(db, child, maxInlineValue)
 			if err != nil {
 				return err
 			}
@@ -77,6 +86,8 @@ func (t *Trie) store(db chaindb.Batch, n *Node) error {
 }
 
 // LoadFromProof sets a partial trie based on the proof slice of encoded nodes.
+// The proof slice can also contain the values stored separately from their
+// node with the state trie version 1, which are then set in their node.
 // Note this is exported because it is imported  is used by:
 // https://github.com/ComposableFi/ibc-go/blob/6d62edaa1a3cb0768c430dab81bb195e0b0c72db/modules/light-clients/11-beefy/types/client_state.go#L78
 func (t *Trie) LoadFromProof(proofEncodedNodes [][]byte, rootHash []byte) error {
@@ -84,44 +95,34 @@ func (t *Trie) LoadFromProof(proofEncodedNodes [][]byte, rootHash []byte) error
 		return ErrEmptyProof
 	}
 
-	proofHashToNode := make(map[string]*Node, len(proofEncodedNodes))
-
-	for i, rawNode := range proofEncodedNodes {
-		decodedNode, err := node.Decode(bytes.NewReader(rawNode))
+	proofHashToEncoding := make(map[string][]byte, len(proofEncodedNodes))
+	for i, encoding := range proofEncodedNodes {
+		hash, err := common.Blake2bHash(encoding)
 		if err != nil {
-			return fmt.Errorf("%w: at index %d: 0x%x",
-				ErrDecodeNode, i, rawNode)
+			return fmt.Errorf("cannot hash proof entry at index %d: %w", i, err)
 		}
+		proofHashToEncoding[common.BytesToHex(hash[:])] = encoding
+	}
 
-		const dirty = false
-		decodedNode.SetDirty(dirty)
-		decodedNode.Encoding = rawNode
-		decodedNode.HashDigest = nil
-
-		_, hash, err := decodedNode.EncodeAndHash(false)
-		if err != nil {
-			return fmt.Errorf("cannot encode and hash node at index %d: %w", i, err)
-		}
-
-		proofHash := common.BytesToHex(hash)
-		proofHashToNode[proofHash] = decodedNode
-
-		if bytes.Equal(hash, rootHash) {
-			// Found root in proof
-			t.root = decodedNode
-		}
+	encodedRoot, ok := proofHashToEncoding[common.BytesToHex(rootHash)]
+	if !ok {
+		return fmt.Errorf("%w: 0x%x", ErrRootNodeNotInProof, rootHash)
 	}
 
-	t.loadProof(proofHashToNode, t.root)
+	root, err := decodeProofNode(proofHashToEncoding, encodedRoot, rootHash)
+	if err != nil {
+		return err
+	}
+	t.root = root
 
-	return nil
+	return t.loadProof(proofHashToEncoding, t.root)
 }
 
 // loadProof is a recursive function that will create all the trie paths based
 // on the mapped proofs slice starting at the root
-func (t *Trie) loadProof(proofHashToNode map[string]*Node, n *Node) {
+func (t *Trie) loadProof(proofHashToEncoding map[string][]byte, n *Node) error {
 	if n.Type() != node.Branch {
-		return
+		return nil
 	}
 
 	branch := n
@@ -130,15 +131,61 @@ func (t *Trie) loadProof(proofHashToNode map[string]*Node, n *Node) {
 			continue
 		}
 
-		proofHash := common.BytesToHex(child.HashDigest)
-		node, ok := proofHashToNode[proofHash]
-		if !ok {
+		if len(child.HashDigest) == 0 {
+			// inlined leaf already decoded with its parent
 			continue
 		}
 
-		branch.Children[i] = node
-		t.loadProof(proofHashToNode, node)
+		// Note an encoding shorter than a hash is inlined in its parent encoding.
+		encoding := child.HashDigest
+		if len(encoding) >= common.HashLength {
+			var ok bool
+			encoding, ok = proofHashToEncoding[common.BytesToHex(child.HashDigest)]
+			if !ok {
+				continue
+			}
+		}
+
+		decodedChild, err := decodeProofNode(proofHashToEncoding, encoding, child.HashDigest)
+		if err != nil {
+			return fmt.Errorf("at child index %d: %w", i, err)
+		}
+
+		branch.Children[i] = decodedChild
+		err = t.loadProof(proofHashToEncoding, decodedChild)
+		if err != nil {
+			// Note: do not wrap error since it's returned recursively.
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeProofNode decodes the encoded node given from the proof,
+// and sets its value from the proof if it is stored separately.
+func decodeProofNode(proofHashToEncoding map[string][]byte,
+	encoding, hash []byte) (decodedNode *Node, err error) {
+	decodedNode, err = node.Decode(bytes.NewReader(encoding))
+	if err != nil {
+		return nil, fmt.Errorf("%w: with hash 0x%x: %s",
+			ErrDecodeNode, hash, err)
 	}
+
+	const dirty = false
+	decodedNode.SetDirty(dirty)
+	decodedNode.Encoding = encoding
+	decodedNode.HashDigest = hash
+
+	if decodedNode.HashedValue {
+		value, ok := proofHashToEncoding[common.BytesToHex(decodedNode.Value)]
+		if ok {
+			decodedNode.Value = value
+			decodedNode.HashedValue = false
+		}
+	}
+
+	return decodedNode, nil
 }
 
 // Load reconstructs the trie from the database from the given root hash.
@@ -166,6 +213,11 @@ func (t *Trie) Load(db chaindb.Database, rootHash common.Hash) error {
 	t.root.Encoding = encodedNode
 	t.root.HashDigest = rootHashBytes
 
+	err = loadValue(db, rootHashBytes, t.root)
+	if err != nil {
+		return fmt.Errorf("cannot load value of root node: %w", err)
+	}
+
 	return t.load(db, t.root)
 }
 
@@ -184,8 +236,9 @@ func (t *Trie) load(db chaindb.Database, n *Node) error {
 
 		if len(hash) == 0 && child.Type() == node.Leaf {
 			// node has already been loaded inline
-			// just set encoding + hash digest
-			_, _, err := child.EncodeAndHash(false)
+			// just set encoding + hash digest.
+			// Note inlined leaves are too small to have their value hashed.
+			_, _, err := child.EncodeAndHash(false, V0.MaxInlineValue())
 			if err != nil {
 				return err
 			}
@@ -209,6 +262,11 @@ func (t *Trie) load(db chaindb.Database, n *Node) error {
 		decodedNode.HashDigest = hash
 		branch.Children[i] = decodedNode
 
+		err = loadValue(db, hash, decodedNode)
+		if err != nil {
+			return fmt.Errorf("cannot load value of child at index %d with hash 0x%x: %w", i, hash, err)
+		}
+
 		err = t.load(db, decodedNode)
 		if err != nil {
 			return fmt.Errorf("cannot load child at index %d with hash 0x%x: %w", i, hash, err)
@@ -245,8 +303,57 @@ func (t *Trie) load(db chaindb.Database, n *Node) error {
 	return nil
 }
 
+// loadValue sets the value of the node decoded with the hash of its value,
+// reading the value stored separately in the database for the node hash given.
+func loadValue(db chaindb.Database, nodeHash []byte, n *Node) error {
+	if !n.HashedValue {
+		return nil
+	}
+
+	value, err := db.Get(valueKey(nodeHash))
+	if err != nil {
+		return fmt.Errorf("cannot find value with hash 0x%x of node with hash 0x%x in database: %w",
+			n.Value, nodeHash, err)
+	}
+
+	n.Value = value
+	n.HashedValue = false
+	return nil
+}
+
+// storeValue puts the value of the node in the database for the node hash given,
+// if the cached encoding of the node contains the hash of its value.
+func storeValue(db chaindb.Batch, nodeHash []byte, n *Node) error {
+	if n.HashedValue {
+		// the value is unknown and was stored with the node it was loaded from.
+		return nil
+	}
+
+	valueHash, err := n.ValueHash()
+	if err != nil {
+		return fmt.Errorf("cannot get value hash: %w", err)
+	} else if valueHash == nil {
+		return nil
+	}
+
+	err = db.Put(valueKey(nodeHash), n.Value)
+	if err != nil {
+		return fmt.Errorf("cannot put value with hash 0x%x in database: %w", valueHash, err)
+	}
+	return nil
+}
+
+// valueKey returns the database key of the value stored separately from the
+// node with the given hash. The value is keyed by its node instead of its own
+// hash, since nodes with the same value would otherwise share the value entry,
+// and pruning one of them would delete the value of the others.
+func valueKey(nodeHash []byte) []byte {
+	return common.MustBlake2bHash(nodeHash).ToBytes()
+}
+
 // PopulateNodeHashes writes hashes of each children of the node given
-// as keys to the map hashesSet.
+// as keys to the map hashesSet, as well as the database keys of their
+// values stored separately from them.
 func (t *Trie) PopulateNodeHashes(n *Node, hashesSet map[common.Hash]struct{}) {
 	if n.Type() != node.Branch {
 		return
@@ -261,33 +368,40 @@ func (t *Trie) PopulateNodeHashes(n *Node, hashesSet map[common.Hash]struct{}) {
 		hash := common.BytesToHash(child.HashDigest)
 		hashesSet[hash] = struct{}{}
 
+		// Note an error can only occur for an invalid cached encoding,
+		// in which case there is no value stored separately.
+		valueHash, err := child.ValueHash()
+		if err == nil && len(valueHash) > 0 {
+			hashesSet[common.BytesToHash(valueKey(child.HashDigest))] = struct{}{}
+		}
+
 		t.PopulateNodeHashes(child, hashesSet)
 	}
 }
 
 // PutInDB inserts a value in the trie at the key given.
 // It writes the updated nodes from the changed node up to the root node
-// to the database in a batch operation.
-func (t *Trie) PutInDB(db chaindb.Database, key, value []byte) error {
+// to the database in a batch operation, using the state trie version given.
+func (t *Trie) PutInDB(db chaindb.Database, version Version, key, value []byte) error {
 	t.Put(key, value)
-	return t.WriteDirty(db)
+	return t.WriteDirty(db, version)
 }
 
 // DeleteFromDB deletes a value from the trie at the key given.
 // It writes the updated nodes from the changed node up to the root node
-// to the database in a batch operation.
-func (t *Trie) DeleteFromDB(db chaindb.Database, key []byte) error {
+// to the database in a batch operation, using the state trie version given.
+func (t *Trie) DeleteFromDB(db chaindb.Database, version Version, key []byte) error {
 	t.Delete(key)
-	return t.WriteDirty(db)
+	return t.WriteDirty(db, version)
 }
 
 // ClearPrefixFromDB deletes all nodes with keys starting the given prefix
 // from the trie. It writes the updated nodes from the changed node up to
-// the root node to the database in a batch operation.
-// in a batch operation.
-func (t *Trie) ClearPrefixFromDB(db chaindb.Database, prefix []byte) error {
+// the root node to the database in a batch operation, using the
+// state trie version given.
+func (t *Trie) ClearPrefixFromDB(db chaindb.Database, version Version, prefix []byte) error {
 	t.ClearPrefix(prefix)
-	return t.WriteDirty(db)
+	return t.WriteDirty(db, version)
 }
 
 // GetFromDB retrieves a value at the given key from the trie using the database.
@@ -313,17 +427,21 @@ func GetFromDB(db chaindb.Database, rootHash common.Hash, key []byte) (
 		return nil, fmt.Errorf("cannot decode root node: %w", err)
 	}
 
-	return getFromDB(db, rootNode, k)
+	return getFromDB(db, rootHash[:], rootNode, k)
 }
 
 // getFromDB recursively searches through the trie and database
-// for the value corresponding to a key.
+// for the value corresponding to a key, from the node with the hash given.
 // Note it does not copy the value so modifying the value bytes
 // slice will modify the value of the node in the trie.
-func getFromDB(db chaindb.Database, n *Node, key []byte) (
+func getFromDB(db chaindb.Database, nodeHash []byte, n *Node, key []byte) (
 	value []byte, err error) {
 	if n.Type() == node.Leaf {
 		if bytes.Equal(n.Key, key) {
+			err = loadValue(db, nodeHash, n)
+			if err != nil {
+				return nil, err
+			}
 			return n.Value, nil
 		}
 		return nil, nil
@@ -332,6 +450,10 @@ func getFromDB(db chaindb.Database, n *Node, key []byte) (
 	branch := n
 	// Key is equal to the key of this branch or is empty
 	if len(key) == 0 || bytes.Equal(branch.Key, key) {
+		err = loadValue(db, nodeHash, branch)
+		if err != nil {
+			return nil, err
+		}
 		return branch.Value, nil
 	}
 
@@ -353,7 +475,7 @@ func getFromDB(db chaindb.Database, n *Node, key []byte) (
 	// Child can be either inlined or a hash pointer.
 	childHash := child.HashDigest
 	if len(childHash) == 0 && child.Type() == node.Leaf {
-		return getFromDB(db, child, key[commonPrefixLength+1:])
+		return getFromDB(db, nil, child, key[commonPrefixLength+1:])
 	}
 
 	encodedChild, err := db.Get(childHash)
@@ -371,14 +493,16 @@ func getFromDB(db chaindb.Database, n *Node, key []byte) (
 			childHash, err)
 	}
 
-	return getFromDB(db, decodedChild, key[commonPrefixLength+1:])
+	return getFromDB(db, childHash, decodedChild, key[commonPrefixLength+1:])
 	// Note: do not wrap error since it's called recursively.
 }
 
-// WriteDirty writes all dirty nodes to the database and sets them to clean
-func (t *Trie) WriteDirty(db chaindb.Database) error {
+// WriteDirty writes all dirty nodes to the database and sets them to clean.
+// The dirty nodes are encoded using the state trie version given, and the
+// values hashed in their encodings are written keyed by their node.
+func (t *Trie) WriteDirty(db chaindb.Database, version Version) error {
 	batch := db.NewBatch()
-	err := t.writeDirty(batch, t.root)
+	err := t.writeDirty(batch, t.root, version.MaxInlineValue())
 	if err != nil {
 		batch.Reset()
 		return err
@@ -387,12 +511,12 @@ func (t *Trie) WriteDirty(db chaindb.Database) error {
 	return batch.Flush()
 }
 
-func (t *Trie) writeDirty(db chaindb.Batch, n *Node) error {
+func (t *Trie) writeDirty(db chaindb.Batch, n *Node, maxInlineValue int) error {
 	if n == nil || !n.Dirty {
 		return nil
 	}
 
-	encoding, hash, err := n.EncodeAndHash(n == t.root)
+	encoding, hash, err := n.EncodeAndHash(n == t.root, maxInlineValue)
 	if err != nil {
 		return fmt.Errorf(
 			"cannot encode and hash node with hash 0x%x: %w",
@@ -406,6 +530,11 @@ func (t *Trie) writeDirty(db chaindb.Batch, n *Node) error {
 			hash, err)
 	}
 
+	err = storeValue(db, hash, n)
+	if err != nil {
+		return fmt.Errorf("cannot store value of node with hash 0x%x: %w", hash, err)
+	}
+
 	if n.Type() != node.Branch {
 		n.SetDirty(false)
 		return nil
@@ -416,7 +545,7 @@ func (t *Trie) writeDirty(db chaindb.Batch, n *Node) error {
 			continue
 		}
 
-		err = t.writeDirty(db, child)
+		err = t.writeDirty(db, child, maxInlineValue)
 		if err != nil {
 			// Note: do not wrap error since it's returned recursively.
 			return err
@@ -424,7 +553,7 @@ func (t *Trie) writeDirty(db chaindb.Batch, n *Node) error {
 	}
 
 	for _, childTrie := range t.childTries {
-		if err := childTrie.writeDirty(db, childTrie.root); err != nil {
+		if err := childTrie.writeDirty(db, childTrie.root, maxInlineValue); err != nil {
 			return fmt.Errorf("failed to write dirty node=0x%x to database: %w", childTrie.root.HashDigest, err)
 		}
 	}
@@ -436,25 +565,28 @@ func (t *Trie) writeDirty(db chaindb.Batch, n *Node) error {
 
 // GetInsertedNodeHashes returns a set of hashes with all
 // the hashes of all nodes that were inserted in the state trie
-// since the last snapshot.
-// We need to compute the hash values of each newly inserted node.
-func (t *Trie) GetInsertedNodeHashes() (hashesSet map[common.Hash]struct{}, err error) {
+// since the last snapshot, as well as the database keys of their values
+// stored separately from them.
+// We need to compute the hash values of each newly inserted node,
+// using the state trie version given.
+func (t *Trie) GetInsertedNodeHashes(version Version) (hashesSet map[common.Hash]struct{}, err error) {
 	hashesSet = make(map[common.Hash]struct{})
-	err = t.getInsertedNodeHashes(t.root, hashesSet)
+	err = t.getInsertedNodeHashes(t.root, version.MaxInlineValue(), hashesSet)
 	if err != nil {
 		return nil, err
 	}
 	return hashesSet, nil
 }
 
-func (t *Trie) getInsertedNodeHashes(n *Node, hashes map[common.Hash]struct{}) (err error) {
+func (t *Trie) getInsertedNodeHashes(n *Node, maxInlineValue int,
+	hashes map[common.Hash]struct{}) (err error) {
 	// TODO pass map of hashes or slice as argument to avoid copying
 	// and using more memory.
 	if n == nil || !n.Dirty {
 		return nil
 	}
 
-	_, hash, err := n.EncodeAndHash(n == t.root)
+	_, hash, err := n.EncodeAndHash(n == t.root, maxInlineValue)
 	if err != nil {
 		return fmt.Errorf(
 			"cannot encode and hash node with hash 0x%x: %w",
@@ -463,6 +595,13 @@ func (t *Trie) getInsertedNodeHashes(n *Node, hashes map[common.Hash]struct{}) (
 
 	hashes[common.BytesToHash(hash)] = struct{}{}
 
+	valueHash, err := n.ValueHash()
+	if err != nil {
+		return fmt.Errorf("cannot get value hash of node with hash 0x%x: %w", hash, err)
+	} else if valueHash != nil {
+		hashes[common.BytesToHash(valueKey(hash))] = struct{}{}
+	}
+
 	if n.Type() != node.Branch {
 		return nil
 	}
@@ -472,7 +611,7 @@ func (t *Trie) getInsertedNodeHashes(n *Node, hashes map[common.Hash]struct{}) (
 			continue
 		}
 
-		err := t.getInsertedNodeHashes(child, hashes)
+		err := t.getInsertedNodeHashes(child, maxInlineValue, hashes)
 		if err != nil {
 			// Note: do not wrap error since this is called recursively.
 			return err
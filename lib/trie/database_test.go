@@ -7,6 +7,7 @@
 	"testing"
 
 	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -29,7 +30,7 @@ func Test_Trie_Store_Load(t *testing.T) {
 	rootHash := trie.MustHash()
 
 	db := newTestDB(t)
-	err := trie.Store(db)
+	err := trie.Store(db, V0)
 	require.NoError(t, err)
 
 	trieFromDB := NewEmptyTrie()
@@ -38,6 +39,35 @@ func Test_Trie_Store_Load(t *testing.T) {
 	assert.Equal(t, trie.String(), trieFromDB.String())
 }
 
+func Test_Trie_Store_Load_V1(t *testing.T) {
+	t.Parallel()
+
+	const size = 1000
+	trie, keyValues := makeSeededTrie(t, size)
+
+	rootHash, err := trie.HashWithVersion(V1)
+	require.NoError(t, err)
+	assert.NotEqual(t, trie.MustHash(), rootHash)
+
+	db := newTestDB(t)
+	err = trie.Store(db, V1)
+	require.NoError(t, err)
+
+	trieFromDB := NewEmptyTrie()
+	err = trieFromDB.Load(db, rootHash)
+	require.NoError(t, err)
+	assert.Equal(t, trie.String(), trieFromDB.String())
+
+	for keyString, expectedValue := range keyValues {
+		key := []byte(keyString)
+		assert.Equal(t, expectedValue, trieFromDB.Get(key))
+
+		value, err := GetFromDB(db, rootHash, key)
+		require.NoError(t, err)
+		assert.Equal(t, expectedValue, value)
+	}
+}
+
 func Test_Trie_WriteDirty_Put(t *testing.T) {
 	t.Parallel()
 
@@ -55,7 +85,7 @@ func Test_Trie_WriteDirty_Put(t *testing.T) {
 
 		trie.Put(key, value)
 
-		err := trie.WriteDirty(db)
+		err := trie.WriteDirty(db, V0)
 		require.NoError(t, err)
 
 		rootHash := trie.MustHash()
@@ -64,7 +94,7 @@ func Test_Trie_WriteDirty_Put(t *testing.T) {
 		assert.Equalf(t, value, valueFromDB, "for key=%x", key)
 	}
 
-	err := trie.Store(db)
+	err := trie.Store(db, V0)
 	require.NoError(t, err)
 
 	// Pick an existing key and replace its value
@@ -73,7 +103,7 @@ func Test_Trie_WriteDirty_Put(t *testing.T) {
 	existingValue := keyValues[string(existingKey)]
 	newValue := append(existingValue, 99)
 	trie.Put(existingKey, newValue)
-	err = trie.WriteDirty(db)
+	err = trie.WriteDirty(db, V0)
 	require.NoError(t, err)
 
 	rootHash := trie.MustHash()
@@ -98,12 +128,12 @@ func Test_Trie_WriteDirty_Delete(t *testing.T) {
 	keysToDelete := pickKeys(keyValues, generator, size/50)
 
 	db := newTestDB(t)
-	err := trie.Store(db)
+	err := trie.Store(db, V0)
 	require.NoError(t, err)
 
 	deletedKeys := make(map[string]struct{}, len(keysToDelete))
 	for _, keyToDelete := range keysToDelete {
-		err = trie.DeleteFromDB(db, keyToDelete)
+		err = trie.DeleteFromDB(db, V0, keyToDelete)
 		require.NoError(t, err)
 
 		deletedKeys[string(keyToDelete)] = struct{}{}
@@ -138,11 +168,11 @@ func Test_Trie_WriteDirty_ClearPrefix(t *testing.T) {
 	keysToClearPrefix := pickKeys(keyValues, generator, size/50)
 
 	db := newTestDB(t)
-	err := trie.Store(db)
+	err := trie.Store(db, V0)
 	require.NoError(t, err)
 
 	for _, keyToClearPrefix := range keysToClearPrefix {
-		err = trie.ClearPrefixFromDB(db, keyToClearPrefix)
+		err = trie.ClearPrefixFromDB(db, V0, keyToClearPrefix)
 		require.NoError(t, err)
 	}
 
@@ -161,7 +191,7 @@ func Test_Trie_GetFromDB(t *testing.T) {
 	trie, keyValues := makeSeededTrie(t, size)
 
 	db := newTestDB(t)
-	err := trie.Store(db)
+	err := trie.Store(db, V0)
 	require.NoError(t, err)
 
 	root := trie.MustHash()
@@ -198,7 +228,7 @@ func Test_Trie_PutChild_Store_Load(t *testing.T) {
 		err := trie.PutChild(keyToChildTrie, childTrie)
 		require.NoError(t, err)
 
-		err = trie.Store(db)
+		err = trie.Store(db, V0)
 		require.NoError(t, err)
 
 		trieFromDB := NewEmptyTrie()
@@ -209,3 +239,27 @@ func Test_Trie_PutChild_Store_Load(t *testing.T) {
 		assert.Equal(t, trie.String(), trieFromDB.String())
 	}
 }
+
+func Test_Trie_GetInsertedNodeHashes_V1(t *testing.T) {
+	t.Parallel()
+
+	value := make([]byte, V1MaxInlineValue+1)
+	trie := NewEmptyTrie()
+	trie.Put([]byte{1}, value)
+
+	hashes, err := trie.GetInsertedNodeHashes(V1)
+	require.NoError(t, err)
+
+	rootHash, err := trie.HashWithVersion(V1)
+	require.NoError(t, err)
+
+	expectedHashes := map[common.Hash]struct{}{
+		rootHash: {},
+		common.BytesToHash(valueKey(rootHash[:])): {},
+	}
+	assert.Equal(t, expectedHashes, hashes)
+
+	hashes, err = trie.GetInsertedNodeHashes(V0)
+	require.NoError(t, err)
+	assert.Equal(t, map[common.Hash]struct{}{trie.MustHash(): {}}, hashes)
+}
@@ -16,13 +16,16 @@ type recorder interface {
 	Record(hash, rawData []byte)
 }
 
-// findAndRecord search for a desired key recording all the nodes in the path including the desired node
+// findAndRecord search for a desired key recording all the nodes in the path including the desired node,
+// as well as its value if it is stored separately from the node.
 func findAndRecord(t *Trie, key []byte, recorder recorder) error {
 	return find(t.root, key, recorder, true)
 }
 
 func find(parent *Node, key []byte, recorder recorder, isCurrentRoot bool) error {
-	enc, hash, err := parent.EncodeAndHash(isCurrentRoot)
+	// Note the nodes loaded from the database keep their stored
+	// encoding, whatever the state trie version given.
+	enc, hash, err := parent.EncodeAndHash(isCurrentRoot, V0.MaxInlineValue())
 	if err != nil {
 		return err
 	}
@@ -30,6 +33,9 @@ func find(parent *Node, key []byte, recorder recorder, isCurrentRoot bool) error
 	recorder.Record(hash, enc)
 
 	if parent.Type() != node.Branch {
+		if bytes.Equal(parent.Key, key) {
+			return recordValue(parent, recorder)
+		}
 		return nil
 	}
 
@@ -38,7 +44,7 @@ func find(parent *Node, key []byte, recorder recorder, isCurrentRoot bool) error
 
 	// found the value at this node
 	if bytes.Equal(branch.Key, key) || len(key) == 0 {
-		return nil
+		return recordValue(branch, recorder)
 	}
 
 	// did not find value
@@ -48,3 +54,20 @@ func find(parent *Node, key []byte, recorder recorder, isCurrentRoot bool) error
 
 	return find(branch.Children[key[length]], key[length+1:], recorder, false)
 }
+
+// recordValue records the value of the node if it is stored separately from the node.
+func recordValue(n *Node, recorder recorder) error {
+	if n.HashedValue {
+		// the value is not known
+		return nil
+	}
+
+	valueHash, err := n.ValueHash()
+	if err != nil {
+		return err
+	} else if valueHash != nil {
+		recorder.Record(valueHash, n.Value)
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@
 	"testing"
 
 	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,7 +34,7 @@ func TestProofGeneration(t *testing.T) {
 	trie.Put([]byte("dog"), generateRandBytes(t, size, generator))
 	trie.Put([]byte("doguinho"), generateRandBytes(t, size, generator))
 
-	err = trie.Store(memdb)
+	err = trie.Store(memdb, V0)
 	require.NoError(t, err)
 
 	hash, err := trie.Hash()
@@ -69,7 +70,7 @@ func testGenerateProof(t *testing.T, entries []Pair, keys [][]byte) ([]byte, [][
 		trie.Put(e.Key, e.Value)
 	}
 
-	err = trie.Store(memdb)
+	err = trie.Store(memdb, V0)
 	require.NoError(t, err)
 
 	root := trie.root.HashDigest
@@ -207,7 +208,7 @@ func TestLeafNodes_SameHash_DifferentPaths_GenerateAndVerifyProof(t *testing.T)
 	tt.Put(key1, value)
 	tt.Put(key2, value)
 
-	err = tt.Store(memdb)
+	err = tt.Store(memdb, V0)
 	require.NoError(t, err)
 
 	hash, err := tt.Hash()
@@ -225,3 +226,40 @@ func TestLeafNodes_SameHash_DifferentPaths_GenerateAndVerifyProof(t *testing.T)
 	require.NoError(t, err)
 	require.True(t, ok)
 }
+
+func TestVerifyProof_V1HashedValues(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+
+	const size = 100
+	generator := newGenerator()
+	largeValue := generateRandBytes(t, size, generator)
+
+	trie := NewEmptyTrie()
+	trie.Put([]byte("cat"), largeValue)
+	trie.Put([]byte("catapulta"), generateRandBytes(t, size, generator))
+	trie.Put([]byte("dog"), []byte("puppy"))
+	trie.Put([]byte("doguinho"), generateRandBytes(t, size, generator))
+
+	err := trie.Store(db, V1)
+	require.NoError(t, err)
+
+	root, err := trie.HashWithVersion(V1)
+	require.NoError(t, err)
+
+	proof, err := GenerateProof(root.ToBytes(), [][]byte{[]byte("cat"), []byte("dog")}, db)
+	require.NoError(t, err)
+
+	valueHash := common.MustBlake2bHash(largeValue)
+	require.Contains(t, proof, largeValue, "proof does not contain value with hash %s", valueHash)
+
+	pairs := []Pair{
+		{Key: []byte("cat"), Value: largeValue},
+		{Key: []byte("dog"), Value: []byte("puppy")},
+	}
+
+	ok, err := VerifyProof(proof, root.ToBytes(), pairs)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
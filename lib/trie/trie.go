@@ -107,6 +107,15 @@ func updateGeneration(currentNode *Node, trieGeneration uint64,
 		deletedHashes[deletedHash] = struct{}{}
 	}
 
+	// The value of the node is stored separately in the database
+	// if the encoding of the node contains the hash of its value.
+	// Note an error can only occur for an invalid cached encoding,
+	// in which case there is no value stored separately.
+	valueHash, err := currentNode.ValueHash()
+	if err == nil && len(valueHash) > 0 && len(deletedHashBytes) > 0 {
+		deletedHashes[common.BytesToHash(valueKey(deletedHashBytes))] = struct{}{}
+	}
+
 	return newNode
 }
 
@@ -154,7 +163,8 @@ func (t *Trie) RootNode() *Node {
 }
 
 // encodeRoot writes the encoding of the root node to the buffer.
-func encodeRoot(root *Node, buffer node.Buffer) (err error) {
+// Values larger than maxInlineValue bytes are hashed in the encodings of the nodes.
+func encodeRoot(root *Node, maxInlineValue int, buffer node.Buffer) (err error) {
 	if root == nil {
 		_, err = buffer.Write([]byte{0})
 		if err != nil {
@@ -162,7 +172,7 @@ func encodeRoot(root *Node, buffer node.Buffer) (err error) {
 		}
 		return nil
 	}
-	return root.Encode(buffer)
+	return root.Encode(buffer, maxInlineValue)
 }
 
 // MustHash returns the hashed root of the trie.
@@ -176,13 +186,21 @@ func (t *Trie) MustHash() common.Hash {
 	return h
 }
 
-// Hash returns the hashed root of the trie.
+// Hash returns the hashed root of the trie, using the
+// state trie version 0 to encode the modified nodes.
 func (t *Trie) Hash() (rootHash common.Hash, err error) {
+	return t.HashWithVersion(V0)
+}
+
+// HashWithVersion returns the hashed root of the trie, using the
+// state trie version given to encode the modified nodes.
+// Note the nodes not modified keep the encoding they were stored with.
+func (t *Trie) HashWithVersion(version Version) (rootHash common.Hash, err error) {
 	buffer := pools.EncodingBuffers.Get().(*bytes.Buffer)
 	buffer.Reset()
 	defer pools.EncodingBuffers.Put(buffer)
 
-	err = encodeRoot(t.root, buffer)
+	err = encodeRoot(t.root, version.MaxInlineValue(), buffer)
 	if err != nil {
 		return [32]byte{}, err
 	}
@@ -359,6 +377,7 @@ func (t *Trie) insertInLeaf(parentLeaf *Node, key, value []byte) (
 		copySettings.CopyValue = false
 		parentLeaf = t.prepLeafForMutation(parentLeaf, copySettings)
 		parentLeaf.Value = value
+		parentLeaf.HashedValue = false
 		return parentLeaf, nodesCreated
 	}
 
@@ -394,6 +413,7 @@ func (t *Trie) insertInLeaf(parentLeaf *Node, key, value []byte) (
 	if len(parentLeaf.Key) == commonPrefixLength {
 		// the key of the parent leaf is at this new branch
 		newBranchParent.Value = parentLeaf.Value
+		newBranchParent.HashedValue = parentLeaf.HashedValue
 	} else {
 		// make the leaf a child of the new branch
 		copySettings := node.DefaultCopySettings
@@ -424,6 +444,7 @@ func (t *Trie) insertInBranch(parentBranch *Node, key, value []byte) (
 
 	if bytes.Equal(key, parentBranch.Key) {
 		parentBranch.Value = value
+		parentBranch.HashedValue = false
 		return parentBranch, 0
 	}
 
@@ -959,6 +980,7 @@ func (t *Trie) deleteBranch(branch *Node, key []byte) (
 		// we need to set to nil if the branch has the same generation
 		// as the current trie.
 		branch.Value = nil
+		branch.HashedValue = false
 		deleted = true
 		var branchChildMerged bool
 		newParent, branchChildMerged = handleDeletion(branch, key)
@@ -1019,10 +1041,11 @@ func handleDeletion(branch *Node, key []byte) (newNode *Node, branchChildMerged
 		const branchChildMerged = false
 		commonPrefixLength := lenCommonPrefix(branch.Key, key)
 		return &Node{
-			Key:        key[:commonPrefixLength],
-			Value:      branch.Value,
-			Dirty:      true,
-			Generation: branch.Generation,
+			Key:         key[:commonPrefixLength],
+			Value:       branch.Value,
+			HashedValue: branch.HashedValue,
+			Dirty:       true,
+			Generation:  branch.Generation,
 		}, branchChildMerged
 	case childrenCount == 1 && branch.Value == nil:
 		const branchChildMerged = true
@@ -1032,21 +1055,23 @@ func handleDeletion(branch *Node, key []byte) (newNode *Node, branchChildMerged
 		if child.Type() == node.Leaf {
 			newLeafKey := concatenateSlices(branch.Key, intToByteSlice(childIndex), child.Key)
 			return &Node{
-				Key:        newLeafKey,
-				Value:      child.Value,
-				Dirty:      true,
-				Generation: branch.Generation,
+				Key:         newLeafKey,
+				Value:       child.Value,
+				HashedValue: child.HashedValue,
+				Dirty:       true,
+				Generation:  branch.Generation,
 			}, branchChildMerged
 		}
 
 		childBranch := child
 		newBranchKey := concatenateSlices(branch.Key, intToByteSlice(childIndex), childBranch.Key)
 		newBranch := &Node{
-			Key:        newBranchKey,
-			Value:      childBranch.Value,
-			Generation: branch.Generation,
-			Children:   make([]*node.Node, node.ChildrenCapacity),
-			Dirty:      true,
+			Key:         newBranchKey,
+			Value:       childBranch.Value,
+			HashedValue: childBranch.HashedValue,
+			Generation:  branch.Generation,
+			Children:    make([]*node.Node, node.ChildrenCapacity),
+			Dirty:       true,
 			// this is the descendants of the original branch minus one
 			Descendants: childBranch.Descendants,
 		}
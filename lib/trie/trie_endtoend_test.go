@@ -278,7 +278,7 @@ func TestTrieDiff(t *testing.T) {
 	}
 
 	newTrie := trie.Snapshot()
-	err = trie.Store(storageDB)
+	err = trie.Store(storageDB, V0)
 	require.NoError(t, err)
 
 	tests = []keyValues{
@@ -295,7 +295,7 @@ func TestTrieDiff(t *testing.T) {
 	deletedKeys := newTrie.deletedKeys
 	require.Len(t, deletedKeys, 3)
 
-	err = newTrie.WriteDirty(storageDB)
+	err = newTrie.WriteDirty(storageDB, V0)
 	require.NoError(t, err)
 
 	for key := range deletedKeys {
@@ -1013,7 +1013,7 @@ func Test_encodeRoot_fuzz(t *testing.T) {
 			assert.Equal(t, value, retrievedValue)
 		}
 		buffer := bytes.NewBuffer(nil)
-		err := trie.root.Encode(buffer)
+		err := trie.root.Encode(buffer, V0.MaxInlineValue())
 		require.NoError(t, err)
 		require.NotEmpty(t, buffer.Bytes())
 	}
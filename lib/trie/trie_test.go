@@ -140,6 +140,34 @@ func Test_Trie_updateGeneration(t *testing.T) {
 				}: {},
 			},
 		},
+		"trie generation higher and hashed value": {
+			trieGeneration: 2,
+			node: &Node{
+				Generation:  1,
+				Key:         []byte{1},
+				Value:       []byte{4, 5},
+				HashedValue: true,
+				HashDigest:  []byte{1, 2, 3},
+				Encoding:    []byte{0x21, 0x01, 4, 5},
+			},
+			copySettings: node.DefaultCopySettings,
+			newNode: &Node{
+				Generation:  2,
+				Key:         []byte{1},
+				Value:       []byte{4, 5},
+				HashedValue: true,
+			},
+			copied: true,
+			expectedDeletedHashes: map[common.Hash]struct{}{
+				{
+					0, 0, 0, 0, 0, 0, 0, 0, 0,
+					0, 0, 0, 0, 0, 0, 0, 0, 0,
+					0, 0, 0, 0, 0, 0, 0, 0, 0,
+					0, 0, 1, 2, 3,
+				}: {},
+				common.BytesToHash(valueKey([]byte{1, 2, 3})): {},
+			},
+		},
 	}
 
 	for name, testCase := range testCases {
@@ -396,7 +424,7 @@ type bufferCalls struct {
 					Return(testCase.bufferCalls.bytesReturn)
 			}
 
-			err := encodeRoot(testCase.root, buffer)
+			err := encodeRoot(testCase.root, V0.MaxInlineValue(), buffer)
 
 			assert.ErrorIs(t, err, testCase.errWrapped)
 			if testCase.errWrapped != nil {
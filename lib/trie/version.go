@@ -0,0 +1,62 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package trie
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/internal/trie/node"
+)
+
+// ErrVersionNotSupported is returned if the state trie version is not supported.
+var ErrVersionNotSupported = errors.New("state trie version not supported")
+
+// Version is the state trie version, which dictates
+// how the values are stored in the trie nodes.
+type Version uint8
+
+const (
+	// V0 is the state trie version storing all the values in the trie nodes.
+	V0 Version = iota
+	// V1 is the state trie version storing the hash of the values larger
+	// than 32 bytes in the trie nodes, and the values themselves in the
+	// database with their hash as key.
+	V1
+)
+
+// V1MaxInlineValue is the maximum size of the values stored
+// in the trie nodes for the state trie version 1.
+const V1MaxInlineValue = 32
+
+// ParseVersion returns the state trie version of
+// the state version number given by the runtime.
+func ParseVersion(version uint32) (Version, error) {
+	switch version {
+	case uint32(V0), uint32(V1):
+		return Version(version), nil
+	default:
+		return 0, fmt.Errorf("%w: %d", ErrVersionNotSupported, version)
+	}
+}
+
+// MaxInlineValue returns the maximum size of the values
+// stored in the trie nodes for the state trie version.
+func (v Version) MaxInlineValue() int {
+	if v == V0 {
+		return node.NoMaxInlineValue
+	}
+	return V1MaxInlineValue
+}
+
+func (v Version) String() string {
+	switch v {
+	case V0:
+		return "v0"
+	case V1:
+		return "v1"
+	default:
+		return fmt.Sprintf("v%d", uint8(v))
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package trie
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/trie/node"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		stateVersion uint32
+		version      Version
+		errWrapped   error
+		errMessage   string
+	}{
+		"v0": {
+			stateVersion: 0,
+			version:      V0,
+		},
+		"v1": {
+			stateVersion: 1,
+			version:      V1,
+		},
+		"unsupported version": {
+			stateVersion: 2,
+			errWrapped:   ErrVersionNotSupported,
+			errMessage:   "state trie version not supported: 2",
+		},
+		"overflowing version": {
+			stateVersion: 256,
+			errWrapped:   ErrVersionNotSupported,
+			errMessage:   "state trie version not supported: 256",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			version, err := ParseVersion(testCase.stateVersion)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.version, version)
+		})
+	}
+}
+
+func Test_Version_MaxInlineValue(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, node.NoMaxInlineValue, V0.MaxInlineValue())
+	assert.Equal(t, 32, V1.MaxInlineValue())
+}
@@ -37,6 +37,13 @@ func TestUint128FromLEBytes(t *testing.T) {
 	require.Equal(t, bytes, res)
 }
 
+func TestUint128_String(t *testing.T) {
+	u, _ := NewUint128(big.NewInt(256))
+	require.Equal(t, "256", u.String())
+
+	require.Equal(t, "340282366920938463463374607431768211455", MaxUint128.String())
+}
+
 func TestUint128_Cmp(t *testing.T) {
 	bytes := []byte{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6}
 	u0, _ := NewUint128(bytes)
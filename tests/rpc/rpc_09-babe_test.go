@@ -7,15 +7,15 @@
 	"context"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	libutils "github.com/ChainSafe/gossamer/lib/utils"
 	"github.com/ChainSafe/gossamer/tests/utils"
 	"github.com/ChainSafe/gossamer/tests/utils/config"
 	"github.com/ChainSafe/gossamer/tests/utils/node"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBabeRPC(t *testing.T) {
-	t.SkipNow() // TODO
-
 	if utils.MODE != rpcSuite {
 		t.Log("Going to skip RPC suite tests")
 		return
@@ -25,6 +25,7 @@ func TestBabeRPC(t *testing.T) {
 	tomlConfig := config.Default()
 	tomlConfig.Init.Genesis = genesisPath
 	tomlConfig.Core.BABELead = true
+	tomlConfig.RPC.Modules = append(tomlConfig.RPC.Modules, "babe")
 	node := node.New(t, tomlConfig)
 	ctx, cancel := context.WithCancel(context.Background())
 	node.InitAndStartTest(ctx, t, cancel)
@@ -32,10 +33,14 @@ func TestBabeRPC(t *testing.T) {
 	t.Run("babe_epochAuthorship", func(t *testing.T) {
 		t.Parallel()
 
-		var response struct{} // TODO
+		var response modules.EpochAuthorshipResponse
 
 		fetchWithTimeout(ctx, t, "babe_epochAuthorship", "", &response)
 
-		// TODO assert response
+		require.Len(t, response, 1)
+		for _, authorship := range response {
+			claimed := len(authorship.Primary) + len(authorship.Secondary) + len(authorship.SecondaryVRF)
+			require.NotZero(t, claimed)
+		}
 	})
 }